@@ -32,6 +32,10 @@ func CompleteOutputFomats(_ *cobra.Command, _ []string, _ string) ([]string, cob
 	return []string{cli.OutputFormatPlain, cli.OutputFormatJSON}, cobra.ShellCompDirectiveNoFileComp
 }
 
+func CompleteDiffOutputFormats(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return []string{cli.OutputFormatPlain, cli.OutputFormatJSON, cli.OutputFormatJSONPatch}, cobra.ShellCompDirectiveNoFileComp
+}
+
 func NoCompletionNoFile(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
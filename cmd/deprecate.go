@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+var deprecateCmd = &cobra.Command{
+	Use:   "deprecate <tmid>",
+	Short: "Mark a TM version as deprecated",
+	Long: `Mark a TM version as deprecated. A deprecated version is still fetched by name, but a warning is printed, so that
+new consumers can be steered to its successor. Devices which have pinned the version's id are not affected.
+Use --undo to return the version to the active state.`,
+	Args:              cobra.ExactArgs(1),
+	Run:               executeDeprecate,
+	ValidArgsFunction: completion.CompleteTMNamesOrIds,
+}
+
+func init() {
+	RootCmd.AddCommand(deprecateCmd)
+	addLifecycleFlags(deprecateCmd)
+}
+
+func executeDeprecate(cmd *cobra.Command, args []string) {
+	setLifecycle(cmd, args[0], model.LifecycleDeprecated)
+}
+
+func addLifecycleFlags(cmd *cobra.Command) {
+	AddRepoDisambiguatorFlags(cmd)
+	cmd.Flags().String("reason", "", "Reason for the change of state, e.g. a description of the defect")
+	cmd.Flags().String("successor", "", "Id of the TM version which should be used instead")
+	_ = cmd.RegisterFlagCompletionFunc("successor", completion.CompleteTMNamesOrIds)
+	cmd.Flags().Bool("undo", false, "Return the TM version to the active state")
+	cmd.MarkFlagsMutuallyExclusive("undo", "reason")
+	cmd.MarkFlagsMutuallyExclusive("undo", "successor")
+}
+
+func setLifecycle(cmd *cobra.Command, id string, state model.LifecycleState) {
+	spec := RepoSpecFromFlags(cmd)
+	undo, _ := cmd.Flags().GetBool("undo")
+	lc := model.Lifecycle{State: state}
+	if undo {
+		lc.State = model.LifecycleActive
+	} else {
+		lc.Reason = cmd.Flag("reason").Value.String()
+		lc.Successor = cmd.Flag("successor").Value.String()
+	}
+
+	err := cli.SetLifecycle(context.Background(), spec, id, lc)
+	if err != nil {
+		cli.Stderrf("%s failed", cmd.Name())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <name>[:<semver>] | <tmid> <name>[:<semver>] | <tmid>",
+	Short: "Show the structural differences between two TMs",
+	Long: `Show the structural differences between two TMs fetched by name or id.
+Properties, actions and events are compared by name and forms by their href and op, so that neither the order of keys
+nor the ids assigned on import produce differences. The TMs may come from different repositories.
+The differences can be printed as a table (plain), as json, or as a JSON Patch (RFC 6902) transforming the first TM into the second.`,
+	Args:              cobra.ExactArgs(2),
+	Run:               executeDiff,
+	ValidArgsFunction: completion.CompleteFetchNames,
+}
+
+func init() {
+	RootCmd.AddCommand(diffCmd)
+	AddRepoConstraintFlags(diffCmd)
+	diffCmd.Flags().String("format", cli.OutputFormatPlain, "output format. One of: [plain, json, jsonpatch]")
+	_ = diffCmd.RegisterFlagCompletionFunc("format", completion.CompleteDiffOutputFormats)
+}
+
+func executeDiff(cmd *cobra.Command, args []string) {
+	spec := RepoSpecFromFlags(cmd)
+	format := cmd.Flag("format").Value.String()
+
+	err := cli.Diff(context.Background(), spec, args[0], args[1], format)
+	if err != nil {
+		cli.Stderrf("diff failed")
+		os.Exit(1)
+	}
+}
@@ -14,6 +14,8 @@ var fetchCmd = &cobra.Command{
 	Short: "Fetch a TM by name or id",
 	Long: `Fetch a TM by name, optionally accepting a semantic version, or id.
 The semantic version can be full or partial, e.g. v1.2.3, v1.2, v1. The 'v' at the beginning of a version is optional.
+Instead of a version, a version range can be given, e.g. ^1.2, ~1.4.0 or '>=1.0 <2.0'. The most recent version within
+the range is fetched.
 When fetching by id, the returned TM's id might have a different timestamp than that in the requested id, because the timestamp
 is considered irrelevant in this case. The TM name, semantic version, and content digest will match exactly, of course.`,
 	Args:              cobra.ExactArgs(1),
@@ -27,15 +29,17 @@ func init() {
 	fetchCmd.Flags().StringP("output", "o", "", "Write the fetched TM to output folder instead of stdout")
 	_ = fetchCmd.MarkFlagDirname("output")
 	fetchCmd.Flags().BoolP("restore-id", "R", false, "Restore the TM's original external id, if it had one")
+	fetchCmd.Flags().Bool("resolve", false, "Resolve tm:extends links and tm:ref references from the catalog and output a self-contained TM")
 }
 
 func executeFetch(cmd *cobra.Command, args []string) {
 	outputPath := cmd.Flag("output").Value.String()
 	restoreId, _ := cmd.Flags().GetBool("restore-id")
+	resolve, _ := cmd.Flags().GetBool("resolve")
 
 	spec := RepoSpecFromFlags(cmd)
 
-	err := cli.Fetch(context.Background(), spec, args[0], outputPath, restoreId)
+	err := cli.Fetch(context.Background(), spec, args[0], outputPath, restoreId, resolve)
 	if err != nil {
 		cli.Stderrf("fetch failed")
 		os.Exit(1)
@@ -37,6 +37,8 @@ func init() {
 	importCmd.Flags().Bool("force", false, `Force import, even if there are conflicts with existing TMs.`)
 	importCmd.Flags().Bool("ignore-existing", false, `Ignore TMs that have conflicts with existing TMs instead of returning an error code.`)
 	importCmd.Flags().Bool("with-attachments", false, `Import all non-json files as attachments to the corresponding TMs. Has no effect when file-or-directory points to a file.`)
+	importCmd.Flags().Bool("reject-breaking", false, `Reject TMs that contain breaking changes compared to the previous version without increasing the major version,
+instead of importing them with a warning.`)
 }
 
 func executeImport(cmd *cobra.Command, args []string) {
@@ -45,6 +47,7 @@ func executeImport(cmd *cobra.Command, args []string) {
 	force, _ := cmd.Flags().GetBool("force")
 	ie, _ := cmd.Flags().GetBool("ignore-existing")
 	wa, _ := cmd.Flags().GetBool("with-attachments")
+	rb, _ := cmd.Flags().GetBool("reject-breaking")
 	format := cmd.Flag("format").Value.String()
 	spec := RepoSpecFromFlags(cmd)
 	opts := repos.ImportOptions{
@@ -53,6 +56,9 @@ func executeImport(cmd *cobra.Command, args []string) {
 		IgnoreExisting:  ie,
 		WithAttachments: wa,
 	}
+	if rb {
+		opts.BreakingChanges = repos.BreakingChangesReject
+	}
 	_, err := cli.NewImportExecutor(time.Now).Import(context.Background(), args[0], spec, optTree, opts, format)
 	if err != nil {
 		cli.Stderrf("import failed")
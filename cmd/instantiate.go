@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/commands"
+)
+
+var instantiateCmd = &cobra.Command{
+	Use:   "instantiate <name>[:<semver>] | <tmid>",
+	Short: "Generate a Thing Description from a TM",
+	Long: `Generate a Thing Description from a TM fetched by name or id.
+References to other TMs via tm:extends and tm:ref are resolved from the catalog. All {{PLACEHOLDER}} values are substituted
+with values from the JSON object in the file given with --placeholders, the TM terms are dropped, and the id and base of
+the Thing Description are set. The resulting Thing Description is validated against the TD JSON schema.
+Instantiation fails if the TM contains placeholders without a value.`,
+	Args:              cobra.ExactArgs(1),
+	Run:               executeInstantiate,
+	ValidArgsFunction: completion.CompleteFetchNames,
+}
+
+func init() {
+	RootCmd.AddCommand(instantiateCmd)
+	AddRepoConstraintFlags(instantiateCmd)
+	instantiateCmd.Flags().StringP("placeholders", "p", "", "JSON file with values for the TM's placeholders by placeholder name")
+	_ = instantiateCmd.MarkFlagFilename("placeholders", "json")
+	instantiateCmd.Flags().String("id", "", "Id of the Thing Description. A random 'urn:uuid:' id is generated if not given")
+	instantiateCmd.Flags().String("base", "", "Base URI of the Thing Description. The TM's base is kept if not given")
+	instantiateCmd.Flags().StringP("output", "o", "", "Write the Thing Description to output file instead of stdout")
+	_ = instantiateCmd.MarkFlagFilename("output")
+}
+
+func executeInstantiate(cmd *cobra.Command, args []string) {
+	placeholders := cmd.Flag("placeholders").Value.String()
+	outputFile := cmd.Flag("output").Value.String()
+	opts := commands.InstantiateOptions{
+		ID:   cmd.Flag("id").Value.String(),
+		Base: cmd.Flag("base").Value.String(),
+	}
+
+	spec := RepoSpecFromFlags(cmd)
+
+	err := cli.Instantiate(context.Background(), spec, args[0], placeholders, outputFile, opts)
+	if err != nil {
+		cli.Stderrf("instantiate failed")
+		os.Exit(1)
+	}
+}
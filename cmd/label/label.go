@@ -0,0 +1,19 @@
+package label
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage TM labels",
+	Long: `The subcommands of the label command allow to manage free-form labels, e.g. 'certified' or 'beta', which are used
+to curate the catalog. You can label a single TM version, or an inventory name, encompassing all TM versions for a device.
+Labels are stored in the repository's index and can be used to filter the output of list, export and copy with --filter.label.
+For all label operations you must unambiguously specify the repository.`,
+}
+
+func init() {
+	cmd.RootCmd.AddCommand(labelCmd)
+}
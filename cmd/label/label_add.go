@@ -0,0 +1,41 @@
+package label
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+)
+
+var labelAddCmd = &cobra.Command{
+	Use:   "add <tm-name-or-id> <label>...",
+	Short: "Add labels to a TM name or version",
+	Long: `Add labels to a TM name or version. A label consists of at most 64 letters, digits, '.', '_', ':' and '-',
+and must start with a letter or digit.`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  labelAdd,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.CompleteTMNamesOrIds(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+func labelAdd(command *cobra.Command, args []string) {
+	spec := cmd.RepoSpecFromFlags(command)
+
+	err := cli.LabelAdd(context.Background(), spec, args[0], args[1:])
+	if err != nil {
+		cli.Stderrf("label add failed")
+		os.Exit(1)
+	}
+}
+
+func init() {
+	cmd.AddRepoDisambiguatorFlags(labelAddCmd)
+	labelCmd.AddCommand(labelAddCmd)
+}
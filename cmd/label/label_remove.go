@@ -0,0 +1,40 @@
+package label
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+)
+
+var labelRemoveCmd = &cobra.Command{
+	Use:   "remove <tm-name-or-id> <label>...",
+	Short: "Remove labels from a TM name or version",
+	Long:  `Remove labels from a TM name or version. Labels which are not present are ignored.`,
+	Args:  cobra.MinimumNArgs(2),
+	Run:   labelRemove,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.CompleteTMNamesOrIds(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+func labelRemove(command *cobra.Command, args []string) {
+	spec := cmd.RepoSpecFromFlags(command)
+
+	err := cli.LabelRemove(context.Background(), spec, args[0], args[1:])
+	if err != nil {
+		cli.Stderrf("label remove failed")
+		os.Exit(1)
+	}
+}
+
+func init() {
+	cmd.AddRepoDisambiguatorFlags(labelRemoveCmd)
+	labelCmd.AddCommand(labelRemoveCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <old-name> <new-name>",
+	Short: "Move all versions of a TM name to a new name",
+	Long: `Move all versions and attachments of a TM name to a new name, e.g. after a manufacturer has been renamed or acquired.
+The new name consists of author, manufacturer, mpn and an optional path, like any TM name, and must be given
+in the sanitized form used in TM ids. The ids of all versions are changed accordingly.
+When the author, manufacturer or mpn changes, 'schema:author', 'schema:manufacturer' or 'schema:mpn' in the TMs are
+changed as well, to the names given with --author-name, --manufacturer-name and --mpn, or else to the new part of
+the TM name. The digests in the ids are recalculated then. Signatures of TMs whose content has changed no longer
+verify, so they are removed and the affected TMs are listed to be signed again with 'tmc sign'.
+The old name is kept as an alias of the new name, so that consumers fetching the old name or ids keep working.`,
+	Args:              cobra.ExactArgs(2),
+	Run:               executeMove,
+	ValidArgsFunction: completion.CompleteTMNames,
+}
+
+func init() {
+	RootCmd.AddCommand(moveCmd)
+	AddRepoDisambiguatorFlags(moveCmd)
+	moveCmd.Flags().String("author-name", "", "New 'schema:author' name of the TMs. Must match the author in <new-name> when sanitized")
+	moveCmd.Flags().String("manufacturer-name", "", "New 'schema:manufacturer' name of the TMs. Must match the manufacturer in <new-name> when sanitized")
+	moveCmd.Flags().String("mpn", "", "New 'schema:mpn' of the TMs. Must match the mpn in <new-name> when sanitized")
+}
+
+func executeMove(cmd *cobra.Command, args []string) {
+	spec := RepoSpecFromFlags(cmd)
+	names := model.DisplayNames{
+		Author:       cmd.Flag("author-name").Value.String(),
+		Manufacturer: cmd.Flag("manufacturer-name").Value.String(),
+		Mpn:          cmd.Flag("mpn").Value.String(),
+	}
+
+	err := cli.Move(context.Background(), spec, args[0], args[1], names)
+	if err != nil {
+		cli.Stderrf("move failed")
+		os.Exit(1)
+	}
+}
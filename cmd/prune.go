@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old TM versions according to a retention policy",
+	Long: `Delete TM versions which are not kept by the retention policy of a repository, along with their attachments.
+
+The policy is read from the 'retention' setting of the repository config, e.g.
+  "retention": {"keep_latest": 3, "keep_minor_heads": true, "keep_younger_than": "90d"}
+A version is kept if any of the rules keeps it:
+  keep_latest        keep the latest N versions per semantic version, e.g. per v1.0.0
+  keep_minor_heads   keep the latest version of each major.minor release
+  keep_younger_than  keep versions with a timestamp younger than the given age, e.g. 90d or 36h
+The --keep-* flags replace the configured policy.
+
+The latest version of each TM name, labelled versions and versions named as successor of another version are never
+deleted. Give a version a label to pin it, e.g. 'tmc label add <tmid> pinned'.
+Use --dry-run to print the versions to be deleted without deleting them.`,
+	Args: cobra.NoArgs,
+	Run:  executePrune,
+}
+
+func init() {
+	RootCmd.AddCommand(pruneCmd)
+	AddRepoDisambiguatorFlags(pruneCmd)
+	AddOutputFormatFlag(pruneCmd)
+	pruneCmd.Flags().Bool("dry-run", false, "Print the versions to be deleted without deleting them")
+	pruneCmd.Flags().Int("keep-latest", 0, "Keep the latest N versions per semantic version")
+	pruneCmd.Flags().Bool("keep-minor-heads", false, "Keep the latest version of each major.minor release")
+	pruneCmd.Flags().String("keep-younger-than", "", "Keep versions younger than the given age, e.g. 90d or 36h")
+}
+
+func executePrune(cmd *cobra.Command, args []string) {
+	spec := RepoSpecFromFlags(cmd)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	format := cmd.Flag("format").Value.String()
+
+	opts := cli.PruneOptions{DryRun: dryRun}
+	if cmd.Flags().Changed("keep-latest") || cmd.Flags().Changed("keep-minor-heads") || cmd.Flags().Changed("keep-younger-than") {
+		policy := &model.RetentionPolicy{}
+		policy.KeepLatest, _ = cmd.Flags().GetInt("keep-latest")
+		policy.KeepMinorHeads, _ = cmd.Flags().GetBool("keep-minor-heads")
+		if age := cmd.Flag("keep-younger-than").Value.String(); age != "" {
+			d, err := model.ParseRetentionAge(age)
+			if err != nil {
+				cli.Stderrf("%v", err)
+				os.Exit(1)
+			}
+			policy.KeepYoungerThan = d
+		}
+		opts.Policy = policy
+	}
+
+	err := cli.Prune(context.Background(), spec, opts, format)
+	if err != nil {
+		cli.Stderrf("prune failed")
+		os.Exit(1)
+	}
+}
@@ -156,6 +156,7 @@ func resetSearchFlags(flags *FilterFlags) {
 	flags.FilterManufacturer = ""
 	flags.FilterMpn = ""
 	flags.FilterProtocol = ""
+	flags.FilterLabel = ""
 }
 
 func TestConvertFilters(t *testing.T) {
@@ -173,6 +174,7 @@ func TestConvertFilters(t *testing.T) {
 	flags.FilterManufacturer = "some manufacturer"
 	flags.FilterMpn = "some mpn"
 	flags.FilterProtocol = "http"
+	flags.FilterLabel = "certified"
 	flags.FilterChangedSince = "20240101000000"
 	name := "omni-corp/omni"
 	// when: converting to Filters
@@ -186,6 +188,7 @@ func TestConvertFilters(t *testing.T) {
 	assert.Equal(t, flags.FilterChangedSince, params.ChangedSince)
 	assert.Equal(t, model.PrefixMatch, params.Options.NameFilterType)
 	assert.Equal(t, []string{flags.FilterProtocol}, params.Protocol)
+	assert.Equal(t, []string{flags.FilterLabel}, params.Label)
 
 	// given: filter params are set with multiple comma-separated values
 	resetSearchFlags(&flags)
@@ -193,6 +196,7 @@ func TestConvertFilters(t *testing.T) {
 	flags.FilterManufacturer = "some manufacturer 1,some manufacturer 2"
 	flags.FilterMpn = "some mpn 1,some mpn 2,some mpn 3"
 	flags.FilterProtocol = "http,https"
+	flags.FilterLabel = "certified,beta"
 	flags.FilterChangedSince = "20240101000000"
 	// when: converting to Filters
 	params = CreateFiltersFromCLI(flags, "")
@@ -202,5 +206,6 @@ func TestConvertFilters(t *testing.T) {
 	assert.Equal(t, strings.Split(flags.FilterManufacturer, ","), params.Manufacturer)
 	assert.Equal(t, strings.Split(flags.FilterMpn, ","), params.Mpn)
 	assert.Equal(t, strings.Split(flags.FilterProtocol, ","), params.Protocol)
+	assert.Equal(t, strings.Split(flags.FilterLabel, ","), params.Label)
 	assert.Equal(t, flags.FilterChangedSince, params.ChangedSince)
 }
@@ -3,11 +3,13 @@ package cmd
 import (
 	"os"
 
+	"github.com/wot-oss/tmc/internal/app/http"
 	"github.com/wot-oss/tmc/internal/app/http/cors"
 	"github.com/wot-oss/tmc/internal/app/http/jwt"
 
 	"github.com/spf13/viper"
 	"github.com/wot-oss/tmc/internal/config"
+	"github.com/wot-oss/tmc/internal/tracing"
 	"github.com/wot-oss/tmc/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -38,6 +40,14 @@ func init() {
 	serveCmd.Flags().String(config.KeyJWTScopesPrefix, "", "If set to a prefix, scopes in validated JWT are expected to start with this prefix (env var TMC_JWTSCOPESPREFIX)")
 	serveCmd.Flags().String(config.KeyJWKSURL, "", "URL to periodically fetch JSON Web Key Sets for token validation (env var TMC_JWKSURL)")
 	serveCmd.Flags().String(config.KeyDefaultScopes, config.DefaultScopesPath, "path to the default scopes file")
+	serveCmd.Flags().Bool(config.KeyAPIKeyValidation, false, "If set to 'true', API keys created with 'serve apikey create' are used to grant access to the API (env var TMC_APIKEYVALIDATION)")
+	serveCmd.PersistentFlags().String(config.KeyAPIKeysFile, "", "Path to the API keys file (default \"<config dir>/apikeys.json\") (env var TMC_APIKEYSFILE)")
+	serveCmd.Flags().String(config.KeyWebhookURLs, "", "Set comma-separated list of URLs to POST notifications about changes to the catalog to (env var TMC_WEBHOOKURLS)")
+	serveCmd.Flags().String(config.KeyWebhookSecret, "", "Secret to sign webhook notifications with in the X-Tmc-Signature header (env var TMC_WEBHOOKSECRET)")
+	serveCmd.Flags().String(config.KeyTracingExporter, "", "Enable OpenTelemetry tracing with this span exporter, one of [otlp, stdout, file] (env var TMC_TRACINGEXPORTER)")
+	serveCmd.Flags().String(config.KeyTracingFile, "", "File to write spans to with the 'file' tracing exporter (env var TMC_TRACINGFILE)")
+	serveCmd.Flags().Int64(config.KeyImportMaxSize, http.DefaultImportMaxSize, "Maximum size in bytes of the body of a bulk import request and of the files unpacked from it (env var TMC_IMPORTMAXSIZE)")
+	serveCmd.Flags().Int(config.KeyImportMaxFiles, http.DefaultImportMaxFiles, "Maximum number of files in a bulk import request (env var TMC_IMPORTMAXFILES)")
 
 	_ = viper.BindPFlag(config.KeyUrlContextRoot, serveCmd.Flags().Lookup(config.KeyUrlContextRoot))
 	_ = viper.BindPFlag(config.KeyCorsAllowedOrigins, serveCmd.Flags().Lookup(config.KeyCorsAllowedOrigins))
@@ -49,6 +59,14 @@ func init() {
 	_ = viper.BindPFlag(config.KeyJWTScopesPrefix, serveCmd.Flags().Lookup(config.KeyJWTScopesPrefix))
 	_ = viper.BindPFlag(config.KeyJWKSURL, serveCmd.Flags().Lookup(config.KeyJWKSURL))
 	_ = viper.BindPFlag(config.KeyDefaultScopes, serveCmd.Flags().Lookup(config.KeyDefaultScopes))
+	_ = viper.BindPFlag(config.KeyAPIKeyValidation, serveCmd.Flags().Lookup(config.KeyAPIKeyValidation))
+	_ = viper.BindPFlag(config.KeyAPIKeysFile, serveCmd.PersistentFlags().Lookup(config.KeyAPIKeysFile))
+	_ = viper.BindPFlag(config.KeyWebhookURLs, serveCmd.Flags().Lookup(config.KeyWebhookURLs))
+	_ = viper.BindPFlag(config.KeyWebhookSecret, serveCmd.Flags().Lookup(config.KeyWebhookSecret))
+	_ = viper.BindPFlag(config.KeyTracingExporter, serveCmd.Flags().Lookup(config.KeyTracingExporter))
+	_ = viper.BindPFlag(config.KeyTracingFile, serveCmd.Flags().Lookup(config.KeyTracingFile))
+	_ = viper.BindPFlag(config.KeyImportMaxSize, serveCmd.Flags().Lookup(config.KeyImportMaxSize))
+	_ = viper.BindPFlag(config.KeyImportMaxFiles, serveCmd.Flags().Lookup(config.KeyImportMaxFiles))
 }
 
 func serve(cmd *cobra.Command, args []string) {
@@ -69,8 +87,17 @@ func getServeOptions() cli.ServeOptions {
 
 	opts.UrlCtxRoot = viper.GetString(config.KeyUrlContextRoot)
 	opts.JWTValidation = viper.GetBool(config.KeyJWTValidation)
+	opts.APIKeyValidation = viper.GetBool(config.KeyAPIKeyValidation)
 	opts.JWTValidationOpts = getJWKSOptions()
 	opts.CORSOptions = getCORSOptions()
+	opts.WebhookURLs = utils.ParseAsList(viper.GetString(config.KeyWebhookURLs), cli.DefaultListSeparator, true)
+	opts.WebhookSecret = viper.GetString(config.KeyWebhookSecret)
+	opts.Tracing = tracing.Options{
+		Exporter: viper.GetString(config.KeyTracingExporter),
+		File:     viper.GetString(config.KeyTracingFile),
+	}
+	opts.ImportMaxSize = viper.GetInt64(config.KeyImportMaxSize)
+	opts.ImportMaxFiles = viper.GetInt(config.KeyImportMaxFiles)
 	return opts
 }
 
@@ -91,5 +118,6 @@ func getJWKSOptions() jwt.JWTValidationOpts {
 	opts.JWKSURLString = viper.GetString(config.KeyJWKSURL)
 	opts.ScopesPrefix = viper.GetString(config.KeyJWTScopesPrefix)
 	opts.WhitelistFile = viper.GetString(config.KeyDefaultScopes)
+	opts.APIKeysFile = getAPIKeysFile()
 	return opts
 }
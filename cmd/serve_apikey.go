@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/config"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys for the REST API server",
+	Long: `The subcommands of the apikey command allow to manage the static API keys accepted by 'serve --apiKeyValidation'.
+Only hashes of the keys are stored in the API keys file, each with the scopes granted to requests presenting the key.
+The server picks up changes to the file without a restart.`,
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create --scope <scope> [--scope <scope>...]",
+	Short: "Create an API key",
+	Long: `Create an API key with the given scopes, e.g. 'tmc.admin' or 'tmc.ns.<namespace>.read', and print it.
+The key cannot be shown again afterward.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := cmd.Flag("name").Value.String()
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		format := cmd.Flag("format").Value.String()
+		err := cli.APIKeyCreate(getAPIKeysFile(), name, scopes, format)
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Long:  `Revoke the API key with the given id`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cli.APIKeyRevoke(getAPIKeysFile(), args[0])
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Long:  `List the ids, names and scopes of the API keys`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := cmd.Flag("format").Value.String()
+		err := cli.APIKeyList(getAPIKeysFile(), format)
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.AddCommand(apiKeyCmd)
+	apiKeyCmd.AddCommand(apiKeyCreateCmd)
+	apiKeyCmd.AddCommand(apiKeyRevokeCmd)
+	apiKeyCmd.AddCommand(apiKeyListCmd)
+	apiKeyCreateCmd.Flags().String("name", "", "Name of the API key, e.g. the client it is issued to")
+	apiKeyCreateCmd.Flags().StringSlice("scope", nil, "Scope granted by the API key. Can be repeated or comma-separated")
+	_ = apiKeyCreateCmd.MarkFlagRequired("scope")
+	AddOutputFormatFlag(apiKeyCreateCmd)
+	AddOutputFormatFlag(apiKeyListCmd)
+}
+
+// getAPIKeysFile returns the configured API keys file, defaulting to a file in the config directory
+func getAPIKeysFile() string {
+	file := viper.GetString(config.KeyAPIKeysFile)
+	if file == "" {
+		return filepath.Join(config.ConfigDir, config.DefaultAPIKeysFileName)
+	}
+	file, err := utils.ExpandHome(file)
+	if err != nil {
+		cli.Stderrf("invalid API keys file: %v", err)
+		os.Exit(1)
+	}
+	return file
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+)
+
+var signCmd = &cobra.Command{
+	Use:   "sign <tmid> --key <private-key-file>",
+	Short: "Sign a TM",
+	Long: `Sign a TM version with an Ed25519 private key. The signature is a detached JWS over the same content that the
+TM's version digest is calculated over. It is stored as the reserved attachment 'tm-signature.jws' of the TM version.
+The private key file must contain a PEM-encoded PKCS #8 key, as generated by 'openssl genpkey -algorithm ed25519'.`,
+	Args:              cobra.ExactArgs(1),
+	Run:               executeSign,
+	ValidArgsFunction: completion.CompleteTMNamesOrIds,
+}
+
+func init() {
+	RootCmd.AddCommand(signCmd)
+	AddRepoDisambiguatorFlags(signCmd)
+	signCmd.Flags().StringP("key", "k", "", "File containing the PEM-encoded Ed25519 private key")
+	_ = signCmd.MarkFlagFilename("key", "pem")
+	_ = signCmd.MarkFlagRequired("key")
+	signCmd.Flags().Bool("force", false, "Replace an existing signature")
+}
+
+func executeSign(cmd *cobra.Command, args []string) {
+	spec := RepoSpecFromFlags(cmd)
+	key := cmd.Flag("key").Value.String()
+	force, _ := cmd.Flags().GetBool("force")
+
+	err := cli.Sign(context.Background(), spec, args[0], key, force)
+	if err != nil {
+		cli.Stderrf("sign failed")
+		os.Exit(1)
+	}
+}
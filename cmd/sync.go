@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <from-repo> <to-repo>",
+	Short: "Mirror the contents of one repository to another",
+	Long: `Copy all TMs and attachments which are missing in <to-repo> from <from-repo>.
+
+Unlike copy, sync compares the indexes of both repositories by TM id and digest and transfers only the difference.
+Use --delete to also remove TMs and attachments from <to-repo> which no longer exist in <from-repo>, making <to-repo> an exact mirror.
+Use --dry-run to print the planned changes without applying them.`,
+	Args:              cobra.ExactArgs(2),
+	Run:               executeSync,
+	ValidArgsFunction: completeSyncArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(syncCmd)
+	AddOutputFormatFlag(syncCmd)
+	syncCmd.Flags().Bool("delete", false, "Delete TMs and attachments from the target repository which do not exist in the source repository")
+	syncCmd.Flags().Bool("dry-run", false, "Print the planned changes without applying them")
+}
+
+func completeSyncArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) >= 2 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completion.CompleteRepoNames(cmd, args, toComplete)
+}
+
+func executeSync(cmd *cobra.Command, args []string) {
+	del, _ := cmd.Flags().GetBool("delete")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	format := cmd.Flag("format").Value.String()
+
+	opts := cli.SyncOptions{Delete: del, DryRun: dryRun}
+	err := cli.Sync(context.Background(), model.NewRepoSpec(args[0]), model.NewRepoSpec(args[1]), opts, format)
+	if err != nil {
+		cli.Stderrf("sync failed")
+		os.Exit(1)
+	}
+}
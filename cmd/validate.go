@@ -5,16 +5,27 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
 	"github.com/wot-oss/tmc/internal/app/cli"
+	"github.com/wot-oss/tmc/internal/model"
 )
 
 var validateCmd = &cobra.Command{
 	Use:   "validate <filename>",
 	Short: "Validate a TM before importing",
-	Long:  `Validate a ThingModel to ensure it is ready to be imported into TM catalog`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Validate a ThingModel to ensure it is ready to be imported into TM catalog.
+When a repository is given with --repo or --directory, the TM is additionally validated against the custom JSON schemas
+and rules stored in the repository's .tmc/validation directory.
+With --format json, all findings are printed with the JSON pointer to the offending part of the TM and the failed
+schema keyword, together with the id the TM would be imported under.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		err := cli.ValidateFile(context.Background(), args[0])
+		spec := model.EmptySpec
+		if cmd.Flag("repo").Value.String() != "" || cmd.Flag("directory").Value.String() != "" {
+			spec = RepoSpecFromFlags(cmd)
+		}
+		format := cmd.Flag("format").Value.String()
+		err := cli.ValidateFile(context.Background(), spec, args[0], format)
 		if err != nil {
 			cli.Stderrf("validate failed")
 			os.Exit(1)
@@ -24,4 +35,9 @@ var validateCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(validateCmd)
+	AddOutputFormatFlag(validateCmd)
+	validateCmd.Flags().StringP("repo", "r", "", "Name of the repository whose validation rules to apply. Mutually exclusive with --directory.")
+	_ = validateCmd.RegisterFlagCompletionFunc("repo", completion.CompleteRepoNames)
+	validateCmd.Flags().StringP("directory", "d", "", "Use the validation rules of the repository in the specified directory. Mutually exclusive with --repo.")
+	_ = validateCmd.MarkFlagDirname("directory")
 }
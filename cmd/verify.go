@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/app/cli"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <tmid> [--key <public-key-file>]...",
+	Short: "Verify the signature of a TM",
+	Long: `Verify the signature of a TM version created with 'sign'. The signature is verified with the public keys given
+with --key or, if none are given, with the trusted keys configured for the repository.
+The public key files must contain a PEM-encoded PKIX key, as generated by 'openssl pkey -pubout'.`,
+	Args:              cobra.ExactArgs(1),
+	Run:               executeVerify,
+	ValidArgsFunction: completion.CompleteTMNamesOrIds,
+}
+
+func init() {
+	RootCmd.AddCommand(verifyCmd)
+	AddRepoDisambiguatorFlags(verifyCmd)
+	verifyCmd.Flags().StringSliceP("key", "k", nil, "File containing a PEM-encoded Ed25519 public key. May be repeated")
+	_ = verifyCmd.MarkFlagFilename("key", "pem")
+}
+
+func executeVerify(cmd *cobra.Command, args []string) {
+	spec := RepoSpecFromFlags(cmd)
+	keys, _ := cmd.Flags().GetStringSlice("key")
+
+	err := cli.Verify(context.Background(), spec, args[0], keys)
+	if err != nil {
+		cli.Stderrf("verify failed")
+		os.Exit(1)
+	}
+}
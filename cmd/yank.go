@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wot-oss/tmc/cmd/completion"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+var yankCmd = &cobra.Command{
+	Use:   "yank <tmid>",
+	Short: "Mark a TM version as yanked",
+	Long: `Mark a TM version as yanked. A yanked version is never chosen when fetching the latest version of a TM by name,
+but can still be fetched by its id, so that devices which have pinned the id keep working. Unlike deleting, yanking
+does not break existing consumers. Use --undo to return the version to the active state.`,
+	Args:              cobra.ExactArgs(1),
+	Run:               executeYank,
+	ValidArgsFunction: completion.CompleteTMNamesOrIds,
+}
+
+func init() {
+	RootCmd.AddCommand(yankCmd)
+	addLifecycleFlags(yankCmd)
+}
+
+func executeYank(cmd *cobra.Command, args []string) {
+	setLifecycle(cmd, args[0], model.LifecycleYanked)
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/app/http/auth"
+)
+
+type APIKeyCreated struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Key     string    `json:"key"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+type APIKeyAbridged struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+// APIKeyCreate creates an API key with given name and scopes in file and prints the key
+func APIKeyCreate(file, name string, scopes []string, format string) error {
+	if !IsValidOutputFormat(format) {
+		Stderrf("%v", ErrInvalidOutputFormat)
+		return ErrInvalidOutputFormat
+	}
+	key, apiKey, err := auth.CreateAPIKey(file, name, scopes)
+	if err != nil {
+		Stderrf("Could not create API key: %v", err)
+		return err
+	}
+	switch format {
+	case OutputFormatJSON:
+		printJSON(APIKeyCreated{
+			ID:      apiKey.ID,
+			Name:    apiKey.Name,
+			Key:     key,
+			Scopes:  apiKey.Scopes,
+			Created: apiKey.Created,
+		})
+	case OutputFormatPlain:
+		fmt.Println(key)
+		Stderrf("Created API key %s. Store the key now, it cannot be shown again", apiKey.ID)
+	}
+	return nil
+}
+
+// APIKeyRevoke removes the API key with given id from file
+func APIKeyRevoke(file, id string) error {
+	err := auth.RevokeAPIKey(file, id)
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
+			Stderrf("API key %s not found", id)
+		} else {
+			Stderrf("Could not revoke API key %s: %v", id, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// APIKeyList prints the API keys in file, without the hashes of the keys
+func APIKeyList(file, format string) error {
+	if !IsValidOutputFormat(format) {
+		Stderrf("%v", ErrInvalidOutputFormat)
+		return ErrInvalidOutputFormat
+	}
+	keys, err := auth.ReadAPIKeys(file)
+	if err != nil {
+		Stderrf("Cannot read API keys: %v", err)
+		return err
+	}
+	res := make([]APIKeyAbridged, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, APIKeyAbridged{ID: k.ID, Name: k.Name, Scopes: k.Scopes, Created: k.Created})
+	}
+	switch format {
+	case OutputFormatJSON:
+		printJSON(res)
+	case OutputFormatPlain:
+		colWidth := columnWidth()
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(table, "ID\tNAME\tCREATED\tSCOPES\n")
+		for _, k := range res {
+			_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", k.ID, elideString(k.Name, colWidth), k.Created.Format(time.RFC3339), strings.Join(k.Scopes, ", "))
+		}
+		_ = table.Flush()
+	}
+	return nil
+}
@@ -95,13 +95,13 @@ func TestAttachmentImport(t *testing.T) {
 	attContent, err := os.ReadFile(attFile)
 	assert.NoError(t, err)
 	t.Run("with original file name", func(t *testing.T) {
-		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmNameOrId), model.Attachment{Name: attName, MediaType: ""}, attContent, true).Return(nil).Once()
+		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmNameOrId), model.Attachment{Name: attName, MediaType: ""}, attContent, true, "").Return(nil).Once()
 		err = AttachmentImport(ctx, model.NewDirSpec("somewhere"), tmNameOrId, attFile, "", "", true)
 		assert.NoError(t, err)
 	})
 
 	t.Run("with overwritten file name", func(t *testing.T) {
-		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmNameOrId), model.Attachment{Name: "differentName.md", MediaType: ""}, attContent, true).Return(nil).Once()
+		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmNameOrId), model.Attachment{Name: "differentName.md", MediaType: ""}, attContent, true, "").Return(nil).Once()
 		err = AttachmentImport(ctx, model.NewDirSpec("somewhere"), tmNameOrId, attFile, "differentName.md", "", true)
 		assert.NoError(t, err)
 	})
@@ -132,7 +132,7 @@ func TestAttachmentDelete(t *testing.T) {
 	ctx := context.Background()
 	tmNameOrId := "author/manufacturer/mpn"
 	attName := "README.md"
-	r.On("DeleteAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmNameOrId), attName).Return(nil).Once()
+	r.On("DeleteAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmNameOrId), attName, "").Return(nil).Once()
 	err := AttachmentDelete(ctx, model.NewDirSpec("somewhere"), tmNameOrId, attName)
 	assert.NoError(t, err)
 }
@@ -30,8 +30,14 @@ func CheckIntegrity(ctx context.Context, spec model.RepoSpec, args []string, for
 		return err
 	}
 
+	digestMode, err := repos.GetDigestMode(spec)
+	if err != nil {
+		Stderrf("could not read the digest mode of %v: %v. check config", spec, err)
+		return err
+	}
+
 	resFilter := resourceFilterFromArgs(args)
-	totalRes, err := checkIndexedResourcesAreValid(ctx, repo, resFilter)
+	totalRes, err := checkIndexedResourcesAreValid(ctx, repo, resFilter, digestMode)
 	if errors.Is(err, errNotARepo) {
 		Stderrf("(%s) is not a TMC repository\n", spec)
 		return nil
@@ -80,7 +86,9 @@ func resourceFilterFromArgs(args []string) model.ResourceFilter {
 
 }
 
-func checkIndexedResourcesAreValid(ctx context.Context, repo repos.Repo, filter model.ResourceFilter) ([]model.CheckResult, error) {
+// checkIndexedResourcesAreValid checks the TMs and attachments in the repo's index. Additionally, it reports a warning
+// for each TM version whose content canonicalizes to the content of another version of the same TM
+func checkIndexedResourcesAreValid(ctx context.Context, repo repos.Repo, filter model.ResourceFilter, digestMode string) ([]model.CheckResult, error) {
 	var results []model.CheckResult
 	list, err := repo.List(ctx, nil)
 	if err != nil {
@@ -92,6 +100,7 @@ func checkIndexedResourcesAreValid(ctx context.Context, repo repos.Repo, filter
 	for _, entry := range list.Entries {
 		rs := checkAttachments(ctx, repo, model.NewTMNameAttachmentContainerRef(entry.Name), entry.Attachments, filter)
 		results = append(results, rs...)
+		canonicalVersions := map[string]string{}
 		for _, version := range entry.Versions {
 			select {
 			case <-ctx.Done():
@@ -100,8 +109,16 @@ func checkIndexedResourcesAreValid(ctx context.Context, repo repos.Repo, filter
 			}
 
 			if filter(version.TMID) {
-				tr := checkThingModel(ctx, repo, version.TMID)
+				tr, canonicalHash := checkThingModel(ctx, repo, version.TMID, digestMode)
 				results = append(results, tr)
+				if canonicalHash != "" {
+					if other, ok := canonicalVersions[canonicalHash]; ok {
+						results = append(results, model.CheckResult{Typ: model.CheckWarn, ResourceName: version.TMID,
+							Message: fmt.Sprintf("content is equal to %s after canonicalization", other)})
+					} else {
+						canonicalVersions[canonicalHash] = version.TMID
+					}
+				}
 			}
 			rs = checkAttachments(ctx, repo, model.NewTMIDAttachmentContainerRef(version.TMID), version.Attachments, filter)
 			results = append(results, rs...)
@@ -132,31 +149,41 @@ func checkAttachments(ctx context.Context, repo repos.Repo, ref model.Attachment
 	return results
 }
 
-func checkThingModel(ctx context.Context, repo repos.Repo, tmid string) model.CheckResult {
+// checkThingModel checks the TM file with given id. Returns the check result and the digest of the canonical form of
+// the file's content, if the file could be read.
+// In a repo with digest mode repos.DigestModeJCS, the digest in the id may be either the canonical digest or, for TMs
+// imported before the mode has been configured, the raw digest
+func checkThingModel(ctx context.Context, repo repos.Repo, tmid string, digestMode string) (model.CheckResult, string) {
 	id, raw, err := repo.Fetch(ctx, tmid)
 	if err != nil {
-		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: fmt.Sprintf("could not fetch the TM file to verify integrity: %s", err.Error())}
+		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: fmt.Sprintf("could not fetch the TM file to verify integrity: %s", err.Error())}, ""
 	}
-	tm, err := validate.ValidateThingModel(raw)
+	// custom validation rules of the repo are not checked, because they may have been added after the TM was imported
+	tm, err := validate.ValidateThingModel(raw, nil)
 	if err != nil {
-		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: fmt.Sprintf("invalid TM content: %s", err.Error())}
+		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: fmt.Sprintf("invalid TM content: %s", err.Error())}, ""
 	}
+	canonicalHash, _, _ := commands.CalculateDigest(raw, repos.DigestModeJCS) // ignore the error, because the file has been validated already
 	if tm.ID == "" {
-		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: "TM id is missing in the file"}
+		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: "TM id is missing in the file"}, canonicalHash
 	}
 	idInFile, err := model.ParseTMID(tm.ID)
 	if err != nil {
-		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: "TM id in the file is invalid"}
+		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: "TM id in the file is invalid"}, canonicalHash
 	}
 	if tm.ID != tmid || id != tmid {
 		err = errors.New("TM id does not match the file location")
-		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: err.Error()}
+		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: err.Error()}, canonicalHash
 	}
 	hashStr, _, _ := commands.CalculateFileDigest(raw) // ignore the error, because the file has been validated already
 
-	if idInFile.Version.Hash != hashStr {
-		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: "file content does not match the digest in ID"}
+	if idInFile.Version.Hash != hashStr && (digestMode != repos.DigestModeJCS || idInFile.Version.Hash != canonicalHash) {
+		msg := "file content does not match the digest in ID"
+		if digestMode != repos.DigestModeJCS && idInFile.Version.Hash == canonicalHash {
+			msg = msg + fmt.Sprintf(". The digest matches the canonical form of the content. Set '%s' to '%s' in repo config", repos.KeyRepoDigest, repos.DigestModeJCS)
+		}
+		return model.CheckResult{Typ: model.CheckErr, ResourceName: tmid, Message: msg}, canonicalHash
 	}
 
-	return model.CheckResult{Typ: model.CheckOK, ResourceName: tmid, Message: fmt.Sprintf("")}
+	return model.CheckResult{Typ: model.CheckOK, ResourceName: tmid, Message: fmt.Sprintf("")}, canonicalHash
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/commands"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
 	"github.com/wot-oss/tmc/internal/repos/mocks"
@@ -274,6 +275,90 @@ func TestCheckIntegrity_IndexedResources(t *testing.T) {
 	})
 }
 
+func TestCheckIntegrity_CanonicalDigest(t *testing.T) {
+	const name = "mycompany/bartech/bazlamp"
+	const id1 = "mycompany/bartech/bazlamp/v0.0.1-20240101120000-78ff2e36fe32.tm.json"
+	// reformatted tm1 with reordered keys
+	reformat := func(id string) []byte {
+		var m map[string]any
+		_ = json.Unmarshal([]byte(tm1), &m)
+		m["id"] = id
+		b, _ := json.MarshalIndent(m, "", "\t")
+		return b
+	}
+	rawHash, _, _ := commands.CalculateDigest(reformat(""), repos.DigestModeRaw)
+	jcsHash, _, _ := commands.CalculateDigest(reformat(""), repos.DigestModeJCS)
+	id2 := "mycompany/bartech/bazlamp/v0.0.1-20240202120000-" + rawHash + ".tm.json"
+	id3 := "mycompany/bartech/bazlamp/v0.0.1-20240303120000-" + jcsHash + ".tm.json"
+	entry := func(ids ...string) model.SearchResult {
+		var vs []model.FoundVersion
+		for _, id := range ids {
+			vs = append(vs, model.FoundVersion{IndexVersion: &model.IndexVersion{TMID: id}})
+		}
+		return model.SearchResult{Entries: []model.FoundEntry{{Name: name, Versions: vs}}}
+	}
+
+	t.Run("with duplicate canonical content", func(t *testing.T) {
+		restore, getStdout := testutils.ReplaceStdout()
+		defer restore()
+		r := mocks.NewRepo(t)
+		rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("r1"), r, nil))
+		rMocks.MockReposGetDigestMode(t, repos.DigestModeRaw)
+		r.On("List", mock.Anything, mock.Anything).Return(entry(id1, id2), nil)
+		r.On("Fetch", mock.Anything, id1).Return(id1, []byte(tm1), nil)
+		r.On("Fetch", mock.Anything, id2).Return(id2, reformat(id2), nil)
+		r.On("CheckIntegrity", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		err := CheckIntegrity(context.Background(), model.NewRepoSpec("r1"), nil, OutputFormatPlain)
+
+		// then: duplicates are reported as warnings only
+		assert.NoError(t, err)
+		stdout := getStdout()
+		assert.Contains(t, stdout, "warning")
+		assert.Contains(t, stdout, id2+": content is equal to "+id1+" after canonicalization")
+	})
+	t.Run("with canonical digest in jcs mode", func(t *testing.T) {
+		r := mocks.NewRepo(t)
+		rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("r1"), r, nil))
+		rMocks.MockReposGetDigestMode(t, repos.DigestModeJCS)
+		r.On("List", mock.Anything, mock.Anything).Return(entry(id3), nil)
+		r.On("Fetch", mock.Anything, id3).Return(id3, reformat(id3), nil)
+		r.On("CheckIntegrity", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		err := CheckIntegrity(context.Background(), model.NewRepoSpec("r1"), nil, OutputFormatPlain)
+
+		assert.NoError(t, err)
+	})
+	t.Run("with raw digest in jcs mode", func(t *testing.T) {
+		r := mocks.NewRepo(t)
+		rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("r1"), r, nil))
+		rMocks.MockReposGetDigestMode(t, repos.DigestModeJCS)
+		r.On("List", mock.Anything, mock.Anything).Return(entry(id1), nil)
+		r.On("Fetch", mock.Anything, id1).Return(id1, []byte(tm1), nil)
+		r.On("CheckIntegrity", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		err := CheckIntegrity(context.Background(), model.NewRepoSpec("r1"), nil, OutputFormatPlain)
+
+		// then: TMs imported before switching to jcs are still valid
+		assert.NoError(t, err)
+	})
+	t.Run("with canonical digest in raw mode", func(t *testing.T) {
+		restore, getStdout := testutils.ReplaceStdout()
+		defer restore()
+		r := mocks.NewRepo(t)
+		rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("r1"), r, nil))
+		rMocks.MockReposGetDigestMode(t, repos.DigestModeRaw)
+		r.On("List", mock.Anything, mock.Anything).Return(entry(id3), nil)
+		r.On("Fetch", mock.Anything, id3).Return(id3, reformat(id3), nil)
+		r.On("CheckIntegrity", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		err := CheckIntegrity(context.Background(), model.NewRepoSpec("r1"), nil, OutputFormatPlain)
+
+		assert.Error(t, err)
+		assert.Contains(t, getStdout(), "file content does not match the digest in ID. The digest matches the canonical form")
+	})
+}
+
 // correct TM
 var tm1 = `{
   "@context": [ "https://www.w3.org/2022/wot/td/v1.1", { "schema":"https://schema.org/" }],
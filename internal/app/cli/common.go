@@ -18,6 +18,8 @@ const DefaultListSeparator = ","
 const (
 	OutputFormatJSON  = "json"
 	OutputFormatPlain = "plain"
+	// OutputFormatJSONPatch outputs differences as a JSON Patch (RFC 6902). Supported only by diff
+	OutputFormatJSONPatch = "jsonpatch"
 )
 
 var ErrInvalidOutputFormat = errors.New("invalid output format")
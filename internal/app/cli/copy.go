@@ -53,6 +53,7 @@ func Copy(ctx context.Context, repo model.RepoSpec, toRepo model.RepoSpec, searc
 
 	var totalRes []OperationResult
 	var copiedIDs []string
+	ic := commands.NewImportCommand(time.Now)
 	for _, entry := range searchResult.Entries {
 		for _, version := range entry.Versions {
 			select {
@@ -60,7 +61,7 @@ func Copy(ctx context.Context, repo model.RepoSpec, toRepo model.RepoSpec, searc
 				return ctx.Err()
 			default:
 			}
-			res, cErr := copyThingModel(ctx, version, target, opts)
+			res, cErr := copyThingModel(ctx, ic, version, target, opts)
 			tmExisted := false
 			var errExists *repos.ErrTMIDConflict
 			if errors.As(cErr, &errExists) { // TM exists in target -> add error result and store the total error (unless ought to ignore), but don't skip copying attachments
@@ -164,7 +165,7 @@ func copyAttachments(ctx context.Context, spec model.RepoSpec, toRepo repos.Repo
 			}
 			continue
 		}
-		wErr := toRepo.ImportAttachment(ctx, ref, att, bytes, force)
+		wErr := toRepo.ImportAttachment(ctx, ref, att, bytes, force, "")
 		if wErr != nil {
 			wErr = fmt.Errorf("could not import attachment %s to %v: %w", att.Name, ref, wErr)
 			results = append(results, OperationResult{
@@ -186,7 +187,7 @@ func copyAttachments(ctx context.Context, spec model.RepoSpec, toRepo repos.Repo
 	return results, err
 }
 
-func copyThingModel(ctx context.Context, version model.FoundVersion, target repos.Repo, opts repos.ImportOptions) (repos.ImportResult, error) {
+func copyThingModel(ctx context.Context, ic *commands.ImportCommand, version model.FoundVersion, target repos.Repo, opts repos.ImportOptions) (repos.ImportResult, error) {
 	spec := model.NewSpecFromFoundSource(version.FoundIn)
 	_, thing, err, errs := commands.FetchByTMID(ctx, spec, version.TMID, false)
 	if err == nil && len(errs) > 0 { // spec cannot be empty, therefore, there can be at most one RepoAccessError
@@ -198,6 +199,8 @@ func copyThingModel(ctx context.Context, version model.FoundVersion, target repo
 		return repos.ImportResultFromError(e)
 	}
 
-	res, err := commands.NewImportCommand(time.Now).ImportFile(ctx, thing, target, opts)
+	// copied TMs are mirrored as they are, so there is no point in judging them again
+	opts.BreakingChanges = repos.BreakingChangesIgnore
+	res, err := ic.ImportFile(ctx, thing, target, opts)
 	return res, err
 }
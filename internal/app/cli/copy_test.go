@@ -114,6 +114,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmID_1 := copyListRes.Entries[0].Versions[0].TMID
@@ -131,14 +133,14 @@ func TestCopy(t *testing.T) {
 		source.On("Fetch", mock.Anything, tmID_3).Return(tmID_3, tmContent3, nil).Once()
 		source.On("FetchAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), "README.md").Return(readmeContent, nil).Once()
 		source.On("FetchAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), "CHANGELOG.md").Return(changelogContent, nil).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_1), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{Force: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_1), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{Force: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmID_1, Message: "", Err: nil}, nil).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_2), utils.NormalizeLineEndings(tmContent2), repos.ImportOptions{Force: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_2), utils.NormalizeLineEndings(tmContent2), repos.ImportOptions{Force: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmID_2, Message: "", Err: nil}, nil).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_3), utils.NormalizeLineEndings(tmContent3), repos.ImportOptions{Force: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_3), utils.NormalizeLineEndings(tmContent3), repos.ImportOptions{Force: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmID_3, Message: "", Err: nil}, nil).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), model.Attachment{Name: "README.md"}, readmeContent, true).Return(nil).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), model.Attachment{Name: "CHANGELOG.md"}, changelogContent, true).Return(nil).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), model.Attachment{Name: "README.md"}, readmeContent, true, "").Return(nil).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), model.Attachment{Name: "CHANGELOG.md"}, changelogContent, true, "").Return(nil).Once()
 		target.On("Index", mock.Anything, tmID_1, tmID_2, tmID_3).Return(nil)
 		target.On("Index", mock.Anything, tmID_1).Return(nil)
 		target.On("Index", mock.Anything, tmID_2).Return(nil)
@@ -158,6 +160,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmID_1 := copyListRes.Entries[0].Versions[0].TMID
@@ -176,14 +180,14 @@ func TestCopy(t *testing.T) {
 		source.On("FetchAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), "README.md").Return(readmeContent, nil).Once()
 		source.On("FetchAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), "CHANGELOG.md").Return(changelogContent, nil).Once()
 		expRes, impErr := repos.ImportResultFromError(&repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID_1})
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_1), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{Force: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_1), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{Force: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(expRes, impErr).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_2), utils.NormalizeLineEndings(tmContent2), repos.ImportOptions{Force: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_2), utils.NormalizeLineEndings(tmContent2), repos.ImportOptions{Force: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResultFromError(&repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID_2})).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_3), utils.NormalizeLineEndings(tmContent3), repos.ImportOptions{Force: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_3), utils.NormalizeLineEndings(tmContent3), repos.ImportOptions{Force: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResultFromError(&repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID_3})).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), model.Attachment{Name: "README.md"}, readmeContent, true).Return(nil).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), model.Attachment{Name: "CHANGELOG.md"}, changelogContent, true).Return(nil).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), model.Attachment{Name: "README.md"}, readmeContent, true, "").Return(nil).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), model.Attachment{Name: "CHANGELOG.md"}, changelogContent, true, "").Return(nil).Once()
 
 		// when: copying from repo
 		err := Copy(context.Background(), sourceSpec, targetSpec, nil, repos.ImportOptions{Force: true}, OutputFormatPlain)
@@ -199,6 +203,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmID_1 := copyListRes.Entries[0].Versions[0].TMID
@@ -217,14 +223,14 @@ func TestCopy(t *testing.T) {
 		source.On("FetchAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), "README.md").Return(readmeContent, nil).Once()
 		source.On("FetchAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), "CHANGELOG.md").Return(changelogContent, nil).Once()
 		expRes, impErr := repos.ImportResultFromError(&repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID_1})
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_1), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{IgnoreExisting: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_1), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{IgnoreExisting: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(expRes, impErr).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_2), utils.NormalizeLineEndings(tmContent2), repos.ImportOptions{IgnoreExisting: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_2), utils.NormalizeLineEndings(tmContent2), repos.ImportOptions{IgnoreExisting: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResultFromError(&repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID_2})).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmID_3), utils.NormalizeLineEndings(tmContent3), repos.ImportOptions{IgnoreExisting: true}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmID_3), utils.NormalizeLineEndings(tmContent3), repos.ImportOptions{IgnoreExisting: true, BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResultFromError(&repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID_3})).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), model.Attachment{Name: "README.md"}, readmeContent, false).Return(repos.ErrAttachmentExists).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), model.Attachment{Name: "CHANGELOG.md"}, changelogContent, false).Return(repos.ErrAttachmentExists).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(copyListRes.Entries[0].Name), model.Attachment{Name: "README.md"}, readmeContent, false, "").Return(repos.ErrAttachmentExists).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmID_3), model.Attachment{Name: "CHANGELOG.md"}, changelogContent, false, "").Return(repos.ErrAttachmentExists).Once()
 
 		// when: copying from repo
 		err := Copy(context.Background(), sourceSpec, targetSpec, nil, repos.ImportOptions{IgnoreExisting: true}, OutputFormatPlain)
@@ -240,6 +246,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec, model.EmptySpec}, []repos.Repo{source, target, nil}, []error{nil, nil, repos.ErrAmbiguous}))
 		err := Copy(context.Background(), model.EmptySpec, model.NewRepoSpec("r1"), nil, repos.ImportOptions{}, OutputFormatPlain)
 		assert.ErrorIs(t, err, repos.ErrAmbiguous)
@@ -256,6 +264,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmid := copySingleListRes.Entries[0].Versions[0].TMID
@@ -278,6 +288,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmid := copySingleListRes.Entries[0].Versions[0].TMID
@@ -306,6 +318,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmid := copySingleListRes.Entries[0].Versions[0].TMID
@@ -314,7 +328,7 @@ func TestCopy(t *testing.T) {
 		source.On("List", mock.Anything, sp).Return(copySingleListRes, nil).Once()
 		source.On("Fetch", mock.Anything, tmid).Return(tmid, tmContent1, nil).Once()
 		source.On("FetchAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmid), "README.md").Return(nil, model.ErrAttachmentNotFound).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmid), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmid), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmid, Message: "", Err: nil}, nil).Once()
 		target.On("Index", mock.Anything, tmid).Return(nil).Twice()
 
@@ -332,6 +346,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmid := copySingleListRes.Entries[0].Versions[0].TMID
@@ -340,7 +356,7 @@ func TestCopy(t *testing.T) {
 		source.On("List", mock.Anything, sp).Return(copySingleListRes, nil).Once()
 		source.On("Fetch", mock.Anything, tmid).Return(tmid, tmContent1, nil).Once()
 		res, resErr := repos.ImportResultFromError(repos.ErrNotSupported)
-		target.On("Import", mock.Anything, model.MustParseTMID(tmid), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmid), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(res, resErr).Once()
 
 		// when: copying from repo
@@ -357,6 +373,8 @@ func TestCopy(t *testing.T) {
 		targetSpec := model.NewRepoSpec("target")
 		source := mocks.NewRepo(t)
 		target := mocks.NewRepo(t)
+		target.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+		target.On("Spec").Return(targetSpec).Maybe()
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunctionFromList(t, []model.RepoSpec{sourceSpec, targetSpec}, []repos.Repo{source, target}, []error{nil, nil}))
 
 		tmid := copySingleListRes.Entries[0].Versions[0].TMID
@@ -366,9 +384,9 @@ func TestCopy(t *testing.T) {
 		source.On("List", mock.Anything, sp).Return(copySingleListRes, nil).Once()
 		source.On("Fetch", mock.Anything, tmid).Return(tmid, tmContent1, nil).Once()
 		source.On("FetchAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmid), "README.md").Return(readmeContent, nil).Once()
-		target.On("Import", mock.Anything, model.MustParseTMID(tmid), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{}).
+		target.On("Import", mock.Anything, model.MustParseTMID(tmid), utils.NormalizeLineEndings(tmContent1), repos.ImportOptions{BreakingChanges: repos.BreakingChangesIgnore}).
 			Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmid, Message: "", Err: nil}, nil).Once()
-		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmid), model.Attachment{Name: "README.md", MediaType: "text/markdown"}, readmeContent, false).Return(os.ErrPermission).Once()
+		target.On("ImportAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(tmid), model.Attachment{Name: "README.md", MediaType: "text/markdown"}, readmeContent, false, "").Return(os.ErrPermission).Once()
 		target.On("Index", mock.Anything, tmid).Return(nil).Twice()
 
 		// when: copying from repo
@@ -35,7 +35,7 @@ func CreateDockerImage(ctx context.Context, repo *model.RepoSpec, imageTag strin
 		}
 		repoType, _ := configuredRepo["type"].(string)
 		switch repoType {
-		case "file":
+		case "file", "git":
 			fmt.Printf("Copying local file/directory for repo: %s\n", name)
 			if err := copyLocalRepo(configuredRepo["loc"].(string), "./docker_context/data/"+name); err != nil {
 				return fmt.Errorf("failed to copy local repo: %w", err)
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+type DiffResult struct {
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Changes []commands.TMChange `json:"changes"`
+}
+
+// Diff prints the structural differences between the TMs given by id or fetch name in the given format
+func Diff(ctx context.Context, spec model.RepoSpec, from, to, format string) error {
+	if !IsValidOutputFormat(format) && format != OutputFormatJSONPatch {
+		Stderrf("%v", ErrInvalidOutputFormat)
+		return ErrInvalidOutputFormat
+	}
+
+	diff, err, errs := commands.DiffByTMIDOrName(ctx, spec, from, to)
+	if err != nil {
+		Stderrf("Could not compare %s and %s: %v", from, to, err)
+		return err
+	}
+	defer printErrs("Errors occurred while fetching:", errs)
+
+	switch format {
+	case OutputFormatJSON:
+		changes := diff.Changes
+		if changes == nil {
+			changes = []commands.TMChange{}
+		}
+		printJSON(DiffResult{From: diff.From, To: diff.To, Changes: changes})
+	case OutputFormatJSONPatch:
+		printJSON(diff.Patch)
+	case OutputFormatPlain:
+		printChanges(diff.From, diff.To, diff.Changes)
+	}
+	return nil
+}
+
+func printChanges(fromId, toId string, changes []commands.TMChange) {
+	fmt.Printf("--- %s\n+++ %s\n", fromId, toId)
+	if len(changes) == 0 {
+		fmt.Println("no differences")
+		return
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(table, "CHANGE\tKIND\tPATH\tDETAILS\n")
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", c.Type, c.Kind, c.Path, changeDetails(c))
+	}
+	_ = table.Flush()
+}
+
+// changeDetails formats the old and new values of a change, unless they are objects or arrays, which would not fit
+// into a single line
+func changeDetails(c commands.TMChange) string {
+	switch c.Type {
+	case commands.ChangeAdded:
+		return scalarString(c.New)
+	case commands.ChangeRemoved:
+		return scalarString(c.Old)
+	default:
+		o, n := scalarString(c.Old), scalarString(c.New)
+		if o == "" && n == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s -> %s", o, n)
+	}
+}
+
+func scalarString(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return ""
+	case []any:
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
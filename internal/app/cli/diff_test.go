@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	"github.com/wot-oss/tmc/internal/testutils"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestDiff(t *testing.T) {
+	fromId := "b-corp/frog/bt3000/v1.0.0-20240108140117-743d1b462uuu.tm.json"
+	toId := "b-corp/frog/bt3000/v1.1.0-20240208140117-854e2c573vvv.tm.json"
+	fromTM := []byte(`{"id": "` + fromId + `", "title": "Frog", "version": {"model": "1.0.0"}, "properties": {"status": {"type": "string"}}}`)
+	toTM := []byte(`{"id": "` + toId + `", "title": "Frog", "version": {"model": "1.1.0"}, "properties": {"status": {"type": "string"}, "color": {"type": "string"}}}`)
+
+	repoSpec := model.NewRepoSpec("r1")
+	r := mocks.NewRepo(t)
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repoSpec, r, nil))
+	r.On("Spec").Return(repoSpec).Maybe()
+	r.On("Fetch", mock.Anything, fromId).Return(fromId, fromTM, nil)
+	r.On("Fetch", mock.Anything, toId).Return(toId, toTM, nil)
+
+	t.Run("with plain format", func(t *testing.T) {
+		restoreStdout, getStdout := testutils.ReplaceStdout()
+		defer restoreStdout()
+
+		err := Diff(context.Background(), repoSpec, fromId, toId, OutputFormatPlain)
+
+		stdout := getStdout()
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "--- "+fromId)
+		assert.Contains(t, stdout, "+++ "+toId)
+		assert.Regexp(t, `added\s+property\s+/properties/color`, stdout)
+		assert.Regexp(t, `modified\s+metadata\s+/version/model\s+"1.0.0" -> "1.1.0"`, stdout)
+		assert.NotContains(t, stdout, "/id")
+	})
+
+	t.Run("with json format", func(t *testing.T) {
+		restoreStdout, getStdout := testutils.ReplaceStdout()
+		defer restoreStdout()
+
+		err := Diff(context.Background(), repoSpec, fromId, toId, OutputFormatJSON)
+
+		stdout := getStdout()
+		assert.NoError(t, err)
+		var res DiffResult
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &res))
+		assert.Equal(t, fromId, res.From)
+		assert.Equal(t, toId, res.To)
+		assert.Len(t, res.Changes, 2)
+		assert.Equal(t, commands.ChangeAdded, res.Changes[0].Type)
+	})
+
+	t.Run("with jsonpatch format", func(t *testing.T) {
+		restoreStdout, getStdout := testutils.ReplaceStdout()
+		defer restoreStdout()
+
+		err := Diff(context.Background(), repoSpec, fromId, toId, OutputFormatJSONPatch)
+
+		stdout := getStdout()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[
+  {"op": "add", "path": "/properties/color", "value": {"type": "string"}},
+  {"op": "replace", "path": "/version/model", "value": "1.1.0"}
+]`, stdout)
+	})
+
+	t.Run("with invalid format", func(t *testing.T) {
+		restoreStderr, _ := testutils.ReplaceStderr()
+		defer restoreStderr()
+
+		err := Diff(context.Background(), repoSpec, fromId, toId, "xml")
+
+		assert.ErrorIs(t, err, ErrInvalidOutputFormat)
+	})
+
+	t.Run("with invalid id", func(t *testing.T) {
+		restoreStderr, getStderr := testutils.ReplaceStderr()
+		defer restoreStderr()
+
+		err := Diff(context.Background(), repoSpec, fromId, "invalid:id:x", OutputFormatPlain)
+
+		assert.Error(t, err)
+		assert.Contains(t, getStderr(), "Could not compare")
+	})
+}
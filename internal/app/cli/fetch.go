@@ -12,7 +12,7 @@ import (
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
-func Fetch(ctx context.Context, repo model.RepoSpec, idOrName, outputPath string, restoreId bool) error {
+func Fetch(ctx context.Context, repo model.RepoSpec, idOrName, outputPath string, restoreId, resolve bool) error {
 
 	id, thing, err, errs := commands.FetchByTMIDOrName(ctx, repo, idOrName, restoreId)
 	if err != nil {
@@ -21,6 +21,14 @@ func Fetch(ctx context.Context, repo model.RepoSpec, idOrName, outputPath string
 	}
 	defer printErrs("Errors occurred while fetching:", errs)
 
+	if resolve {
+		thing, err = commands.ResolveTM(ctx, repo, id, thing, nil)
+		if err != nil {
+			Stderrf("Could not resolve references: %v", err)
+			return err
+		}
+	}
+
 	thing = utils.ConvertToNativeLineEndings(thing)
 
 	if outputPath == "" {
@@ -25,7 +25,7 @@ func TestFetchExecutor_Fetch_To_Stdout(t *testing.T) {
 	r.On("Fetch", mock.Anything, "author/manufacturer/mpn/folder/sub/v1.0.0-20231205123243-c49617d2e4fc.tm.json").
 		Return("author/manufacturer/mpn/folder/sub/v1.0.0-20231205123243-c49617d2e4fc.tm.json", []byte("{}"), nil)
 
-	err := Fetch(context.Background(), model.NewRepoSpec("repo"), "author/manufacturer/mpn/folder/sub/v1.0.0-20231205123243-c49617d2e4fc.tm.json", "", false)
+	err := Fetch(context.Background(), model.NewRepoSpec("repo"), "author/manufacturer/mpn/folder/sub/v1.0.0-20231205123243-c49617d2e4fc.tm.json", "", false, false)
 	assert.NoError(t, err)
 	stdout := getOutput()
 	assert.Equal(t, "{}\n", stdout)
@@ -45,7 +45,7 @@ func TestFetchExecutor_Fetch_To_OutputFolder(t *testing.T) {
 	r.On("Fetch", mock.Anything, tmid).Return(aid, tm, nil)
 
 	// when: fetching to output folder
-	err = Fetch(context.Background(), model.NewRepoSpec("repo"), tmid, temp, false)
+	err = Fetch(context.Background(), model.NewRepoSpec("repo"), tmid, temp, false, false)
 	// then: the file exists below the output folder with tree structure given by the ID
 	assert.NoError(t, err)
 	assert.FileExists(t, filepath.Join(temp, aid))
@@ -54,7 +54,7 @@ func TestFetchExecutor_Fetch_To_OutputFolder(t *testing.T) {
 
 	// when: fetching again the ID to same output folder
 	time.Sleep(time.Millisecond * 200)
-	err = Fetch(context.Background(), model.NewRepoSpec("repo"), tmid, temp, false)
+	err = Fetch(context.Background(), model.NewRepoSpec("repo"), tmid, temp, false, false)
 	// then: the file has been overwritten and has a newer mod time
 	assert.NoError(t, err)
 	assert.FileExists(t, filepath.Join(temp, aid))
@@ -66,7 +66,7 @@ func TestFetchExecutor_Fetch_To_OutputFolder(t *testing.T) {
 	fileNoDir := filepath.Join(temp, "file.txt")
 	_ = os.WriteFile(fileNoDir, []byte("text"), 0660)
 	// when: fetching to output folder
-	err = Fetch(context.Background(), model.NewRepoSpec("repo"), tmid, fileNoDir, false)
+	err = Fetch(context.Background(), model.NewRepoSpec("repo"), tmid, fileNoDir, false, false)
 	// then: an error is returned
 	assert.Error(t, err)
 }
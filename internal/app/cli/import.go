@@ -4,11 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/fs"
-	"mime"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/wot-oss/tmc/internal/commands"
 	"github.com/wot-oss/tmc/internal/model"
@@ -52,13 +49,6 @@ func (p *ImportExecutor) Import(ctx context.Context, filename string, spec model
 	}
 
 	var res []repos.ImportResult
-	if stat.IsDir() {
-		res, err = p.importDirectory(ctx, abs, repo, optTree, opts)
-	} else {
-		singleRes, impErr := p.importFile(ctx, filename, repo, opts)
-		res = []repos.ImportResult{singleRes}
-		err = impErr
-	}
 	defer func() {
 		switch format {
 		case OutputFormatJSON:
@@ -69,9 +59,22 @@ func (p *ImportExecutor) Import(ctx context.Context, filename string, spec model
 			}
 		}
 	}()
-	successfulIds := getSuccessfulIds(res)
-	if len(successfulIds) > 0 {
-		indexErr := repo.Index(ctx, successfulIds...)
+	if stat.IsDir() {
+		// the directory import updates the index by itself
+		res, err = commands.NewImportCommand(p.now).ImportFS(ctx, os.DirFS(abs), repo, optTree, opts)
+		for _, r := range res {
+			var errExists *repos.ErrTMIDConflict
+			if r.Type == repos.ImportResultError && !errors.As(r.Err, &errExists) {
+				Stderrf("%v", r.Err)
+			}
+		}
+		return res, err
+	}
+
+	singleRes, err := p.importFile(ctx, filename, repo, opts)
+	res = []repos.ImportResult{singleRes}
+	if singleRes.IsSuccessful() {
+		indexErr := repo.Index(ctx, singleRes.TmID)
 		if indexErr != nil {
 			Stderrf("Cannot create index: %v", indexErr)
 			return res, indexErr
@@ -80,85 +83,6 @@ func (p *ImportExecutor) Import(ctx context.Context, filename string, spec model
 	return res, err
 }
 
-func getSuccessfulIds(res []repos.ImportResult) []string {
-	var r []string
-	for _, pr := range res {
-		if pr.IsSuccessful() {
-			r = append(r, pr.TmID)
-		}
-	}
-	return r
-}
-
-func (p *ImportExecutor) importDirectory(ctx context.Context, absDirname string, repo repos.Repo, optTree bool, opts repos.ImportOptions) ([]repos.ImportResult, error) {
-	var results []repos.ImportResult
-	var tErr error
-	uuidToTmNameMap := make(map[string]string)
-	err := filepath.WalkDir(absDirname, func(path string, d fs.DirEntry, err error) error {
-		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
-			return nil
-		}
-		if err != nil {
-			return err
-		}
-
-		fileOpts := opts
-		if optTree {
-			fileOpts.OptPath = filepath.ToSlash(filepath.Dir(strings.TrimPrefix(path, absDirname)))
-		}
-
-		res, err := p.importFile(ctx, path, repo, fileOpts)
-		if opts.WithAttachments {
-			var tmId model.TMID
-			var tmConflictErr *repos.ErrTMIDConflict
-			if errors.As(err, &tmConflictErr) {
-				tmId, err = model.ParseTMID(tmConflictErr.ExistingId)
-				uuidToTmNameMap[filepath.Base(path)] = tmId.Name
-			} else {
-				tmId, err = model.ParseTMID(res.TmID)
-				uuidToTmNameMap[filepath.Base(path)] = tmId.Name
-			}
-		}
-		results = append(results, res)
-		if tErr == nil {
-			tErr = err
-		}
-		return nil
-	})
-	if err != nil {
-		return results, err
-	}
-	if opts.WithAttachments {
-		repo.Index(ctx)
-		err = filepath.WalkDir(absDirname, func(path string, d fs.DirEntry, err error) error {
-			if d.IsDir() || !strings.Contains(d.Name(), ".json") {
-				if err != nil {
-					fmt.Printf("Error encountered during directory traversal %s: %v\n", d.Name(), err)
-					return err
-				}
-				if d.IsDir() {
-					return nil
-				}
-				ext := filepath.Ext(d.Name())
-				mimeType := mime.TypeByExtension(ext)
-				if mimeType == "" {
-					//fallback to "application/octet-stream", if the filetype is unknown
-					fmt.Printf("%s: unknown MIME type for extension '%s'\n", d.Name(), ext)
-					mimeType = "application/octet-stream"
-				}
-				err = AttachmentImport(ctx, repo.Spec(), uuidToTmNameMap[filepath.Base(filepath.Dir(path))], path, d.Name(), mimeType, true)
-				if err != nil {
-					fmt.Printf("File ignored while attachment import -- no mapping to TM possible %s: %v\n", d.Name(), err)
-					return nil
-				}
-			}
-			return nil
-		})
-	}
-	return results, tErr
-
-}
-
 func (p *ImportExecutor) importFile(ctx context.Context, filename string, repo repos.Repo, opts repos.ImportOptions) (repos.ImportResult, error) {
 	_, raw, err := utils.ReadRequiredFile(filename)
 	if err != nil {
@@ -166,35 +90,10 @@ func (p *ImportExecutor) importFile(ctx context.Context, filename string, repo r
 		Stderrf("%v", err.Error())
 		return repos.ImportResultFromError(err)
 	}
-	res, err := commands.NewImportCommand(p.now).ImportFile(ctx, raw, repo, opts)
-	if err != nil {
-		var errExists *repos.ErrTMIDConflict
-		if errors.As(err, &errExists) {
-			res.Message = fmt.Sprintf("file %s already exists as %s", filename, errExists.ExistingId)
-			if opts.IgnoreExisting {
-				return res, nil
-			}
-			return res, err
-		}
-		err := fmt.Errorf("error importing file %s: %w", filename, err)
-		Stderrf("%v", err.Error())
-		return repos.ImportResultFromError(err)
-	}
-	switch res.Type {
-	case repos.ImportResultWarning:
-		warn := res.Message
-		var cErr *repos.ErrTMIDConflict
-		if errors.As(res.Err, &cErr) {
-			warn = fmt.Sprintf("TM's version and timestamp clash with existing one %s", cErr.ExistingId)
-		}
-		msg := fmt.Sprintf("file %s imported as %s with warning: %s", filename, res.TmID, warn)
-		res.Message = msg
-	case repos.ImportResultOK:
-		res.Message = fmt.Sprintf("file %s imported as %s", filename, res.TmID)
-	default:
-		err := fmt.Errorf("unexpected ImportResult type %v when importing file %s", res.Type, filename)
-		Stderrf("%v", err.Error())
-		return repos.ImportResultFromError(err)
+	res, err := commands.NewImportCommand(p.now).ImportNamedFile(ctx, filename, raw, repo, opts)
+	var errExists *repos.ErrTMIDConflict
+	if res.Type == repos.ImportResultError && !errors.As(res.Err, &errExists) {
+		Stderrf("%v", res.Err)
 	}
 	return res, err
 }
@@ -19,6 +19,10 @@ import (
 func TestImportExecutor_Import(t *testing.T) {
 	r := mocks.NewRepo(t)
 	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("repo"), r, nil))
+	// no previous versions to check for breaking changes
+	r.On("Versions", mock.Anything, mock.Anything).Return(nil, model.ErrTMNameNotFound).Maybe()
+	r.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+	r.On("Spec").Return(model.NewRepoSpec("repo")).Maybe()
 
 	t.Run("import when none exists", func(t *testing.T) {
 
@@ -125,6 +129,10 @@ func TestImportExecutor_Import(t *testing.T) {
 func TestImportExecutor_Import_Directory(t *testing.T) {
 	r := mocks.NewRepo(t)
 	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("repo"), r, nil))
+	// no previous versions to check for breaking changes
+	r.On("Versions", mock.Anything, mock.Anything).Return(nil, model.ErrTMNameNotFound).Maybe()
+	r.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+	r.On("Spec").Return(model.NewRepoSpec("repo")).Maybe()
 
 	t.Run("import directory", func(t *testing.T) {
 		clk := testutils.NewTestClock(time.Date(2023, time.November, 10, 12, 32, 43, 0, time.UTC), time.Second)
@@ -276,11 +284,10 @@ func TestImportExecutor_Import_Directory(t *testing.T) {
 		r.On("Import", mock.Anything, tmid1, mock.Anything, opts).Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmid1.String()}, nil)
 		tmid2 := model.MustParseTMID("omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20231110123244-575dfac219e2.tm.json")
 		r.On("Import", mock.Anything, tmid2, mock.Anything, opts).Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmid2.String()}, nil)
-		r.On("Index", mock.Anything).Return(nil)
 		r.On("Index", mock.Anything, tmid1.String(), tmid2.String()).Return(nil)
-		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmid1.Name), model.Attachment{Name: "test.svg", MediaType: "image/svg+xml"}, mock.Anything, mock.Anything).Return(nil)
-		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmid2.Name), model.Attachment{Name: "test.svg", MediaType: "image/svg+xml"}, mock.Anything, mock.Anything).Return(nil)
-		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmid2.Name), model.Attachment{Name: "test.txt", MediaType: "text/plain; charset=utf-8"}, mock.Anything, mock.Anything).Return(nil)
+		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmid1.Name), model.Attachment{Name: "test.svg", MediaType: "image/svg+xml"}, mock.Anything, mock.Anything, "").Return(nil)
+		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmid2.Name), model.Attachment{Name: "test.svg", MediaType: "image/svg+xml"}, mock.Anything, mock.Anything, "").Return(nil)
+		r.On("ImportAttachment", ctx, model.NewTMNameAttachmentContainerRef(tmid2.Name), model.Attachment{Name: "test.txt", MediaType: "text/plain; charset=utf-8"}, mock.Anything, mock.Anything, "").Return(nil)
 		res, err := e.Import(context.Background(), "../../../test/data/import_attachments/subfolder_with_attachments", repoSpec, false, opts, OutputFormatPlain)
 		assert.NoError(t, err)
 		assert.Len(t, res, 2)
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+// Instantiate turns the TM given by id or fetch name into a Thing Description, substituting placeholders with values
+// read from placeholdersFile, and writes it to outputFile or to stdout, if outputFile is empty
+func Instantiate(ctx context.Context, repo model.RepoSpec, idOrName, placeholdersFile, outputFile string, opts commands.InstantiateOptions) error {
+	if placeholdersFile != "" {
+		_, raw, err := utils.ReadRequiredFile(placeholdersFile)
+		if err != nil {
+			Stderrf("Could not read placeholders file: %v", err)
+			return err
+		}
+		err = json.Unmarshal(raw, &opts.Placeholders)
+		if err != nil {
+			Stderrf("Placeholders file must contain a JSON object: %v", err)
+			return err
+		}
+	}
+
+	id, thing, err, errs := commands.FetchByTMIDOrName(ctx, repo, idOrName, false)
+	if err != nil {
+		Stderrf("Could not fetch from repo: %v", err)
+		return err
+	}
+	defer printErrs("Errors occurred while fetching:", errs)
+
+	td, err := commands.InstantiateTM(ctx, repo, id, thing, opts)
+	if err != nil {
+		var mErr *commands.ErrMissingPlaceholders
+		if errors.As(err, &mErr) {
+			for _, n := range mErr.Names {
+				Stderrf("missing value for placeholder %s", n)
+			}
+		}
+		Stderrf("Could not instantiate %s: %v", id, err)
+		return err
+	}
+
+	td = utils.ConvertToNativeLineEndings(td)
+	if outputFile == "" {
+		fmt.Println(string(td))
+		return nil
+	}
+	err = os.WriteFile(outputFile, td, 0660)
+	if err != nil {
+		Stderrf("could not write Thing Description to file %s: %v", outputFile, err)
+		return err
+	}
+	return nil
+}
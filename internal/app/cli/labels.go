@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+func LabelAdd(ctx context.Context, spec model.RepoSpec, tmNameOrId string, labels []string) error {
+	err := commands.UpdateLabels(ctx, spec, toAttachmentContainerRef(tmNameOrId), labels, nil)
+	if err != nil {
+		Stderrf("Failed to add labels %s to %s: %v", strings.Join(labels, ", "), tmNameOrId, err)
+	}
+	return err
+}
+
+func LabelRemove(ctx context.Context, spec model.RepoSpec, tmNameOrId string, labels []string) error {
+	err := commands.UpdateLabels(ctx, spec, toAttachmentContainerRef(tmNameOrId), nil, labels)
+	if err != nil {
+		Stderrf("Failed to remove labels %s from %s: %v", strings.Join(labels, ", "), tmNameOrId, err)
+	}
+	return err
+}
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+func SetLifecycle(ctx context.Context, repo model.RepoSpec, id string, lc model.Lifecycle) error {
+	err := commands.SetLifecycle(ctx, repo, id, lc)
+	if err != nil {
+		Stderrf("Could not set lifecycle state of %s to %s: %v", id, lc.State, err)
+		return err
+	}
+	return nil
+}
@@ -43,14 +43,14 @@ func printIndex(res model.SearchResult) {
 	colWidth := columnWidth()
 	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-	_, _ = fmt.Fprintf(table, "NAME\tAUTHOR\tMANUFACTURER\tMPN\tREPO\n")
+	_, _ = fmt.Fprintf(table, "NAME\tAUTHOR\tMANUFACTURER\tMPN\tREPO\tSTATE\n")
 	for _, value := range res.Entries {
 		name := value.Name
 		man := elideString(value.Manufacturer.Name, colWidth)
 		mpn := elideString(value.Mpn, colWidth)
 		auth := elideString(value.Author.Name, colWidth)
 		repo := elideString(fmt.Sprintf("%v", value.FoundIn), colWidth)
-		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", name, auth, man, mpn, repo)
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", name, auth, man, mpn, repo, displayState(value.State()))
 	}
 	_ = table.Flush()
 }
@@ -64,17 +64,29 @@ func toListResults(res model.SearchResult) []ListResultEntry {
 			Manufacturer: e.Manufacturer.Name,
 			MPN:          e.Mpn,
 			Repo:         e.FoundIn.String(),
+			State:        displayState(e.State()),
+			Labels:       e.Labels,
 		})
 	}
 	return r
 }
 
 type ListResultEntry struct {
-	Name         string `json:"name"`
-	Author       string `json:"author"`
-	Manufacturer string `json:"manufacturer"`
-	MPN          string `json:"mpn"`
-	Repo         string `json:"repo"`
+	Name         string   `json:"name"`
+	Author       string   `json:"author"`
+	Manufacturer string   `json:"manufacturer"`
+	MPN          string   `json:"mpn"`
+	Repo         string   `json:"repo"`
+	State        string   `json:"state,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+}
+
+// displayState returns the lifecycle state as displayed in the output, which is empty for active TMs
+func displayState(s model.LifecycleState) string {
+	if s == model.LifecycleActive {
+		return ""
+	}
+	return string(s)
 }
 
 func elideString(value string, colWidth int) string {
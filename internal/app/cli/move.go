@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+func Move(ctx context.Context, spec model.RepoSpec, oldName, newName string, names model.DisplayNames) error {
+	unsigned, err := commands.Move(ctx, spec, oldName, newName, names)
+	if err != nil {
+		Stderrf("Could not move %s to %s: %v", oldName, newName, err)
+		return err
+	}
+	if len(unsigned) > 0 {
+		fmt.Println("The content of the following signed TMs has been changed by the move. Their signatures have been removed and they must be signed again:")
+		for _, id := range unsigned {
+			fmt.Println(id)
+		}
+	}
+	return nil
+}
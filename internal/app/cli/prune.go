@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+type PruneOptions struct {
+	// DryRun only prints the versions to be deleted without deleting them
+	DryRun bool
+	// Policy replaces the retention policy configured for the repo, if not nil
+	Policy *model.RetentionPolicy
+}
+
+// Prune deletes all TM versions from the repo given by spec, which are not kept by its retention policy
+func Prune(ctx context.Context, spec model.RepoSpec, opts PruneOptions, format string) error {
+	if !IsValidOutputFormat(format) {
+		Stderrf("%v", ErrInvalidOutputFormat)
+		return ErrInvalidOutputFormat
+	}
+	var policy model.RetentionPolicy
+	if opts.Policy != nil {
+		policy = *opts.Policy
+	} else {
+		var err error
+		policy, err = repos.GetRetentionPolicy(spec)
+		if err != nil {
+			Stderrf("Could not read retention policy: %v", err)
+			return err
+		}
+	}
+	ids, err := commands.PruneCandidates(ctx, spec, policy, time.Now())
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRetentionPolicy) {
+			Stderrf("Nothing to prune: %v. Configure %s for the repository or use the --keep-* flags", err, repos.KeyRepoRetention)
+		} else {
+			Stderrf("Could not determine TMs to prune: %v", err)
+		}
+		return err
+	}
+
+	var results []OperationResult
+	if opts.DryRun {
+		for _, id := range ids {
+			results = append(results, OperationResult{opResultOK, id, "to be deleted"})
+		}
+	} else {
+		if format == OutputFormatPlain {
+			fmt.Printf("Pruning %d TMs (%s)...\n", len(ids), policy)
+		}
+		results, err = applyPrune(ctx, spec, ids)
+	}
+
+	switch format {
+	case OutputFormatJSON:
+		printJSON(results)
+	case OutputFormatPlain:
+		for _, res := range results {
+			fmt.Println(res)
+		}
+	}
+	return err
+}
+
+func applyPrune(ctx context.Context, spec model.RepoSpec, ids []string) ([]OperationResult, error) {
+	r, err := repos.Get(spec)
+	if err != nil {
+		return nil, err
+	}
+	var results []OperationResult
+	for _, id := range ids {
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		default:
+		}
+		dErr := r.Delete(ctx, id)
+		if dErr != nil && !errors.Is(dErr, model.ErrTMNotFound) {
+			results = append(results, OperationResult{opResultErr, id, fmt.Sprintf("couldn't delete TM: %v", dErr)})
+			if err == nil {
+				err = dErr
+			}
+			continue
+		}
+		results = append(results, OperationResult{opResultOK, id, "deleted"})
+	}
+	return results, err
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/testutils"
+)
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	policy := &model.RetentionPolicy{KeepYoungerThan: time.Hour}
+	expired := []string{
+		"omnicorp-tm-department/omnicorp/omnilamp/subfolder/v0.0.0-20240409155220-80424c65e4e6.tm.json",
+		"omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json",
+		"omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20240409155220-e414b33a9edf.tm.json",
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		spec, _ := setupSyncRepos(t)
+		before := listTMIDs(t, spec)
+		restore, getOutput := testutils.ReplaceStdout()
+		err := Prune(ctx, spec, PruneOptions{DryRun: true, Policy: policy}, OutputFormatPlain)
+		out := getOutput()
+		restore()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, before, listTMIDs(t, spec))
+		for _, id := range expired {
+			assert.Contains(t, out, id)
+		}
+		assert.Contains(t, out, "to be deleted")
+	})
+	t.Run("prune", func(t *testing.T) {
+		spec, _ := setupSyncRepos(t)
+		restore, getOutput := testutils.ReplaceStdout()
+		err := Prune(ctx, spec, PruneOptions{Policy: policy}, OutputFormatPlain)
+		out := getOutput()
+		restore()
+		assert.NoError(t, err)
+		assert.Contains(t, out, "Pruning 3 TMs")
+		ids := listTMIDs(t, spec)
+		assert.ElementsMatch(t, []string{
+			"omnicorp-tm-department/omnicorp/omnilamp/subfolder/v3.2.1-20240409155220-3f779458e453.tm.json",
+			"omnicorp-tm-department/omnicorp/omnilamp/v3.11.1-20240409155220-da7dbd7ed830.tm.json",
+		}, ids)
+	})
+	t.Run("labelled version is kept", func(t *testing.T) {
+		spec, _ := setupSyncRepos(t)
+		r, err := repos.Get(spec)
+		require.NoError(t, err)
+		require.NoError(t, r.UpdateLabels(ctx, model.NewTMIDAttachmentContainerRef(expired[1]), []string{"pinned"}, nil))
+		restore, getOutput := testutils.ReplaceStdout()
+		err = Prune(ctx, spec, PruneOptions{Policy: policy}, OutputFormatPlain)
+		_ = getOutput()
+		restore()
+		assert.NoError(t, err)
+		assert.Contains(t, listTMIDs(t, spec), expired[1])
+		assert.Len(t, listTMIDs(t, spec), 3)
+	})
+	t.Run("no policy", func(t *testing.T) {
+		spec, _ := setupSyncRepos(t)
+		err := Prune(ctx, spec, PruneOptions{}, OutputFormatPlain)
+		assert.ErrorIs(t, err, model.ErrInvalidRetentionPolicy)
+	})
+}
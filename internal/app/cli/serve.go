@@ -3,14 +3,21 @@ package cli
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"net"
 	nethttp "net/http"
 	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/wot-oss/tmc/internal/app/http/cors"
+	"github.com/wot-oss/tmc/internal/app/http/events"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/tracing"
 	"github.com/wot-oss/tmc/internal/utils"
 
 	"github.com/wot-oss/tmc/internal/app/http/jwt"
@@ -22,11 +29,25 @@ import (
 //go:embed banner.txt
 var banner string
 
+const shutdownTimeout = 5 * time.Second
+
 type ServeOptions struct {
 	UrlCtxRoot string
 	cors.CORSOptions
 	jwt.JWTValidationOpts
 	JWTValidation bool
+	// APIKeyValidation enables access to the API with the keys in JWTValidationOpts.APIKeysFile
+	APIKeyValidation bool
+	// WebhookURLs are the URLs notifications about changes to the catalog are POSTed to
+	WebhookURLs []string
+	// WebhookSecret is the key of the HMAC signature of webhook notifications
+	WebhookSecret string
+	// Tracing configures the export of OpenTelemetry spans
+	Tracing tracing.Options
+	// ImportMaxSize is the maximum size in bytes of the body of a bulk import request and of the files unpacked from it
+	ImportMaxSize int64
+	// ImportMaxFiles is the maximum number of files in a bulk import request
+	ImportMaxFiles int
 }
 
 func Serve(host, port string, opts ServeOptions, repo model.RepoSpec) error {
@@ -46,6 +67,17 @@ func Serve(host, port string, opts ServeOptions, repo model.RepoSpec) error {
 		return err
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), opts.Tracing)
+	if err != nil {
+		err = fmt.Errorf("Could not start tm catalog server on %s:%s, could not set up tracing: %v\n", host, port, err)
+		Stderrf("%v", err.Error())
+		log.Error(err.Error())
+		return err
+	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
 	httpHandler, err := createHttpHandler(repo, opts)
 	if err != nil {
 		err = fmt.Errorf("Could not start tm catalog server on %s:%s, %v\n", host, port, err)
@@ -91,9 +123,19 @@ func Serve(host, port string, opts ServeOptions, repo model.RepoSpec) error {
 	log.Info(verMsg)
 	log.Info(startMsg)
 
+	// shut down gracefully on interrupt, so that pending spans are flushed
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+	}()
+
 	// start server
 	err = s.ListenAndServe()
-	if err != nil {
+	if err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
 		err = fmt.Errorf("Could not start tm catalog server on %s:%s, %v\n", host, port, err)
 		Stderrf("%v", err.Error())
 		log.Error(err.Error())
@@ -104,15 +146,23 @@ func Serve(host, port string, opts ServeOptions, repo model.RepoSpec) error {
 }
 
 func createHttpHandler(repo model.RepoSpec, opts ServeOptions) (nethttp.Handler, error) {
+	repos.EnableMetrics(repo)
 	// create an instance of our handler (server interface)
 	handlerService, err := http.NewDefaultHandlerService(repo)
 	if err != nil {
 		return nil, err
 	}
+	for _, u := range opts.WebhookURLs {
+		pu, err := url.Parse(u)
+		if err != nil || (pu.Scheme != "http" && pu.Scheme != "https") || pu.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL: %s", u)
+		}
+		handlerService.AddEventSink(events.NewWebhook(events.WebhookOptions{URL: u, Secret: opts.WebhookSecret}))
+	}
 
 	var jwtValidation bool
 	jwtValidation = false
-	if opts.JWTValidation {
+	if opts.JWTValidation || opts.APIKeyValidation {
 		jwtValidation = true
 	}
 	handler := http.NewTmcHandler(
@@ -120,6 +170,8 @@ func createHttpHandler(repo model.RepoSpec, opts ServeOptions) (nethttp.Handler,
 		http.TmcHandlerOptions{
 			UrlContextRoot: opts.UrlCtxRoot,
 			JWTValidation:  jwtValidation,
+			ImportMaxSize:  opts.ImportMaxSize,
+			ImportMaxFiles: opts.ImportMaxFiles,
 		})
 
 	// collect Middlewares for the main http handler
@@ -142,8 +194,13 @@ func getMiddlewares(opts ServeOptions) []server.MiddlewareFunc {
 	var mws []server.MiddlewareFunc
 	mws = append(mws, http.WithLogAfterRequestProcessing)
 	mws = append(mws, http.WithRequestLogger)
-	if opts.JWTValidation == true {
-		mws = append(mws, jwt.GetMiddleware(opts.JWTValidationOpts))
+	if opts.JWTValidation || opts.APIKeyValidation {
+		jwtOpts := opts.JWTValidationOpts
+		if !opts.APIKeyValidation {
+			jwtOpts.APIKeysFile = ""
+		}
+		jwtOpts.APIKeysOnly = !opts.JWTValidation
+		mws = append(mws, jwt.GetMiddleware(jwtOpts))
 	}
 	return mws
 }
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/signing"
+)
+
+// Sign signs the TM with given id with the private key in keyFile and stores the signature as attachment to the TM
+func Sign(ctx context.Context, spec model.RepoSpec, id, keyFile string, force bool) error {
+	key, err := signing.LoadPrivateKey(keyFile)
+	if err != nil {
+		Stderrf("Could not load signing key: %v", err)
+		return err
+	}
+	err = commands.SignTM(ctx, spec, id, key, force)
+	if err != nil {
+		Stderrf("Could not sign %s: %v", id, err)
+		return err
+	}
+	fmt.Printf("signed %s with key %s\n", id, signing.KeyID(key.Public().(ed25519.PublicKey)))
+	return nil
+}
+
+// Verify verifies the signature of the TM with given id with the public keys in keyFiles or with the repo's trusted
+// keys, if no keyFiles are given
+func Verify(ctx context.Context, spec model.RepoSpec, id string, keyFiles []string) error {
+	keys, err := signing.LoadPublicKeys(keyFiles)
+	if err != nil {
+		Stderrf("Could not load public key: %v", err)
+		return err
+	}
+	kid, err := commands.VerifyTM(ctx, spec, id, keys)
+	if err != nil {
+		Stderrf("Could not verify %s: %v", id, err)
+		return err
+	}
+	fmt.Printf("signature of %s is valid, signed with key %s\n", id, kid)
+	return nil
+}
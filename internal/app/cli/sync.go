@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+type SyncOptions struct {
+	// Delete enables deleting TMs and attachments from the target repo, which are not present in the source repo
+	Delete bool
+	// DryRun only prints the planned changes without applying them
+	DryRun bool
+}
+
+type syncStepType int
+
+const (
+	syncCopyTM = syncStepType(iota)
+	syncCopyAttachment
+	syncDeleteTM
+	syncDeleteAttachment
+)
+
+// syncStep is a single change that has to be applied to a target repo in order to bring it in sync with a source repo
+type syncStep struct {
+	typ        syncStepType
+	from       model.RepoSpec
+	version    model.FoundVersion
+	force      bool
+	ref        model.AttachmentContainerRef
+	attachment model.Attachment
+	id         string
+}
+
+func (s syncStep) resourceId() string {
+	switch s.typ {
+	case syncCopyAttachment, syncDeleteAttachment:
+		relDir, _ := model.RelAttachmentsDir(s.ref)
+		return fmt.Sprintf("%s/%s", relDir, s.attachment.Name)
+	case syncCopyTM:
+		return s.version.TMID
+	default:
+		return s.id
+	}
+}
+
+func (s syncStep) describe(target model.RepoSpec) string {
+	switch s.typ {
+	case syncCopyTM:
+		if s.force {
+			return fmt.Sprintf("to be overwritten in %v", target)
+		}
+		return fmt.Sprintf("to be copied to %v", target)
+	case syncCopyAttachment:
+		return fmt.Sprintf("to be copied to %v", target)
+	default:
+		return fmt.Sprintf("to be deleted from %v", target)
+	}
+}
+
+// Sync brings the target repo in sync with the source repo by copying all TMs and attachments that are missing in the
+// target or differ in digest. The index of the target is used to determine what is missing, so that only the
+// difference is transferred
+func Sync(ctx context.Context, from, to model.RepoSpec, opts SyncOptions, format string) error {
+	if !IsValidOutputFormat(format) {
+		Stderrf("%v", ErrInvalidOutputFormat)
+		return ErrInvalidOutputFormat
+	}
+	if from.RepoName() == to.RepoName() && from.Dir() == to.Dir() {
+		Stderrf("Source repo cannot be the same as target")
+		return ErrInvalidArgs
+	}
+	toRepo, err := repos.Get(to)
+	if err != nil {
+		Stderrf("Could not initialize a target repo instance for %s: %v\ncheck config", to, err)
+		return err
+	}
+
+	fromContents, err := listForSync(ctx, from, false)
+	if err != nil {
+		return err
+	}
+	toContents, err := listForSync(ctx, to, true)
+	if err != nil {
+		return err
+	}
+
+	steps := planSync(from, fromContents, toContents, opts.Delete)
+
+	var totalRes []OperationResult
+	if opts.DryRun {
+		for _, s := range steps {
+			totalRes = append(totalRes, OperationResult{opResultOK, s.resourceId(), s.describe(to)})
+		}
+	} else {
+		if format == OutputFormatPlain {
+			fmt.Printf("Syncing %d changes...\n", len(steps))
+		}
+		totalRes, err = applySync(ctx, steps, toRepo)
+	}
+
+	switch format {
+	case OutputFormatJSON:
+		printJSON(totalRes)
+	case OutputFormatPlain:
+		for _, res := range totalRes {
+			fmt.Println(res)
+		}
+	}
+	return err
+}
+
+// listForSync lists the contents of the repo. An unindexed repo is treated as empty if allowNoIndex is true, so that a
+// newly created repo can be used as sync target
+func listForSync(ctx context.Context, spec model.RepoSpec, allowNoIndex bool) (model.SearchResult, error) {
+	res, err, errs := commands.List(ctx, spec, nil)
+	if err == nil && len(errs) > 0 {
+		err = errs[0]
+	}
+	if allowNoIndex && errors.Is(err, repos.ErrNoIndex) {
+		return model.SearchResult{}, nil
+	}
+	if err != nil {
+		Stderrf("Error listing %v: %v", spec, err)
+	}
+	return res, err
+}
+
+// planSync computes the steps necessary to make target contain everything that is contained in source. With
+// withDelete, it also computes the steps to delete everything from target that is not contained in source
+func planSync(from model.RepoSpec, source, target model.SearchResult, withDelete bool) []syncStep {
+	targetVersions := make(map[string]model.FoundVersion)
+	targetAttachments := attachmentSet(target)
+	for _, e := range target.Entries {
+		for _, v := range e.Versions {
+			targetVersions[v.TMID] = v
+		}
+	}
+	missingAttachments := func(ref model.AttachmentContainerRef, atts []model.Attachment) []syncStep {
+		var steps []syncStep
+		for _, a := range atts {
+			if _, ok := targetAttachments[ref.String()+"/"+a.Name]; !ok {
+				steps = append(steps, syncStep{typ: syncCopyAttachment, from: from, ref: ref, attachment: a})
+			}
+		}
+		return steps
+	}
+
+	var steps []syncStep
+	sourceIds := make(map[string]struct{})
+	for _, e := range source.Entries {
+		for _, v := range e.Versions {
+			sourceIds[v.TMID] = struct{}{}
+			tv, found := targetVersions[v.TMID]
+			if !found || tv.Digest != v.Digest {
+				steps = append(steps, syncStep{typ: syncCopyTM, from: from, version: v, force: found})
+			}
+			steps = append(steps, missingAttachments(model.NewTMIDAttachmentContainerRef(v.TMID), v.Attachments)...)
+		}
+		// TM name attachments go after the versions, because they can only be imported once the target knows the name
+		steps = append(steps, missingAttachments(model.NewTMNameAttachmentContainerRef(e.Name), e.Attachments)...)
+	}
+	if withDelete {
+		sourceAttachments := attachmentSet(source)
+		obsoleteAttachments := func(ref model.AttachmentContainerRef, atts []model.Attachment) []syncStep {
+			var steps []syncStep
+			for _, a := range atts {
+				if _, ok := sourceAttachments[ref.String()+"/"+a.Name]; !ok {
+					steps = append(steps, syncStep{typ: syncDeleteAttachment, ref: ref, attachment: a})
+				}
+			}
+			return steps
+		}
+		// attachments go before the versions, because TM name attachments can only be deleted while the target knows the name.
+		// Attachments of deleted versions are deleted along with them
+		var delSteps []syncStep
+		for _, e := range target.Entries {
+			steps = append(steps, obsoleteAttachments(model.NewTMNameAttachmentContainerRef(e.Name), e.Attachments)...)
+			for _, v := range e.Versions {
+				if _, ok := sourceIds[v.TMID]; !ok {
+					delSteps = append(delSteps, syncStep{typ: syncDeleteTM, id: v.TMID})
+					continue
+				}
+				steps = append(steps, obsoleteAttachments(model.NewTMIDAttachmentContainerRef(v.TMID), v.Attachments)...)
+			}
+		}
+		steps = append(steps, delSteps...)
+	}
+	return steps
+}
+
+// attachmentSet returns the set of all attachments in res, identified by their container ref and name
+func attachmentSet(res model.SearchResult) map[string]struct{} {
+	set := make(map[string]struct{})
+	add := func(ref model.AttachmentContainerRef, atts []model.Attachment) {
+		for _, a := range atts {
+			set[ref.String()+"/"+a.Name] = struct{}{}
+		}
+	}
+	for _, e := range res.Entries {
+		add(model.NewTMNameAttachmentContainerRef(e.Name), e.Attachments)
+		for _, v := range e.Versions {
+			add(model.NewTMIDAttachmentContainerRef(v.TMID), v.Attachments)
+		}
+	}
+	return set
+}
+
+func applySync(ctx context.Context, steps []syncStep, target repos.Repo) ([]OperationResult, error) {
+	var results []OperationResult
+	var err error
+	ic := commands.NewImportCommand(time.Now)
+	addErr := func(id string, sErr error) {
+		results = append(results, OperationResult{opResultErr, id, sErr.Error()})
+		if err == nil {
+			err = sErr
+		}
+	}
+	for _, s := range steps {
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		default:
+		}
+		switch s.typ {
+		case syncCopyTM:
+			opts := repos.ImportOptions{Force: s.force, OptPath: optPathFromName(s.version.TMID)}
+			res, cErr := copyThingModel(ctx, ic, s.version, target, opts)
+			if cErr != nil {
+				addErr(s.version.TMID, fmt.Errorf("couldn't copy TM %s: %w", s.version.TMID, cErr))
+				continue
+			}
+			// need to index the TM to be able to push attachments to it
+			if iErr := target.Index(ctx, res.TmID); iErr != nil {
+				addErr(res.TmID, fmt.Errorf("could not update index: %w", iErr))
+				continue
+			}
+			if res.Type == repos.ImportResultWarning {
+				results = append(results, OperationResult{opResultWarn, res.TmID, fmt.Sprintf("copied with warning: %s", res.Message)})
+			} else {
+				results = append(results, OperationResult{opResultOK, res.TmID, "copied"})
+			}
+		case syncCopyAttachment:
+			aRes, aErr := copyAttachments(ctx, s.from, target, s.ref, []model.Attachment{s.attachment}, false, false)
+			for i := range aRes {
+				if aRes[i].Type == opResultOK {
+					aRes[i].Text = "copied"
+				}
+			}
+			results = append(results, aRes...)
+			if err == nil && aErr != nil {
+				err = aErr
+			}
+		case syncDeleteTM:
+			dErr := target.Delete(ctx, s.id)
+			if dErr != nil && !errors.Is(dErr, model.ErrTMNotFound) {
+				addErr(s.id, fmt.Errorf("couldn't delete TM %s: %w", s.id, dErr))
+				continue
+			}
+			results = append(results, OperationResult{opResultOK, s.id, "deleted"})
+		case syncDeleteAttachment:
+			dErr := target.DeleteAttachment(ctx, s.ref, s.attachment.Name, "")
+			if dErr != nil && !errors.Is(dErr, model.ErrAttachmentNotFound) {
+				addErr(s.resourceId(), fmt.Errorf("couldn't delete attachment %s: %w", s.resourceId(), dErr))
+				continue
+			}
+			results = append(results, OperationResult{opResultOK, s.resourceId(), "deleted"})
+		}
+	}
+	return results, err
+}
+
+// optPathFromName returns the optional path part of a TM's name, which follows author, manufacturer and mpn
+func optPathFromName(tmid string) string {
+	id, err := model.ParseTMID(tmid)
+	if err != nil {
+		return ""
+	}
+	parts := strings.SplitN(id.Name, "/", 4)
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/testutils"
+)
+
+const (
+	syncTestId1 = "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"
+	syncTestId2 = "omnicorp-tm-department/omnicorp/omnilamp/subfolder/v3.2.1-20240409155220-3f779458e453.tm.json"
+)
+
+func setupSyncRepos(t *testing.T) (model.RepoSpec, model.RepoSpec) {
+	t.Helper()
+	fromDir := t.TempDir()
+	require.NoError(t, testutils.CopyDir("../../../test/data/index", fromDir))
+	from := model.NewDirSpec(fromDir)
+	fromRepo, err := repos.Get(from)
+	require.NoError(t, err)
+	require.NoError(t, fromRepo.Index(context.Background()))
+	to := model.NewDirSpec(t.TempDir())
+	return from, to
+}
+
+func listTMIDs(t *testing.T, spec model.RepoSpec) []string {
+	t.Helper()
+	r, err := repos.Get(spec)
+	require.NoError(t, err)
+	res, err := r.List(context.Background(), nil)
+	require.NoError(t, err)
+	var ids []string
+	for _, e := range res.Entries {
+		for _, v := range e.Versions {
+			ids = append(ids, v.TMID)
+		}
+	}
+	return ids
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("copies everything missing", func(t *testing.T) {
+		from, to := setupSyncRepos(t)
+		restore, getOutput := testutils.ReplaceStdout()
+		err := Sync(ctx, from, to, SyncOptions{}, OutputFormatJSON)
+		out := getOutput()
+		restore()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, listTMIDs(t, from), listTMIDs(t, to))
+		assert.FileExists(t, filepath.Join(to.Dir(), syncTestId2))
+
+		var res []map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(out), &res))
+		for _, r := range res {
+			assert.Equal(t, "OK", r["type"], r)
+		}
+
+		// when: syncing again
+		restore, getOutput = testutils.ReplaceStdout()
+		err = Sync(ctx, from, to, SyncOptions{}, OutputFormatJSON)
+		out = getOutput()
+		restore()
+		// then: there is nothing to do
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", out)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		from, to := setupSyncRepos(t)
+		restore, getOutput := testutils.ReplaceStdout()
+		err := Sync(ctx, from, to, SyncOptions{DryRun: true}, OutputFormatPlain)
+		out := getOutput()
+		restore()
+		assert.NoError(t, err)
+		assert.Contains(t, out, syncTestId1+" to be copied to "+to.String())
+		assert.NoFileExists(t, filepath.Join(to.Dir(), syncTestId1))
+	})
+
+	t.Run("with delete", func(t *testing.T) {
+		from, to := setupSyncRepos(t)
+		restore, _ := testutils.ReplaceStdout()
+		defer restore()
+		require.NoError(t, Sync(ctx, from, to, SyncOptions{}, OutputFormatPlain))
+		fromRepo, _ := repos.Get(from)
+		require.NoError(t, fromRepo.Delete(ctx, syncTestId1))
+
+		// when: syncing without delete
+		err := Sync(ctx, from, to, SyncOptions{}, OutputFormatPlain)
+		// then: the TM remains in target
+		assert.NoError(t, err)
+		assert.Contains(t, listTMIDs(t, to), syncTestId1)
+
+		// when: syncing with delete
+		err = Sync(ctx, from, to, SyncOptions{Delete: true}, OutputFormatPlain)
+		// then: the TM is removed from target
+		assert.NoError(t, err)
+		assert.NotContains(t, listTMIDs(t, to), syncTestId1)
+		assert.ElementsMatch(t, listTMIDs(t, from), listTMIDs(t, to))
+	})
+
+	t.Run("with delete removes attachments", func(t *testing.T) {
+		from, to := setupSyncRepos(t)
+		restore, _ := testutils.ReplaceStdout()
+		defer restore()
+		require.NoError(t, Sync(ctx, from, to, SyncOptions{}, OutputFormatPlain))
+		fromRepo, _ := repos.Get(from)
+		toRepo, _ := repos.Get(to)
+		name := "omnicorp-tm-department/omnicorp/omnilamp"
+		verRef := model.NewTMIDAttachmentContainerRef(name + "/v0.0.0-20240409155220-e414b33a9edf.tm.json")
+		require.NoError(t, fromRepo.DeleteAttachment(ctx, verRef, "manual.txt", ""))
+		nameRef := model.NewTMNameAttachmentContainerRef(name)
+		require.NoError(t, toRepo.ImportAttachment(ctx, nameRef, model.Attachment{Name: "stale.txt"}, []byte("stale"), false, ""))
+
+		// when: syncing without delete
+		require.NoError(t, Sync(ctx, from, to, SyncOptions{}, OutputFormatPlain))
+		// then: the attachments remain in target
+		_, err := toRepo.FetchAttachment(ctx, verRef, "manual.txt")
+		assert.NoError(t, err)
+		_, err = toRepo.FetchAttachment(ctx, nameRef, "stale.txt")
+		assert.NoError(t, err)
+
+		// when: syncing with delete
+		require.NoError(t, Sync(ctx, from, to, SyncOptions{Delete: true}, OutputFormatPlain))
+		// then: the attachments missing in source are removed from target
+		_, err = toRepo.FetchAttachment(ctx, verRef, "manual.txt")
+		assert.ErrorIs(t, err, model.ErrAttachmentNotFound)
+		_, err = toRepo.FetchAttachment(ctx, nameRef, "stale.txt")
+		assert.ErrorIs(t, err, model.ErrAttachmentNotFound)
+	})
+
+	t.Run("with delete removes attachments of deleted TM names", func(t *testing.T) {
+		from, to := setupSyncRepos(t)
+		restore, _ := testutils.ReplaceStdout()
+		defer restore()
+		name := "omnicorp-tm-department/omnicorp/omnilamp/subfolder"
+		fromRepo, _ := repos.Get(from)
+		require.NoError(t, fromRepo.ImportAttachment(ctx, model.NewTMNameAttachmentContainerRef(name), model.Attachment{Name: "README.md"}, []byte("readme"), false, ""))
+		require.NoError(t, Sync(ctx, from, to, SyncOptions{}, OutputFormatPlain))
+		require.DirExists(t, filepath.Join(to.Dir(), name, model.AttachmentsDir))
+		for _, id := range listTMIDs(t, from) {
+			if strings.HasPrefix(id, name+"/") {
+				require.NoError(t, fromRepo.Delete(ctx, id))
+			}
+		}
+
+		restore, getOutput := testutils.ReplaceStdout()
+		err := Sync(ctx, from, to, SyncOptions{Delete: true, DryRun: true}, OutputFormatPlain)
+		out := getOutput()
+		restore()
+		assert.NoError(t, err)
+		assert.Contains(t, out, name+"/"+model.AttachmentsDir+"/README.md to be deleted from "+to.String())
+
+		err = Sync(ctx, from, to, SyncOptions{Delete: true}, OutputFormatPlain)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, listTMIDs(t, from), listTMIDs(t, to))
+		assert.NoDirExists(t, filepath.Join(to.Dir(), name))
+	})
+
+	t.Run("same repo", func(t *testing.T) {
+		err := Sync(ctx, model.NewRepoSpec("r1"), model.NewRepoSpec("r1"), SyncOptions{}, OutputFormatPlain)
+		assert.ErrorIs(t, err, ErrInvalidArgs)
+	})
+}
+
+func TestOptPathFromName(t *testing.T) {
+	assert.Equal(t, "", optPathFromName(syncTestId1))
+	assert.Equal(t, "subfolder", optPathFromName(syncTestId2))
+	assert.Equal(t, "", optPathFromName("invalid"))
+}
@@ -2,22 +2,56 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"time"
 
-	"github.com/wot-oss/tmc/internal/commands/validate"
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
-func ValidateFile(ctx context.Context, filename string) error {
+var ErrValidationFailed = errors.New("TM is not valid")
+
+// ValidateFile validates the TM in file filename. If spec is not empty, the TM is additionally validated against the
+// custom schemas and rules of the repo given by spec. With format json, the findings and the id the TM would be
+// imported under are printed as JSON
+func ValidateFile(ctx context.Context, spec model.RepoSpec, filename string, format string) error {
+	if !IsValidOutputFormat(format) {
+		Stderrf("%v", ErrInvalidOutputFormat)
+		return ErrInvalidOutputFormat
+	}
 	_, raw, err := utils.ReadRequiredFile(filename)
 	if err != nil {
 		Stderrf("could not read file: %v\n", err)
 		return err
 	}
 
-	_, err = validate.ValidateThingModel(raw)
+	var repo repos.Repo
+	if spec != model.EmptySpec {
+		repo, err = repos.Get(spec)
+		if err != nil {
+			Stderrf("could not initialize a repo instance for %v: %v\n", spec, err)
+			return err
+		}
+	}
+
+	res, err := commands.NewImportCommand(time.Now).Validate(ctx, raw, repo, "")
 	if err != nil {
-		Stderrf("validation error: %v\n", err)
+		Stderrf("could not load validation rules: %v\n", err)
 		return err
 	}
+
+	switch format {
+	case OutputFormatJSON:
+		printJSON(res)
+	case OutputFormatPlain:
+		for _, f := range res.Findings {
+			Stderrf("validation error: %s", f)
+		}
+	}
+	if !res.Valid {
+		return ErrValidationFailed
+	}
 	return nil
 }
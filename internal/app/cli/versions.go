@@ -37,21 +37,32 @@ func ListVersions(ctx context.Context, spec model.RepoSpec, name, format string)
 }
 
 type VersionResultEntry struct {
-	Version     string `json:"version"`
-	Description string `json:"description,omitempty"`
-	Repo        string `json:"repo"`
-	ID          string `json:"id"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Repo        string   `json:"repo"`
+	ID          string   `json:"id"`
+	State       string   `json:"state,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+	Successor   string   `json:"successor,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
 }
 
 func toVersionResults(vers []model.FoundVersion) []VersionResultEntry {
 	var r []VersionResultEntry
 	for _, e := range vers {
-		r = append(r, VersionResultEntry{
+		res := VersionResultEntry{
 			Version:     e.Version.Model,
 			Description: e.Description,
 			Repo:        e.FoundIn.String(),
 			ID:          e.TMID,
-		})
+			State:       displayState(e.State()),
+			Labels:      e.Labels,
+		}
+		if e.Lifecycle != nil {
+			res.Reason = e.Lifecycle.Reason
+			res.Successor = e.Lifecycle.Successor
+		}
+		r = append(r, res)
 	}
 	return r
 
@@ -61,9 +72,9 @@ func printFoundVersions(versions []model.FoundVersion) {
 	//	colWidth := columnWidth()
 	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-	_, _ = fmt.Fprintf(table, "VERSION\tID\tREPO\tDESCRIPTION\n")
+	_, _ = fmt.Fprintf(table, "VERSION\tID\tREPO\tSTATE\tDESCRIPTION\n")
 	for _, v := range versions {
-		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", v.Version.Model, v.TMID, v.FoundIn, v.Description)
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", v.Version.Model, v.TMID, v.FoundIn, displayState(v.State()), v.Description)
 	}
 	_ = table.Flush()
 }
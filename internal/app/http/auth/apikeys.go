@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const apiKeyPrefix = "tmc_"
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+var ErrNoScopes = errors.New("api key must have at least one scope")
+
+// APIKey is a static API key which grants its scopes to requests presenting it. Only the hash of the key is stored
+type APIKey struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Hash    string    `json:"hash"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+type apiKeysContent struct {
+	Keys []APIKey `json:"keys"`
+}
+
+// ReadAPIKeys reads the API keys from file. A missing file contains no keys
+func ReadAPIKeys(file string) ([]APIKey, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read api keys file %s: %w", file, err)
+	}
+	var c apiKeysContent
+	if err := json.Unmarshal(content, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys file %s: %w", file, err)
+	}
+	return c.Keys, nil
+}
+
+func writeAPIKeys(file string, keys []APIKey) error {
+	content, err := json.MarshalIndent(apiKeysContent{Keys: keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// CreateAPIKey generates a new API key with given name and scopes and stores its hash in file.
+// Returns the key, which cannot be recovered from the file afterward, and the stored APIKey
+func CreateAPIKey(file, name string, scopes []string) (string, APIKey, error) {
+	if len(scopes) == 0 {
+		return "", APIKey{}, ErrNoScopes
+	}
+	keys, err := ReadAPIKeys(file)
+	if err != nil {
+		return "", APIKey{}, err
+	}
+	idBytes := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", APIKey{}, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", APIKey{}, err
+	}
+	id := hex.EncodeToString(idBytes)
+	key := apiKeyPrefix + id + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	apiKey := APIKey{
+		ID:      id,
+		Name:    name,
+		Hash:    hashAPIKey(key),
+		Scopes:  scopes,
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := writeAPIKeys(file, append(keys, apiKey)); err != nil {
+		return "", APIKey{}, err
+	}
+	return key, apiKey, nil
+}
+
+// RevokeAPIKey removes the API key with given id from file
+func RevokeAPIKey(file, id string) error {
+	keys, err := ReadAPIKeys(file)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(keys, func(k APIKey) bool { return k.ID == id })
+	if i < 0 {
+		return ErrAPIKeyNotFound
+	}
+	return writeAPIKeys(file, slices.Delete(keys, i, i+1))
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyStore looks up API keys in a file, which is read again whenever it has been modified, so that created and
+// revoked keys take effect without a restart
+type APIKeyStore struct {
+	file    string
+	mu      sync.Mutex
+	keys    []APIKey
+	modTime time.Time
+	err     error
+}
+
+func NewAPIKeyStore(file string) *APIKeyStore {
+	return &APIKeyStore{file: file}
+}
+
+// Lookup returns the stored APIKey matching key. Returns ErrAPIKeyNotFound if there is none
+func (s *APIKeyStore) Lookup(key string) (APIKey, error) {
+	keys, err := s.current()
+	if err != nil {
+		return APIKey{}, err
+	}
+	h := []byte(hashAPIKey(key))
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare(h, []byte(k.Hash)) == 1 {
+			return k, nil
+		}
+	}
+	return APIKey{}, ErrAPIKeyNotFound
+}
+
+func (s *APIKeyStore) current() ([]APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var modTime time.Time
+	if info, err := os.Stat(s.file); err == nil {
+		modTime = info.ModTime()
+	}
+	if s.modTime.IsZero() || !modTime.Equal(s.modTime) {
+		s.keys, s.err = ReadAPIKeys(s.file)
+		s.modTime = modTime
+	}
+	return s.keys, s.err
+}
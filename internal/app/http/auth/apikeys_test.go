@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeys(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config", "apikeys.json")
+
+	keys, err := ReadAPIKeys(file)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, _, err = CreateAPIKey(file, "no-scopes", nil)
+	assert.ErrorIs(t, err, ErrNoScopes)
+
+	keyA, apiKeyA, err := CreateAPIKey(file, "ci", []string{"tmc.ns.a-corp.read", "tmc.ns.a-corp.write"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(keyA, apiKeyPrefix+apiKeyA.ID+"_"))
+	keyB, apiKeyB, err := CreateAPIKey(file, "", []string{"tmc.admin"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, keyA, keyB)
+	assert.NotEqual(t, apiKeyA.ID, apiKeyB.ID)
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), keyA)
+	assert.NotContains(t, string(content), keyB)
+	info, err := os.Stat(file)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	keys, err = ReadAPIKeys(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []APIKey{apiKeyA, apiKeyB}, keys)
+
+	store := NewAPIKeyStore(file)
+	k, err := store.Lookup(keyA)
+	assert.NoError(t, err)
+	assert.Equal(t, apiKeyA, k)
+	_, err = store.Lookup(keyA + "x")
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+
+	assert.ErrorIs(t, RevokeAPIKey(file, "unknown"), ErrAPIKeyNotFound)
+	assert.NoError(t, RevokeAPIKey(file, apiKeyA.ID))
+	// make sure the modification is noticed on file systems with coarse timestamps
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(file, future, future))
+
+	_, err = store.Lookup(keyA)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	k, err = store.Lookup(keyB)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tmc.admin"}, k.Scopes)
+
+	assert.NoError(t, os.WriteFile(file, []byte("not json"), 0600))
+	assert.NoError(t, os.Chtimes(file, future.Add(time.Minute), future.Add(time.Minute)))
+	_, err = store.Lookup(keyB)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrAPIKeyNotFound)
+}
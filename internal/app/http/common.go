@@ -12,8 +12,10 @@ import (
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/wot-oss/tmc/internal/app/http/server"
 	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/commands/validate"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/signing"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
@@ -22,6 +24,9 @@ const (
 	Error401Title                  = "Unauthorized"
 	Error404Title                  = "Not Found"
 	Error409Title                  = "Conflict"
+	Error412Title                  = "Precondition Failed"
+	Error413Title                  = "Content Too Large"
+	Error422Title                  = "Unprocessable Entity"
 	Error503Title                  = "Service Unavailable"
 	Error500Title                  = "Internal Server Error"
 	Error500Detail                 = "An unhandled error has occurred. Try again later. If it is a bug we already recorded it. Retrying will most likely not help"
@@ -33,12 +38,23 @@ const (
 	HeaderAuthorization       = "Authorization"
 	HeaderContentType         = "Content-Type"
 	HeaderCacheControl        = "Cache-Control"
+	HeaderLocation            = "Location"
+	HeaderETag                = "ETag"
+	HeaderLastModified        = "Last-Modified"
+	HeaderIfMatch             = "If-Match"
+	HeaderIfNoneMatch         = "If-None-Match"
+	HeaderIfModifiedSince     = "If-Modified-Since"
 	HeaderXContentTypeOptions = "X-Content-Type-Options"
 	MimeText                  = "text/plain"
 	MimeJSON                  = "application/json"
 	MimeTMJSON                = "application/tm+json"
+	MimeTDJSON                = "application/td+json"
+	MimeJSONPatch             = "application/json-patch+json"
 	MimeOctetStream           = "application/octet-stream"
 	MimeProblemJSON           = "application/problem+json"
+	MimeEventStream           = "text/event-stream"
+	MimeZip                   = "application/zip"
+	MimeMultipartFormData     = "multipart/form-data"
 	NoSniff                   = "nosniff"
 	NoCache                   = "no-cache, no-store, max-age=0, must-revalidate"
 
@@ -85,13 +101,16 @@ func HandleErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	var eErr *repos.ErrTMIDConflict
 	var aErr *repos.RepoAccessError
 	var bErr *BaseHttpError
+	var rErr *commands.ErrResolve
 
 	switch true {
 	// handle sentinel errors with errors.Is()
 	case errors.Is(err, model.ErrInvalidId),
 		errors.Is(err, model.ErrInvalidIdOrName),
 		errors.Is(err, model.ErrInvalidFetchName),
+		errors.Is(err, model.ErrInvalidLifecycle),
 		errors.Is(err, commands.ErrTMNameTooLong),
+		errors.Is(err, commands.ErrReservedAttachmentName),
 		errors.Is(err, repos.ErrRepoNotFound),
 		errors.Is(err, ErrIncompatibleParameters),
 		errors.Is(err, repos.ErrInvalidCompletionParams):
@@ -110,7 +129,31 @@ func HandleErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 		errTitle = Error409Title
 		errDetail = err.Error()
 		errStatus = http.StatusConflict
+	case errors.Is(err, repos.ErrPreconditionFailed):
+		errTitle = Error412Title
+		errDetail = err.Error()
+		errStatus = http.StatusPreconditionFailed
+	case errors.Is(err, commands.ErrInvalidTD):
+		errTitle = Error422Title
+		errDetail = err.Error()
+		errStatus = http.StatusUnprocessableEntity
+	case errors.Is(err, signing.ErrUnsigned):
+		errTitle = Error422Title
+		errDetail = err.Error()
+		errStatus = http.StatusUnprocessableEntity
+		errCode = signing.CodeUnsigned
+	case errors.Is(err, signing.ErrInvalidSignature):
+		errTitle = Error422Title
+		errDetail = err.Error()
+		errStatus = http.StatusUnprocessableEntity
+		errCode = signing.CodeInvalidSignature
 	// handle error values we want to access with errors.As()
+	// resolve errors may wrap not found errors of referenced TMs, so they must be handled first
+	case errors.As(err, &rErr):
+		errTitle = Error422Title
+		errDetail = err.Error()
+		errStatus = http.StatusUnprocessableEntity
+		errCode = rErr.Code()
 	case errors.As(err, &nfErr):
 		errTitle = Error404Title
 		errDetail = err.Error()
@@ -132,7 +175,9 @@ func HandleErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	// handle error values we don't need to access with errors.As,
 	// but don't create a separate var above
 	case errors.As(err, new(*jsonschema.ValidationError)),
-		errors.As(err, new(*json.SyntaxError)):
+		errors.As(err, new(*json.SyntaxError)),
+		errors.As(err, new(*commands.ErrMissingPlaceholders)),
+		errors.As(err, new(*validate.ErrCustomValidation)):
 		errTitle = Error400Title
 		errDetail = err.Error()
 		errStatus = http.StatusBadRequest
@@ -209,6 +254,10 @@ func NewBadRequestError(err error, detail string, args ...any) error {
 	return newBaseHttpError(err, http.StatusBadRequest, Error400Title, detail, args...)
 }
 
+func NewContentTooLargeError(err error, detail string, args ...any) error {
+	return newBaseHttpError(err, http.StatusRequestEntityTooLarge, Error413Title, detail, args...)
+}
+
 func NewServiceUnavailableError(err error, detail string) error {
 	return newBaseHttpError(err, http.StatusServiceUnavailable, Error503Title, "%s", detail)
 }
@@ -249,6 +298,7 @@ func convertParams(params any) *model.Filters {
 	var filterManufacturer *string
 	var filterMpn *string
 	var filterProtocol *string
+	var filterLabel *string
 	var filterName *string
 	var filterChangedSince *string
 
@@ -257,6 +307,7 @@ func convertParams(params any) *model.Filters {
 		filterManufacturer = invParams.FilterManufacturer
 		filterMpn = invParams.FilterMpn
 		filterProtocol = invParams.FilterProtocol
+		filterLabel = invParams.FilterLabel
 		filterName = invParams.FilterName
 		filterChangedSince = invParams.FilterChangedSince
 	} else if authorsParams, ok := params.(server.GetAuthorsParams); ok {
@@ -273,7 +324,7 @@ func convertParams(params any) *model.Filters {
 		filterProtocol = mpnsParams.FilterProtocol
 	}
 
-	return model.ToFilters(filterAuthor, filterManufacturer, filterMpn, filterProtocol, filterName, filterChangedSince,
+	return model.ToFilters(filterAuthor, filterManufacturer, filterMpn, filterProtocol, filterLabel, filterName, filterChangedSince,
 		&model.FilterOptions{NameFilterType: model.PrefixMatch})
 }
 
@@ -378,6 +429,94 @@ func toImportThingModelResponse(res repos.ImportResult) server.ImportThingModelR
 	}
 }
 
+func toBulkImportResponse(res []repos.ImportResult) server.BulkImportResponse {
+	data := []server.BulkImportResult{}
+	for _, r := range res {
+		br := server.BulkImportResult{
+			Type:    r.Type.String(),
+			Message: r.Message,
+		}
+		if r.TmID != "" {
+			br.TmID = &r.TmID
+		}
+		var ce repos.CodedError
+		if errors.As(r.Err, &ce) {
+			code := ce.Code()
+			br.Code = &code
+		}
+		data = append(data, br)
+	}
+	return server.BulkImportResponse{
+		Data: data,
+	}
+}
+
+func toValidationResponse(res commands.ValidationResult) server.ValidationResponse {
+	data := server.ValidationResult{
+		Valid:    res.Valid,
+		Findings: []server.ValidationFinding{},
+	}
+	if res.TMID != "" {
+		data.TmID = &res.TMID
+	}
+	for _, f := range res.Findings {
+		vf := server.ValidationFinding{
+			Source:  f.Source,
+			Pointer: f.Pointer,
+			Message: f.Message,
+		}
+		if f.Keyword != "" {
+			vf.Keyword = &f.Keyword
+		}
+		data.Findings = append(data.Findings, vf)
+	}
+	return server.ValidationResponse{
+		Data: data,
+	}
+}
+
+func toTMDiffResponse(diff commands.TMDiff) server.TMDiffResponse {
+	changes := []server.TMChange{}
+	for _, c := range diff.Changes {
+		c := c
+		sc := server.TMChange{
+			Type: server.TMChangeType(c.Type),
+			Kind: server.TMChangeKind(c.Kind),
+			Path: c.Path,
+		}
+		if c.Type != commands.ChangeAdded {
+			sc.Old = &c.Old
+		}
+		if c.Type != commands.ChangeRemoved {
+			sc.New = &c.New
+		}
+		changes = append(changes, sc)
+	}
+	return server.TMDiffResponse{
+		Data: server.TMDiff{
+			From:    diff.From,
+			To:      diff.To,
+			Changes: changes,
+		},
+	}
+}
+
+func toJSONPatch(patch []commands.JSONPatchOperation) []server.JSONPatchOperation {
+	res := []server.JSONPatchOperation{}
+	for _, op := range patch {
+		op := op
+		sop := server.JSONPatchOperation{
+			Op:   server.JSONPatchOperationOp(op.Op),
+			Path: op.Path,
+		}
+		if sop.Op != server.Remove {
+			sop.Value = &op.Value
+		}
+		res = append(res, sop)
+	}
+	return res
+}
+
 func infoResponse() server.InfoResponse {
 	return server.InfoResponse{
 		Name: "tmc",
@@ -57,7 +57,7 @@ func TestWithCORS(t *testing.T) {
 	// then: origins are set correct on CORS middleware handler
 	assert.Equal(t, "[http://example.org https://sample.com]", corsOrigins)
 	// then: headers contain the default CORS allowed header, the manual allowed header and the default header set by WithCORS()
-	assert.Equal(t, "[Accept Accept-Language Content-Language Origin X-Api-Key X-Bar Content-Type]", corsHeaders)
+	assert.Equal(t, "[Accept Accept-Language Content-Language Origin X-Api-Key X-Bar Content-Type If-Match If-None-Match]", corsHeaders)
 	// then: allow credentials is set correct on CORS middleware handler
 	assert.Equal(t, "true", corsCredentials)
 	// then: max age is set correct on CORS middleware handler
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+// strongETag returns v as a strong entity tag
+func strongETag(v string) string {
+	return `"` + v + `"`
+}
+
+// contentETag returns a strong entity tag derived from the hash of content
+func contentETag(content []byte) string {
+	sum := sha256.Sum256(content)
+	return strongETag(hex.EncodeToString(sum[:12]))
+}
+
+// tmETag returns the entity tag of the content of a TM with given digest. The digest is part of the TM's id and changes
+// with its content, so only the representation with the restored original id needs to be told apart
+func tmETag(digest string, restoreId bool) string {
+	if restoreId {
+		return strongETag(digest + "-restored")
+	}
+	return strongETag(digest)
+}
+
+// inventoryETag returns the entity tag of an inventory listing, which changes with every update of the index the listing
+// is made from. The namespaces the listing has been restricted to are folded in, as they make for a different listing
+func inventoryETag(lastUpdated time.Time, namespaces []string) string {
+	v := strconv.FormatInt(lastUpdated.UnixNano(), 36)
+	if len(namespaces) > 0 {
+		sum := sha256.Sum256([]byte(strings.Join(namespaces, ",")))
+		v += "-" + hex.EncodeToString(sum[:6])
+	}
+	return strongETag(v)
+}
+
+// etagMatches reports whether etag is matched by header, the value of an If-Match or If-None-Match header.
+// Weak comparison ignores the weakness indicator of the entity tags in header
+func etagMatches(header, etag string, weak bool) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if weak {
+			t = strings.TrimPrefix(t, "W/")
+		}
+		if t == "*" || t == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified reports whether the client's copy of the resource with the validators etag and lastModified is up to date,
+// as stated by the If-None-Match or, in its absence, the If-Modified-Since header of the request
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get(HeaderIfNoneMatch); inm != "" {
+		return etag != "" && etagMatches(inm, etag, true)
+	}
+	if ims := r.Header.Get(HeaderIfModifiedSince); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// checkIfMatch returns repos.ErrPreconditionFailed if the request has an If-Match header which does not match etag, the
+// current entity tag of the target resource. An empty etag denotes that the resource does not exist
+func checkIfMatch(r *http.Request, etag string) error {
+	im := r.Header.Get(HeaderIfMatch)
+	if im == "" {
+		return nil
+	}
+	if etag == "" || !etagMatches(im, etag, false) {
+		return repos.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// HandleConditionalByteResponse works like HandleByteResponse with status 200, but sends the validators etag and
+// lastModified along, and responds with 304 Not Modified if the client's copy is up to date. If etag is empty, it is
+// derived from data. A zero lastModified is not sent
+func HandleConditionalByteResponse(w http.ResponseWriter, r *http.Request, mime string, data []byte, etag string, lastModified time.Time) {
+	if etag == "" {
+		etag = contentETag(data)
+	}
+	w.Header().Set(HeaderETag, etag)
+	if !lastModified.IsZero() {
+		w.Header().Set(HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	HandleByteResponse(w, r, http.StatusOK, mime, data)
+}
+
+// HandleConditionalJsonResponse works like HandleConditionalByteResponse for data marshalled like in HandleJsonResponse
+func HandleConditionalJsonResponse(w http.ResponseWriter, r *http.Request, data interface{}, etag string, lastModified time.Time) {
+	body, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	HandleConditionalByteResponse(w, r, MimeJSON, body, etag, lastModified)
+}
@@ -0,0 +1,208 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/app/http/mocks"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/testutils"
+)
+
+func Test_etagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		weak   bool
+		exp    bool
+	}{
+		{`"abc"`, false, true},
+		{`"xyz", "abc"`, false, true},
+		{`"xyz"`, false, false},
+		{`*`, false, true},
+		{`W/"abc"`, false, false},
+		{`W/"abc"`, true, true},
+		{`W/"xyz", W/"abc"`, true, true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.exp, etagMatches(test.header, `"abc"`, test.weak), "header %s, weak %v", test.header, test.weak)
+	}
+}
+
+func Test_ConditionalThingModel(t *testing.T) {
+	tmID := "b-corp/eagle/pm20/v1.0.0-20240107123001-234d1b462fff.tm.json"
+	tmContent := []byte("this is the content of a ThingModel")
+	route := "/thing-models/" + tmID
+	lastModified := "Sun, 07 Jan 2024 12:30:01 GMT"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("sends validators", func(t *testing.T) {
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return(tmID, tmContent, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
+		assertResponseTM200(t, rec)
+		assert.Equal(t, `"234d1b462fff"`, rec.Header().Get(HeaderETag))
+		assert.Equal(t, lastModified, rec.Header().Get(HeaderLastModified))
+	})
+	t.Run("with matching If-None-Match", func(t *testing.T) {
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return(tmID, tmContent, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, route).WithHeader(HeaderIfNoneMatch, `"234d1b462fff"`).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, 0, rec.Body.Len())
+		assert.Equal(t, `"234d1b462fff"`, rec.Header().Get(HeaderETag))
+	})
+	t.Run("with restoreId and If-None-Match of plain content", func(t *testing.T) {
+		hs.On("FetchThingModel", mock.Anything, "", tmID, true, false, []string(nil)).Return(tmID, tmContent, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, route+"?restoreId=true").WithHeader(HeaderIfNoneMatch, `"234d1b462fff"`).RunOnHandler(httpHandler)
+		assertResponseTM200(t, rec)
+		assert.Equal(t, `"234d1b462fff-restored"`, rec.Header().Get(HeaderETag))
+	})
+	t.Run("with resolve", func(t *testing.T) {
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, true, []string(nil)).Return(tmID, tmContent, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, route+"?resolve=true").RunOnHandler(httpHandler)
+		assertResponseTM200(t, rec)
+		assert.Equal(t, contentETag(tmContent), rec.Header().Get(HeaderETag))
+	})
+	t.Run("with id of a different stored version", func(t *testing.T) {
+		// the requested id matches a version stored under a different id, e.g. by its digest only or after a move
+		storedId := "b-corp/eagle/pm20/v1.0.0-20240108140117-5a3840060b05.tm.json"
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return(storedId, tmContent, nil).Twice()
+		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
+		assertResponseTM200(t, rec)
+		assert.Equal(t, `"5a3840060b05"`, rec.Header().Get(HeaderETag))
+		assert.Equal(t, "Mon, 08 Jan 2024 14:01:17 GMT", rec.Header().Get(HeaderLastModified))
+		// and then: a copy validated by the entity tag of the requested id is stale
+		rec = testutils.NewRequest(http.MethodGet, route).WithHeader(HeaderIfNoneMatch, `"234d1b462fff"`).RunOnHandler(httpHandler)
+		assertResponseTM200(t, rec)
+	})
+	t.Run("with If-Modified-Since", func(t *testing.T) {
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return(tmID, tmContent, nil).Twice()
+		rec := testutils.NewRequest(http.MethodGet, route).WithHeader(HeaderIfModifiedSince, lastModified).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		rec = testutils.NewRequest(http.MethodGet, route).WithHeader(HeaderIfModifiedSince, "Sat, 06 Jan 2024 12:30:01 GMT").RunOnHandler(httpHandler)
+		assertResponseTM200(t, rec)
+	})
+	t.Run("by fetch name", func(t *testing.T) {
+		fn := "b-corp/eagle/pm20"
+		hs.On("FetchLatestThingModel", mock.Anything, "", fn, false).Return(tmID, tmContent, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, "/thing-models/.latest/"+fn).WithHeader(HeaderIfNoneMatch, `W/"234d1b462fff"`).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+}
+
+func Test_ConditionalInventory(t *testing.T) {
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+	res := listResult2
+	res.LastUpdated = time.Date(2024, 1, 7, 12, 30, 1, 500, time.UTC)
+	etag := inventoryETag(res.LastUpdated, nil)
+
+	t.Run("sends validators", func(t *testing.T) {
+		hs.On("ListInventory", mock.Anything, "", (*model.Filters)(nil), -1, -1).Return(&res, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, "/inventory").RunOnHandler(httpHandler)
+		assertResponse200(t, rec)
+		assert.Equal(t, etag, rec.Header().Get(HeaderETag))
+		assert.Equal(t, "Sun, 07 Jan 2024 12:30:01 GMT", rec.Header().Get(HeaderLastModified))
+	})
+	t.Run("with matching If-None-Match", func(t *testing.T) {
+		hs.On("ListInventory", mock.Anything, "", (*model.Filters)(nil), -1, -1).Return(&res, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, "/inventory").WithHeader(HeaderIfNoneMatch, etag).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, 0, rec.Body.Len())
+	})
+	t.Run("with outdated If-None-Match", func(t *testing.T) {
+		hs.On("ListInventory", mock.Anything, "", (*model.Filters)(nil), -1, -1).Return(&res, nil).Once()
+		rec := testutils.NewRequest(http.MethodGet, "/inventory").WithHeader(HeaderIfNoneMatch, inventoryETag(res.LastUpdated.Add(-time.Minute), nil)).RunOnHandler(httpHandler)
+		assertResponse200(t, rec)
+	})
+	t.Run("inventory entry", func(t *testing.T) {
+		tmID := listResult2.Entries[0].Versions[0].TMID
+		hs.On("GetTMMetadata", mock.Anything, "", tmID).Return(listResult2.Entries[0].Versions, nil).Twice()
+		rec := testutils.NewRequest(http.MethodGet, "/inventory/"+tmID).RunOnHandler(httpHandler)
+		assertResponse200(t, rec)
+		entryETag := rec.Header().Get(HeaderETag)
+		assert.Equal(t, contentETag(rec.Body.Bytes()), entryETag)
+		rec = testutils.NewRequest(http.MethodGet, "/inventory/"+tmID).WithHeader(HeaderIfNoneMatch, entryETag).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+}
+
+func Test_ConditionalAttachment(t *testing.T) {
+	tmID := listResult2.Entries[0].Versions[0].TMID
+	ref := model.NewTMIDAttachmentContainerRef(tmID)
+	attContent := []byte("this is the content of an attachment")
+	etag := contentETag(attContent)
+	route := "/thing-models/" + tmID + "/.attachments/README.txt"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("get with matching If-None-Match", func(t *testing.T) {
+		hs.On("FetchAttachment", mock.Anything, "", ref, "README.txt", false).Return(attContent, nil).Twice()
+		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, etag, rec.Header().Get(HeaderETag))
+		rec = testutils.NewRequest(http.MethodGet, route).WithHeader(HeaderIfNoneMatch, etag).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+	t.Run("put with matching If-Match", func(t *testing.T) {
+		hs.On("FetchAttachment", mock.Anything, "", ref, "README.txt", false).Return(attContent, nil).Once()
+		hs.On("ImportAttachment", mock.Anything, "", ref, "README.txt", []byte("new content"), MimeText, true, etag).Return(nil).Once()
+		rec := testutils.NewRequest(http.MethodPut, route+"?force=true").
+			WithHeader(HeaderContentType, MimeText).
+			WithHeader(HeaderIfMatch, etag).
+			WithBody([]byte("new content")).
+			RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("put with outdated If-Match", func(t *testing.T) {
+		hs.On("FetchAttachment", mock.Anything, "", ref, "README.txt", false).Return([]byte("changed content"), nil).Once()
+		rec := testutils.NewRequest(http.MethodPut, route+"?force=true").
+			WithHeader(HeaderContentType, MimeText).
+			WithHeader(HeaderIfMatch, etag).
+			WithBody([]byte("new content")).
+			RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		assert.Equal(t, MimeProblemJSON, rec.Header().Get(HeaderContentType))
+	})
+	t.Run("delete with If-Match of missing attachment", func(t *testing.T) {
+		hs.On("FetchAttachment", mock.Anything, "", ref, "README.txt", false).Return(nil, model.NewErrNotFound(model.ErrAttachmentNotFound.Subject)).Once()
+		rec := testutils.NewRequest(http.MethodDelete, route).WithHeader(HeaderIfMatch, "*").RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	})
+	t.Run("delete with matching If-Match", func(t *testing.T) {
+		hs.On("FetchAttachment", mock.Anything, "", ref, "README.txt", false).Return(attContent, nil).Once()
+		hs.On("DeleteAttachment", mock.Anything, "", ref, "README.txt", etag).Return(nil).Once()
+		rec := testutils.NewRequest(http.MethodDelete, route).WithHeader(HeaderIfMatch, etag).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("delete failing remotely", func(t *testing.T) {
+		hs.On("DeleteAttachment", mock.Anything, "", ref, "README.txt", "").Return(repos.ErrPreconditionFailed).Once()
+		rec := testutils.NewRequest(http.MethodDelete, route).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	})
+}
+
+func Test_ConditionalDeleteThingModel(t *testing.T) {
+	tmID := listResult2.Entries[0].Versions[0].TMID
+	route := "/thing-models/" + tmID + "?force=true"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("with matching If-Match", func(t *testing.T) {
+		hs.On("GetTMMetadata", mock.Anything, "", tmID).Return(listResult2.Entries[0].Versions, nil).Once()
+		hs.On("DeleteThingModel", mock.Anything, "", tmID).Return(nil).Once()
+		rec := testutils.NewRequest(http.MethodDelete, route).WithHeader(HeaderIfMatch, `"234d1b462fff"`).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("with TM not found", func(t *testing.T) {
+		hs.On("GetTMMetadata", mock.Anything, "", tmID).Return(nil, model.ErrTMNotFound).Once()
+		rec := testutils.NewRequest(http.MethodDelete, route).WithHeader(HeaderIfMatch, `"234d1b462fff"`).RunOnHandler(httpHandler)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	})
+}
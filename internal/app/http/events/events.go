@@ -0,0 +1,126 @@
+package events
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Type is the type of change to the catalog an Event notifies about
+type Type string
+
+const (
+	TypeTMImported         Type = "tm.imported"
+	TypeTMDeleted          Type = "tm.deleted"
+	TypeAttachmentImported Type = "attachment.imported"
+	TypeAttachmentDeleted  Type = "attachment.deleted"
+)
+
+// Event is a notification about a change to the catalog
+type Event struct {
+	// ID consists of the epoch of the Broker which published the event and a sequence number, so that it is unique
+	// across restarts of the catalog server
+	ID   string    `json:"id"`
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+	Repo string    `json:"repo,omitempty"`
+	// TMID is the id of the imported or deleted TM, or of the TM the attachment belongs to
+	TMID string `json:"tmID,omitempty"`
+	// TMName is the name of the TM the attachment belongs to, if it is attached to a TM name rather than a TM id
+	TMName     string `json:"tmName,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	Attachment string `json:"attachment,omitempty"`
+}
+
+const (
+	historySize      = 256
+	subscriberBuffer = 64
+)
+
+// Sink receives all events published by a Broker. Enqueue must not block
+type Sink interface {
+	Enqueue(e Event)
+}
+
+// Broker distributes published events to subscribers and sinks, and keeps the latest events so that subscribers can
+// catch up with events they have missed while reconnecting
+type Broker struct {
+	mu sync.Mutex
+	// epoch distinguishes the ids of events published by this Broker from those published before a restart
+	epoch   string
+	seq     uint64
+	history []Event
+	subs    map[chan Event]struct{}
+	sinks   []Sink
+	now     func() time.Time
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:  make(map[chan Event]struct{}),
+		now:   time.Now,
+	}
+}
+
+// AddSink registers a sink which receives all events published from now on
+func (b *Broker) AddSink(s Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, s)
+}
+
+// Publish assigns an id and a timestamp to e and distributes it to all subscribers and sinks.
+// A subscriber which does not keep up with the events is unsubscribed by closing its channel
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	e.ID = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+	e.Time = b.now().UTC()
+	b.history = append(b.history, e)
+	if len(b.history) > historySize {
+		b.history = b.history[len(b.history)-historySize:]
+	}
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+	for _, s := range b.sinks {
+		s.Enqueue(e)
+	}
+}
+
+// Subscribe returns a channel which receives all events published from now on, preceded by the retained events
+// published after the event with id lastEventID, if it is not empty. The returned function cancels the subscription
+// and must be called when the subscriber is done
+func (b *Broker) Subscribe(lastEventID string) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var missed []Event
+	if lastEventID != "" {
+		for i, e := range b.history {
+			if e.ID == lastEventID {
+				missed = b.history[i+1:]
+				break
+			}
+		}
+	}
+	ch := make(chan Event, subscriberBuffer+len(missed))
+	for _, e := range missed {
+		ch <- e
+	}
+	b.subs[ch] = struct{}{}
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	events []Event
+}
+
+func (s *recordingSink) Enqueue(e Event) {
+	s.events = append(s.events, e)
+}
+
+func TestBroker(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	newBroker := func() *Broker {
+		b := NewBroker()
+		b.epoch = "e"
+		b.now = func() time.Time { return now }
+		return b
+	}
+
+	t.Run("publish to subscribers and sinks", func(t *testing.T) {
+		b := newBroker()
+		sink := &recordingSink{}
+		b.AddSink(sink)
+		ch1, cancel1 := b.Subscribe("")
+		defer cancel1()
+		ch2, cancel2 := b.Subscribe("")
+		defer cancel2()
+
+		b.Publish(Event{Type: TypeTMImported, TMID: "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"})
+		b.Publish(Event{Type: TypeTMDeleted, TMID: "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"})
+
+		exp := []Event{
+			{ID: "e-1", Type: TypeTMImported, Time: now, TMID: "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"},
+			{ID: "e-2", Type: TypeTMDeleted, Time: now, TMID: "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"},
+		}
+		assert.Equal(t, exp, []Event{<-ch1, <-ch1})
+		assert.Equal(t, exp, []Event{<-ch2, <-ch2})
+		assert.Equal(t, exp, sink.events)
+	})
+	t.Run("replay missed events", func(t *testing.T) {
+		b := newBroker()
+		for i := 0; i < 3; i++ {
+			b.Publish(Event{Type: TypeAttachmentImported, Attachment: "README.md"})
+		}
+		ch, cancel := b.Subscribe("e-1")
+		defer cancel()
+		assert.Equal(t, "e-2", (<-ch).ID)
+		assert.Equal(t, "e-3", (<-ch).ID)
+		b.Publish(Event{Type: TypeAttachmentDeleted, Attachment: "README.md"})
+		assert.Equal(t, "e-4", (<-ch).ID)
+	})
+	t.Run("unknown last event id", func(t *testing.T) {
+		b := newBroker()
+		b.Publish(Event{Type: TypeTMImported})
+		ch, cancel := b.Subscribe("42")
+		defer cancel()
+		assert.Len(t, ch, 0)
+	})
+	t.Run("last event id from before a restart", func(t *testing.T) {
+		// given: the last event id received from a broker before the server has been restarted
+		before := NewBroker()
+		before.Publish(Event{Type: TypeTMImported})
+		lastEventID := before.history[0].ID
+		// and given: the events published by the broker after the restart
+		b := NewBroker()
+		for i := 0; i < 3; i++ {
+			b.Publish(Event{Type: TypeTMImported})
+		}
+		// then: the ids of the events differ
+		assert.NotEqual(t, lastEventID, b.history[0].ID)
+		// and then: the events after the restart are not mistaken for the ones following the last event id
+		ch, cancel := b.Subscribe(lastEventID)
+		defer cancel()
+		assert.Len(t, ch, 0)
+	})
+
+	t.Run("slow subscriber is dropped", func(t *testing.T) {
+		b := newBroker()
+		ch, cancel := b.Subscribe("")
+		defer cancel()
+		for i := 0; i < subscriberBuffer+1; i++ {
+			b.Publish(Event{Type: TypeTMImported})
+		}
+		n := 0
+		for range ch {
+			n++
+		}
+		assert.Equal(t, subscriberBuffer, n)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		b := newBroker()
+		ch, cancel := b.Subscribe("")
+		cancel()
+		cancel()
+		_, ok := <-ch
+		assert.False(t, ok)
+		b.Publish(Event{Type: TypeTMImported})
+	})
+	t.Run("history is limited", func(t *testing.T) {
+		b := newBroker()
+		for i := 0; i < historySize+10; i++ {
+			b.Publish(Event{Type: TypeTMImported})
+		}
+		assert.Len(t, b.history, historySize)
+		assert.Equal(t, "e-11", b.history[0].ID)
+	})
+}
@@ -0,0 +1,167 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	HeaderSignature = "X-Tmc-Signature"
+	HeaderEvent     = "X-Tmc-Event"
+	HeaderDelivery  = "X-Tmc-Delivery"
+
+	SignaturePrefix = "sha256="
+
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+	defaultTimeout        = 10 * time.Second
+	webhookQueueSize      = 1024
+)
+
+type WebhookOptions struct {
+	URL string
+	// Secret is the key of the HMAC-SHA256 signature sent in HeaderSignature. No signature is sent if empty
+	Secret string
+	// MaxAttempts is the number of delivery attempts per event. Defaults to 5
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, which doubles with each further retry. Defaults to 1s
+	InitialBackoff time.Duration
+	Client         *http.Client
+}
+
+// Webhook is a Sink which POSTs events as JSON to a URL. Events are delivered one after another in the order they
+// have been published. Failed deliveries are retried with exponential backoff
+type Webhook struct {
+	opts   WebhookOptions
+	queue  chan Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	log    *slog.Logger
+}
+
+// NewWebhook creates a Webhook and starts delivering events enqueued to it
+func NewWebhook(opts WebhookOptions) *Webhook {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaultInitialBackoff
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Webhook{
+		opts:   opts,
+		queue:  make(chan Event, webhookQueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		log:    utils.GetLogger(ctx, "events.Webhook").With("url", opts.URL),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w
+}
+
+// Enqueue queues e for delivery. If the queue is full, because the receiver has been unavailable for a long time, e is
+// dropped and a warning is logged
+func (w *Webhook) Enqueue(e Event) {
+	select {
+	case w.queue <- e:
+	default:
+		w.log.Warn("webhook queue is full. dropping event", "event", e.ID, "type", e.Type)
+	}
+}
+
+// Close stops the delivery. Events which have not been delivered yet are dropped
+func (w *Webhook) Close() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Webhook) run() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case e := <-w.queue:
+			err := w.deliver(e)
+			if err != nil {
+				w.log.Error("could not deliver event", "event", e.ID, "type", e.Type, "error", err)
+			}
+		}
+	}
+}
+
+func (w *Webhook) deliver(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	backoff := w.opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(e, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.opts.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		w.log.Debug("delivery failed. retrying", "event", e.ID, "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-w.ctx.Done():
+			return w.ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a single delivery attempt. Returns whether a failed attempt should be retried
+func (w *Webhook) post(e Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, w.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tmc/"+utils.GetTmcVersion())
+	req.Header.Set(HeaderEvent, string(e.Type))
+	req.Header.Set(HeaderDelivery, e.ID)
+	if w.opts.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(w.opts.Secret, body))
+	}
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		return true, fmt.Errorf("received status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("received status %d", resp.StatusCode)
+	}
+}
+
+// Sign returns the value of HeaderSignature for body: "sha256=" followed by the hex-encoded HMAC-SHA256 of body with
+// secret as key
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*httptest.Server, <-chan delivery, *atomic.Int32) {
+	t.Helper()
+	deliveries := make(chan delivery, 10)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		status := http.StatusNoContent
+		if n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		w.WriteHeader(status)
+		if status < 300 {
+			deliveries <- delivery{header: r.Header, body: body}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, deliveries, &calls
+}
+
+func TestWebhook(t *testing.T) {
+	e := Event{
+		ID:     "7",
+		Type:   TypeTMImported,
+		Time:   time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Repo:   "r1",
+		TMID:   "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json",
+		Digest: "a1b2c3d4e5f6",
+	}
+
+	t.Run("delivers signed events", func(t *testing.T) {
+		srv, deliveries, _ := newReceiver(t)
+		w := NewWebhook(WebhookOptions{URL: srv.URL, Secret: "s3cr3t"})
+		defer w.Close()
+		w.Enqueue(e)
+
+		d := <-deliveries
+		assert.Equal(t, "application/json", d.header.Get("Content-Type"))
+		assert.Equal(t, string(TypeTMImported), d.header.Get(HeaderEvent))
+		assert.Equal(t, "7", d.header.Get(HeaderDelivery))
+		assert.Equal(t, Sign("s3cr3t", d.body), d.header.Get(HeaderSignature))
+		var received Event
+		require.NoError(t, json.Unmarshal(d.body, &received))
+		assert.Equal(t, e, received)
+	})
+	t.Run("without secret", func(t *testing.T) {
+		srv, deliveries, _ := newReceiver(t)
+		w := NewWebhook(WebhookOptions{URL: srv.URL})
+		defer w.Close()
+		w.Enqueue(e)
+
+		d := <-deliveries
+		assert.Empty(t, d.header.Get(HeaderSignature))
+	})
+	t.Run("retries server errors", func(t *testing.T) {
+		srv, deliveries, calls := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+		w := NewWebhook(WebhookOptions{URL: srv.URL, InitialBackoff: time.Millisecond})
+		defer w.Close()
+		w.Enqueue(e)
+
+		<-deliveries
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		srv, deliveries, calls := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway)
+		w := NewWebhook(WebhookOptions{URL: srv.URL, InitialBackoff: time.Millisecond, MaxAttempts: 2})
+		defer w.Close()
+		w.Enqueue(e)
+		e2 := e
+		e2.ID = "8"
+		w.Enqueue(e2)
+
+		d := <-deliveries
+		assert.Equal(t, "8", d.header.Get(HeaderDelivery))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("does not retry client errors", func(t *testing.T) {
+		srv, deliveries, calls := newReceiver(t, http.StatusBadRequest)
+		w := NewWebhook(WebhookOptions{URL: srv.URL, InitialBackoff: time.Millisecond})
+		defer w.Close()
+		w.Enqueue(e)
+		e2 := e
+		e2.ID = "8"
+		w.Enqueue(e2)
+
+		d := <-deliveries
+		assert.Equal(t, "8", d.header.Get(HeaderDelivery))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=6146142a2ce0159e84c0767881e4ec80bc397da62526e7d19f70795eb79460c0", Sign("secret", []byte(`{"id":"1"}`)))
+}
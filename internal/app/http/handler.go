@@ -1,24 +1,39 @@
 package http
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/wot-oss/tmc/internal/app/http/server"
+	"github.com/wot-oss/tmc/internal/commands"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
+// ContextKeyBearerAuthNamespaces holds the namespaces reads are restricted to, including the TMs referenced by a
+// requested TM
 const ContextKeyBearerAuthNamespaces = "BearerAuth.Namespaces"
 
+// ContextKeyBearerAuthWriteNamespaces holds the namespaces a bulk import is restricted to
+const ContextKeyBearerAuthWriteNamespaces = "BearerAuth.WriteNamespaces"
+
 type TmcHandler struct {
 	Service     HandlerService
 	Options     TmcHandlerOptions
@@ -27,9 +42,19 @@ type TmcHandler struct {
 	zipDataName string
 }
 
+const (
+	DefaultImportMaxSize  int64 = 100 << 20
+	DefaultImportMaxFiles       = 10000
+)
+
 type TmcHandlerOptions struct {
 	UrlContextRoot string
 	JWTValidation  bool
+	// ImportMaxSize limits the size in bytes of the body of a bulk import request and the total size of the files
+	// unpacked from it. Defaults to DefaultImportMaxSize
+	ImportMaxSize int64
+	// ImportMaxFiles limits the number of files in a bulk import request. Defaults to DefaultImportMaxFiles
+	ImportMaxFiles int
 }
 
 type ExportJobStatus struct {
@@ -46,6 +71,12 @@ type JobManager struct {
 }
 
 func NewTmcHandler(handlerService HandlerService, options TmcHandlerOptions) *TmcHandler {
+	if options.ImportMaxSize <= 0 {
+		options.ImportMaxSize = DefaultImportMaxSize
+	}
+	if options.ImportMaxFiles <= 0 {
+		options.ImportMaxFiles = DefaultImportMaxFiles
+	}
 	return &TmcHandler{
 		Service:     handlerService,
 		Options:     options,
@@ -158,7 +189,15 @@ func (h *TmcHandler) GetInventory(w http.ResponseWriter, r *http.Request, params
 
 	ctx := h.createContext(r)
 	resp := toInventoryResponse(ctx, *inv, page, pageSize)
-	HandleJsonResponse(w, r, http.StatusOK, resp)
+	etag := ""
+	if !inv.LastUpdated.IsZero() {
+		var namespaces []string
+		if h.Options.JWTValidation {
+			namespaces = extractNamespacesFromContext(r.Context())
+		}
+		etag = inventoryETag(inv.LastUpdated, namespaces)
+	}
+	HandleConditionalJsonResponse(w, r, resp, etag, inv.LastUpdated)
 }
 
 func filterLatestVersions(inv *model.SearchResult) *model.SearchResult {
@@ -187,7 +226,11 @@ func filterLatestVersions(inv *model.SearchResult) *model.SearchResult {
 }
 
 func extractNamespacesFromContext(ctx context.Context) []string {
-	val := ctx.Value(ContextKeyBearerAuthNamespaces)
+	return extractNamespacesFromContextKey(ctx, ContextKeyBearerAuthNamespaces)
+}
+
+func extractNamespacesFromContextKey(ctx context.Context, key string) []string {
+	val := ctx.Value(key)
 	if val == nil {
 		return nil
 	}
@@ -205,13 +248,13 @@ func (h *TmcHandler) GetInventoryByName(w http.ResponseWriter, r *http.Request,
 	entries, err := h.Service.FindInventoryEntries(r.Context(), repo, tmName)
 
 	if err != nil {
-		HandleErrorResponse(w, r, err)
+		h.handleErrorOrRedirectMovedName(w, r, repo, tmName, err)
 		return
 	}
 
 	ctx := h.createContext(r)
 	resp := toInventoryEntryResponse(ctx, entries)
-	HandleJsonResponse(w, r, http.StatusOK, resp)
+	HandleConditionalJsonResponse(w, r, resp, "", time.Time{})
 }
 
 // GetInventoryByFetchName Get the metadata of the most recent TM version matching the name
@@ -226,7 +269,7 @@ func (h *TmcHandler) GetInventoryByFetchName(w http.ResponseWriter, r *http.Requ
 
 	ctx := h.createContext(r)
 	resp := toInventoryEntryVersionResponse(ctx, entry)
-	HandleJsonResponse(w, r, http.StatusOK, resp)
+	HandleConditionalJsonResponse(w, r, resp, "", time.Time{})
 }
 
 // GetThingModelByFetchName Get the content of a Thing Model by fetch name
@@ -237,13 +280,14 @@ func (h *TmcHandler) GetThingModelByFetchName(w http.ResponseWriter, r *http.Req
 		restoreId = *params.RestoreId
 	}
 
-	data, err := h.Service.FetchLatestThingModel(r.Context(), convertRepoName(params.Repo), fetchName, restoreId)
+	id, data, err := h.Service.FetchLatestThingModel(r.Context(), convertRepoName(params.Repo), fetchName, restoreId)
 	if err != nil {
 		HandleErrorResponse(w, r, err)
 		return
 	}
 
-	HandleByteResponse(w, r, http.StatusOK, MimeTMJSON, data)
+	etag, lastModified := tmValidators(id, restoreId)
+	HandleConditionalByteResponse(w, r, MimeTMJSON, data, etag, lastModified)
 }
 
 // GetInventoryByID returns the metadata of a single TM by ID
@@ -260,7 +304,7 @@ func (h *TmcHandler) GetInventoryByID(w http.ResponseWriter, r *http.Request, tm
 
 	ctx := h.createContext(r)
 	resp := toInventoryEntryVersionsResponse(ctx, versions)
-	HandleJsonResponse(w, r, http.StatusOK, resp)
+	HandleConditionalJsonResponse(w, r, resp, "", time.Time{})
 }
 
 // GetThingModelById Get the content of a Thing Model by its ID
@@ -270,13 +314,144 @@ func (h *TmcHandler) GetThingModelById(w http.ResponseWriter, r *http.Request, i
 	if params.RestoreId != nil {
 		restoreId = *params.RestoreId
 	}
+	resolve := false
+	if params.Resolve != nil {
+		resolve = *params.Resolve
+	}
+
+	foundId, data, err := h.Service.FetchThingModel(r.Context(), convertRepoName(params.Repo), id, restoreId, resolve, extractNamespacesFromContext(r.Context()))
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	// the validators are derived from the version actually found, which differs from the requested one, if id has been
+	// matched by its digest only or has been moved
+	etag, lastModified := tmValidators(foundId, restoreId)
+	if resolve {
+		// the resolved content depends on the referenced TMs as well
+		etag = ""
+	}
+	HandleConditionalByteResponse(w, r, MimeTMJSON, data, etag, lastModified)
+}
+
+// tmValidators returns the entity tag and the modification time of the content of the TM with given id. Both are empty,
+// if id cannot be parsed
+func tmValidators(id string, restoreId bool) (string, time.Time) {
+	tmID, err := model.ParseTMID(id)
+	if err != nil {
+		return "", time.Time{}
+	}
+	lastModified, _ := time.Parse(model.PseudoVersionTimestampFormat, tmID.Version.Timestamp)
+	return tmETag(tmID.Version.Hash, restoreId), lastModified
+}
+
+// InstantiateThingModel Instantiate a Thing Description from a Thing Model
+// (POST /thing-models/{tmID}/.td)
+func (h *TmcHandler) InstantiateThingModel(w http.ResponseWriter, r *http.Request, tmID string, params server.InstantiateThingModelParams) {
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
 
-	data, err := h.Service.FetchThingModel(r.Context(), convertRepoName(params.Repo), id, restoreId)
+	opts := commands.InstantiateOptions{Namespaces: extractNamespacesFromContext(r.Context())}
+	if len(b) > 0 {
+		contentType := r.Header.Get(HeaderContentType)
+		if contentType != MimeJSON {
+			HandleErrorResponse(w, r, NewBadRequestError(nil, "Invalid Content-Type header: %s", contentType))
+			return
+		}
+		var req server.InstantiateThingModelRequest
+		err = json.Unmarshal(b, &req)
+		if err != nil {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+		if req.Placeholders != nil {
+			opts.Placeholders = *req.Placeholders
+		}
+		if req.Id != nil {
+			opts.ID = *req.Id
+		}
+		if req.Base != nil {
+			opts.Base = *req.Base
+		}
+	}
+
+	data, err := h.Service.InstantiateThingModel(r.Context(), convertRepoName(params.Repo), tmID, opts)
 	if err != nil {
 		HandleErrorResponse(w, r, err)
 		return
 	}
-	HandleByteResponse(w, r, http.StatusOK, MimeTMJSON, data)
+	HandleByteResponse(w, r, http.StatusOK, MimeTDJSON, data)
+}
+
+// SetThingModelLifecycle Set the lifecycle state of a Thing Model version
+// (PUT /thing-models/{tmID}/.lifecycle)
+func (h *TmcHandler) SetThingModelLifecycle(w http.ResponseWriter, r *http.Request, tmID string, params server.SetThingModelLifecycleParams) {
+	contentType := r.Header.Get(HeaderContentType)
+	if contentType != MimeJSON {
+		HandleErrorResponse(w, r, NewBadRequestError(nil, "Invalid Content-Type header: %s", contentType))
+		return
+	}
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	var req server.TMLifecycle
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		HandleErrorResponse(w, r, NewBadRequestError(err, "Invalid request body"))
+		return
+	}
+	lc := model.Lifecycle{State: model.LifecycleState(req.State)}
+	if req.Reason != nil {
+		lc.Reason = *req.Reason
+	}
+	if req.Successor != nil {
+		lc.Successor = *req.Successor
+	}
+
+	err = h.Service.SetThingModelLifecycle(r.Context(), convertRepoName(params.Repo), tmID, lc)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	_, _ = w.Write(nil)
+}
+
+// GetThingModelDiff Compare two Thing Models
+// (GET /thing-models/.diff)
+func (h *TmcHandler) GetThingModelDiff(w http.ResponseWriter, r *http.Request, params server.GetThingModelDiffParams) {
+	format := server.Json
+	if params.Format != nil {
+		format = *params.Format
+	}
+	if format != server.Json && format != server.Jsonpatch {
+		HandleErrorResponse(w, r, NewBadRequestError(nil, "Invalid format: %s", format))
+		return
+	}
+
+	diff, err := h.Service.DiffThingModels(r.Context(), convertRepoName(params.Repo), params.From, params.To)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+
+	if format == server.Jsonpatch {
+		data, err := json.MarshalIndent(toJSONPatch(diff.Patch), "", "    ")
+		if err != nil {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+		HandleByteResponse(w, r, http.StatusOK, MimeJSONPatch, data)
+		return
+	}
+	HandleJsonResponse(w, r, http.StatusOK, toTMDiffResponse(diff))
 }
 
 // ExportCatalog Export the entire catalog as a zip file
@@ -297,7 +472,7 @@ func (h *TmcHandler) GetExportedCatalog(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Type", MimeZip)
 	w.Header().Set("Content-Disposition", "attachment; filename="+h.zipDataName)
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
 
@@ -322,7 +497,7 @@ func (h *TmcHandler) ExportCatalog(w http.ResponseWriter, r *http.Request, param
 		HandleErrorResponse(w, r, err)
 		return
 	}
-	_, err := h.Service.ListInventory(context.Background(), *params.Repo, nil, -1, -1)
+	_, err := h.Service.ListInventory(r.Context(), *params.Repo, nil, -1, -1)
 	if err != nil {
 		h.JobManager.ReleaseExportingLock()
 		HandleErrorResponse(w, r, err)
@@ -360,8 +535,24 @@ func (h *TmcHandler) DeleteThingModelById(w http.ResponseWriter, r *http.Request
 		HandleErrorResponse(w, r, NewBadRequestError(nil, "invalid value of 'force' query parameter"))
 		return
 	}
+	repo := convertRepoName(params.Repo)
+	if r.Header.Get(HeaderIfMatch) != "" {
+		etag := ""
+		versions, err := h.Service.GetTMMetadata(r.Context(), repo, tmID)
+		if err != nil && !isNotFound(err, model.ErrTMNotFound) {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+		if len(versions) > 0 {
+			etag = tmETag(versions[0].Digest, false)
+		}
+		if err := checkIfMatch(r, etag); err != nil {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+	}
 
-	err := h.Service.DeleteThingModel(r.Context(), convertRepoName(params.Repo), tmID)
+	err := h.Service.DeleteThingModel(r.Context(), repo, tmID)
 	if err != nil {
 		HandleErrorResponse(w, r, err)
 		return
@@ -410,6 +601,204 @@ func (h *TmcHandler) ImportThingModel(w http.ResponseWriter, r *http.Request, p
 
 }
 
+func (h *TmcHandler) ValidateThingModel(w http.ResponseWriter, r *http.Request, p server.ValidateThingModelParams) {
+	contentType := r.Header.Get(HeaderContentType)
+	if contentType != MimeJSON {
+		HandleErrorResponse(w, r, NewBadRequestError(nil, "Invalid Content-Type header: %s", contentType))
+		return
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	if len(b) == 0 {
+		HandleErrorResponse(w, r, NewBadRequestError(nil, "Empty request body"))
+		return
+	}
+
+	optPath := ""
+	if p.OptPath != nil {
+		optPath = *p.OptPath
+	}
+	res, err := h.Service.ValidateThingModel(r.Context(), convertRepoName(p.Repo), b, optPath)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+
+	HandleJsonResponse(w, r, http.StatusOK, toValidationResponse(res))
+}
+
+func (h *TmcHandler) ImportThingModels(w http.ResponseWriter, r *http.Request, p server.ImportThingModelsParams) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(HeaderContentType))
+	if err != nil || (mediaType != MimeZip && mediaType != MimeMultipartFormData) {
+		HandleErrorResponse(w, r, NewBadRequestError(nil, "Invalid Content-Type header: %s", r.Header.Get(HeaderContentType)))
+		return
+	}
+	maxSize, maxFiles := h.Options.ImportMaxSize, h.Options.ImportMaxFiles
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	defer r.Body.Close()
+
+	var fsys fs.FS
+	if mediaType == MimeZip {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			HandleErrorResponse(w, r, importBodyError(err, maxSize, "Invalid request body"))
+			return
+		}
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			HandleErrorResponse(w, r, NewBadRequestError(err, "Invalid zip archive"))
+			return
+		}
+		if len(zr.File) > maxFiles {
+			HandleErrorResponse(w, r, NewContentTooLargeError(nil, "Too many files in zip archive, the maximum is %d", maxFiles))
+			return
+		}
+		// the zip reader fails on reading more than the declared size, so the declared sizes can be relied upon
+		var total uint64
+		for _, f := range zr.File {
+			total += f.UncompressedSize64
+		}
+		if total > uint64(maxSize) {
+			HandleErrorResponse(w, r, NewContentTooLargeError(nil, "Unpacked size of zip archive exceeds the maximum of %d bytes", maxSize))
+			return
+		}
+		fsys = zr
+	} else {
+		dir, err := os.MkdirTemp("", "tmc-import")
+		if err != nil {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+		defer os.RemoveAll(dir)
+		err = saveMultipartFiles(r, dir, maxFiles, maxSize)
+		if err != nil {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+		fsys = os.DirFS(dir)
+	}
+
+	if namespaces := extractNamespacesFromContextKey(r.Context(), ContextKeyBearerAuthWriteNamespaces); namespaces != nil {
+		err = checkImportNamespaces(fsys, namespaces)
+		if err != nil {
+			HandleErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	opts := repos.ImportOptions{
+		Force:           convertForceParam(p.Force),
+		WithAttachments: p.WithAttachments != nil && *p.WithAttachments,
+		IgnoreExisting:  p.IgnoreExisting != nil && *p.IgnoreExisting,
+	}
+	optTree := p.OptTree != nil && *p.OptTree
+
+	res, err := h.Service.ImportThingModels(r.Context(), convertRepoName(p.Repo), fsys, optTree, opts)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+
+	HandleJsonResponse(w, r, http.StatusOK, toBulkImportResponse(res))
+}
+
+// saveMultipartFiles writes the files of the multipart form in the body of r to dir. The file names may contain
+// directories, which are recreated below dir. Fails if there are more than maxFiles files or if their total size
+// exceeds maxSize bytes
+func saveMultipartFiles(r *http.Request, dir string, maxFiles int, maxSize int64) error {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return NewBadRequestError(err, "Invalid multipart body")
+	}
+	files := 0
+	remaining := maxSize
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return importBodyError(err, maxSize, "Invalid multipart body")
+		}
+		// part.FileName() would strip the directories, which are needed for optTree and attachments
+		_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+		name := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(params["filename"], "\\", "/")), "/")
+		if name == "" {
+			continue
+		}
+		files++
+		if files > maxFiles {
+			return NewContentTooLargeError(nil, "Too many files in multipart body, the maximum is %d", maxFiles)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(target), 0700)
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(io.LimitReader(part, remaining+1))
+		if err != nil {
+			return importBodyError(err, maxSize, "Invalid multipart body")
+		}
+		remaining -= int64(len(content))
+		if remaining < 0 {
+			return NewContentTooLargeError(nil, "Total size of files in multipart body exceeds the maximum of %d bytes", maxSize)
+		}
+		err = os.WriteFile(target, content, 0600)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// checkImportNamespaces checks that the authors of all TMs in fsys are among namespaces, so that a bulk import
+// does not write anything if any of the TMs may not be imported. Attachments are imported only to TMs imported along
+// with them and are not checked. Files which are not TMs are left to the import to report
+func checkImportNamespaces(fsys fs.FS, namespaces []string) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == model.AttachmentsDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		raw, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil
+		}
+		var tm model.ThingModel
+		if json.Unmarshal(raw, &tm) != nil {
+			return nil
+		}
+		author := tm.Author.Name
+		if !slices.ContainsFunc(namespaces, func(ns string) bool {
+			return author != "" && strings.EqualFold(utils.SanitizeName(author), utils.SanitizeName(ns))
+		}) {
+			return NewUnauthorizedError(nil, "user cannot import thing models into this namespace: %s", author)
+		}
+		return nil
+	})
+}
+
+// importBodyError converts an error from reading the body of a bulk import request to an http error
+func importBodyError(err error, maxSize int64, detail string) error {
+	var mbErr *http.MaxBytesError
+	if errors.As(err, &mbErr) {
+		return NewContentTooLargeError(nil, "Request body exceeds the maximum of %d bytes", maxSize)
+	}
+	return NewBadRequestError(err, "%s", detail)
+}
+
 func (h *TmcHandler) GetAuthors(w http.ResponseWriter, r *http.Request, params server.GetAuthorsParams) {
 
 	filters := convertParams(params)
@@ -467,6 +856,63 @@ func (h *TmcHandler) GetRepos(w http.ResponseWriter, r *http.Request) {
 	HandleJsonResponse(w, r, http.StatusOK, resp)
 }
 
+// eventsKeepAliveInterval is the interval of comments sent on an idle event stream, which prevent proxies from closing it
+var eventsKeepAliveInterval = 30 * time.Second
+
+// GetEvents streams notifications about changes to the catalog as Server-Sent Events
+// (GET /events)
+func (h *TmcHandler) GetEvents(w http.ResponseWriter, r *http.Request, params server.GetEventsParams) {
+	var lastEventID, repo string
+	if params.LastEventID != nil {
+		lastEventID = *params.LastEventID
+	}
+	if params.Repo != nil {
+		repo = *params.Repo
+	}
+	log := utils.GetLogger(r.Context(), "http.GetEvents")
+	ch, cancel := h.Service.SubscribeEvents(r.Context(), lastEventID)
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set(HeaderContentType, MimeEventStream)
+	w.Header().Set(HeaderCacheControl, NoCache)
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Error("cannot stream events", "error", err)
+		return
+	}
+
+	ticker := time.NewTicker(eventsKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			_, err = io.WriteString(w, ": keep-alive\n\n")
+		case e, ok := <-ch:
+			if !ok {
+				// the subscription has been dropped, because we did not keep up. The client is expected to reconnect
+				// with Last-Event-ID and receive the missed events
+				return
+			}
+			if repo != "" && e.Repo != repo {
+				continue
+			}
+			data, _ := json.Marshal(e)
+			_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			log.Debug("event stream closed", "error", err)
+			return
+		}
+	}
+}
+
 // GetHealth Get the overall health of the service
 // (GET /healthz)
 func (h *TmcHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
@@ -568,16 +1014,42 @@ func (h *TmcHandler) GetTMNameAttachment(w http.ResponseWriter, r *http.Request,
 func (h *TmcHandler) fetchAttachment(w http.ResponseWriter, r *http.Request, repo string, ref model.AttachmentContainerRef, attachmentFileName string, concat bool) {
 	data, err := h.Service.FetchAttachment(r.Context(), repo, ref, attachmentFileName, concat)
 	if err != nil {
-		HandleErrorResponse(w, r, err)
+		h.handleErrorOrRedirectMovedName(w, r, repo, ref.TMName, err)
 		return
 	}
-	HandleByteResponse(w, r, http.StatusOK, MimeOctetStream, data)
+	HandleConditionalByteResponse(w, r, MimeOctetStream, data, "", time.Time{})
+}
+
+// checkAttachmentIfMatch evaluates the If-Match header of the request, if any, against the current content of the attachment
+func (h *TmcHandler) checkAttachmentIfMatch(r *http.Request, repo string, ref model.AttachmentContainerRef, attachmentFileName string) error {
+	if r.Header.Get(HeaderIfMatch) == "" {
+		return nil
+	}
+	etag := ""
+	data, err := h.Service.FetchAttachment(r.Context(), repo, ref, attachmentFileName, false)
+	if err != nil && !isNotFound(err, model.ErrAttachmentNotFound) {
+		return err
+	}
+	if err == nil {
+		etag = contentETag(data)
+	}
+	return checkIfMatch(r, etag)
+}
+
+// isNotFound reports whether err is a not found error of the same subject as nf. Unlike errors.Is, it recognizes not
+// found errors recreated from responses of remote repos
+func isNotFound(err error, nf *model.ErrNotFound) bool {
+	var nfErr *model.ErrNotFound
+	return errors.As(err, &nfErr) && nfErr.Code() == nf.Code()
 }
 
 func (h *TmcHandler) deleteAttachment(w http.ResponseWriter, r *http.Request, repo string, ref model.AttachmentContainerRef, attachmentFileName string) {
-	err := h.Service.DeleteAttachment(r.Context(), repo, ref, attachmentFileName)
+	err := h.checkAttachmentIfMatch(r, repo, ref, attachmentFileName)
+	if err == nil {
+		err = h.Service.DeleteAttachment(r.Context(), repo, ref, attachmentFileName, r.Header.Get(HeaderIfMatch))
+	}
 	if err != nil {
-		HandleErrorResponse(w, r, err)
+		h.handleErrorOrRedirectMovedName(w, r, repo, ref.TMName, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -616,9 +1088,12 @@ func (h *TmcHandler) putAttachment(w http.ResponseWriter, r *http.Request, repo
 		return
 	}
 
-	err = h.Service.ImportAttachment(r.Context(), repo, ref, attachmentFileName, b, contentType, force)
+	err = h.checkAttachmentIfMatch(r, repo, ref, attachmentFileName)
+	if err == nil {
+		err = h.Service.ImportAttachment(r.Context(), repo, ref, attachmentFileName, b, contentType, force, r.Header.Get(HeaderIfMatch))
+	}
 	if err != nil {
-		HandleErrorResponse(w, r, err)
+		h.handleErrorOrRedirectMovedName(w, r, repo, ref.TMName, err)
 		return
 	}
 
@@ -626,6 +1101,36 @@ func (h *TmcHandler) putAttachment(w http.ResponseWriter, r *http.Request, repo
 	_, _ = w.Write(nil)
 }
 
+// handleErrorOrRedirectMovedName redirects the request permanently to the same '.tmName' route of the name tmName
+// has been moved to, if err is caused by tmName not being found. Otherwise, handles err with HandleErrorResponse
+func (h *TmcHandler) handleErrorOrRedirectMovedName(w http.ResponseWriter, r *http.Request, repo, tmName string, err error) {
+	var bErr *BaseHttpError
+	notFound := errors.Is(err, model.ErrTMNameNotFound) || (errors.As(err, &bErr) && bErr.Status == http.StatusNotFound)
+	if tmName == "" || !notFound {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	newName, rErr := h.Service.ResolveMovedTMName(r.Context(), repo, tmName)
+	if rErr != nil || newName == "" {
+		HandleErrorResponse(w, r, err)
+		return
+	}
+	w.Header().Set(HeaderLocation, movedNameLocation(r.URL, tmName, newName))
+	w.WriteHeader(http.StatusMovedPermanently)
+	_, _ = w.Write(nil)
+}
+
+// movedNameLocation returns the location of the resource at u after replacing the TM name oldName in u's path by
+// newName. The location is relative to u, so that it stays valid behind a reverse proxy
+func movedNameLocation(u *url.URL, oldName, newName string) string {
+	_, rest, _ := strings.Cut(u.Path, "/"+tmNamePath+"/"+oldName)
+	loc := strings.Repeat("../", strings.Count(oldName+rest, "/")) + newName + rest
+	if u.RawQuery != "" {
+		loc += "?" + u.RawQuery
+	}
+	return loc
+}
+
 func (h *TmcHandler) createContext(r *http.Request) context.Context {
 	relPathDepth := getRelativeDepth(r.URL.Path, basePathInventory)
 
@@ -664,6 +1169,7 @@ func (jm *JobManager) TryAcquireExportingLock() bool {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	setExportJobState(jm.job.Status)
 	return true
 }
 
@@ -680,6 +1186,7 @@ func (jm *JobManager) CreateJob() ExportJobStatus {
 		UpdatedAt: time.Now(),
 	}
 	jm.job = job
+	setExportJobState(job.Status)
 	return job
 }
 
@@ -695,6 +1202,7 @@ func (jm *JobManager) UpdateJobStatus(status, message string) {
 	jm.job.Status = status
 	jm.job.UpdatedAt = time.Now()
 	jm.job.Error = ""
+	setExportJobState(status)
 }
 
 func (jm *JobManager) MarkJobFailed(errorMessage string) {
@@ -703,4 +1211,5 @@ func (jm *JobManager) MarkJobFailed(errorMessage string) {
 	jm.job.Status = "failed"
 	jm.job.Error = errorMessage
 	jm.job.UpdatedAt = time.Now()
+	setExportJobState(jm.job.Status)
 }
@@ -1,10 +1,14 @@
 package http
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -12,11 +16,14 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/wot-oss/tmc/internal/app/http/events"
 	"github.com/wot-oss/tmc/internal/app/http/mocks"
 	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/commands/validate"
 	"github.com/wot-oss/tmc/internal/testutils"
 
 	"github.com/stretchr/testify/assert"
@@ -24,6 +31,7 @@ import (
 	"github.com/wot-oss/tmc/internal/app/http/server"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/signing"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
@@ -288,11 +296,12 @@ func Test_Inventory(t *testing.T) {
 		fMan := "man1,man2"
 		fMpn := "mpn1,mpn2"
 		fProtos := "coap,https"
+		fLabels := "certified,beta"
 
-		filterRoute := fmt.Sprintf("%s?filter.author=%s&filter.manufacturer=%s&filter.mpn=%s&filter.protocol=%s",
-			route, fAuthors, fMan, fMpn, fProtos)
+		filterRoute := fmt.Sprintf("%s?filter.author=%s&filter.manufacturer=%s&filter.mpn=%s&filter.protocol=%s&filter.label=%s",
+			route, fAuthors, fMan, fMpn, fProtos, fLabels)
 		// and given: filters, expected to be converted from request query parameters
-		expectedFilters := model.ToFilters(&fAuthors, &fMan, &fMpn, &fProtos, nil, nil, &model.FilterOptions{NameFilterType: model.PrefixMatch})
+		expectedFilters := model.ToFilters(&fAuthors, &fMan, &fMpn, &fProtos, &fLabels, nil, nil, &model.FilterOptions{NameFilterType: model.PrefixMatch})
 
 		hs.On("ListInventory", mock.Anything, "", expectedFilters, -1, -1).Return(&listResult1, nil).Once()
 
@@ -596,6 +605,29 @@ func Test_InventoryByName(t *testing.T) {
 		// then: it returns status 500 and json error as body
 		assertResponse500(t, rec, route)
 	})
+
+	t.Run("with moved name", func(t *testing.T) {
+		oldName := "b-corp/hawk/pm20"
+		oldRoute := "/inventory/.tmName/" + oldName + "?repo=r1"
+		hs.On("FindInventoryEntries", mock.Anything, "r1", oldName).Return(nil, NewNotFoundError(nil, "not found")).Once()
+		hs.On("ResolveMovedTMName", mock.Anything, "r1", oldName).Return(inventoryName, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, oldRoute).RunOnHandler(httpHandler)
+		// then: it redirects permanently to the new name, relative to the old one
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, "../../"+inventoryName+"?repo=r1", rec.Header().Get(HeaderLocation))
+	})
+
+	t.Run("with name not found", func(t *testing.T) {
+		oldName := "b-corp/hawk/pm20"
+		oldRoute := "/inventory/.tmName/" + oldName
+		hs.On("FindInventoryEntries", mock.Anything, "", oldName).Return(nil, NewNotFoundError(nil, "not found")).Once()
+		hs.On("ResolveMovedTMName", mock.Anything, "", oldName).Return("", nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, oldRoute).RunOnHandler(httpHandler)
+		// then: it returns status 404
+		assertResponse404(t, rec, oldRoute)
+	})
 }
 
 func Test_Authors(t *testing.T) {
@@ -635,7 +667,7 @@ func Test_Authors(t *testing.T) {
 			route, fMan, fMpn)
 
 		// and given: filters, expected to be converted from request query parameters
-		expectedFilters := model.ToFilters(nil, &fMan, &fMpn, nil, nil, nil, &model.FilterOptions{NameFilterType: model.PrefixMatch})
+		expectedFilters := model.ToFilters(nil, &fMan, &fMpn, nil, nil, nil, nil, &model.FilterOptions{NameFilterType: model.PrefixMatch})
 
 		hs.On("ListAuthors", mock.Anything, expectedFilters).Return(authors, nil).Once()
 
@@ -910,7 +942,7 @@ func Test_FetchThingModel(t *testing.T) {
 	httpHandler := setupTestHttpHandler(hs)
 
 	t.Run("with valid repo", func(t *testing.T) {
-		hs.On("FetchThingModel", mock.Anything, "", tmID, false).Return(tmContent, nil).Once()
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return(tmID, tmContent, nil).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
 		// then: it returns status 200
@@ -919,7 +951,7 @@ func Test_FetchThingModel(t *testing.T) {
 	})
 
 	t.Run("with false restoreId", func(t *testing.T) {
-		hs.On("FetchThingModel", mock.Anything, "", tmID, false).Return(tmContent, nil).Once()
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return(tmID, tmContent, nil).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodGet, route+"?restoreId=false").RunOnHandler(httpHandler)
 		// then: it returns status 200
@@ -927,7 +959,7 @@ func Test_FetchThingModel(t *testing.T) {
 		assert.Equal(t, tmContent, rec.Body.Bytes())
 	})
 	t.Run("with true restoreId", func(t *testing.T) {
-		hs.On("FetchThingModel", mock.Anything, "", tmID, true).Return(tmContent, nil).Once()
+		hs.On("FetchThingModel", mock.Anything, "", tmID, true, false, []string(nil)).Return(tmID, tmContent, nil).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodGet, route+"?restoreId=true").RunOnHandler(httpHandler)
 		// then: it returns status 200
@@ -944,21 +976,233 @@ func Test_FetchThingModel(t *testing.T) {
 	t.Run("with invalid tmID", func(t *testing.T) {
 		// given: route with invalid tmID
 		invalidRoute := "/thing-models/some-invalid-tm-id"
-		hs.On("FetchThingModel", mock.Anything, "", "some-invalid-tm-id", false).Return(nil, model.ErrInvalidId).Once()
+		hs.On("FetchThingModel", mock.Anything, "", "some-invalid-tm-id", false, false, []string(nil)).Return("", nil, model.ErrInvalidId).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodGet, invalidRoute).RunOnHandler(httpHandler)
 		// then: it returns status 400 and json error as body
 		assertResponse400(t, rec, invalidRoute)
 	})
 
+	t.Run("with resolve", func(t *testing.T) {
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, true, []string(nil)).Return(tmID, tmContent, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, route+"?resolve=true").RunOnHandler(httpHandler)
+		// then: it returns status 200
+		assertResponseTM200(t, rec)
+		assert.Equal(t, tmContent, rec.Body.Bytes())
+	})
+	t.Run("with unresolvable reference", func(t *testing.T) {
+		rErr := &commands.ErrResolve{Type: commands.ResolveErrVersionNotFound, Ref: "a/b/c:2", Chain: []string{tmID}, Err: model.ErrTMNotFound}
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, true, []string(nil)).Return("", nil, rErr).Once()
+		// when: calling the route
+		rr := route + "?resolve=true"
+		rec := testutils.NewRequest(http.MethodGet, rr).RunOnHandler(httpHandler)
+		// then: it returns status 422 with the type of resolve error as code
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var errResponse server.ErrorResponse
+		assertUnmarshalResponse(t, rec.Body.Bytes(), &errResponse)
+		assert.Equal(t, Error422Title, errResponse.Title)
+		assert.Equal(t, rr, *errResponse.Instance)
+		if assert.NotNil(t, errResponse.Code) {
+			assert.Equal(t, "versionNotFound", *errResponse.Code)
+		}
+	})
+
+	t.Run("with unverified signature", func(t *testing.T) {
+		sErr := repos.NewRepoAccessError(model.NewRepoSpec("r1"), fmt.Errorf("%s: %w", tmID, signing.ErrUnsigned))
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return("", nil, sErr).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
+		// then: it returns status 422 with the signature error code
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var errResponse server.ErrorResponse
+		assertUnmarshalResponse(t, rec.Body.Bytes(), &errResponse)
+		assert.Equal(t, Error422Title, errResponse.Title)
+		if assert.NotNil(t, errResponse.Code) {
+			assert.Equal(t, signing.CodeUnsigned, *errResponse.Code)
+		}
+	})
+
 	t.Run("with not found error", func(t *testing.T) {
-		hs.On("FetchThingModel", mock.Anything, "", tmID, false).Return(nil, model.ErrTMNotFound).Once()
+		hs.On("FetchThingModel", mock.Anything, "", tmID, false, false, []string(nil)).Return("", nil, model.ErrTMNotFound).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
 		// then: it returns status 404 and json error as body
 		assertResponse404(t, rec, route)
 	})
 }
+func Test_FetchThingModelByFetchName(t *testing.T) {
+	tmContent := []byte("this is the content of a ThingModel")
+	ver := listResult2.Entries[0].Versions[0]
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("with version range", func(t *testing.T) {
+		fn := "a-corp/eagle/bt2000:>=1.0 <2.0"
+		hs.On("FetchLatestThingModel", mock.Anything, "", fn, false).Return(ver.TMID, tmContent, nil).Once()
+		// when: calling the route with url encoded range
+		rec := testutils.NewRequest(http.MethodGet, "/thing-models/.latest/a-corp/eagle/bt2000:%3E=1.0%20%3C2.0").RunOnHandler(httpHandler)
+		// then: it returns status 200
+		assertResponseTM200(t, rec)
+		assert.Equal(t, tmContent, rec.Body.Bytes())
+	})
+
+	t.Run("inventory with version range", func(t *testing.T) {
+		fn := "a-corp/eagle/bt2000:^1.2"
+		hs.On("GetLatestTMMetadata", mock.Anything, "", fn).Return(ver, nil).Once()
+		// when: calling the route with url encoded range
+		rec := testutils.NewRequest(http.MethodGet, "/inventory/.latest/a-corp/eagle/bt2000:%5E1.2").RunOnHandler(httpHandler)
+		// then: it returns status 200
+		assertResponse200(t, rec)
+	})
+}
+
+func Test_InstantiateThingModel(t *testing.T) {
+	tmID := listResult2.Entries[0].Versions[0].TMID
+	tdContent := []byte("this is the content of a Thing Description")
+
+	route := "/thing-models/" + tmID + "/.td"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("with placeholders", func(t *testing.T) {
+		opts := commands.InstantiateOptions{Placeholders: map[string]any{"HOST": "192.168.0.10", "PORT": float64(8080)}, ID: "urn:dev:1"}
+		hs.On("InstantiateThingModel", mock.Anything, "", tmID, opts).Return(tdContent, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"placeholders": {"HOST": "192.168.0.10", "PORT": 8080}, "id": "urn:dev:1"}`)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 200 and the TD
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MimeTDJSON, rec.Header().Get(HeaderContentType))
+		assert.Equal(t, tdContent, rec.Body.Bytes())
+	})
+
+	t.Run("without body", func(t *testing.T) {
+		hs.On("InstantiateThingModel", mock.Anything, "", tmID, commands.InstantiateOptions{}).Return(tdContent, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPost, route).RunOnHandler(httpHandler)
+		// then: it returns status 200
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("with invalid body", func(t *testing.T) {
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"placeholders": `)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 400
+		assertResponse400(t, rec, route)
+	})
+
+	t.Run("with missing placeholders", func(t *testing.T) {
+		mErr := &commands.ErrMissingPlaceholders{Names: []string{"HOST", "PORT"}}
+		hs.On("InstantiateThingModel", mock.Anything, "", tmID, commands.InstantiateOptions{}).Return(nil, mErr).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPost, route).RunOnHandler(httpHandler)
+		// then: it returns status 400 listing the missing placeholders
+		assertResponse400(t, rec, route)
+		var errResponse server.ErrorResponse
+		assertUnmarshalResponse(t, rec.Body.Bytes(), &errResponse)
+		assert.Contains(t, *errResponse.Detail, "HOST, PORT")
+	})
+
+	t.Run("with invalid TD", func(t *testing.T) {
+		hs.On("InstantiateThingModel", mock.Anything, "", tmID, commands.InstantiateOptions{}).Return(nil, fmt.Errorf("%w: missing properties", commands.ErrInvalidTD)).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPost, route).RunOnHandler(httpHandler)
+		// then: it returns status 422
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("with not found error", func(t *testing.T) {
+		hs.On("InstantiateThingModel", mock.Anything, "", tmID, commands.InstantiateOptions{}).Return(nil, model.ErrTMNotFound).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPost, route).RunOnHandler(httpHandler)
+		// then: it returns status 404 and json error as body
+		assertResponse404(t, rec, route)
+	})
+}
+
+func Test_GetThingModelDiff(t *testing.T) {
+	fromID := listResult2.Entries[0].Versions[0].TMID
+	toID := "b-corp/eagle/PM20/v1.1.0-20240207123001-345e1c573aaa.tm.json"
+	route := "/thing-models/.diff?from=" + fromID + "&to=" + toID
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	diff := commands.TMDiff{
+		From: fromID,
+		To:   toID,
+		Changes: []commands.TMChange{
+			{Type: commands.ChangeAdded, Kind: commands.ElementProperty, Path: "/properties/color", New: map[string]any{"type": "string"}},
+			{Type: commands.ChangeModified, Kind: commands.ElementMetadata, Path: "/version/model", Old: "1.0.0", New: "1.1.0"},
+			{Type: commands.ChangeRemoved, Kind: commands.ElementForm, Path: "/forms/0", Old: map[string]any{"href": "/all"}},
+		},
+		Patch: []commands.JSONPatchOperation{
+			{Op: "add", Path: "/properties/color", Value: map[string]any{"type": "string"}},
+			{Op: "replace", Path: "/version/model", Value: "1.1.0"},
+			{Op: "remove", Path: "/forms"},
+		},
+	}
+
+	t.Run("with json format", func(t *testing.T) {
+		hs.On("DiffThingModels", mock.Anything, "", fromID, toID).Return(diff, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
+		// then: it returns status 200 and the list of changes
+		assertResponse200(t, rec)
+		assert.JSONEq(t, `{"data": {"from": "`+fromID+`", "to": "`+toID+`", "changes": [
+			{"type": "added", "kind": "property", "path": "/properties/color", "new": {"type": "string"}},
+			{"type": "modified", "kind": "metadata", "path": "/version/model", "old": "1.0.0", "new": "1.1.0"},
+			{"type": "removed", "kind": "form", "path": "/forms/0", "old": {"href": "/all"}}
+		]}}`, rec.Body.String())
+	})
+
+	t.Run("with jsonpatch format", func(t *testing.T) {
+		hs.On("DiffThingModels", mock.Anything, "r1", fromID, toID).Return(diff, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, route+"&format=jsonpatch&repo=r1").RunOnHandler(httpHandler)
+		// then: it returns status 200 and the JSON Patch
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MimeJSONPatch, rec.Header().Get(HeaderContentType))
+		assert.JSONEq(t, `[
+			{"op": "add", "path": "/properties/color", "value": {"type": "string"}},
+			{"op": "replace", "path": "/version/model", "value": "1.1.0"},
+			{"op": "remove", "path": "/forms"}
+		]`, rec.Body.String())
+	})
+
+	t.Run("with invalid format", func(t *testing.T) {
+		invRoute := route + "&format=xml"
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, invRoute).RunOnHandler(httpHandler)
+		// then: it returns status 400
+		assertResponse400(t, rec, invRoute)
+	})
+
+	t.Run("without to parameter", func(t *testing.T) {
+		invRoute := "/thing-models/.diff?from=" + fromID
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, invRoute).RunOnHandler(httpHandler)
+		// then: it returns status 400
+		assertResponse400(t, rec, invRoute)
+	})
+
+	t.Run("with not found error", func(t *testing.T) {
+		hs.On("DiffThingModels", mock.Anything, "", fromID, toID).Return(commands.TMDiff{}, model.ErrTMNotFound).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, route).RunOnHandler(httpHandler)
+		// then: it returns status 404 and json error as body
+		assertResponse404(t, rec, route)
+	})
+}
+
 func Test_FetchAttachment(t *testing.T) {
 	tmID := listResult2.Entries[0].Versions[0].TMID
 	attContent := []byte("this is the content of an attachment")
@@ -1000,6 +1244,19 @@ func Test_FetchAttachment(t *testing.T) {
 			assert.Equal(t, model.ErrTMNotFound.Subject, *errResponse.Code)
 		}
 	})
+
+	t.Run("with moved tm name", func(t *testing.T) {
+		oldName := "a-corp/eagle/bt2000"
+		newName := "a-corp/falcon/bt2000/legacy"
+		nameRoute := "/thing-models/.tmName/" + oldName + "/.attachments/README.txt"
+		hs.On("FetchAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(oldName), "README.txt", false).Return(nil, model.ErrTMNameNotFound).Once()
+		hs.On("ResolveMovedTMName", mock.Anything, "", oldName).Return(newName, nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodGet, nameRoute).RunOnHandler(httpHandler)
+		// then: it redirects permanently to the attachment of the new name
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, "../../../../"+newName+"/.attachments/README.txt", rec.Header().Get(HeaderLocation))
+	})
 }
 
 func Test_ImportThingModel(t *testing.T) {
@@ -1193,6 +1450,259 @@ func Test_ImportThingModel(t *testing.T) {
 		assertResponse500(t, rec, route)
 	})
 }
+func Test_ImportThingModels(t *testing.T) {
+	tmID := "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20231110123243-98b3fbd291f4.tm.json"
+	_, tmContent, err := utils.ReadRequiredFile("../../../test/data/import/omnilamp-versioned.json")
+	assert.NoError(t, err)
+	route := "/thing-models/.bulk"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	files := map[string][]byte{
+		"sub/omnilamp.json":                   tmContent,
+		"sub/.attachments/omnilamp.json/a.md": []byte("# A"),
+	}
+	hasFiles := mock.MatchedBy(func(fsys fs.FS) bool {
+		for name, content := range files {
+			b, err := fs.ReadFile(fsys, name)
+			if err != nil || !bytes.Equal(content, b) {
+				return false
+			}
+		}
+		return true
+	})
+	results := []repos.ImportResult{
+		{Type: repos.ImportResultOK, TmID: tmID, Message: "file sub/omnilamp.json imported as " + tmID},
+		{Type: repos.ImportResultError, Message: "file x.json already exists", Err: &repos.ErrTMIDConflict{Type: repos.IdConflictSameContent, ExistingId: tmID}},
+	}
+	opts := repos.ImportOptions{Force: true, WithAttachments: true}
+
+	assertBulkResponse := func(t *testing.T, rec *httptest.ResponseRecorder) {
+		assertResponse200(t, rec)
+		var response server.BulkImportResponse
+		assertUnmarshalResponse(t, rec.Body.Bytes(), &response)
+		if assert.Len(t, response.Data, 2) {
+			assert.Equal(t, "OK", response.Data[0].Type)
+			assert.Equal(t, &tmID, response.Data[0].TmID)
+			assert.Equal(t, "error", response.Data[1].Type)
+			assert.Nil(t, response.Data[1].TmID)
+			if assert.NotNil(t, response.Data[1].Code) {
+				assert.Equal(t, "1:"+tmID, *response.Data[1].Code)
+			}
+		}
+	}
+
+	t.Run("with zip", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			f, _ := zw.Create(name)
+			_, _ = f.Write(content)
+		}
+		assert.NoError(t, zw.Close())
+		hs.On("ImportThingModels", mock.Anything, "", hasFiles, true, opts).Return(results, nil).Once()
+
+		rec := testutils.NewRequest(http.MethodPost, route+"?force=true&optTree=true&withAttachments=true").
+			WithHeader(HeaderContentType, MimeZip).
+			WithBody(buf.Bytes()).
+			RunOnHandler(httpHandler)
+		assertBulkResponse(t, rec)
+	})
+
+	t.Run("with multipart", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		for name, content := range files {
+			f, _ := mw.CreateFormFile("files", name)
+			_, _ = f.Write(content)
+		}
+		assert.NoError(t, mw.Close())
+		hs.On("ImportThingModels", mock.Anything, "", hasFiles, false, opts).Return(results, nil).Once()
+
+		rec := testutils.NewRequest(http.MethodPost, route+"?force=true&withAttachments=true").
+			WithHeader(HeaderContentType, mw.FormDataContentType()).
+			WithBody(buf.Bytes()).
+			RunOnHandler(httpHandler)
+		assertBulkResponse(t, rec)
+	})
+
+	t.Run("with invalid zip", func(t *testing.T) {
+		rec := testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeZip).
+			WithBody(tmContent).
+			RunOnHandler(httpHandler)
+		assertResponse400(t, rec, route)
+	})
+
+	t.Run("with wrong Content-Type", func(t *testing.T) {
+		for _, c := range []string{"", MimeJSON, "multipart/mixed"} {
+			rec := testutils.NewRequest(http.MethodPost, route).
+				WithHeader(HeaderContentType, c).
+				WithBody(tmContent).
+				RunOnHandler(httpHandler)
+			assertResponse400(t, rec, route)
+		}
+	})
+}
+
+func Test_ImportThingModels_Limits(t *testing.T) {
+	route := "/thing-models/.bulk"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := NewHttpHandler(NewTmcHandler(hs, TmcHandlerOptions{ImportMaxSize: 1000, ImportMaxFiles: 2}), nil)
+
+	zipOf := func(files map[string][]byte) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			f, _ := zw.Create(name)
+			_, _ = f.Write(content)
+		}
+		assert.NoError(t, zw.Close())
+		return buf.Bytes()
+	}
+	postZip := func(body []byte) *httptest.ResponseRecorder {
+		return testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeZip).
+			WithBody(body).
+			RunOnHandler(httpHandler)
+	}
+	postMultipart := func(files map[string][]byte) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		for name, content := range files {
+			f, _ := mw.CreateFormFile("files", name)
+			_, _ = f.Write(content)
+		}
+		assert.NoError(t, mw.Close())
+		return testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, mw.FormDataContentType()).
+			WithBody(buf.Bytes()).
+			RunOnHandler(httpHandler)
+	}
+
+	t.Run("zip body too large", func(t *testing.T) {
+		rec := postZip(bytes.Repeat([]byte("x"), 1001))
+		assertResponse413(t, rec, route)
+	})
+	t.Run("zip with too many files", func(t *testing.T) {
+		rec := postZip(zipOf(map[string][]byte{"a.json": {}, "b.json": {}, "c.json": {}}))
+		assertResponse413(t, rec, route)
+	})
+	t.Run("zip unpacked too large", func(t *testing.T) {
+		body := zipOf(map[string][]byte{"a.json": bytes.Repeat([]byte(" "), 10000)})
+		assert.Less(t, len(body), 1000)
+		rec := postZip(body)
+		assertResponse413(t, rec, route)
+	})
+	t.Run("multipart with too many files", func(t *testing.T) {
+		rec := postMultipart(map[string][]byte{"a.json": {}, "b.json": {}, "c.json": {}})
+		assertResponse413(t, rec, route)
+	})
+	t.Run("multipart too large", func(t *testing.T) {
+		rec := postMultipart(map[string][]byte{"a.json": bytes.Repeat([]byte("x"), 1001)})
+		assertResponse413(t, rec, route)
+	})
+}
+
+func Test_checkImportNamespaces(t *testing.T) {
+	tm := func(author string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(`{"schema:author":{"schema:name":"` + author + `"}}`)}
+	}
+	fsys := fstest.MapFS{
+		"a/tm.json":                      tm("A-Corp"),
+		"b/tm.json":                      tm("b-corp"),
+		"b/.attachments/tm.json/tm.json": tm("c-corp"),
+		"not-a-tm.json":                  &fstest.MapFile{Data: []byte("not json")},
+		"README.md":                      &fstest.MapFile{Data: []byte("# Readme")},
+	}
+
+	t.Run("all authors allowed", func(t *testing.T) {
+		assert.NoError(t, checkImportNamespaces(fsys, []string{"a-corp", "b-corp"}))
+	})
+	t.Run("an author not allowed", func(t *testing.T) {
+		err := checkImportNamespaces(fsys, []string{"a-corp"})
+		var bErr *BaseHttpError
+		if assert.ErrorAs(t, err, &bErr) {
+			assert.Equal(t, http.StatusUnauthorized, bErr.Status)
+			assert.Contains(t, bErr.Detail, "b-corp")
+		}
+	})
+	t.Run("missing author not allowed", func(t *testing.T) {
+		assert.Error(t, checkImportNamespaces(fstest.MapFS{"tm.json": &fstest.MapFile{Data: []byte("{}")}}, []string{"a-corp"}))
+	})
+}
+
+func Test_ValidateThingModel(t *testing.T) {
+	tmID := "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20231110123243-98b3fbd291f4.tm.json"
+	_, tmContent, err := utils.ReadRequiredFile("../../../test/data/import/omnilamp-versioned.json")
+	assert.NoError(t, err)
+	route := "/thing-models/.validate"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("with findings", func(t *testing.T) {
+		res := commands.ValidationResult{
+			Valid: false,
+			TMID:  tmID,
+			Findings: []validate.Finding{
+				{Source: validate.SourceTMSchema, Pointer: "/properties/status/readOnly", Keyword: "type", Message: "expected boolean, but got string"},
+				{Source: "numeric-properties-have-unit", Pointer: "/properties/dim", Message: "numeric properties must declare a unit"},
+			},
+		}
+		hs.On("ValidateThingModel", mock.Anything, "", tmContent, "opt").Return(res, nil).Once()
+
+		rec := testutils.NewRequest(http.MethodPost, route+"?optPath=opt").
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody(tmContent).
+			RunOnHandler(httpHandler)
+
+		assertResponse200(t, rec)
+		var response server.ValidationResponse
+		assertUnmarshalResponse(t, rec.Body.Bytes(), &response)
+		assert.False(t, response.Data.Valid)
+		assert.Equal(t, &tmID, response.Data.TmID)
+		if assert.Len(t, response.Data.Findings, 2) {
+			f := response.Data.Findings[0]
+			assert.Equal(t, validate.SourceTMSchema, f.Source)
+			assert.Equal(t, "/properties/status/readOnly", f.Pointer)
+			if assert.NotNil(t, f.Keyword) {
+				assert.Equal(t, "type", *f.Keyword)
+			}
+			assert.Nil(t, response.Data.Findings[1].Keyword)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		hs.On("ValidateThingModel", mock.Anything, "", tmContent, "").Return(commands.ValidationResult{Valid: true, TMID: tmID, Findings: []validate.Finding{}}, nil).Once()
+
+		rec := testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody(tmContent).
+			RunOnHandler(httpHandler)
+
+		assertResponse200(t, rec)
+		assert.JSONEq(t, `{"data": {"valid": true, "tmID": "`+tmID+`", "findings": []}}`, rec.Body.String())
+	})
+
+	t.Run("with wrong Content-Type", func(t *testing.T) {
+		rec := testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeText).
+			WithBody(tmContent).
+			RunOnHandler(httpHandler)
+		assertResponse400(t, rec, route)
+	})
+
+	t.Run("with empty request body", func(t *testing.T) {
+		rec := testutils.NewRequest(http.MethodPost, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			RunOnHandler(httpHandler)
+		assertResponse400(t, rec, route)
+	})
+}
+
 func Test_ImportAttachment(t *testing.T) {
 
 	attContent := []byte("# readme.md file")
@@ -1205,7 +1715,7 @@ func Test_ImportAttachment(t *testing.T) {
 		route := "/thing-models/" + tmID + "/.attachments/README.md"
 
 		t.Run("with success", func(t *testing.T) {
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.md", attContent, "text/markdown", true).Return(nil).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.md", attContent, "text/markdown", true, "").Return(nil).Once()
 			// when: calling the route
 			rec := testutils.NewRequest(http.MethodPut, route+"?force=true").
 				WithHeader(HeaderContentType, "text/markdown").
@@ -1230,7 +1740,7 @@ func Test_ImportAttachment(t *testing.T) {
 		t.Run("with invalid id", func(t *testing.T) {
 			// given: some route with invalid tmID
 			route := "/thing-models/not-an-id/.attachments/README.md"
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef("not-an-id"), "README.md", attContent, "text/markdown", false).Return(model.ErrInvalidIdOrName).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef("not-an-id"), "README.md", attContent, "text/markdown", false, "").Return(model.ErrInvalidIdOrName).Once()
 			// when: calling the route
 
 			rec := testutils.NewRequest(http.MethodPut, route).
@@ -1245,7 +1755,7 @@ func Test_ImportAttachment(t *testing.T) {
 		t.Run("with attachment conflict", func(t *testing.T) {
 			// given: some route with invalid tmID
 			route := "/thing-models/" + tmID + "/.attachments/DONTREADME.md"
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "DONTREADME.md", attContent, "text/markdown", false).Return(repos.ErrAttachmentExists).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "DONTREADME.md", attContent, "text/markdown", false, "").Return(repos.ErrAttachmentExists).Once()
 			// when: calling the route
 			rec := testutils.NewRequest(http.MethodPut, route).
 				WithHeader(HeaderContentType, "text/markdown").
@@ -1272,7 +1782,7 @@ func Test_ImportAttachment(t *testing.T) {
 
 		t.Run("with unknown error", func(t *testing.T) {
 			// and given: some unknown error
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.md", attContent, MimeOctetStream, false).Return(unknownErr).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.md", attContent, MimeOctetStream, false, "").Return(unknownErr).Once()
 			// when: calling the route
 			rec := testutils.NewRequest(http.MethodPut, route).
 				WithHeader(HeaderContentType, MimeOctetStream).
@@ -1288,7 +1798,7 @@ func Test_ImportAttachment(t *testing.T) {
 		route := "/thing-models/.tmName/" + tmName + "/.attachments/README.md"
 
 		t.Run("with success", func(t *testing.T) {
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(tmName), "README.md", attContent, "text/markdown", true).Return(nil).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(tmName), "README.md", attContent, "text/markdown", true, "").Return(nil).Once()
 			// when: calling the route
 			rec := testutils.NewRequest(http.MethodPut, route+"?force=true").
 				WithHeader(HeaderContentType, "text/markdown").
@@ -1313,7 +1823,7 @@ func Test_ImportAttachment(t *testing.T) {
 		t.Run("with invalid id", func(t *testing.T) {
 			// given: some route with invalid tmName
 			route := "/thing-models/.tmName/not-an-name/.attachments/README.md"
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef("not-an-name"), "README.md", attContent, "text/markdown", false).Return(model.ErrInvalidIdOrName).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef("not-an-name"), "README.md", attContent, "text/markdown", false, "").Return(model.ErrInvalidIdOrName).Once()
 			// when: calling the route
 
 			rec := testutils.NewRequest(http.MethodPut, route).
@@ -1328,7 +1838,7 @@ func Test_ImportAttachment(t *testing.T) {
 		t.Run("with attachment conflict", func(t *testing.T) {
 			// given: some route with invalid tmName
 			route := "/thing-models/.tmName/" + tmName + "/.attachments/DONTREADME.md"
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(tmName), "DONTREADME.md", attContent, "text/markdown", false).Return(repos.ErrAttachmentExists).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(tmName), "DONTREADME.md", attContent, "text/markdown", false, "").Return(repos.ErrAttachmentExists).Once()
 			// when: calling the route
 			rec := testutils.NewRequest(http.MethodPut, route).
 				WithHeader(HeaderContentType, "text/markdown").
@@ -1355,7 +1865,7 @@ func Test_ImportAttachment(t *testing.T) {
 
 		t.Run("with unknown error", func(t *testing.T) {
 			// and given: some unknown error
-			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(tmName), "README.md", attContent, MimeOctetStream, false).Return(unknownErr).Once()
+			hs.On("ImportAttachment", mock.Anything, "", model.NewTMNameAttachmentContainerRef(tmName), "README.md", attContent, MimeOctetStream, false, "").Return(unknownErr).Once()
 			// when: calling the route
 			rec := testutils.NewRequest(http.MethodPut, route).
 				WithHeader(HeaderContentType, MimeOctetStream).
@@ -1426,6 +1936,83 @@ func Test_DeleteThingModelById(t *testing.T) {
 	})
 
 }
+func Test_SetThingModelLifecycle(t *testing.T) {
+	tmID := listResult2.Entries[0].Versions[0].TMID
+	route := "/thing-models/" + tmID + "/.lifecycle"
+
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("deprecate", func(t *testing.T) {
+		lc := model.Lifecycle{State: model.LifecycleDeprecated, Reason: "wrong unit", Successor: "a/b/c/v1.0.1-20231005123243-b49617d2e4fc.tm.json"}
+		hs.On("SetThingModelLifecycle", mock.Anything, "", tmID, lc).Return(nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPut, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"state": "deprecated", "reason": "wrong unit", "successor": "a/b/c/v1.0.1-20231005123243-b49617d2e4fc.tm.json"}`)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 204
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, 0, rec.Body.Len())
+	})
+
+	t.Run("with repo", func(t *testing.T) {
+		lc := model.Lifecycle{State: model.LifecycleYanked}
+		hs.On("SetThingModelLifecycle", mock.Anything, "r1", tmID, lc).Return(nil).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPut, route+"?repo=r1").
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"state": "yanked"}`)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 204
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("with invalid content type", func(t *testing.T) {
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPut, route).
+			WithHeader(HeaderContentType, "text/plain").
+			WithBody([]byte(`{"state": "yanked"}`)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 400
+		assertResponse400(t, rec, route)
+	})
+
+	t.Run("with invalid body", func(t *testing.T) {
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPut, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"state": `)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 400
+		assertResponse400(t, rec, route)
+	})
+
+	t.Run("with invalid lifecycle", func(t *testing.T) {
+		lc := model.Lifecycle{State: "retired"}
+		hs.On("SetThingModelLifecycle", mock.Anything, "", tmID, lc).Return(model.ErrInvalidLifecycle).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPut, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"state": "retired"}`)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 400
+		assertResponse400(t, rec, route)
+	})
+
+	t.Run("with not found error", func(t *testing.T) {
+		lc := model.Lifecycle{State: model.LifecycleYanked}
+		hs.On("SetThingModelLifecycle", mock.Anything, "", tmID, lc).Return(model.ErrTMNotFound).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodPut, route).
+			WithHeader(HeaderContentType, MimeJSON).
+			WithBody([]byte(`{"state": "yanked"}`)).
+			RunOnHandler(httpHandler)
+		// then: it returns status 404
+		assertResponse404(t, rec, route)
+	})
+}
+
 func Test_DeleteAttachment(t *testing.T) {
 	tmID := listResult2.Entries[0].Versions[0].TMID
 
@@ -1435,7 +2022,7 @@ func Test_DeleteAttachment(t *testing.T) {
 	route := "/thing-models/" + tmID + "/.attachments/README.txt"
 
 	t.Run("with valid tmID", func(t *testing.T) {
-		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.txt").Return(nil).Once()
+		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.txt", "").Return(nil).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodDelete, route).RunOnHandler(httpHandler)
 		// then: it returns status 204
@@ -1446,7 +2033,7 @@ func Test_DeleteAttachment(t *testing.T) {
 	t.Run("with invalid tmID", func(t *testing.T) {
 		// given: route with invalid tmID
 		route := "/thing-models/some-invalid-tm-id/.attachments/README.txt"
-		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef("some-invalid-tm-id"), "README.txt").Return(model.ErrInvalidId).Once()
+		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef("some-invalid-tm-id"), "README.txt", "").Return(model.ErrInvalidId).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodDelete, route).RunOnHandler(httpHandler)
 		// then: it returns status 400 and json error as body
@@ -1454,7 +2041,7 @@ func Test_DeleteAttachment(t *testing.T) {
 	})
 
 	t.Run("with not found error", func(t *testing.T) {
-		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.txt").Return(model.ErrAttachmentNotFound).Once()
+		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), "README.txt", "").Return(model.ErrAttachmentNotFound).Once()
 		// when: calling the route
 		rec := testutils.NewRequest(http.MethodDelete, route).RunOnHandler(httpHandler)
 		// then: it returns status 404 and json error as body
@@ -1466,6 +2053,14 @@ func Test_DeleteAttachment(t *testing.T) {
 		}
 	})
 
+	t.Run("with reserved attachment name", func(t *testing.T) {
+		route := "/thing-models/" + tmID + "/.attachments/" + model.SignatureAttachmentName
+		hs.On("DeleteAttachment", mock.Anything, "", model.NewTMIDAttachmentContainerRef(tmID), model.SignatureAttachmentName, "").Return(commands.ErrReservedAttachmentName).Once()
+		// when: calling the route
+		rec := testutils.NewRequest(http.MethodDelete, route).RunOnHandler(httpHandler)
+		// then: it returns status 400 and json error as body
+		assertResponse400(t, rec, route)
+	})
 }
 
 func Test_Completions(t *testing.T) {
@@ -1556,6 +2151,15 @@ func assertResponse400(t *testing.T, rec *httptest.ResponseRecorder, route strin
 	assert.Equal(t, NoSniff, rec.Header().Get(HeaderXContentTypeOptions))
 }
 
+func assertResponse413(t *testing.T, rec *httptest.ResponseRecorder, route string) {
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var errResponse server.ErrorResponse
+	assertUnmarshalResponse(t, rec.Body.Bytes(), &errResponse)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, errResponse.Status)
+	assert.Equal(t, route, *errResponse.Instance)
+	assert.Equal(t, Error413Title, errResponse.Title)
+}
+
 func assertResponse401(t *testing.T, rec *httptest.ResponseRecorder) {
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 	assert.Equal(t, MimeProblemJSON, rec.Header().Get(HeaderContentType))
@@ -1860,3 +2464,69 @@ func createPaginatedSearchResult(fullResult model.SearchResult, offset, limit in
 		TotalCount:  fullResult.TotalCount,
 	}
 }
+
+func Test_GetEvents(t *testing.T) {
+	hs := mocks.NewHandlerService(t)
+	srv := httptest.NewServer(setupTestHttpHandler(hs))
+	defer srv.Close()
+
+	readEvents := func(t *testing.T, req *http.Request, n int) []string {
+		t.Helper()
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, MimeEventStream, resp.Header.Get(HeaderContentType))
+		var res []string
+		buf := make([]byte, 4096)
+		var data string
+		for len(res) < n {
+			k, err := resp.Body.Read(buf)
+			data += string(buf[:k])
+			if err != nil {
+				break
+			}
+			res = strings.Split(strings.TrimSuffix(data, "\n\n"), "\n\n")
+			if !strings.HasSuffix(data, "\n\n") {
+				res = res[:len(res)-1]
+			}
+		}
+		return res
+	}
+
+	t.Run("stream events", func(t *testing.T) {
+		ch := make(chan events.Event, 3)
+		ch <- events.Event{ID: "5", Type: events.TypeTMImported, Repo: "r1", TMID: "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json", Digest: "a1b2c3d4e5f6"}
+		ch <- events.Event{ID: "6", Type: events.TypeAttachmentDeleted, Repo: "r2", TMName: "a/b/c", Attachment: "README.md"}
+		ch <- events.Event{ID: "7", Type: events.TypeTMDeleted, Repo: "r1", TMID: "a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json", Digest: "a1b2c3d4e5f6"}
+		hs.On("SubscribeEvents", mock.Anything, "4").Return((<-chan events.Event)(ch), func() {}).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/events?repo=r1", nil)
+		req.Header.Set("Last-Event-ID", "4")
+		evs := readEvents(t, req, 2)
+
+		assert.Equal(t, []string{
+			"id: 5\nevent: tm.imported\ndata: " + `{"id":"5","type":"tm.imported","time":"0001-01-01T00:00:00Z","repo":"r1","tmID":"a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json","digest":"a1b2c3d4e5f6"}`,
+			"id: 7\nevent: tm.deleted\ndata: " + `{"id":"7","type":"tm.deleted","time":"0001-01-01T00:00:00Z","repo":"r1","tmID":"a/b/c/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json","digest":"a1b2c3d4e5f6"}`,
+		}, evs)
+	})
+	t.Run("keep alive and end of subscription", func(t *testing.T) {
+		orig := eventsKeepAliveInterval
+		eventsKeepAliveInterval = 10 * time.Millisecond
+		defer func() { eventsKeepAliveInterval = orig }()
+		ch := make(chan events.Event)
+		hs.On("SubscribeEvents", mock.Anything, "").Return((<-chan events.Event)(ch), func() {}).Once()
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(ch)
+		}()
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/events", nil)
+		evs := readEvents(t, req, 100)
+		if assert.NotEmpty(t, evs) {
+			assert.Equal(t, ": keep-alive", evs[0])
+		}
+	})
+}
@@ -12,6 +12,10 @@ type JWTValidationOpts struct {
 	JWKSURLString string
 	ScopesPrefix  string
 	WhitelistFile string
+	// APIKeysFile is the file of API keys accepted as an alternative to JWTs. API keys are disabled if empty
+	APIKeysFile string
+	// APIKeysOnly disables the validation of JWTs, so that a JWKS URL is not needed
+	APIKeysOnly bool
 }
 
 func validateOptions(opts JWTValidationOpts) {
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 
 	httptmc "github.com/wot-oss/tmc/internal/app/http"
@@ -20,16 +21,27 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const HeaderXAPIKey = "X-API-Key"
+
 var jwksKeyFunc jwt.Keyfunc
+var apiKeys *auth.APIKeyStore
 var jwtServiceID string
 var scopesFromWhitelist []string
 var scopesPrefix string
 
 // GetMiddleware starts a go routine that periodically fetches the JWKS
 // key set and returns a middleware that uses that keyset to validate a
-// token
+// token. If opts.APIKeysFile is set, the API keys in that file are accepted as well, or exclusively if
+// opts.APIKeysOnly is set
 func GetMiddleware(opts JWTValidationOpts) server.MiddlewareFunc {
-	jwksKeyFunc = startJWKSFetch(opts).Keyfunc
+	jwksKeyFunc = nil
+	if !opts.APIKeysOnly {
+		jwksKeyFunc = startJWKSFetch(opts).Keyfunc
+	}
+	apiKeys = nil
+	if opts.APIKeysFile != "" {
+		apiKeys = auth.NewAPIKeyStore(opts.APIKeysFile)
+	}
 	jwtServiceID = opts.JWTServiceID
 	if opts.ScopesPrefix != "" {
 		scopesPrefix = opts.ScopesPrefix + "."
@@ -58,33 +70,22 @@ func jwtValidationMiddleware(h http.Handler) http.Handler {
 		if scopes != nil {
 			log := utils.GetLogger(r.Context(), "jwt.validation.middleware").With("authentication", true)
 			log.Debug("jwt: protected endpoint:", "path", r.URL)
-			// protected endpoint, check for bearer tokenString in header
-			tokenString, err := extractBearerToken(r)
-			if err != nil {
-				log.Warn("failed to extract token", "error", err)
-				httptmc.HandleErrorResponse(w, r, httptmc.NewUnauthorizedError(nil, "%v", err.Error()))
-				return
-			}
-			// got token, validate it
-			var token *jwt.Token
-			if token, err = jwt.ParseWithClaims(tokenString, jwt.MapClaims{}, jwksKeyFunc); err != nil {
-				log.Warn("token validation failed", "error", err)
-				httptmc.HandleErrorResponse(w, r, httptmc.NewUnauthorizedError(nil, "%v", err.Error()))
-				return
-			}
-
-			// Validate audience claim
-			if err := validateAudClaim(token); err != nil {
-				log.Warn("audience validation failed", "error", err)
-				httptmc.HandleErrorResponse(w, r, httptmc.NewUnauthorizedError(nil, "%v", err.Error()))
-				return
-			}
-
-			scopes, err := getScopesFromToken(token, nil)
-			if err != nil {
-				log.Warn("failed to get scopes from token", "error", err)
-				httptmc.HandleErrorResponse(w, r, httptmc.NewUnauthorizedError(nil, "%v", err.Error()))
-				return
+			var scopes []string
+			var err error
+			if key, ok := extractAPIKey(r); ok {
+				scopes, err = getScopesFromAPIKey(key)
+				if err != nil {
+					log.Warn("api key validation failed", "error", err)
+					httptmc.HandleErrorResponse(w, r, httptmc.NewUnauthorizedError(nil, "%v", err.Error()))
+					return
+				}
+			} else {
+				scopes, err = getScopesFromBearerToken(r)
+				if err != nil {
+					log.Warn("token validation failed", "error", err)
+					httptmc.HandleErrorResponse(w, r, httptmc.NewUnauthorizedError(nil, "%v", err.Error()))
+					return
+				}
 			}
 			_, err = getAuthStatus(r, scopes)
 			if err != nil {
@@ -97,11 +98,52 @@ func jwtValidationMiddleware(h http.Handler) http.Handler {
 	})
 }
 
+// getScopesFromBearerToken validates the bearer token of the request and returns its scopes
+func getScopesFromBearerToken(r *http.Request) ([]string, error) {
+	if jwksKeyFunc == nil {
+		return nil, APIKeyNotFoundError
+	}
+	// protected endpoint, check for bearer tokenString in header
+	tokenString, err := extractBearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	// got token, validate it
+	token, err := jwt.ParseWithClaims(tokenString, jwt.MapClaims{}, jwksKeyFunc)
+	if err != nil {
+		return nil, err
+	}
+	// Validate audience claim
+	if err := validateAudClaim(token); err != nil {
+		return nil, err
+	}
+	return getScopesFromToken(token, nil)
+}
+
+// getScopesFromAPIKey looks up key among the configured API keys and returns its scopes
+func getScopesFromAPIKey(key string) ([]string, error) {
+	if apiKeys == nil {
+		return nil, ErrAPIKeysDisabled
+	}
+	k, err := apiKeys.Lookup(key)
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	// copy, as scopes from the whitelist are appended to them
+	return slices.Clone(k.Scopes), nil
+}
+
 var extractAuthScopes = func(r *http.Request) any {
 	return r.Context().Value(server.BearerAuthScopes)
 }
 
 var TokenNotFoundError = errors.New("'Authorization' header does not contain a bearer token")
+var APIKeyNotFoundError = errors.New("request contains no api key in the 'Authorization' or 'X-API-Key' header")
+var ErrAPIKeysDisabled = errors.New("api keys are not accepted by this server")
+var ErrInvalidAPIKey = errors.New("invalid api key")
 
 var extractBearerToken = func(r *http.Request) (string, error) {
 	// get header and extract token string
@@ -116,6 +158,17 @@ var extractBearerToken = func(r *http.Request) (string, error) {
 	return token, nil
 }
 
+// extractAPIKey returns the API key from an 'Authorization: ApiKey <key>' or an 'X-API-Key: <key>' header
+func extractAPIKey(r *http.Request) (string, bool) {
+	if key, found := strings.CutPrefix(r.Header.Get("Authorization"), "ApiKey "); found && key != "" {
+		return key, true
+	}
+	if key := r.Header.Get(HeaderXAPIKey); key != "" {
+		return key, true
+	}
+	return "", false
+}
+
 var ErrInvalidAudClaim = errors.New("claim 'aud' did not contain valid service id")
 var ErrToken = errors.New("token fields do not match the expected values")
 
@@ -186,12 +239,8 @@ func getAuthStatus(r *http.Request, scopes []string) (bool, error) {
 			if scope == scopesPrefix+"tmc.admin" {
 				return true, nil
 			}
-			if strings.HasPrefix(scope, scopesPrefix+"tmc.ns.") && strings.HasSuffix(scope, ".read") {
-				parts := strings.Split(scope, ".")
-				if len(parts) >= 4 {
-					namespace := parts[2]
-					allowedNamespaces = append(allowedNamespaces, namespace)
-				}
+			if s, ok, _ := parseNamespaceScope(scope); ok && s.operation == "read" {
+				allowedNamespaces = append(allowedNamespaces, s.namespace)
 			}
 		}
 		if len(allowedNamespaces) > 0 {
@@ -202,6 +251,7 @@ func getAuthStatus(r *http.Request, scopes []string) (bool, error) {
 		}
 	}
 
+	var nsScopes []namespaceScope
 	for _, scope := range scopes {
 		if scope == scopesPrefix+"tmc.admin" {
 			return true, nil
@@ -209,53 +259,154 @@ func getAuthStatus(r *http.Request, scopes []string) (bool, error) {
 		if scope == scopesPrefix+"tmc.repos.read" && pathParts[0] == "repos" && r.Method == "GET" {
 			return true, nil
 		}
-		if scope == scopesPrefix+"tmc.internal.read" && pathParts[0] == "info" && r.Method == "GET" {
+		if scope == scopesPrefix+"tmc.internal.read" && (pathParts[0] == "info" || pathParts[0] == "metrics") && r.Method == "GET" {
 			return true, nil
 		}
 		if (scope == scopesPrefix+"tmc.health.read") && pathParts[0] == "healthz" && r.Method == "GET" {
 			return true, nil
 		}
-		if strings.HasPrefix(scope, scopesPrefix+"tmc.ns.") {
-			parts := strings.Split(scope, ".")
-			if len(parts) >= 4 {
-				namespaceFromScope := parts[2]
-				if namespaceFromPath == "" && r.Method == "POST" && pathParts[0] == "thing-models" {
-					if strings.HasSuffix(scope, ".write") {
-						tmbody, err := io.ReadAll(r.Body)
-						if err != nil {
-							panic(err)
-						}
-						r.Body = io.NopCloser(bytes.NewReader(tmbody))
-						var tm model.ThingModel
-						err = json.Unmarshal(tmbody, &tm)
-						if err != nil {
-							panic(err)
-						}
-						fmt.Println(utils.SanitizeName(tm.Author.Name))
-						if strings.EqualFold(utils.SanitizeName(tm.Author.Name), utils.SanitizeName(namespaceFromScope)) || namespaceFromScope == "*" {
-							fmt.Println(utils.SanitizeName(tm.Author.Name), utils.SanitizeName(namespaceFromScope))
-							r.Body = io.NopCloser(bytes.NewReader(tmbody))
-							return true, nil
-						} else {
-							return false, fmt.Errorf("user cannot import thing models into this namespace: %s", tm.Author.Name)
-						}
-					}
+		s, ok, err := parseNamespaceScope(scope)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			nsScopes = append(nsScopes, s)
+		}
+	}
+	if len(nsScopes) == 0 || (pathParts[0] != "thing-models" && pathParts[0] != "inventory") {
+		return false, fmt.Errorf("user does not have access to this resource")
+	}
+
+	operations := requiredOperations(r.Method, pathParts)
+	if r.Method == "POST" && pathParts[0] == "thing-models" && len(pathParts) == 2 {
+		switch pathParts[1] {
+		case ".validate":
+			// validating does not write anything, so any namespace scope grants it
+			return true, nil
+		case ".bulk":
+			// the namespaces TMs are imported into are given by their authors, which are checked by the handler
+			// against the namespaces put into the context
+			var namespaces []string
+			for _, s := range nsScopes {
+				if !slices.Contains(operations, s.operation) {
+					continue
 				}
-				if (strings.EqualFold(utils.SanitizeName(namespaceFromPath), utils.SanitizeName(namespaceFromScope)) || namespaceFromScope == "*") && (pathParts[0] == "thing-models" || pathParts[0] == "inventory") {
-					if strings.HasSuffix(scope, ".read") && r.Method == "GET" {
-						return true, nil
-					} else if strings.HasSuffix(scope, ".write") && (r.Method == "POST" || r.Method == "PUT") {
-						return true, nil
-					} else if strings.HasSuffix(scope, "attachments.delete") && r.Method == "DELETE" && (pathParts[2] == ".attachments" || pathParts[3] == ".attachments") {
-						return true, nil
-					} else if strings.HasSuffix(scope, "thingmodels.delete") && r.Method == "DELETE" && pathParts[0] == "thing-models" {
-						return true, nil
-					}
+				if s.namespace == "*" {
+					return true, nil
 				}
-			} else {
-				return false, fmt.Errorf("scope '%s' malformed: expected format 'tmc.ns.<namespace>.<operation>'", scope)
+				namespaces = append(namespaces, s.namespace)
+			}
+			if len(namespaces) == 0 {
+				return false, fmt.Errorf("user cannot import thing models into any namespace")
 			}
+			ctx := context.WithValue(r.Context(), httptmc.ContextKeyBearerAuthWriteNamespaces, namespaces)
+			*r = *r.WithContext(ctx)
+			return true, nil
 		}
 	}
+	if r.Method == "POST" && pathParts[0] == "thing-models" && len(pathParts) == 1 {
+		// the namespace a TM is imported into is given by its author
+		author, err := readAuthorFromBody(r)
+		if err != nil {
+			return false, err
+		}
+		if !hasNamespaceScope(nsScopes, author, operations) {
+			return false, fmt.Errorf("user cannot import thing models into this namespace: %s", author)
+		}
+		return true, nil
+	}
+	if hasNamespaceScope(nsScopes, namespaceFromPath, operations) {
+		if pathParts[0] == "thing-models" {
+			setReadNamespaces(r, nsScopes, namespaceFromPath)
+		}
+		return true, nil
+	}
 	return false, fmt.Errorf("user does not have access to this resource")
 }
+
+// setReadNamespaces puts the namespaces the user may read into the context of r, so that the TMs referenced by a
+// requested TM are resolved from these namespaces only. namespace, the namespace of the requested TM, is always
+// included. Nothing is put, if the user may read all namespaces
+func setReadNamespaces(r *http.Request, scopes []namespaceScope, namespace string) {
+	namespaces := []string{namespace}
+	for _, s := range scopes {
+		if s.operation != "read" {
+			continue
+		}
+		if s.namespace == "*" {
+			return
+		}
+		if !slices.Contains(namespaces, s.namespace) {
+			namespaces = append(namespaces, s.namespace)
+		}
+	}
+	ctx := context.WithValue(r.Context(), httptmc.ContextKeyBearerAuthNamespaces, namespaces)
+	*r = *r.WithContext(ctx)
+}
+
+// namespaceScope is a scope of the form 'tmc.ns.<namespace>.<operation>'
+type namespaceScope struct {
+	namespace string
+	operation string
+}
+
+// parseNamespaceScope parses scope into a namespaceScope. Returns false if scope is not a namespace scope at all and an
+// error if it is malformed
+func parseNamespaceScope(scope string) (namespaceScope, bool, error) {
+	s, found := strings.CutPrefix(scope, scopesPrefix+"tmc.ns.")
+	if !found {
+		return namespaceScope{}, false, nil
+	}
+	namespace, operation, found := strings.Cut(s, ".")
+	if !found || namespace == "" || operation == "" {
+		return namespaceScope{}, false, fmt.Errorf("scope '%s' malformed: expected format 'tmc.ns.<namespace>.<operation>'", scope)
+	}
+	return namespaceScope{namespace: namespace, operation: operation}, true, nil
+}
+
+// requiredOperations returns the namespace scope operations, any of which grants the request with given method and path
+func requiredOperations(method string, pathParts []string) []string {
+	switch method {
+	case "GET":
+		return []string{"read"}
+	case "POST", "PUT":
+		return []string{"write"}
+	case "DELETE":
+		if slices.Contains(pathParts, ".attachments") {
+			return []string{"delete", "attachments.delete"}
+		}
+		return []string{"delete", "thingmodels.delete"}
+	}
+	return nil
+}
+
+// hasNamespaceScope reports whether any of scopes grants one of operations on namespace
+func hasNamespaceScope(scopes []namespaceScope, namespace string, operations []string) bool {
+	for _, s := range scopes {
+		if !slices.Contains(operations, s.operation) {
+			continue
+		}
+		if s.namespace == "*" || (namespace != "" && strings.EqualFold(utils.SanitizeName(namespace), utils.SanitizeName(s.namespace))) {
+			return true
+		}
+	}
+	return false
+}
+
+// readAuthorFromBody returns the author name of the ThingModel in the request body, leaving the body intact for the
+// handler
+func readAuthorFromBody(r *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", errors.New("request body is empty")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	var tm model.ThingModel
+	if err := json.Unmarshal(body, &tm); err != nil {
+		return "", fmt.Errorf("cannot determine the author of the thing model: %w", err)
+	}
+	return tm.Author.Name, nil
+}
@@ -5,14 +5,23 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"io"
 	"net/http"
 	httpt "net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	httptmc "github.com/wot-oss/tmc/internal/app/http"
+	"github.com/wot-oss/tmc/internal/app/http/auth"
 	"github.com/wot-oss/tmc/internal/app/http/server"
+	"github.com/wot-oss/tmc/internal/commands"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
 )
 
 func newToken(claims jwt.MapClaims, key *rsa.PrivateKey) string {
@@ -423,6 +432,7 @@ func Test_Authorization_Scopes(t *testing.T) {
 			}{
 				{"GET", "/repos", nil, http.StatusOK, true},
 				{"GET", "/inventory", nil, http.StatusUnauthorized, false},
+				{"GET", "/metrics", nil, http.StatusUnauthorized, false},
 			},
 		},
 		{
@@ -436,6 +446,7 @@ func Test_Authorization_Scopes(t *testing.T) {
 			}{
 				{"GET", "/info/some", nil, http.StatusOK, true},
 				{"GET", "/info/other/more", nil, http.StatusOK, true},
+				{"GET", "/metrics", nil, http.StatusOK, true},
 				{"GET", "/inventory", nil, http.StatusUnauthorized, false},
 			},
 		},
@@ -491,3 +502,372 @@ func Test_Authorization_Scopes(t *testing.T) {
 		}
 	}
 }
+
+func Test_Authorization_NamespaceScopes(t *testing.T) {
+	keyA, _ := rsa.GenerateKey(rand.Reader, 1024)
+	futureDate := time.Now().Add(24 * time.Hour).Unix()
+	pastDate := time.Now().Add(-24 * time.Hour).Unix()
+	jwtServiceID := "some-service-id"
+
+	tmACorp := []byte("{\"schema:author\":{\"schema:name\":\"A-Corp\"}}")
+	tmCCorp := []byte("{\"schema:author\":{\"schema:name\":\"c-corp\"}}")
+	tmIDACorp := "/thing-models/a-corp/eagle/bt2000/v1.0.0-20240108140117-243d1b462ccc.tm.json"
+	tmIDBCorp := "/thing-models/b-corp/frog/bt3000/v1.0.0-20240108140117-743d1b462uuu.tm.json"
+
+	type request struct {
+		method, endpoint string
+		body             []byte
+		expectedStatus   int
+		authorized       bool
+	}
+	tests := []struct {
+		name     string
+		prefix   string
+		scope    []string
+		requests []request
+	}{
+		{
+			name:  "ns read",
+			scope: []string{"tmc.ns.a-corp.read"},
+			requests: []request{
+				{"GET", tmIDACorp, nil, http.StatusOK, true},
+				{"GET", tmIDACorp + "/.attachments/README.md", nil, http.StatusOK, true},
+				{"GET", "/thing-models/.tmName/a-corp/eagle/bt2000/.attachments/README.md", nil, http.StatusOK, true},
+				{"GET", tmIDBCorp + "/.attachments/README.md", nil, http.StatusUnauthorized, false},
+				{"GET", "/thing-models/.tmName/b-corp/frog/bt3000/.attachments/README.md", nil, http.StatusUnauthorized, false},
+				{"PUT", tmIDACorp + "/.attachments/README.md", []byte("content"), http.StatusUnauthorized, false},
+				{"DELETE", tmIDACorp, nil, http.StatusUnauthorized, false},
+				{"POST", "/thing-models/.validate", tmCCorp, http.StatusOK, true},
+				{"POST", "/thing-models/.bulk", []byte("content"), http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:  "ns write in several namespaces",
+			scope: []string{"tmc.ns.b-corp.write", "tmc.ns.a-corp.write"},
+			requests: []request{
+				{"POST", "/thing-models", tmACorp, http.StatusOK, true},
+				{"POST", "/thing-models", tmCCorp, http.StatusUnauthorized, false},
+				{"POST", "/thing-models", []byte("not a thing model"), http.StatusUnauthorized, false},
+				{"PUT", tmIDACorp + "/.attachments/README.md", []byte("content"), http.StatusOK, true},
+				{"PUT", "/thing-models/.tmName/b-corp/frog/bt3000/.attachments/README.md", []byte("content"), http.StatusOK, true},
+				{"PUT", "/thing-models/.tmName/c-corp/frog/bt3000/.attachments/README.md", []byte("content"), http.StatusUnauthorized, false},
+				{"POST", "/thing-models/.bulk", []byte("content"), http.StatusOK, true},
+				{"POST", "/thing-models/.validate", tmCCorp, http.StatusOK, true},
+				{"GET", tmIDACorp, nil, http.StatusUnauthorized, false},
+				{"DELETE", tmIDACorp, nil, http.StatusUnauthorized, false},
+				{"DELETE", tmIDACorp + "/.attachments/README.md", nil, http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:  "ns delete",
+			scope: []string{"tmc.ns.a-corp.delete"},
+			requests: []request{
+				{"DELETE", tmIDACorp, nil, http.StatusOK, true},
+				{"DELETE", tmIDACorp + "/.attachments/README.md", nil, http.StatusOK, true},
+				{"DELETE", "/thing-models/.tmName/a-corp/eagle/bt2000/.attachments/README.md", nil, http.StatusOK, true},
+				{"DELETE", tmIDBCorp, nil, http.StatusUnauthorized, false},
+				{"DELETE", tmIDBCorp + "/.attachments/README.md", nil, http.StatusUnauthorized, false},
+				{"GET", tmIDACorp, nil, http.StatusUnauthorized, false},
+				{"POST", "/thing-models", tmACorp, http.StatusUnauthorized, false},
+				{"POST", "/thing-models/.bulk", []byte("content"), http.StatusUnauthorized, false},
+				{"POST", "/thing-models/.validate", tmACorp, http.StatusOK, true},
+			},
+		},
+		{
+			name:  "ns attachments.delete does not delete thing models",
+			scope: []string{"tmc.ns.a-corp.attachments.delete"},
+			requests: []request{
+				{"DELETE", tmIDACorp + "/.attachments/README.md", nil, http.StatusOK, true},
+				{"DELETE", tmIDACorp, nil, http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:  "ns thingmodels.delete does not delete attachments",
+			scope: []string{"tmc.ns.a-corp.thingmodels.delete"},
+			requests: []request{
+				{"DELETE", tmIDACorp, nil, http.StatusOK, true},
+				{"DELETE", tmIDACorp + "/.attachments/README.md", nil, http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:  "wildcard write",
+			scope: []string{"tmc.ns.*.write"},
+			requests: []request{
+				{"POST", "/thing-models", tmCCorp, http.StatusOK, true},
+				{"POST", "/thing-models/.bulk", []byte("content"), http.StatusOK, true},
+				{"DELETE", tmIDACorp, nil, http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:  "malformed scope",
+			scope: []string{"tmc.ns.a-corp"},
+			requests: []request{
+				{"GET", tmIDACorp, nil, http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:   "with scopes prefix",
+			prefix: "myprefix.",
+			scope:  []string{"myprefix.tmc.ns.a-corp.read", "tmc.ns.b-corp.read"},
+			requests: []request{
+				{"GET", tmIDACorp, nil, http.StatusOK, true},
+				{"GET", tmIDBCorp, nil, http.StatusUnauthorized, false},
+			},
+		},
+	}
+
+	defer func() { scopesPrefix = "" }()
+	for _, tt := range tests {
+		scopesPrefix = tt.prefix
+		tokenString := newToken(jwt.MapClaims{"aud": jwtServiceID, "nbf": pastDate, "exp": futureDate, "scope": tt.scope}, keyA)
+
+		extractBearerToken = func(r *http.Request) (string, error) {
+			return tokenString, nil
+		}
+
+		jwksKeyFunc = func(*jwt.Token) (any, error) {
+			return &keyA.PublicKey, nil
+		}
+
+		for _, req := range tt.requests {
+			authorized := false
+			var body []byte
+			wrappedHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorized = true
+				body, _ = io.ReadAll(r.Body)
+			})
+			protected := jwtValidationMiddleware(wrappedHandler)
+
+			out := httpt.NewRecorder()
+			httpReq := httpt.NewRequest(req.method, req.endpoint, bytes.NewReader(req.body))
+			httpReq = httpReq.WithContext(context.WithValue(httpReq.Context(), server.BearerAuthScopes, []string{}))
+			protected.ServeHTTP(out, httpReq)
+
+			if out.Result().StatusCode != req.expectedStatus || authorized != req.authorized {
+				t.Fatalf("[%s] Unexpected result for request: %s %s\nExpected status: %d, Authorized: %v\nGot status: %d, Authorized: %v",
+					tt.name, req.method, req.endpoint, req.expectedStatus, req.authorized, out.Result().StatusCode, authorized)
+			}
+			// the body must still be readable by the handler after it has been inspected
+			if authorized && !bytes.Equal(body, req.body) {
+				t.Fatalf("[%s] Request body has not been passed on for request: %s %s", tt.name, req.method, req.endpoint)
+			}
+		}
+	}
+}
+
+func Test_Authorization_BulkImportNamespaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		namespaces []string
+	}{
+		{"restricted to namespaces with write scope", []string{"tmc.ns.a-corp.write", "tmc.ns.b-corp.read", "tmc.ns.c-corp.write"}, []string{"a-corp", "c-corp"}},
+		{"not restricted with wildcard write scope", []string{"tmc.ns.a-corp.write", "tmc.ns.*.write"}, nil},
+		{"not restricted with admin scope", []string{"tmc.admin"}, nil},
+	}
+	for _, tt := range tests {
+		r := httpt.NewRequest(http.MethodPost, "/thing-models/.bulk", nil)
+		ok, err := getAuthStatus(r, tt.scopes)
+		if !ok || err != nil {
+			t.Fatalf("[%s] bulk import not authorized: %v", tt.name, err)
+		}
+		namespaces, _ := r.Context().Value(httptmc.ContextKeyBearerAuthWriteNamespaces).([]string)
+		if !slices.Equal(namespaces, tt.namespaces) {
+			t.Fatalf("[%s] unexpected namespaces in context: %v, expected: %v", tt.name, namespaces, tt.namespaces)
+		}
+	}
+}
+
+func Test_Authorization_APIKeys(t *testing.T) {
+	keyA, _ := rsa.GenerateKey(rand.Reader, 1024)
+	file := filepath.Join(t.TempDir(), "apikeys.json")
+	apiKeyACorp, _, _ := auth.CreateAPIKey(file, "a-corp", []string{"tmc.ns.a-corp.read"})
+	apiKeyAdmin, _, _ := auth.CreateAPIKey(file, "admin", []string{"tmc.admin"})
+	tokenACorp := newToken(jwt.MapClaims{
+		"aud":   "some-service-id",
+		"nbf":   time.Now().Add(-24 * time.Hour).Unix(),
+		"exp":   time.Now().Add(24 * time.Hour).Unix(),
+		"scope": []string{"tmc.ns.a-corp.read"},
+	}, keyA)
+	tmIDACorp := "/thing-models/a-corp/eagle/bt2000/v1.0.0-20240108140117-243d1b462ccc.tm.json"
+	tmIDBCorp := "/thing-models/b-corp/frog/bt3000/v1.0.0-20240108140117-743d1b462uuu.tm.json"
+
+	defer func() { apiKeys = nil }()
+	// other tests replace the extraction of the bearer token
+	extractBearerToken = func(r *http.Request) (string, error) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found {
+			return "", TokenNotFoundError
+		}
+		return token, nil
+	}
+	jwtServiceID = "some-service-id"
+
+	type request struct {
+		endpoint       string
+		header, value  string
+		expectedStatus int
+		authorized     bool
+	}
+	tests := []struct {
+		name     string
+		keysFile string
+		jwt      bool
+		requests []request
+	}{
+		{
+			name:     "api keys only",
+			keysFile: file,
+			requests: []request{
+				{tmIDACorp, "Authorization", "ApiKey " + apiKeyACorp, http.StatusOK, true},
+				{tmIDACorp, HeaderXAPIKey, apiKeyACorp, http.StatusOK, true},
+				{tmIDBCorp, HeaderXAPIKey, apiKeyACorp, http.StatusUnauthorized, false},
+				{tmIDBCorp, HeaderXAPIKey, apiKeyAdmin, http.StatusOK, true},
+				{tmIDACorp, HeaderXAPIKey, apiKeyACorp + "x", http.StatusUnauthorized, false},
+				{tmIDACorp, "Authorization", "Bearer " + tokenACorp, http.StatusUnauthorized, false},
+				{tmIDACorp, "", "", http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name:     "api keys and jwt",
+			keysFile: file,
+			jwt:      true,
+			requests: []request{
+				{tmIDACorp, HeaderXAPIKey, apiKeyACorp, http.StatusOK, true},
+				{tmIDACorp, "Authorization", "Bearer " + tokenACorp, http.StatusOK, true},
+				{tmIDBCorp, "Authorization", "Bearer " + tokenACorp, http.StatusUnauthorized, false},
+			},
+		},
+		{
+			name: "jwt only",
+			jwt:  true,
+			requests: []request{
+				{tmIDACorp, HeaderXAPIKey, apiKeyAdmin, http.StatusUnauthorized, false},
+				{tmIDACorp, "Authorization", "Bearer " + tokenACorp, http.StatusOK, true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		apiKeys = nil
+		if tt.keysFile != "" {
+			apiKeys = auth.NewAPIKeyStore(tt.keysFile)
+		}
+		jwksKeyFunc = nil
+		if tt.jwt {
+			jwksKeyFunc = func(*jwt.Token) (any, error) {
+				return &keyA.PublicKey, nil
+			}
+		}
+
+		for _, req := range tt.requests {
+			authorized := false
+			wrappedHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorized = true
+			})
+			protected := jwtValidationMiddleware(wrappedHandler)
+
+			out := httpt.NewRecorder()
+			httpReq := httpt.NewRequest("GET", req.endpoint, nil)
+			if req.header != "" {
+				httpReq.Header.Set(req.header, req.value)
+			}
+			httpReq = httpReq.WithContext(context.WithValue(httpReq.Context(), server.BearerAuthScopes, []string{}))
+			protected.ServeHTTP(out, httpReq)
+
+			if out.Result().StatusCode != req.expectedStatus || authorized != req.authorized {
+				t.Fatalf("[%s] Unexpected result for request: GET %s with %s\nExpected status: %d, Authorized: %v\nGot status: %d, Authorized: %v",
+					tt.name, req.endpoint, req.header, req.expectedStatus, req.authorized, out.Result().StatusCode, authorized)
+			}
+		}
+	}
+}
+
+func Test_Authorization_ResolveNamespaces(t *testing.T) {
+	const (
+		baseId   = "a-corp/eagle/base/v1.0.0-20240108140117-aaaaaaaaaaaa.tm.json"
+		childId  = "a-corp/eagle/child/v1.0.0-20240108140117-bbbbbbbbbbbb.tm.json"
+		spyId    = "a-corp/eagle/spy/v1.0.0-20240108140117-cccccccccccc.tm.json"
+		secretId = "b-corp/frog/secret/v1.0.0-20240108140117-dddddddddddd.tm.json"
+	)
+	tm := func(id, links string) string {
+		parts := strings.Split(id, "/")
+		return `{
+  "@context": ["https://www.w3.org/2022/wot/td/v1.1"],
+  "@type": "tm:ThingModel",
+  "id": "` + id + `",
+  "title": "` + parts[2] + `",
+  "schema:author": {"schema:name": "` + parts[0] + `"},
+  "schema:manufacturer": {"schema:name": "` + parts[1] + `"},
+  "schema:mpn": "` + parts[2] + `",
+  "version": {"model": "1.0.0"},
+  "links": [` + links + `],
+  "properties": {"` + parts[2] + `": {"type": "string"}}
+}`
+	}
+	dir := t.TempDir()
+	for id, content := range map[string]string{
+		baseId:   tm(baseId, ""),
+		childId:  tm(childId, `{"rel": "tm:extends", "href": "a-corp/eagle/base"}`),
+		spyId:    tm(spyId, `{"rel": "tm:extends", "href": "b-corp/frog/secret"}`),
+		secretId: tm(secretId, ""),
+	} {
+		p := filepath.Join(dir, id)
+		if err := os.MkdirAll(filepath.Dir(p), 0770); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0660); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := model.NewDirSpec(dir)
+	r, err := repos.Get(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Index(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	hs, _ := httptmc.NewDefaultHandlerService(spec)
+	handler := httptmc.NewHttpHandler(httptmc.NewTmcHandler(hs, httptmc.TmcHandlerOptions{JWTValidation: true}),
+		[]server.MiddlewareFunc{jwtValidationMiddleware})
+
+	keyA, _ := rsa.GenerateKey(rand.Reader, 1024)
+	jwksKeyFunc = func(*jwt.Token) (any, error) {
+		return &keyA.PublicKey, nil
+	}
+	get := func(scope []string, id string) *httpt.ResponseRecorder {
+		tokenString := newToken(jwt.MapClaims{"aud": jwtServiceID, "nbf": time.Now().Add(-time.Hour).Unix(), "exp": time.Now().Add(time.Hour).Unix(), "scope": scope}, keyA)
+		extractBearerToken = func(r *http.Request) (string, error) {
+			return tokenString, nil
+		}
+		out := httpt.NewRecorder()
+		handler.ServeHTTP(out, httpt.NewRequest(http.MethodGet, "/thing-models/"+id+"?resolve=true", nil))
+		return out
+	}
+
+	t.Run("reference within the namespace", func(t *testing.T) {
+		out := get([]string{"tmc.ns.a-corp.read"}, childId)
+		if out.Code != http.StatusOK || !strings.Contains(out.Body.String(), `"base"`) {
+			t.Fatalf("unexpected response: %d %s", out.Code, out.Body.String())
+		}
+	})
+	t.Run("reference outside the namespace", func(t *testing.T) {
+		out := get([]string{"tmc.ns.a-corp.read"}, spyId)
+		if out.Code != http.StatusUnprocessableEntity || strings.Contains(out.Body.String(), `"secret"`) || !strings.Contains(out.Body.String(), string(commands.ResolveErrForbidden)) {
+			t.Fatalf("unexpected response: %d %s", out.Code, out.Body.String())
+		}
+	})
+	t.Run("reference into another readable namespace", func(t *testing.T) {
+		out := get([]string{"tmc.ns.a-corp.read", "tmc.ns.b-corp.read"}, spyId)
+		if out.Code != http.StatusOK || !strings.Contains(out.Body.String(), `"secret"`) {
+			t.Fatalf("unexpected response: %d %s", out.Code, out.Body.String())
+		}
+	})
+	t.Run("reference with wildcard read scope", func(t *testing.T) {
+		out := get([]string{"tmc.ns.*.read"}, spyId)
+		if out.Code != http.StatusOK || !strings.Contains(out.Body.String(), `"secret"`) {
+			t.Fatalf("unexpected response: %d %s", out.Code, out.Body.String())
+		}
+	})
+}
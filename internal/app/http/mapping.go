@@ -66,6 +66,9 @@ func (m *Mapper) GetInventoryEntry(entry model.FoundEntry) server.InventoryEntry
 	if atts != nil {
 		invEntry.Attachments = &atts
 	}
+	if len(entry.Labels) > 0 {
+		invEntry.Labels = &entry.Labels
+	}
 
 	return invEntry
 }
@@ -89,6 +92,7 @@ func (m *Mapper) GetInventoryEntryVersion(version model.FoundVersion) server.Inv
 	invVersion.Description = version.Description
 	invVersion.Timestamp = version.TimeStamp
 	invVersion.Digest = version.Digest
+	invVersion.Lifecycle = m.GetLifecycle(version.Lifecycle)
 
 	hrefContent, _ := url.JoinPath(basePathThingModels, version.TMID)
 	hrefContent = resolveRelativeLink(m.Ctx, hrefContent)
@@ -109,6 +113,9 @@ func (m *Mapper) GetInventoryEntryVersion(version model.FoundVersion) server.Inv
 	if len(version.Protocols) > 0 {
 		invVersion.Protocols = &version.Protocols
 	}
+	if len(version.Labels) > 0 {
+		invVersion.Labels = &version.Labels
+	}
 
 	atts := m.GetAttachmentsList(model.NewTMIDAttachmentContainerRef(version.TMID), version.AttachmentContainer, version.FoundIn.RepoName)
 	if atts != nil {
@@ -124,6 +131,22 @@ func (m *Mapper) GetInventoryEntryVersion(version model.FoundVersion) server.Inv
 	return invVersion
 }
 
+func (m *Mapper) GetLifecycle(lc *model.Lifecycle) *server.TMLifecycle {
+	if lc == nil {
+		return nil
+	}
+	res := &server.TMLifecycle{
+		State: server.TMLifecycleState(lc.State),
+	}
+	if lc.Reason != "" {
+		res.Reason = &lc.Reason
+	}
+	if lc.Successor != "" {
+		res.Successor = &lc.Successor
+	}
+	return res
+}
+
 func (m *Mapper) GetAttachmentsList(ref model.AttachmentContainerRef, container model.AttachmentContainer, foundInRepo string) server.AttachmentsList {
 	var attList server.AttachmentsList
 	for _, v := range container.Attachments {
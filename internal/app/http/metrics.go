@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/wot-oss/tmc/internal/metrics"
+)
+
+const metricsPath = "/metrics"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled HTTP requests per operationId and status code.",
+	}, []string{"operation", "code"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of handled HTTP requests per operationId.",
+	}, []string{"operation"})
+	exportJobState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "export",
+		Name:      "job_state",
+		Help:      "State of the catalog export job. The gauge of the current state is 1, all others are 0.",
+	}, []string{"state"})
+
+	exportJobStates = []string{"idle", "pending", "packing", "completed", "failed"}
+)
+
+func init() {
+	metrics.MustRegister(httpRequests, httpRequestDuration, exportJobState)
+	setExportJobState("idle")
+}
+
+// setExportJobState sets the gauge of state to 1 and the gauges of all other export job states to 0
+func setExportJobState(state string) {
+	for _, s := range exportJobStates {
+		v := 0.0
+		if s == state {
+			v = 1
+		}
+		exportJobState.WithLabelValues(s).Set(v)
+	}
+}
+
+// nameRoutesByOperation names each route of r after the operationId of the generated handler function it is served by,
+// e.g. "getInventory" for ServerInterfaceWrapper.GetInventory
+func nameRoutesByOperation(r *mux.Router) {
+	_ = r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		h := route.GetHandler()
+		if h == nil || route.GetName() != "" {
+			return nil
+		}
+		v := reflect.ValueOf(h)
+		if v.Kind() != reflect.Func {
+			return nil
+		}
+		fn := runtime.FuncForPC(v.Pointer())
+		if fn == nil {
+			return nil
+		}
+		name := fn.Name()
+		name = strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "-fm")
+		first, size := utf8.DecodeRuneInString(name)
+		route.Name(string(unicode.ToLower(first)) + name[size:])
+		return nil
+	})
+}
+
+// withMetrics is a mux middleware which records the number and the duration of requests to named routes, labelled
+// with the route name
+func withMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil || route.GetName() == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		op := route.GetName()
+		rec := &statusRecorder{ResponseWriter: w}
+		start := time.Now()
+		defer func() {
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			httpRequests.WithLabelValues(op, strconv.Itoa(rec.status)).Inc()
+			httpRequestDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// statusRecorder remembers the status code written to the wrapped http.ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to access the wrapped http.ResponseWriter, e.g. for flushing event streams
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/app/http/mocks"
+	"github.com/wot-oss/tmc/internal/app/http/server"
+	"github.com/wot-oss/tmc/internal/testutils"
+)
+
+func Test_metrics(t *testing.T) {
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+
+	t.Run("counts requests per operationId", func(t *testing.T) {
+		ok := testutil.ToFloat64(httpRequests.WithLabelValues("getHealthLive", "204"))
+		failed := testutil.ToFloat64(httpRequests.WithLabelValues("getHealthLive", "503"))
+		hs.On("CheckHealthLive", mock.Anything).Return(nil).Twice()
+		hs.On("CheckHealthLive", mock.Anything).Return(unknownErr).Once()
+
+		testutils.NewRequest(http.MethodGet, "/healthz/live").RunOnHandler(httpHandler)
+		testutils.NewRequest(http.MethodGet, "/healthz/live").RunOnHandler(httpHandler)
+		testutils.NewRequest(http.MethodGet, "/healthz/live").RunOnHandler(httpHandler)
+
+		assert.Equal(t, ok+2, testutil.ToFloat64(httpRequests.WithLabelValues("getHealthLive", "204")))
+		assert.Equal(t, failed+1, testutil.ToFloat64(httpRequests.WithLabelValues("getHealthLive", "503")))
+	})
+
+	t.Run("names routes after operationIds", func(t *testing.T) {
+		hs.On("GetTMMetadata", mock.Anything, "", "author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json").Return(nil, unknownErr).Once()
+		before := testutil.ToFloat64(httpRequests.WithLabelValues("getInventoryByID", "500"))
+
+		testutils.NewRequest(http.MethodGet, "/inventory/author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json").RunOnHandler(httpHandler)
+
+		assert.Equal(t, before+1, testutil.ToFloat64(httpRequests.WithLabelValues("getInventoryByID", "500")))
+	})
+
+	t.Run("exposes metrics", func(t *testing.T) {
+		rec := testutils.NewRequest(http.MethodGet, "/metrics").RunOnHandler(httpHandler)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		body, _ := io.ReadAll(rec.Body)
+		assert.Contains(t, string(body), `tmc_http_requests_total{code="204",operation="getHealthLive"}`)
+		assert.Contains(t, string(body), `tmc_http_request_duration_seconds_count{operation="getHealthLive"}`)
+		assert.Contains(t, string(body), `tmc_export_job_state{state="idle"}`)
+		assert.NotContains(t, string(body), `operation="metrics"`)
+	})
+
+	t.Run("protects metrics with middlewares", func(t *testing.T) {
+		// a middleware like the one of JWT validation, which rejects all requests to protected endpoints
+		reject := func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Context().Value(server.BearerAuthScopes) != nil {
+					HandleErrorResponse(w, r, NewUnauthorizedError(nil, "rejected"))
+					return
+				}
+				h.ServeHTTP(w, r)
+			})
+		}
+		protected := NewHttpHandler(NewTmcHandler(hs, TmcHandlerOptions{}), []server.MiddlewareFunc{reject})
+
+		rec := testutils.NewRequest(http.MethodGet, "/metrics").RunOnHandler(protected)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func Test_exportJobStateMetric(t *testing.T) {
+	jm := NewJobManager()
+
+	jm.TryAcquireExportingLock()
+	assert.Equal(t, 1.0, testutil.ToFloat64(exportJobState.WithLabelValues("pending")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(exportJobState.WithLabelValues("idle")))
+
+	jm.UpdateJobStatus("packing", "")
+	assert.Equal(t, 1.0, testutil.ToFloat64(exportJobState.WithLabelValues("packing")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(exportJobState.WithLabelValues("pending")))
+
+	jm.MarkJobFailed("boom")
+	assert.Equal(t, 1.0, testutil.ToFloat64(exportJobState.WithLabelValues("failed")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(exportJobState.WithLabelValues("packing")))
+}
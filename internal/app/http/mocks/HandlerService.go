@@ -3,8 +3,14 @@
 package mocks
 
 import (
+	commands "github.com/wot-oss/tmc/internal/commands"
+
+	events "github.com/wot-oss/tmc/internal/app/http/events"
+
 	context "context"
 
+	fs "io/fs"
+
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/wot-oss/tmc/internal/model"
@@ -89,17 +95,17 @@ func (_m *HandlerService) CheckHealthStartup(ctx context.Context) error {
 	return r0
 }
 
-// DeleteAttachment provides a mock function with given fields: ctx, repo, ref, attachmentFileName
-func (_m *HandlerService) DeleteAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string) error {
-	ret := _m.Called(ctx, repo, ref, attachmentFileName)
+// DeleteAttachment provides a mock function with given fields: ctx, repo, ref, attachmentFileName, ifMatch
+func (_m *HandlerService) DeleteAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, ifMatch string) error {
+	ret := _m.Called(ctx, repo, ref, attachmentFileName, ifMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteAttachment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, model.AttachmentContainerRef, string) error); ok {
-		r0 = rf(ctx, repo, ref, attachmentFileName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.AttachmentContainerRef, string, string) error); ok {
+		r0 = rf(ctx, repo, ref, attachmentFileName, ifMatch)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,6 +131,34 @@ func (_m *HandlerService) DeleteThingModel(ctx context.Context, repo string, tmI
 	return r0
 }
 
+// DiffThingModels provides a mock function with given fields: ctx, repo, from, to
+func (_m *HandlerService) DiffThingModels(ctx context.Context, repo string, from string, to string) (commands.TMDiff, error) {
+	ret := _m.Called(ctx, repo, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffThingModels")
+	}
+
+	var r0 commands.TMDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (commands.TMDiff, error)); ok {
+		return rf(ctx, repo, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) commands.TMDiff); ok {
+		r0 = rf(ctx, repo, from, to)
+	} else {
+		r0 = ret.Get(0).(commands.TMDiff)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, repo, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExportCatalog provides a mock function with given fields: ctx, repo
 func (_m *HandlerService) ExportCatalog(ctx context.Context, repo string) ([]byte, error) {
 	ret := _m.Called(ctx, repo)
@@ -186,63 +220,77 @@ func (_m *HandlerService) FetchAttachment(ctx context.Context, repo string, ref
 }
 
 // FetchLatestThingModel provides a mock function with given fields: ctx, repo, fetchName, restoreId
-func (_m *HandlerService) FetchLatestThingModel(ctx context.Context, repo string, fetchName string, restoreId bool) ([]byte, error) {
+func (_m *HandlerService) FetchLatestThingModel(ctx context.Context, repo string, fetchName string, restoreId bool) (string, []byte, error) {
 	ret := _m.Called(ctx, repo, fetchName, restoreId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FetchLatestThingModel")
 	}
 
-	var r0 []byte
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) ([]byte, error)); ok {
+	var r0 string
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (string, []byte, error)); ok {
 		return rf(ctx, repo, fetchName, restoreId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) []byte); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) string); ok {
 		r0 = rf(ctx, repo, fetchName, restoreId)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) []byte); ok {
 		r1 = rf(ctx, repo, fetchName, restoreId)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, bool) error); ok {
+		r2 = rf(ctx, repo, fetchName, restoreId)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// FetchThingModel provides a mock function with given fields: ctx, repo, tmID, restoreId
-func (_m *HandlerService) FetchThingModel(ctx context.Context, repo string, tmID string, restoreId bool) ([]byte, error) {
-	ret := _m.Called(ctx, repo, tmID, restoreId)
+// FetchThingModel provides a mock function with given fields: ctx, repo, tmID, restoreId, resolve, namespaces
+func (_m *HandlerService) FetchThingModel(ctx context.Context, repo string, tmID string, restoreId bool, resolve bool, namespaces []string) (string, []byte, error) {
+	ret := _m.Called(ctx, repo, tmID, restoreId, resolve, namespaces)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FetchThingModel")
 	}
 
-	var r0 []byte
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) ([]byte, error)); ok {
-		return rf(ctx, repo, tmID, restoreId)
+	var r0 string
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, bool, []string) (string, []byte, error)); ok {
+		return rf(ctx, repo, tmID, restoreId, resolve, namespaces)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) []byte); ok {
-		r0 = rf(ctx, repo, tmID, restoreId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, bool, []string) string); ok {
+		r0 = rf(ctx, repo, tmID, restoreId, resolve, namespaces)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool, bool, []string) []byte); ok {
+		r1 = rf(ctx, repo, tmID, restoreId, resolve, namespaces)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
-		r1 = rf(ctx, repo, tmID, restoreId)
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, bool, bool, []string) error); ok {
+		r2 = rf(ctx, repo, tmID, restoreId, resolve, namespaces)
 	} else {
-		r1 = ret.Error(1)
+		r2 = ret.Error(2)
 	}
 
-	return r0, r1
+	return r0, r1, r2
 }
 
 // FindInventoryEntries provides a mock function with given fields: ctx, repo, name
@@ -363,17 +411,17 @@ func (_m *HandlerService) GetTMMetadata(ctx context.Context, repo string, tmID s
 	return r0, r1
 }
 
-// ImportAttachment provides a mock function with given fields: ctx, repo, ref, attachmentFileName, content, contentType, force
-func (_m *HandlerService) ImportAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, content []byte, contentType string, force bool) error {
-	ret := _m.Called(ctx, repo, ref, attachmentFileName, content, contentType, force)
+// ImportAttachment provides a mock function with given fields: ctx, repo, ref, attachmentFileName, content, contentType, force, ifMatch
+func (_m *HandlerService) ImportAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, content []byte, contentType string, force bool, ifMatch string) error {
+	ret := _m.Called(ctx, repo, ref, attachmentFileName, content, contentType, force, ifMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ImportAttachment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, model.AttachmentContainerRef, string, []byte, string, bool) error); ok {
-		r0 = rf(ctx, repo, ref, attachmentFileName, content, contentType, force)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.AttachmentContainerRef, string, []byte, string, bool, string) error); ok {
+		r0 = rf(ctx, repo, ref, attachmentFileName, content, contentType, force, ifMatch)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -409,6 +457,66 @@ func (_m *HandlerService) ImportThingModel(ctx context.Context, repo string, fil
 	return r0, r1
 }
 
+// ImportThingModels provides a mock function with given fields: ctx, repo, fsys, optTree, opts
+func (_m *HandlerService) ImportThingModels(ctx context.Context, repo string, fsys fs.FS, optTree bool, opts repos.ImportOptions) ([]repos.ImportResult, error) {
+	ret := _m.Called(ctx, repo, fsys, optTree, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportThingModels")
+	}
+
+	var r0 []repos.ImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, fs.FS, bool, repos.ImportOptions) ([]repos.ImportResult, error)); ok {
+		return rf(ctx, repo, fsys, optTree, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, fs.FS, bool, repos.ImportOptions) []repos.ImportResult); ok {
+		r0 = rf(ctx, repo, fsys, optTree, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repos.ImportResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, fs.FS, bool, repos.ImportOptions) error); ok {
+		r1 = rf(ctx, repo, fsys, optTree, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InstantiateThingModel provides a mock function with given fields: ctx, repo, tmID, opts
+func (_m *HandlerService) InstantiateThingModel(ctx context.Context, repo string, tmID string, opts commands.InstantiateOptions) ([]byte, error) {
+	ret := _m.Called(ctx, repo, tmID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InstantiateThingModel")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, commands.InstantiateOptions) ([]byte, error)); ok {
+		return rf(ctx, repo, tmID, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, commands.InstantiateOptions) []byte); ok {
+		r0 = rf(ctx, repo, tmID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, commands.InstantiateOptions) error); ok {
+		r1 = rf(ctx, repo, tmID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListAuthors provides a mock function with given fields: ctx, filters
 func (_m *HandlerService) ListAuthors(ctx context.Context, filters *model.Filters) ([]string, error) {
 	ret := _m.Called(ctx, filters)
@@ -559,6 +667,34 @@ func (_m *HandlerService) ListRepos(ctx context.Context) ([]model.RepoDescriptio
 	return r0, r1
 }
 
+// ResolveMovedTMName provides a mock function with given fields: ctx, repo, name
+func (_m *HandlerService) ResolveMovedTMName(ctx context.Context, repo string, name string) (string, error) {
+	ret := _m.Called(ctx, repo, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveMovedTMName")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, repo, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, repo, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, repo, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SearchInventory provides a mock function with given fields: ctx, repo, query, offset, limit
 func (_m *HandlerService) SearchInventory(ctx context.Context, repo string, query string, offset int, limit int) (*model.SearchResult, error) {
 	ret := _m.Called(ctx, repo, query, offset, limit)
@@ -589,6 +725,84 @@ func (_m *HandlerService) SearchInventory(ctx context.Context, repo string, quer
 	return r0, r1
 }
 
+// SetThingModelLifecycle provides a mock function with given fields: ctx, repo, tmID, lc
+func (_m *HandlerService) SetThingModelLifecycle(ctx context.Context, repo string, tmID string, lc model.Lifecycle) error {
+	ret := _m.Called(ctx, repo, tmID, lc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetThingModelLifecycle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Lifecycle) error); ok {
+		r0 = rf(ctx, repo, tmID, lc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubscribeEvents provides a mock function with given fields: ctx, lastEventID
+func (_m *HandlerService) SubscribeEvents(ctx context.Context, lastEventID string) (<-chan events.Event, func()) {
+	ret := _m.Called(ctx, lastEventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeEvents")
+	}
+
+	var r0 <-chan events.Event
+	var r1 func()
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan events.Event, func())); ok {
+		return rf(ctx, lastEventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan events.Event); ok {
+		r0 = rf(ctx, lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan events.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) func()); ok {
+		r1 = rf(ctx, lastEventID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// ValidateThingModel provides a mock function with given fields: ctx, repo, file, optPath
+func (_m *HandlerService) ValidateThingModel(ctx context.Context, repo string, file []byte, optPath string) (commands.ValidationResult, error) {
+	ret := _m.Called(ctx, repo, file, optPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateThingModel")
+	}
+
+	var r0 commands.ValidationResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, string) (commands.ValidationResult, error)); ok {
+		return rf(ctx, repo, file, optPath)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, string) commands.ValidationResult); ok {
+		r0 = rf(ctx, repo, file, optPath)
+	} else {
+		r0 = ret.Get(0).(commands.ValidationResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte, string) error); ok {
+		r1 = rf(ctx, repo, file, optPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewHandlerService creates a new instance of HandlerService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewHandlerService(t interface {
@@ -1,10 +1,12 @@
 package http
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"github.com/wot-oss/tmc/internal/app/http/server"
+	"github.com/wot-oss/tmc/internal/metrics"
 )
 
 // READ ME !!!
@@ -26,7 +28,23 @@ func NewHttpHandler(si server.ServerInterface, mws []server.MiddlewareFunc) http
 		ErrorHandlerFunc: HandleErrorResponse,
 		Middlewares:      mws,
 	}
-	return server.HandlerWithOptions(si, options)
+	h := server.HandlerWithOptions(si, options)
+	nameRoutesByOperation(r)
+	r.Handle(metricsPath, withMiddlewares(metrics.Handler(), mws)).Methods(http.MethodGet)
+	r.Use(withTracing, withMetrics)
+	return h
+}
+
+// withMiddlewares wraps h, which is not served by a generated route, in mws the same way as the generated routes are
+// wrapped, so that it is logged and protected by authentication like them
+func withMiddlewares(h http.Handler, mws []server.MiddlewareFunc) http.Handler {
+	for _, mw := range mws {
+		h = mw(h)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), server.BearerAuthScopes, []string{})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 func handleNoRoute(w http.ResponseWriter, r *http.Request) {
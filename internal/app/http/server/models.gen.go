@@ -3,6 +3,10 @@
 // Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT EDIT.
 package server
 
+import (
+	"time"
+)
+
 const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
@@ -13,6 +17,42 @@ const (
 	Names      GetCompletionsParamsKind = "names"
 )
 
+// Defines values for GetThingModelDiffParamsFormat.
+const (
+	Json      GetThingModelDiffParamsFormat = "json"
+	Jsonpatch GetThingModelDiffParamsFormat = "jsonpatch"
+)
+
+// Defines values for JSONPatchOperationOp.
+const (
+	Add     JSONPatchOperationOp = "add"
+	Remove  JSONPatchOperationOp = "remove"
+	Replace JSONPatchOperationOp = "replace"
+)
+
+// Defines values for TMChangeKind.
+const (
+	Action   TMChangeKind = "action"
+	Event    TMChangeKind = "event"
+	Form     TMChangeKind = "form"
+	Metadata TMChangeKind = "metadata"
+	Property TMChangeKind = "property"
+)
+
+// Defines values for TMChangeType.
+const (
+	Added    TMChangeType = "added"
+	Modified TMChangeType = "modified"
+	Removed  TMChangeType = "removed"
+)
+
+// Defines values for TMLifecycleState.
+const (
+	Active     TMLifecycleState = "active"
+	Deprecated TMLifecycleState = "deprecated"
+	Yanked     TMLifecycleState = "yanked"
+)
+
 // AttachmentLinks defines model for AttachmentLinks.
 type AttachmentLinks struct {
 	Content string `json:"content"`
@@ -33,6 +73,41 @@ type AuthorsResponse struct {
 	Data []string `json:"data"`
 }
 
+// BulkImportResponse defines model for BulkImportResponse.
+type BulkImportResponse struct {
+	Data []BulkImportResult `json:"data"`
+}
+
+// BulkImportResult defines model for BulkImportResult.
+type BulkImportResult struct {
+	Code    *string `json:"code,omitempty"`
+	Message string  `json:"message"`
+	TmID    *string `json:"tmID,omitempty"`
+
+	// Type outcome of the import of the file: OK, warning or error
+	Type string `json:"type"`
+}
+
+// CatalogEvent defines model for CatalogEvent.
+type CatalogEvent struct {
+	Attachment *string `json:"attachment,omitempty"`
+	Digest     *string `json:"digest,omitempty"`
+
+	// Id Opaque id of the event, unique across restarts of the server
+	Id   string    `json:"id"`
+	Repo *string   `json:"repo,omitempty"`
+	Time time.Time `json:"time"`
+
+	// TmID Id of the imported or deleted TM, or of the TM the attachment belongs to
+	TmID *string `json:"tmID,omitempty"`
+
+	// TmName Name of the TM the attachment belongs to, if it is attached to a TM name
+	TmName *string `json:"tmName,omitempty"`
+
+	// Type One of 'tm.imported', 'tm.deleted', 'attachment.imported', 'attachment.deleted'
+	Type string `json:"type"`
+}
+
 // ErrorResponse RFC 7807 compliant error response with additional 'code' field in case of conflicting TM.
 type ErrorResponse struct {
 	// Code Used only when the received TM already exists, thus a conflict. This will contain the id of the conflicting TM.
@@ -74,10 +149,25 @@ type InfoVersion struct {
 	Specification  *string `json:"specification,omitempty"`
 }
 
+// InstantiateThingModelRequest defines model for InstantiateThingModelRequest.
+type InstantiateThingModelRequest struct {
+	// Base base URI of the Thing Description. The Thing Model's base is kept if not given
+	Base *string `json:"base,omitempty"`
+
+	// Id id of the Thing Description. A random 'urn:uuid:' id is generated if not given
+	Id *string `json:"id,omitempty"`
+
+	// Placeholders values for the placeholders in the Thing Model by placeholder name
+	Placeholders *map[string]interface{} `json:"placeholders,omitempty"`
+}
+
 // InventoryEntry defines model for InventoryEntry.
 type InventoryEntry struct {
-	Attachments *AttachmentsList     `json:"attachments,omitempty"`
-	Links       *InventoryEntryLinks `json:"links,omitempty"`
+	Attachments *AttachmentsList `json:"attachments,omitempty"`
+
+	// Labels Free-form labels used to curate inventory entries and versions
+	Labels *Labels              `json:"labels,omitempty"`
+	Links  *InventoryEntryLinks `json:"links,omitempty"`
 
 	// Repo The name of the source repository where the inventory entry or version resides.
 	// May be left empty when there is only a single repository served by the backend and thus there is not need for
@@ -102,12 +192,16 @@ type InventoryEntryResponse struct {
 
 // InventoryEntryVersion defines model for InventoryEntryVersion.
 type InventoryEntryVersion struct {
-	Attachments *AttachmentsList            `json:"attachments,omitempty"`
-	Description string                      `json:"description"`
-	Digest      string                      `json:"digest"`
-	ExternalID  string                      `json:"externalID"`
-	Links       *InventoryEntryVersionLinks `json:"links,omitempty"`
-	Protocols   *[]string                   `json:"protocols,omitempty"`
+	Attachments *AttachmentsList `json:"attachments,omitempty"`
+	Description string           `json:"description"`
+	Digest      string           `json:"digest"`
+	ExternalID  string           `json:"externalID"`
+
+	// Labels Free-form labels used to curate inventory entries and versions
+	Labels    *Labels                     `json:"labels,omitempty"`
+	Lifecycle *TMLifecycle                `json:"lifecycle,omitempty"`
+	Links     *InventoryEntryVersionLinks `json:"links,omitempty"`
+	Protocols *[]string                   `json:"protocols,omitempty"`
 
 	// Repo The name of the source repository where the inventory entry or version resides.
 	// May be left empty when there is only a single repository served by the backend and thus there is not need for
@@ -135,12 +229,27 @@ type InventoryEntryVersionsResponse struct {
 	Data []InventoryEntryVersion `json:"data"`
 }
 
+// Labels Free-form labels used to curate inventory entries and versions
+type Labels = []string
+
 // InventoryResponse defines model for InventoryResponse.
 type InventoryResponse struct {
 	Data []InventoryEntry `json:"data"`
 	Meta *Meta            `json:"meta,omitempty"`
 }
 
+// JSONPatchOperation defines model for JSONPatchOperation.
+type JSONPatchOperation struct {
+	Op   JSONPatchOperationOp `json:"op"`
+	Path string               `json:"path"`
+
+	// Value the value to add or to replace with
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// JSONPatchOperationOp defines model for JSONPatchOperation.Op.
+type JSONPatchOperationOp string
+
 // ManufacturersResponse defines model for ManufacturersResponse.
 type ManufacturersResponse struct {
 	Data []string `json:"data"`
@@ -210,6 +319,86 @@ type SearchMatch struct {
 // disambiguation. See also '/repos'
 type SourceRepository = string
 
+// TMChange defines model for TMChange.
+type TMChange struct {
+	// Kind kind of the Thing Model element the change belongs to
+	Kind TMChangeKind `json:"kind"`
+
+	// New value in the newer Thing Model
+	New *interface{} `json:"new,omitempty"`
+
+	// Old value in the older Thing Model
+	Old *interface{} `json:"old,omitempty"`
+
+	// Path JSON pointer to the changed element. Points into the older Thing Model for removed elements and into the  newer Thing Model otherwise
+	Path string       `json:"path"`
+	Type TMChangeType `json:"type"`
+}
+
+// TMChangeKind kind of the Thing Model element the change belongs to
+type TMChangeKind string
+
+// TMChangeType defines model for TMChange.Type.
+type TMChangeType string
+
+// TMDiff defines model for TMDiff.
+type TMDiff struct {
+	Changes []TMChange `json:"changes"`
+
+	// From ID of the older Thing Model
+	From string `json:"from"`
+
+	// To ID of the newer Thing Model
+	To string `json:"to"`
+}
+
+// TMDiffResponse defines model for TMDiffResponse.
+type TMDiffResponse struct {
+	Data TMDiff `json:"data"`
+}
+
+// TMLifecycle defines model for TMLifecycle.
+type TMLifecycle struct {
+	// Reason reason for deprecating or yanking the version
+	Reason *string `json:"reason,omitempty"`
+
+	// State lifecycle state of the Thing Model version. Versions without lifecycle metadata are active
+	State TMLifecycleState `json:"state"`
+
+	// Successor ID of the Thing Model version to be used instead
+	Successor *string `json:"successor,omitempty"`
+}
+
+// TMLifecycleState lifecycle state of the Thing Model version. Versions without lifecycle metadata are active
+type TMLifecycleState string
+
+// ValidationFinding defines model for ValidationFinding.
+type ValidationFinding struct {
+	// Keyword JSON schema keyword whose assertion failed
+	Keyword *string `json:"keyword,omitempty"`
+	Message string  `json:"message"`
+
+	// Pointer JSON pointer to the offending part of the TM. Empty for the TM as a whole
+	Pointer string `json:"pointer"`
+
+	// Source what reported the finding: 'json', 'tmc-mandatory', 'tm-schema', a protocol binding or a custom schema or rule of the repository
+	Source string `json:"source"`
+}
+
+// ValidationResponse defines model for ValidationResponse.
+type ValidationResponse struct {
+	Data ValidationResult `json:"data"`
+}
+
+// ValidationResult defines model for ValidationResult.
+type ValidationResult struct {
+	Findings []ValidationFinding `json:"findings"`
+
+	// TmID ID the Thing Model would be imported under. Missing if the TM lacks the fields needed to generate one
+	TmID  *string `json:"tmID,omitempty"`
+	Valid bool    `json:"valid"`
+}
+
 // AttachmentFileName defines model for AttachmentFileName.
 type AttachmentFileName = string
 
@@ -231,6 +420,9 @@ type TMID = string
 // TMName defines model for TMName.
 type TMName = string
 
+// PreconditionFailed RFC 7807 compliant error response with additional 'code' field in case of conflicting TM.
+type PreconditionFailed = ErrorResponse
+
 // UnauthorizedError RFC 7807 compliant error response with additional 'code' field in case of conflicting TM.
 type UnauthorizedError = ErrorResponse
 
@@ -267,6 +459,15 @@ type GetAuthorsParams struct {
 	FilterProtocol *string `form:"filter.protocol,omitempty" json:"filter.protocol,omitempty"`
 }
 
+// GetEventsParams defines parameters for GetEvents.
+type GetEventsParams struct {
+	// Repo Only stream events about changes to the given named repository. See '/repos'
+	Repo *string `form:"repo,omitempty" json:"repo,omitempty"`
+
+	// LastEventID Id of the last event received before reconnecting
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
 // GetInventoryParams defines parameters for GetInventory.
 type GetInventoryParams struct {
 	// Repo Source repository name. Optionally constrains the results to only those from given named repository. See '/repos'
@@ -288,6 +489,10 @@ type GetInventoryParams struct {
 	// The filter works additive to other filters.
 	FilterProtocol *string `form:"filter.protocol,omitempty" json:"filter.protocol,omitempty"`
 
+	// FilterLabel Filters the inventory by one or more labels attached to an inventory entry or to any of its versions.
+	// The filter works additive to other filters.
+	FilterLabel *string `form:"filter.label,omitempty" json:"filter.label,omitempty"`
+
 	// FilterName Filters the inventory by inventory entry name having a prefix match of full path parts.
 	// The filter works additive to other filters.
 	FilterName *string `form:"filter.name,omitempty" json:"filter.name,omitempty"`
@@ -394,6 +599,45 @@ type ImportThingModelParams struct {
 	OptPath *string `form:"optPath,omitempty" json:"optPath,omitempty"`
 }
 
+// ImportThingModelsMultipartBody defines parameters for ImportThingModels.
+type ImportThingModelsMultipartBody = map[string]interface{}
+
+// ImportThingModelsParams defines parameters for ImportThingModels.
+type ImportThingModelsParams struct {
+	// Repo Source/target repository name. The parameter is required when repository is ambiguous. See '/repos'
+	Repo *RepoDisambiguator `form:"repo,omitempty" json:"repo,omitempty"`
+
+	// Force flag to force the import, ignoring any conflicts with existing data
+	Force *ForceImport `form:"force,omitempty" json:"force,omitempty"`
+
+	// OptTree use the directory of each file within the archive as its optional path
+	OptTree *bool `form:"optTree,omitempty" json:"optTree,omitempty"`
+
+	// WithAttachments import the files in '.attachments' directories as attachments of the imported TMs
+	WithAttachments *bool `form:"withAttachments,omitempty" json:"withAttachments,omitempty"`
+
+	// IgnoreExisting do not report TMs which already exist in the repository as errors
+	IgnoreExisting *bool `form:"ignoreExisting,omitempty" json:"ignoreExisting,omitempty"`
+}
+
+// GetThingModelDiffParams defines parameters for GetThingModelDiff.
+type GetThingModelDiffParams struct {
+	// From ID or fetch name of the older Thing Model
+	From string `form:"from" json:"from"`
+
+	// To ID or fetch name of the newer Thing Model
+	To string `form:"to" json:"to"`
+
+	// Format format of the differences. 'json' returns a list of changes, 'jsonpatch' returns a JSON Patch (RFC 6902),  which transforms the older Thing Model into the newer one. Defaults to 'json'
+	Format *GetThingModelDiffParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Repo Source repository name. Optionally constrains the results to only those from given named repository. See '/repos'
+	Repo *RepoConstraint `form:"repo,omitempty" json:"repo,omitempty"`
+}
+
+// GetThingModelDiffParamsFormat defines parameters for GetThingModelDiff.
+type GetThingModelDiffParamsFormat string
+
 // GetThingModelByFetchNameParams defines parameters for GetThingModelByFetchName.
 type GetThingModelByFetchNameParams struct {
 	// Repo Source repository name. Optionally constrains the results to only those from given named repository. See '/repos'
@@ -427,6 +671,18 @@ type PutTMNameAttachmentParams struct {
 	Force *ForceImport `form:"force,omitempty" json:"force,omitempty"`
 }
 
+// ValidateThingModelJSONBody defines parameters for ValidateThingModel.
+type ValidateThingModelJSONBody = map[string]interface{}
+
+// ValidateThingModelParams defines parameters for ValidateThingModel.
+type ValidateThingModelParams struct {
+	// Repo Source/target repository name. The parameter is required when repository is ambiguous. See '/repos'
+	Repo *RepoDisambiguator `form:"repo,omitempty" json:"repo,omitempty"`
+
+	// OptPath optional path parts to append to the target path (and id) of imported TM, after the mandatory path structure
+	OptPath *string `form:"optPath,omitempty" json:"optPath,omitempty"`
+}
+
 // DeleteThingModelByIdParams defines parameters for DeleteThingModelById.
 type DeleteThingModelByIdParams struct {
 	// Repo Source/target repository name. The parameter is required when repository is ambiguous. See '/repos'
@@ -443,6 +699,9 @@ type GetThingModelByIdParams struct {
 
 	// RestoreId restore the TM's original external id, if it had one
 	RestoreId *bool `form:"restoreId,omitempty" json:"restoreId,omitempty"`
+
+	// Resolve resolve all 'tm:extends' links and 'tm:ref' references from the catalog and return a single self-contained Thing Model. Definitions in the requested Thing Model take precedence over referenced ones
+	Resolve *bool `form:"resolve,omitempty" json:"resolve,omitempty"`
 }
 
 // DeleteThingModelAttachmentByNameParams defines parameters for DeleteThingModelAttachmentByName.
@@ -466,5 +725,29 @@ type PutTMIDAttachmentParams struct {
 	Force *ForceImport `form:"force,omitempty" json:"force,omitempty"`
 }
 
+// InstantiateThingModelParams defines parameters for InstantiateThingModel.
+type InstantiateThingModelParams struct {
+	// Repo Source repository name. Optionally constrains the results to only those from given named repository. See '/repos'
+	Repo *RepoConstraint `form:"repo,omitempty" json:"repo,omitempty"`
+}
+
+// SetThingModelLifecycleParams defines parameters for SetThingModelLifecycle.
+type SetThingModelLifecycleParams struct {
+	// Repo Source/target repository name. The parameter is required when repository is ambiguous. See '/repos'
+	Repo *RepoDisambiguator `form:"repo,omitempty" json:"repo,omitempty"`
+}
+
 // ImportThingModelJSONRequestBody defines body for ImportThingModel for application/json ContentType.
 type ImportThingModelJSONRequestBody = ImportThingModelJSONBody
+
+// ImportThingModelsMultipartRequestBody defines body for ImportThingModels for multipart/form-data ContentType.
+type ImportThingModelsMultipartRequestBody = ImportThingModelsMultipartBody
+
+// ValidateThingModelJSONRequestBody defines body for ValidateThingModel for application/json ContentType.
+type ValidateThingModelJSONRequestBody = ValidateThingModelJSONBody
+
+// InstantiateThingModelJSONRequestBody defines body for InstantiateThingModel for application/json ContentType.
+type InstantiateThingModelJSONRequestBody = InstantiateThingModelRequest
+
+// SetThingModelLifecycleJSONRequestBody defines body for SetThingModelLifecycle for application/json ContentType.
+type SetThingModelLifecycleJSONRequestBody = TMLifecycle
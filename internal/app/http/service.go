@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/wot-oss/tmc/internal/app/http/events"
 	"github.com/wot-oss/tmc/internal/commands"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
@@ -22,10 +24,16 @@ type HandlerService interface {
 	ListManufacturers(ctx context.Context, filters *model.Filters) ([]string, error)
 	ListMpns(ctx context.Context, filters *model.Filters) ([]string, error)
 	FindInventoryEntries(ctx context.Context, repo string, name string) ([]model.FoundEntry, error)
-	FetchThingModel(ctx context.Context, repo, tmID string, restoreId bool) ([]byte, error)
-	FetchLatestThingModel(ctx context.Context, repo, fetchName string, restoreId bool) ([]byte, error)
+	ResolveMovedTMName(ctx context.Context, repo string, name string) (string, error)
+	FetchThingModel(ctx context.Context, repo, tmID string, restoreId, resolve bool, namespaces []string) (string, []byte, error)
+	FetchLatestThingModel(ctx context.Context, repo, fetchName string, restoreId bool) (string, []byte, error)
+	InstantiateThingModel(ctx context.Context, repo, tmID string, opts commands.InstantiateOptions) ([]byte, error)
+	DiffThingModels(ctx context.Context, repo, from, to string) (commands.TMDiff, error)
 	ImportThingModel(ctx context.Context, repo string, file []byte, opts repos.ImportOptions) (repos.ImportResult, error)
+	ImportThingModels(ctx context.Context, repo string, fsys fs.FS, optTree bool, opts repos.ImportOptions) ([]repos.ImportResult, error)
+	ValidateThingModel(ctx context.Context, repo string, file []byte, optPath string) (commands.ValidationResult, error)
 	DeleteThingModel(ctx context.Context, repo string, tmID string) error
+	SetThingModelLifecycle(ctx context.Context, repo string, tmID string, lc model.Lifecycle) error
 	ExportCatalog(ctx context.Context, repo string) ([]byte, error)
 	CheckHealth(ctx context.Context) error
 	CheckHealthLive(ctx context.Context) error
@@ -35,22 +43,63 @@ type HandlerService interface {
 	GetTMMetadata(ctx context.Context, repo string, tmID string) ([]model.FoundVersion, error)
 	GetLatestTMMetadata(ctx context.Context, repo string, fetchName string) (model.FoundVersion, error)
 	FetchAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, concat bool) ([]byte, error)
-	ImportAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, content []byte, contentType string, force bool) error
-	DeleteAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string) error
+	ImportAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, content []byte, contentType string, force bool, ifMatch string) error
+	DeleteAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName, ifMatch string) error
 	ListRepos(ctx context.Context) ([]model.RepoDescription, error)
+	SubscribeEvents(ctx context.Context, lastEventID string) (<-chan events.Event, func())
 }
 
 type defaultHandlerService struct {
 	serveRepo model.RepoSpec
+	events    *events.Broker
 }
 
 func NewDefaultHandlerService(servedRepo model.RepoSpec) (*defaultHandlerService, error) {
 	dhs := &defaultHandlerService{
 		serveRepo: servedRepo,
+		events:    events.NewBroker(),
 	}
 	return dhs, nil
 }
 
+// AddEventSink registers a sink, e.g. a webhook, which receives notifications about all changes made to the served repos
+func (dhs *defaultHandlerService) AddEventSink(s events.Sink) {
+	dhs.events.AddSink(s)
+}
+
+// SubscribeEvents returns a channel receiving notifications about all changes made to the served repos from now on,
+// preceded by the retained notifications following the one with lastEventID
+func (dhs *defaultHandlerService) SubscribeEvents(ctx context.Context, lastEventID string) (<-chan events.Event, func()) {
+	return dhs.events.Subscribe(lastEventID)
+}
+
+func (dhs *defaultHandlerService) publishTMEvent(typ events.Type, spec model.RepoSpec, tmID string) {
+	e := events.Event{Type: typ, Repo: eventRepoName(spec), TMID: tmID}
+	if id, err := model.ParseTMID(tmID); err == nil {
+		e.Digest = id.Version.Hash
+	}
+	dhs.events.Publish(e)
+}
+
+func (dhs *defaultHandlerService) publishAttachmentEvent(typ events.Type, spec model.RepoSpec, ref model.AttachmentContainerRef, attachmentFileName string) {
+	e := events.Event{Type: typ, Repo: eventRepoName(spec), TMID: ref.TMID, TMName: ref.TMName, Attachment: attachmentFileName}
+	if id, err := model.ParseTMID(ref.TMID); err == nil {
+		e.Digest = id.Version.Hash
+	}
+	dhs.events.Publish(e)
+}
+
+// eventRepoName returns the name of the repo given by spec as it appears in events.
+// An empty spec is resolved to the only repo being served
+func eventRepoName(spec model.RepoSpec) string {
+	if spec.IsEmpty() {
+		if r, err := repos.Get(spec); err == nil {
+			spec = r.Spec()
+		}
+	}
+	return spec.ToFoundSource().String()
+}
+
 func (dhs *defaultHandlerService) ListInventory(ctx context.Context, repo string, filters *model.Filters, offset, limit int) (*model.SearchResult, error) {
 	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
@@ -183,41 +232,88 @@ func (dhs *defaultHandlerService) FindInventoryEntries(ctx context.Context, repo
 	return res.Entries, nil
 }
 
-func (dhs *defaultHandlerService) FetchThingModel(ctx context.Context, repo string, tmID string, restoreId bool) ([]byte, error) {
+// ResolveMovedTMName returns the name the TM name name has been moved to, or an empty string if it has not been moved
+func (dhs *defaultHandlerService) ResolveMovedTMName(ctx context.Context, repo string, name string) (string, error) {
+	spec, err := dhs.inferTargetRepo(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	return commands.ResolveMovedName(ctx, spec, name)
+}
+
+// FetchThingModel returns the id and the content of the TM version with given id. The returned id may differ from tmID,
+// if tmID has been matched by its digest only or is an alias left behind by a move.
+// namespaces restricts the TMs referenced by a resolved TM, see commands.ResolveTM
+func (dhs *defaultHandlerService) FetchThingModel(ctx context.Context, repo string, tmID string, restoreId, resolve bool, namespaces []string) (string, []byte, error) {
 	_, err := model.ParseTMID(tmID)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	_, data, err, _ := commands.FetchByTMID(ctx, spec, tmID, restoreId)
+	id, data, err, _ := commands.FetchByTMID(ctx, spec, tmID, restoreId)
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+	if resolve {
+		data, err = commands.ResolveTM(ctx, spec, id, data, namespaces)
+		if err != nil {
+			return "", nil, err
+		}
 	}
-	return data, nil
+	return id, data, nil
 }
-func (dhs *defaultHandlerService) FetchLatestThingModel(ctx context.Context, repo string, fetchName string, restoreId bool) ([]byte, error) {
+
+// FetchLatestThingModel returns the id and the content of the latest TM version matching fetchName
+func (dhs *defaultHandlerService) FetchLatestThingModel(ctx context.Context, repo string, fetchName string, restoreId bool) (string, []byte, error) {
 	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	fn, err := model.ParseFetchName(fetchName)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	id, foundIn, err, _ := commands.ResolveFetchName(ctx, spec, fn)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	_, data, err, _ := commands.FetchByTMID(ctx, foundIn, id, restoreId)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, data, nil
+}
+
+func (dhs *defaultHandlerService) InstantiateThingModel(ctx context.Context, repo string, tmID string, opts commands.InstantiateOptions) ([]byte, error) {
+	_, err := model.ParseTMID(tmID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
+
+	id, data, err, _ := commands.FetchByTMID(ctx, spec, tmID, false)
+	if err != nil {
+		return nil, err
+	}
+	return commands.InstantiateTM(ctx, spec, id, data, opts)
+}
+
+func (dhs *defaultHandlerService) DiffThingModels(ctx context.Context, repo, from, to string) (commands.TMDiff, error) {
+	spec, err := dhs.inferTargetRepo(ctx, repo)
+	if err != nil {
+		return commands.TMDiff{}, err
+	}
+
+	diff, err, _ := commands.DiffByTMIDOrName(ctx, spec, from, to)
+	return diff, err
 }
 
 func (dhs *defaultHandlerService) ImportThingModel(ctx context.Context, repoName string, file []byte, opts repos.ImportOptions) (repos.ImportResult, error) {
@@ -239,18 +335,84 @@ func (dhs *defaultHandlerService) ImportThingModel(ctx context.Context, repoName
 		if err != nil {
 			return repos.ImportResultFromError(err)
 		}
+		dhs.publishTMEvent(events.TypeTMImported, repo.Spec(), res.TmID)
+	}
+
+	return res, nil
+}
+
+// ImportThingModels imports all TM files found in fsys and, if requested, their attachments. The results of the
+// single imports are returned even if some of them failed. The returned error is set only if the import as a whole failed
+func (dhs *defaultHandlerService) ImportThingModels(ctx context.Context, repoName string, fsys fs.FS, optTree bool, opts repos.ImportOptions) ([]repos.ImportResult, error) {
+	spec, err := dhs.inferTargetRepo(ctx, repoName)
+	if err != nil {
+		return nil, err
 	}
 
+	repo, err := repos.Get(spec)
+	if err != nil {
+		return nil, err
+	}
+	ar := &attachmentRecordingRepo{Repo: repo}
+	res, err := commands.NewImportCommand(time.Now).ImportFS(ctx, fsys, ar, optTree, opts)
+	for _, r := range res {
+		if r.IsSuccessful() {
+			dhs.publishTMEvent(events.TypeTMImported, repo.Spec(), r.TmID)
+		}
+	}
+	for _, a := range ar.imported {
+		dhs.publishAttachmentEvent(events.TypeAttachmentImported, repo.Spec(), a.ref, a.name)
+	}
+	if err != nil && !slices.ContainsFunc(res, func(r repos.ImportResult) bool { return errors.Is(err, r.Err) }) {
+		return res, err
+	}
 	return res, nil
 }
 
+type importedAttachment struct {
+	ref  model.AttachmentContainerRef
+	name string
+}
+
+// attachmentRecordingRepo records the attachments successfully imported to the wrapped repo, so that their events
+// can be published after the events of the TMs they are attached to
+type attachmentRecordingRepo struct {
+	repos.Repo
+	imported []importedAttachment
+}
+
+func (a *attachmentRecordingRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
+	err := a.Repo.ImportAttachment(ctx, container, attachment, content, force, ifMatch)
+	if err == nil {
+		a.imported = append(a.imported, importedAttachment{ref: container, name: attachment.Name})
+	}
+	return err
+}
+
+func (dhs *defaultHandlerService) ValidateThingModel(ctx context.Context, repoName string, file []byte, optPath string) (commands.ValidationResult, error) {
+	spec, err := dhs.inferTargetRepo(ctx, repoName)
+	if err != nil {
+		return commands.ValidationResult{}, err
+	}
+
+	repo, err := repos.Get(spec)
+	if err != nil {
+		return commands.ValidationResult{}, err
+	}
+	return commands.NewImportCommand(time.Now).Validate(ctx, file, repo, optPath)
+}
+
 func (dhs *defaultHandlerService) DeleteThingModel(ctx context.Context, repo string, tmID string) error {
 	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
 		return err
 	}
 	err = commands.Delete(ctx, spec, tmID)
-	return err
+	if err != nil {
+		return err
+	}
+	dhs.publishTMEvent(events.TypeTMDeleted, spec, tmID)
+	return nil
 }
 
 func (dhs *defaultHandlerService) ExportCatalog(ctx context.Context, repo string) ([]byte, error) {
@@ -342,15 +504,26 @@ func (dhs *defaultHandlerService) FetchAttachment(ctx context.Context, repo stri
 	content, err := commands.AttachmentFetch(ctx, spec, ref, attachmentFileName, concat)
 	return content, err
 }
-func (dhs *defaultHandlerService) DeleteAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string) error {
+func (dhs *defaultHandlerService) DeleteAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName, ifMatch string) error {
 	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
 		return err
 	}
-	err = commands.DeleteAttachment(ctx, spec, ref, attachmentFileName)
-	return err
+	err = commands.DeleteAttachment(ctx, spec, ref, attachmentFileName, ifMatch)
+	if err != nil {
+		return err
+	}
+	dhs.publishAttachmentEvent(events.TypeAttachmentDeleted, spec, ref, attachmentFileName)
+	return nil
+}
+func (dhs *defaultHandlerService) SetThingModelLifecycle(ctx context.Context, repo string, tmID string, lc model.Lifecycle) error {
+	spec, err := dhs.inferTargetRepo(ctx, repo)
+	if err != nil {
+		return err
+	}
+	return commands.SetLifecycle(ctx, spec, tmID, lc)
 }
-func (dhs *defaultHandlerService) ImportAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, content []byte, contentType string, force bool) error {
+func (dhs *defaultHandlerService) ImportAttachment(ctx context.Context, repo string, ref model.AttachmentContainerRef, attachmentFileName string, content []byte, contentType string, force bool, ifMatch string) error {
 	spec, err := dhs.inferTargetRepo(ctx, repo)
 	if err != nil {
 		return err
@@ -358,8 +531,12 @@ func (dhs *defaultHandlerService) ImportAttachment(ctx context.Context, repo str
 	err = commands.ImportAttachment(ctx, spec, ref, model.Attachment{
 		Name:      attachmentFileName,
 		MediaType: contentType,
-	}, content, force)
-	return err
+	}, content, force, ifMatch)
+	if err != nil {
+		return err
+	}
+	dhs.publishAttachmentEvent(events.TypeAttachmentImported, spec, ref, attachmentFileName)
+	return nil
 }
 
 func (dhs *defaultHandlerService) CheckHealth(ctx context.Context) error {
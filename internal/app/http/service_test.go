@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"sort"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/app/http/events"
 	"github.com/wot-oss/tmc/internal/repos/mocks"
 	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
 	"github.com/wot-oss/tmc/internal/utils"
@@ -372,7 +374,7 @@ func TestService_FetchThingModel(t *testing.T) {
 	t.Run("with invalid tmID", func(t *testing.T) {
 		invalidTmID := ""
 		// when: fetching ThingModel
-		res, err := underTest.FetchThingModel(nil, "", invalidTmID, false)
+		_, res, err := underTest.FetchThingModel(nil, "", invalidTmID, false, false, nil)
 		// then: it returns nil result
 		assert.Nil(t, res)
 		// and then: error is ErrInvalidId
@@ -384,7 +386,7 @@ func TestService_FetchThingModel(t *testing.T) {
 		r.On("Fetch", mock.Anything, tmID).Return(tmID, nil, model.ErrTMNotFound).Once()
 		rMocks.MockReposAll(t, rMocks.CreateMockAllFunction(nil, r))
 		// when: fetching ThingModel
-		res, err := underTest.FetchThingModel(context.Background(), "", tmID, false)
+		_, res, err := underTest.FetchThingModel(context.Background(), "", tmID, false, false, nil)
 		// then: it returns nil result
 		assert.Nil(t, res)
 		// and then: error is ErrNotFound
@@ -397,8 +399,9 @@ func TestService_FetchThingModel(t *testing.T) {
 		r.On("Fetch", mock.Anything, tmID).Return(tmID, raw, nil).Once()
 		rMocks.MockReposAll(t, rMocks.CreateMockAllFunction(nil, r))
 		// when: fetching ThingModel
-		res, err := underTest.FetchThingModel(context.Background(), "", tmID, false)
-		// then: it returns the unchanged ThingModel content
+		id, res, err := underTest.FetchThingModel(context.Background(), "", tmID, false, false, nil)
+		// then: it returns the id and the unchanged ThingModel content
+		assert.Equal(t, tmID, id)
 		assert.NotNil(t, res)
 		assert.Equal(t, raw, res)
 		// and then: there is no error
@@ -411,7 +414,7 @@ func TestService_FetchLatestThingModel(t *testing.T) {
 
 	t.Run("with invalid fetch name", func(t *testing.T) {
 		// when: fetching ThingModel
-		res, err := underTest.FetchLatestThingModel(context.Background(), "", "b-corp\\eagle/PM20", false)
+		_, res, err := underTest.FetchLatestThingModel(context.Background(), "", "b-corp\\eagle/PM20", false)
 		// then: it returns nil result
 		assert.Nil(t, res)
 		// and then: error is ErrInvalidFetchName
@@ -420,7 +423,7 @@ func TestService_FetchLatestThingModel(t *testing.T) {
 
 	t.Run("with invalid semantic version", func(t *testing.T) {
 		// when: fetching ThingModel
-		res, err := underTest.FetchLatestThingModel(context.Background(), "", "b-corp/eagle/PM20:v1.", false)
+		_, res, err := underTest.FetchLatestThingModel(context.Background(), "", "b-corp/eagle/PM20:v1.", false)
 		// then: it returns nil result
 		assert.Nil(t, res)
 		// and then: error is ErrInvalidIdOrName
@@ -432,7 +435,7 @@ func TestService_FetchLatestThingModel(t *testing.T) {
 		r.On("Versions", mock.Anything, fn).Return(nil, nil).Once()
 		rMocks.MockReposAll(t, rMocks.CreateMockAllFunction(nil, r))
 		// when: fetching ThingModel
-		res, err := underTest.FetchLatestThingModel(context.Background(), "", fn, false)
+		_, res, err := underTest.FetchLatestThingModel(context.Background(), "", fn, false)
 		// then: it returns nil result
 		assert.Nil(t, res)
 		// and then: error is ErrTMNameNotFound
@@ -449,8 +452,9 @@ func TestService_FetchLatestThingModel(t *testing.T) {
 		rMocks.MockReposAll(t, rMocks.CreateMockAllFunction(nil, r))
 		rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, model.NewRepoSpec("someRepo"), r, nil))
 		// when: fetching ThingModel
-		res, err := underTest.FetchLatestThingModel(context.Background(), "", fn, false)
-		// then: it returns the unchanged ThingModel content
+		id, res, err := underTest.FetchLatestThingModel(context.Background(), "", fn, false)
+		// then: it returns the id and the unchanged ThingModel content
+		assert.Equal(t, tmID, id)
 		assert.NotNil(t, res)
 		assert.Equal(t, raw, res)
 		// and then: there is no error
@@ -510,17 +514,28 @@ func TestService_GetLatestTMMetadata(t *testing.T) {
 func Test_DeleteThingModel(t *testing.T) {
 
 	r := mocks.NewRepo(t)
+	r.On("Spec").Return(repo).Maybe()
 	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repo, r, nil))
 	rMocks.MockReposGetDescriptions(t, []model.RepoDescription{{Name: "someRepo"}}, nil)
 	underTest, _ := NewDefaultHandlerService(model.EmptySpec)
+	evs, cancel := underTest.SubscribeEvents(context.Background(), "")
+	defer cancel()
 
 	t.Run("without errors", func(t *testing.T) {
-		tmid := "some-id"
+		tmid := "author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"
 		r.On("Delete", mock.Anything, tmid).Return(nil).Once()
 		// when: deleting ThingModel
 		err := underTest.DeleteThingModel(context.Background(), "", tmid)
 		// then: it returns nil result
 		assert.NoError(t, err)
+		// and then: an event is published
+		if assert.Len(t, evs, 1) {
+			e := <-evs
+			assert.Equal(t, events.TypeTMDeleted, e.Type)
+			assert.Equal(t, "someRepo", e.Repo)
+			assert.Equal(t, tmid, e.TMID)
+			assert.Equal(t, "a1b2c3d4e5f6", e.Digest)
+		}
 	})
 
 	t.Run("with error when deleting", func(t *testing.T) {
@@ -530,12 +545,16 @@ func Test_DeleteThingModel(t *testing.T) {
 		err := underTest.DeleteThingModel(context.Background(), "someRepo", tmid)
 		// then: it returns error result
 		assert.ErrorIs(t, err, model.ErrTMNotFound)
+		// and then: no event is published
+		assert.Len(t, evs, 0)
 	})
 
 }
 
 func TestService_ImportThingModel(t *testing.T) {
 	r := mocks.NewRepo(t)
+	r.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+	r.On("Spec").Return(model.NewRepoSpec("r1")).Maybe()
 	underTest, _ := NewDefaultHandlerService(repo)
 
 	t.Run("with validation error", func(t *testing.T) {
@@ -610,6 +629,101 @@ func TestService_ImportThingModel(t *testing.T) {
 	})
 }
 
+func TestService_ImportThingModels(t *testing.T) {
+	r := mocks.NewRepo(t)
+	r.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+	r.On("Versions", mock.Anything, mock.Anything).Return(nil, model.ErrTMNameNotFound).Maybe()
+	r.On("Spec").Return(model.NewRepoSpec("r1")).Maybe()
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repo, r, nil))
+	underTest, _ := NewDefaultHandlerService(repo)
+	_, tmContent, _ := utils.ReadRequiredFile("../../../test/data/import/omnilamp.json")
+	fsys := fstest.MapFS{
+		"invalid.json":  {Data: []byte("invalid content")},
+		"omnilamp.json": {Data: tmContent},
+	}
+
+	t.Run("with partial success", func(t *testing.T) {
+		r.On("Import", mock.Anything, mock.Anything, mock.Anything, repos.ImportOptions{}).Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: "new-id"}, nil).Once()
+		r.On("Index", mock.Anything, "new-id").Return(nil).Once()
+		// when: importing a valid and an invalid file
+		res, err := underTest.ImportThingModels(context.Background(), "", fsys, false, repos.ImportOptions{})
+		// then: there is no error
+		assert.NoError(t, err)
+		// and then: a result is returned for each file
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, repos.ImportResultError, res[0].Type)
+			assert.Equal(t, repos.ImportResultOK, res[1].Type)
+		}
+	})
+	t.Run("with index error", func(t *testing.T) {
+		r.On("Import", mock.Anything, mock.Anything, mock.Anything, repos.ImportOptions{}).Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: "new-id"}, nil).Once()
+		r.On("Index", mock.Anything, "new-id").Return(errors.New("index failed")).Once()
+		// when: importing
+		_, err := underTest.ImportThingModels(context.Background(), "", fsys, false, repos.ImportOptions{})
+		// then: the error is returned
+		assert.ErrorContains(t, err, "index failed")
+	})
+	t.Run("with attachments", func(t *testing.T) {
+		tmID := "omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20240409155220-575dfac219e2.tm.json"
+		fsys := fstest.MapFS{
+			"omnilamp.json":                        {Data: tmContent},
+			".attachments/omnilamp.json/README.md": {Data: []byte("# Omnilamp")},
+			".attachments/omnilamp.json/notes.txt": {Data: []byte("notes")},
+		}
+		opts := repos.ImportOptions{WithAttachments: true}
+		r.On("Import", mock.Anything, mock.Anything, mock.Anything, opts).Return(repos.ImportResult{Type: repos.ImportResultOK, TmID: tmID}, nil).Once()
+		r.On("Index", mock.Anything, tmID).Return(nil).Once()
+		ref := model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnilamp")
+		r.On("ImportAttachment", mock.Anything, ref, model.Attachment{Name: "README.md", MediaType: "text/markdown; charset=utf-8"}, []byte("# Omnilamp"), true, "").Return(nil).Once()
+		r.On("ImportAttachment", mock.Anything, ref, mock.Anything, []byte("notes"), true, "").Return(errors.New("failed")).Once()
+		evs, cancel := underTest.SubscribeEvents(context.Background(), "")
+		defer cancel()
+
+		// when: importing a TM with two attachments, one of which fails to import
+		res, err := underTest.ImportThingModels(context.Background(), "", fsys, false, opts)
+		assert.NoError(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, repos.ImportResultError, res[1].Type)
+		}
+
+		// then: the import of the TM and of the successfully imported attachment are published in this order
+		if assert.Len(t, evs, 2) {
+			e := <-evs
+			assert.Equal(t, events.TypeTMImported, e.Type)
+			assert.Equal(t, tmID, e.TMID)
+			e = <-evs
+			assert.Equal(t, events.Event{ID: e.ID, Time: e.Time, Type: events.TypeAttachmentImported, Repo: "r1", TMName: ref.TMName, Attachment: "README.md"}, e)
+		}
+	})
+}
+
+func TestService_ValidateThingModel(t *testing.T) {
+	r := mocks.NewRepo(t)
+	r.On("ValidationFiles", mock.Anything).Return(map[string][]byte{}, nil).Maybe()
+	r.On("Spec").Return(model.NewRepoSpec("r1")).Maybe()
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repo, r, nil))
+	underTest, _ := NewDefaultHandlerService(repo)
+
+	t.Run("with valid TM", func(t *testing.T) {
+		_, tmContent, _ := utils.ReadRequiredFile("../../../test/data/import/omnilamp.json")
+		res, err := underTest.ValidateThingModel(context.Background(), "", tmContent, "opt")
+		assert.NoError(t, err)
+		assert.True(t, res.Valid)
+		assert.Regexp(t, "^omnicorp-tm-department/omnicorp/omnilamp/opt/v0.0.0-[0-9]{14}-575dfac219e2.tm.json$", res.TMID)
+	})
+	t.Run("with invalid TM", func(t *testing.T) {
+		res, err := underTest.ValidateThingModel(context.Background(), "", []byte("invalid content"), "")
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Len(t, res.Findings, 1)
+	})
+	t.Run("with repo that cannot be found", func(t *testing.T) {
+		rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repo, nil, repos.ErrRepoNotFound))
+		_, err := underTest.ValidateThingModel(context.Background(), "", []byte("{}"), "")
+		assert.ErrorIs(t, err, repos.ErrRepoNotFound)
+	})
+}
+
 func TestService_GetTMMetadata(t *testing.T) {
 	underTest, _ := NewDefaultHandlerService(model.EmptySpec)
 	tmID := "b-corp/eagle/PM20/v1.0.0-20240107123001-234d1b462fff.tm.json"
@@ -901,13 +1015,20 @@ func TestService_ImportAttachment(t *testing.T) {
 	r.On("ImportAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(inventoryName), model.Attachment{
 		Name:      attName,
 		MediaType: "text/markdown",
-	}, attContent, true).Return(nil).Once()
+	}, attContent, true, "").Return(nil).Once()
 	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repo, r, nil))
 	rMocks.MockReposGetDescriptions(t, []model.RepoDescription{{Name: "someRepo"}}, nil)
+	evs, cancel := underTest.SubscribeEvents(context.Background(), "")
+	defer cancel()
 	// when: pushing an attachment
-	err := underTest.ImportAttachment(context.Background(), "someRepo", model.NewTMNameAttachmentContainerRef(inventoryName), attName, attContent, "text/markdown", true)
+	err := underTest.ImportAttachment(context.Background(), "someRepo", model.NewTMNameAttachmentContainerRef(inventoryName), attName, attContent, "text/markdown", true, "")
 	// then: service returns no error
 	assert.NoError(t, err)
+	// and then: an event is published
+	if assert.Len(t, evs, 1) {
+		e := <-evs
+		assert.Equal(t, events.Event{ID: e.ID, Time: e.Time, Type: events.TypeAttachmentImported, Repo: "someRepo", TMName: inventoryName, Attachment: attName}, e)
+	}
 }
 
 func TestService_DeleteAttachment(t *testing.T) {
@@ -916,12 +1037,21 @@ func TestService_DeleteAttachment(t *testing.T) {
 	attName := "README.md"
 	// given: repo returns an attachment
 	r := mocks.NewRepo(t)
-	r.On("DeleteAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(inventoryName), attName).Return(nil).Once()
+	r.On("DeleteAttachment", mock.Anything, model.NewTMNameAttachmentContainerRef(inventoryName), attName, "").Return(nil).Once()
+	r.On("Spec").Return(repo).Once()
 	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, repo, r, nil))
+	evs, cancel := underTest.SubscribeEvents(context.Background(), "")
+	defer cancel()
 	// when: deleting an attachment
-	err := underTest.DeleteAttachment(context.Background(), "", model.NewTMNameAttachmentContainerRef(inventoryName), attName)
+	err := underTest.DeleteAttachment(context.Background(), "", model.NewTMNameAttachmentContainerRef(inventoryName), attName, "")
 	// then: service returns no error
 	assert.NoError(t, err)
+	// and then: an event with the name of the only served repo is published
+	if assert.Len(t, evs, 1) {
+		e := <-evs
+		assert.Equal(t, events.TypeAttachmentDeleted, e.Type)
+		assert.Equal(t, "someRepo", e.Repo)
+	}
 }
 
 func TestService_ListRepos(t *testing.T) {
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/app/http/mocks"
+	"github.com/wot-oss/tmc/internal/testutils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_tracing(t *testing.T) {
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	hs := mocks.NewHandlerService(t)
+	httpHandler := setupTestHttpHandler(hs)
+	stop := testutils.SetupTracing(t)
+
+	var serviceSpan trace.SpanContext
+	hs.On("CheckHealthLive", mock.Anything).Run(func(args mock.Arguments) {
+		serviceSpan = trace.SpanContextFromContext(args.Get(0).(context.Context))
+	}).Return(nil).Once()
+
+	rec := testutils.NewRequest(http.MethodGet, "/healthz/live").
+		WithHeader("traceparent", "00-"+traceID+"-"+parentID+"-01").
+		RunOnHandler(httpHandler)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	spans := stop()
+	if assert.Len(t, spans, 1) {
+		s := spans[0]
+		assert.Equal(t, "getHealthLive", s.Name)
+		assert.Equal(t, traceID, s.SpanContext.TraceID)
+		assert.Equal(t, parentID, s.Parent.SpanID)
+		assert.Equal(t, "/healthz/live", s.Attr("http.route"))
+		assert.Equal(t, float64(http.StatusNoContent), s.Attr("http.response.status_code"))
+		// the service is called within the server span
+		assert.Equal(t, s.SpanContext.TraceID, serviceSpan.TraceID().String())
+		assert.Equal(t, s.SpanContext.SpanID, serviceSpan.SpanID().String())
+	}
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -10,25 +11,39 @@ import (
 	"github.com/wot-oss/tmc/internal/repos"
 )
 
-func ImportAttachment(ctx context.Context, spec model.RepoSpec, ref model.AttachmentContainerRef, att model.Attachment, content []byte, force bool) error {
+// ErrReservedAttachmentName is returned when an attachment with a name reserved for tmc, such as the name of the
+// signature attachment, is to be imported or deleted directly
+var ErrReservedAttachmentName = errors.New("attachment name is reserved")
+
+// ImportAttachment imports the attachment to the container in the repo with given spec. ifMatch is the entity tag
+// the existing attachment is expected to have, if any. See repos.Repo
+func ImportAttachment(ctx context.Context, spec model.RepoSpec, ref model.AttachmentContainerRef, att model.Attachment, content []byte, force bool, ifMatch string) error {
+	sanitizedAttachmentName := strings.ReplaceAll(filepath.ToSlash(filepath.Clean(att.Name)), "/", "-")
+	if sanitizedAttachmentName == model.SignatureAttachmentName {
+		return fmt.Errorf("%w: %s. Use sign command instead", ErrReservedAttachmentName, sanitizedAttachmentName)
+	}
 	repo, err := repos.Get(spec)
 	if err != nil {
 		return err
 	}
 
-	sanitizedAttachmentName := strings.ReplaceAll(filepath.ToSlash(filepath.Clean(att.Name)), "/", "-")
 	sanitizedAtt := model.Attachment{Name: sanitizedAttachmentName, MediaType: att.MediaType}
-	err = repo.ImportAttachment(ctx, ref, sanitizedAtt, content, force)
+	err = repo.ImportAttachment(ctx, ref, sanitizedAtt, content, force, ifMatch)
 	return err
 }
 
-func DeleteAttachment(ctx context.Context, spec model.RepoSpec, ref model.AttachmentContainerRef, attachmentName string) error {
+// DeleteAttachment deletes the attachment from the container in the repo with given spec. ifMatch is handled as in
+// ImportAttachment
+func DeleteAttachment(ctx context.Context, spec model.RepoSpec, ref model.AttachmentContainerRef, attachmentName, ifMatch string) error {
+	if attachmentName == model.SignatureAttachmentName {
+		return fmt.Errorf("%w: %s", ErrReservedAttachmentName, attachmentName)
+	}
 	repo, err := repos.Get(spec)
 	if err != nil {
 		return err
 	}
 
-	err = repo.DeleteAttachment(ctx, ref, attachmentName)
+	err = repo.DeleteAttachment(ctx, ref, attachmentName, ifMatch)
 	return err
 }
 func AttachmentFetch(ctx context.Context, spec model.RepoSpec, ref model.AttachmentContainerRef, attachmentName string, concat bool) ([]byte, error) {
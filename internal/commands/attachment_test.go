@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestImportAndDeleteAttachment(t *testing.T) {
+	spec := model.NewRepoSpec("r1")
+	ref := model.NewTMIDAttachmentContainerRef("author/manufacturer/mpn/v1.0.0-20231205123243-c49617d2e4fc.tm.json")
+	r := mocks.NewRepo(t)
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+
+	t.Run("import", func(t *testing.T) {
+		r.On("ImportAttachment", mock.Anything, ref, model.Attachment{Name: "README.md", MediaType: "text/markdown"}, []byte("# readme"), false, "").Return(nil).Once()
+		err := ImportAttachment(context.Background(), spec, ref, model.Attachment{Name: "README.md", MediaType: "text/markdown"}, []byte("# readme"), false, "")
+		assert.NoError(t, err)
+	})
+	t.Run("delete", func(t *testing.T) {
+		r.On("DeleteAttachment", mock.Anything, ref, "README.md", "").Return(nil).Once()
+		err := DeleteAttachment(context.Background(), spec, ref, "README.md", "")
+		assert.NoError(t, err)
+	})
+	t.Run("signature", func(t *testing.T) {
+		err := ImportAttachment(context.Background(), spec, ref, model.Attachment{Name: model.SignatureAttachmentName}, []byte("sig"), true, "")
+		assert.ErrorIs(t, err, ErrReservedAttachmentName)
+		err = ImportAttachment(context.Background(), spec, ref, model.Attachment{Name: "./" + model.SignatureAttachmentName}, []byte("sig"), true, "")
+		assert.ErrorIs(t, err, ErrReservedAttachmentName)
+		err = DeleteAttachment(context.Background(), spec, ref, model.SignatureAttachmentName, "")
+		assert.ErrorIs(t, err, ErrReservedAttachmentName)
+	})
+}
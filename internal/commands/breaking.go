@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const codeBreakingChanges = "breakingChanges"
+
+// BreakingChange describes a change between two versions of a TM, which may break integrations relying on the older version
+type BreakingChange struct {
+	// Path is the JSON pointer to the changed element in the older TM
+	Path   string
+	Reason string
+}
+
+func (c BreakingChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Path, c.Reason)
+}
+
+// ErrBreakingChanges is returned when a TM version contains breaking changes compared to the previous version,
+// although the major version in 'version.model' has not been increased
+type ErrBreakingChanges struct {
+	// PreviousId is the id of the version, which the TM has been compared with
+	PreviousId string
+	Changes    []BreakingChange
+}
+
+func (e *ErrBreakingChanges) Error() string {
+	var cs []string
+	for _, c := range e.Changes {
+		cs = append(cs, c.String())
+	}
+	return fmt.Sprintf("breaking changes compared to %s without increasing the major version: %s", e.PreviousId, strings.Join(cs, "; "))
+}
+
+func (e *ErrBreakingChanges) Code() string {
+	return codeBreakingChanges
+}
+
+// checkBreakingChanges compares the TM to be imported as id with the most recent version in repo, which is not newer
+// than id. Versions with major version 0 are not checked, because anything may change during initial development.
+// Returns *ErrBreakingChanges if the TM contains breaking changes without a major version increase. Any other errors
+// are logged only, as they must not prevent the import
+func checkBreakingChanges(ctx context.Context, repo repos.Repo, id model.TMID, raw []byte) error {
+	if id.Version.Base.Major() == 0 {
+		return nil
+	}
+	versions, err := repo.Versions(ctx, id.Name)
+	if err != nil {
+		// nothing to compare with
+		utils.GetLogger(ctx, "commands.checkBreakingChanges").Debug("could not list versions", "name", id.Name, "error", err)
+		return nil
+	}
+	prev, ok := findPreviousVersion(versions, id)
+	if !ok || prev.Version.Base.Major() < id.Version.Base.Major() {
+		return nil
+	}
+	_, prevRaw, err := repo.Fetch(ctx, prev.String())
+	if err != nil {
+		// failing to compare must not fail the import
+		utils.GetLogger(ctx, "commands.checkBreakingChanges").Warn("could not fetch previous version", "id", prev.String(), "error", err)
+		return nil
+	}
+	changes, err := FindBreakingChanges(prevRaw, raw)
+	if err != nil {
+		utils.GetLogger(ctx, "commands.checkBreakingChanges").Warn("could not compare with previous version", "id", prev.String(), "error", err)
+		return nil
+	}
+	if len(changes) > 0 {
+		return &ErrBreakingChanges{PreviousId: prev.String(), Changes: changes}
+	}
+	return nil
+}
+
+// findPreviousVersion finds the most recent version which is not newer than id and does not have the same content
+func findPreviousVersion(versions []model.FoundVersion, id model.TMID) (model.TMID, bool) {
+	var prev model.TMID
+	found := false
+	for _, v := range versions {
+		vid, err := model.ParseTMID(v.TMID)
+		if err != nil || vid.Version.Hash == id.Version.Hash || vid.Version.Base.GreaterThan(id.Version.Base) {
+			continue
+		}
+		if !found || vid.Version.Base.GreaterThan(prev.Version.Base) ||
+			(vid.Version.Base.Equal(prev.Version.Base) && vid.Version.Timestamp > prev.Version.Timestamp) {
+			prev, found = vid, true
+		}
+	}
+	return prev, found
+}
+
+// FindBreakingChanges compares two versions of a TM and returns the changes that may break integrations relying on
+// the older version: removed properties, actions or events, changed data types, newly required fields and removed forms
+func FindBreakingChanges(oldRaw, newRaw []byte) ([]BreakingChange, error) {
+	var oldTM, newTM map[string]any
+	err := json.Unmarshal(oldRaw, &oldTM)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(newRaw, &newTM)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []BreakingChange
+	for _, aff := range []string{"properties", "actions", "events"} {
+		oldAffs, newAffs := jsMap(oldTM, aff), jsMap(newTM, aff)
+		for _, name := range sortedKeys(oldAffs) {
+			path := "/" + aff + "/" + escapeJSONPointer(name)
+			oldAff, _ := oldAffs[name].(map[string]any)
+			newAff, ok := newAffs[name].(map[string]any)
+			if !ok {
+				res = append(res, BreakingChange{Path: path, Reason: string(affordanceKinds[aff]) + " removed"})
+				continue
+			}
+			switch aff {
+			case "properties":
+				res = append(res, compareDataSchemas(path, oldAff, newAff)...)
+			case "actions":
+				res = append(res, compareDataSchemas(path+"/input", jsMap(oldAff, "input"), jsMap(newAff, "input"))...)
+				res = append(res, compareDataSchemas(path+"/output", jsMap(oldAff, "output"), jsMap(newAff, "output"))...)
+			case "events":
+				res = append(res, compareDataSchemas(path+"/data", jsMap(oldAff, "data"), jsMap(newAff, "data"))...)
+			}
+			res = append(res, compareForms(path+"/forms", utils.JsGetArray(oldAff, "forms"), utils.JsGetArray(newAff, "forms"))...)
+		}
+	}
+	res = append(res, compareForms("/forms", utils.JsGetArray(oldTM, "forms"), utils.JsGetArray(newTM, "forms"))...)
+	return res, nil
+}
+
+// compareDataSchemas recursively compares the types, required fields and object properties of two data schemas
+func compareDataSchemas(path string, oldS, newS map[string]any) []BreakingChange {
+	if oldS == nil {
+		return nil
+	}
+	if newS == nil {
+		return []BreakingChange{{Path: path, Reason: "data schema removed"}}
+	}
+	var res []BreakingChange
+	oldType, oldHasType := oldS["type"]
+	newType, newHasType := newS["type"]
+	if oldHasType && newHasType && fmt.Sprint(oldType) != fmt.Sprint(newType) {
+		res = append(res, BreakingChange{Path: path + "/type", Reason: fmt.Sprintf("type changed from %v to %v", oldType, newType)})
+	}
+
+	oldReq := utils.JsGetArray(oldS, "required")
+	for _, r := range utils.JsGetArray(newS, "required") {
+		if !slices.Contains(oldReq, r) {
+			res = append(res, BreakingChange{Path: path + "/required", Reason: fmt.Sprintf("field %v newly required", r)})
+		}
+	}
+
+	oldProps, newProps := jsMap(oldS, "properties"), jsMap(newS, "properties")
+	for _, name := range sortedKeys(oldProps) {
+		pPath := path + "/properties/" + escapeJSONPointer(name)
+		oldP, _ := oldProps[name].(map[string]any)
+		newP, ok := newProps[name].(map[string]any)
+		if !ok {
+			res = append(res, BreakingChange{Path: pPath, Reason: "field removed"})
+			continue
+		}
+		res = append(res, compareDataSchemas(pPath, oldP, newP)...)
+	}
+
+	if oldItems, ok := oldS["items"].(map[string]any); ok {
+		newItems, _ := newS["items"].(map[string]any)
+		res = append(res, compareDataSchemas(path+"/items", oldItems, newItems)...)
+	}
+	return res
+}
+
+// compareForms finds forms in oldForms, for which there is no form with the same href and op in newForms
+func compareForms(path string, oldForms, newForms []any) []BreakingChange {
+	var newKeys []string
+	for _, f := range newForms {
+		newKeys = append(newKeys, formKey(f))
+	}
+	var res []BreakingChange
+	for i, f := range oldForms {
+		if !slices.Contains(newKeys, formKey(f)) {
+			fm, _ := f.(map[string]any)
+			href, _ := utils.JsGetString(fm, "href")
+			res = append(res, BreakingChange{Path: fmt.Sprintf("%s/%d", path, i), Reason: fmt.Sprintf("form with href %s removed", href)})
+		}
+	}
+	return res
+}
+
+func formKey(form any) string {
+	fm, _ := form.(map[string]any)
+	href, _ := utils.JsGetString(fm, "href")
+	var ops []string
+	switch op := fm["op"].(type) {
+	case string:
+		ops = []string{op}
+	case []any:
+		for _, o := range op {
+			ops = append(ops, fmt.Sprint(o))
+		}
+	}
+	slices.Sort(ops)
+	return href + " " + strings.Join(ops, ",")
+}
+
+func sortedKeys(m map[string]any) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func escapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func jsMap(js map[string]any, key string) map[string]any {
+	m, _ := utils.JsGetMap(js, key)
+	return m
+}
@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+func TestFindBreakingChanges(t *testing.T) {
+	old := `{
+  "properties": {
+    "status": {"type": "string", "forms": [{"href": "/status", "op": ["readproperty", "observeproperty"]}]},
+    "config": {"type": "object", "properties": {"mode": {"type": "string"}, "level": {"type": "integer"}}, "required": ["mode"]},
+    "history": {"type": "array", "items": {"type": "number"}}
+  },
+  "actions": {
+    "toggle": {"input": {"type": "boolean"}},
+    "reset": {}
+  },
+  "events": {
+    "overheating": {"data": {"type": "string"}}
+  },
+  "forms": [{"href": "/all", "op": "readallproperties"}]
+}`
+
+	tests := []struct {
+		name string
+		new  string
+		exp  []BreakingChange
+	}{
+		{
+			name: "no changes",
+			new:  old,
+			exp:  nil,
+		},
+		{
+			name: "compatible changes",
+			new: `{
+  "properties": {
+    "status": {"type": "string", "description": "new", "forms": [{"href": "/status", "op": ["observeproperty", "readproperty"]}, {"href": "/status2"}]},
+    "config": {"type": "object", "properties": {"mode": {"type": "string"}, "level": {"type": "integer"}, "extra": {"type": "string"}}, "required": ["mode"]},
+    "history": {"type": "array", "items": {"type": "number"}},
+    "newProp": {"type": "string"}
+  },
+  "actions": {
+    "toggle": {"input": {"type": "boolean"}},
+    "reset": {},
+    "newAction": {}
+  },
+  "events": {
+    "overheating": {"data": {"type": "string"}}
+  },
+  "forms": [{"href": "/all", "op": "readallproperties"}]
+}`,
+			exp: nil,
+		},
+		{
+			name: "breaking changes",
+			new: `{
+  "properties": {
+    "status": {"type": "integer", "forms": [{"href": "/status", "op": "readproperty"}]},
+    "config": {"type": "object", "properties": {"mode": {"type": "string"}}, "required": ["mode", "extra"]},
+    "history": {"type": "array", "items": {"type": "string"}}
+  },
+  "actions": {
+    "toggle": {"input": {"type": "string"}}
+  },
+  "events": {
+    "overheating": {}
+  }
+}`,
+			exp: []BreakingChange{
+				{Path: "/properties/config/required", Reason: "field extra newly required"},
+				{Path: "/properties/config/properties/level", Reason: "field removed"},
+				{Path: "/properties/history/items/type", Reason: "type changed from number to string"},
+				{Path: "/properties/status/type", Reason: "type changed from string to integer"},
+				{Path: "/properties/status/forms/0", Reason: "form with href /status removed"},
+				{Path: "/actions/reset", Reason: "action removed"},
+				{Path: "/actions/toggle/input/type", Reason: "type changed from boolean to string"},
+				{Path: "/events/overheating/data", Reason: "data schema removed"},
+				{Path: "/forms/0", Reason: "form with href /all removed"},
+			},
+		},
+		{
+			name: "affordances removed",
+			new: `{
+  "properties": {
+    "status": {"type": "string", "forms": [{"href": "/status", "op": ["readproperty", "observeproperty"]}]},
+    "config": {"type": "object", "properties": {"mode": {"type": "string"}, "level": {"type": "integer"}}, "required": ["mode"]}
+  },
+  "actions": {
+    "toggle": {"input": {"type": "boolean"}},
+    "reset": {}
+  },
+  "forms": [{"href": "/all", "op": "readallproperties"}]
+}`,
+			exp: []BreakingChange{
+				{Path: "/properties/history", Reason: "property removed"},
+				{Path: "/events/overheating", Reason: "event removed"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := FindBreakingChanges([]byte(old), []byte(test.new))
+			assert.NoError(t, err)
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
+
+func TestImportFile_BreakingChanges(t *testing.T) {
+	repo, err := repos.NewFileRepo(map[string]any{
+		"type": "file",
+		"loc":  t.TempDir(),
+	}, model.EmptySpec)
+	require.NoError(t, err)
+	clk := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	c := NewImportCommand(func() time.Time {
+		clk = clk.Add(time.Second)
+		return clk
+	})
+	ctx := context.Background()
+
+	_, raw, err := utils.ReadRequiredFile("../../test/data/import/omnilamp-versioned.json")
+	require.NoError(t, err)
+	res, err := c.ImportFile(ctx, raw, repo, repos.ImportOptions{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Index(ctx, res.TmID))
+	firstId := res.TmID
+
+	breaking := bytes.Replace(raw, []byte(`"type": "string",
+      "readOnly"`), []byte(`"type": "boolean",
+      "readOnly"`), 1)
+
+	t.Run("minor version with breaking changes", func(t *testing.T) {
+		minor := bytes.Replace(breaking, []byte(`"v3.2.1"`), []byte(`"v3.3.0"`), 1)
+		res, err := c.ImportFile(ctx, minor, repo, repos.ImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, repos.ImportResultWarning, res.Type)
+		var bErr *ErrBreakingChanges
+		if assert.True(t, errors.As(res.Err, &bErr)) {
+			assert.Equal(t, firstId, bErr.PreviousId)
+			assert.Equal(t, []BreakingChange{{Path: "/properties/status/type", Reason: "type changed from string to boolean"}}, bErr.Changes)
+			assert.Equal(t, "breakingChanges", bErr.Code())
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		patch := bytes.Replace(breaking, []byte(`"v3.2.1"`), []byte(`"v3.2.2"`), 1)
+		res, err := c.ImportFile(ctx, patch, repo, repos.ImportOptions{BreakingChanges: repos.BreakingChangesReject})
+		var bErr *ErrBreakingChanges
+		assert.ErrorAs(t, err, &bErr)
+		assert.Equal(t, repos.ImportResultError, res.Type)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		patch := bytes.Replace(breaking, []byte(`"v3.2.1"`), []byte(`"v3.2.3"`), 1)
+		res, err := c.ImportFile(ctx, patch, repo, repos.ImportOptions{BreakingChanges: repos.BreakingChangesIgnore})
+		assert.NoError(t, err)
+		assert.Equal(t, repos.ImportResultOK, res.Type)
+	})
+
+	t.Run("major version with breaking changes", func(t *testing.T) {
+		major := bytes.Replace(breaking, []byte(`"v3.2.1"`), []byte(`"v4.0.0"`), 1)
+		res, err := c.ImportFile(ctx, major, repo, repos.ImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, repos.ImportResultOK, res.Type)
+	})
+
+	t.Run("previous version cannot be fetched", func(t *testing.T) {
+		root := t.TempDir()
+		repo, err := repos.NewFileRepo(map[string]any{"type": "file", "loc": root}, model.EmptySpec)
+		require.NoError(t, err)
+		res, err := c.ImportFile(ctx, raw, repo, repos.ImportOptions{})
+		require.NoError(t, err)
+		require.NoError(t, repo.Index(ctx, res.TmID))
+		// the index entry remains, but its file is gone
+		require.NoError(t, os.Remove(filepath.Join(root, res.TmID)))
+
+		minor := bytes.Replace(breaking, []byte(`"v3.2.1"`), []byte(`"v3.4.0"`), 1)
+		res, err = c.ImportFile(ctx, minor, repo, repos.ImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, repos.ImportResultOK, res.Type)
+	})
+}
@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+// ChangeType describes how an element differs between two TMs
+type ChangeType string
+
+const (
+	ChangeAdded    = ChangeType("added")
+	ChangeRemoved  = ChangeType("removed")
+	ChangeModified = ChangeType("modified")
+)
+
+// ElementKind is the kind of TM element a change belongs to
+type ElementKind string
+
+const (
+	ElementMetadata = ElementKind("metadata")
+	ElementProperty = ElementKind("property")
+	ElementAction   = ElementKind("action")
+	ElementEvent    = ElementKind("event")
+	ElementForm     = ElementKind("form")
+)
+
+var affordanceKinds = map[string]ElementKind{
+	"properties": ElementProperty,
+	"actions":    ElementAction,
+	"events":     ElementEvent,
+}
+
+// TMChange is a single difference between two TMs
+type TMChange struct {
+	Type ChangeType  `json:"type"`
+	Kind ElementKind `json:"kind"`
+	// Path is the JSON pointer to the changed element. Points into the older TM for removed elements and into the newer TM otherwise
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// JSONPatchOperation is an operation of a JSON Patch as defined in RFC 6902
+type JSONPatchOperation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	m := map[string]any{"op": o.Op, "path": o.Path}
+	if o.Op != "remove" {
+		// value is mandatory for all other operations, even if it is null
+		m["value"] = o.Value
+	}
+	return json.Marshal(m)
+}
+
+// TMDiff is the result of a structural comparison of two TMs
+type TMDiff struct {
+	// From and To are the ids of the compared TMs, if they have been fetched from the catalog
+	From, To string
+	Changes  []TMChange
+	// Patch is a JSON Patch, which transforms the older TM into the newer one, not taking the order of forms into account
+	Patch []JSONPatchOperation
+}
+
+// DiffByTMIDOrName fetches the TMs given by id or fetch name from the repos given by spec and compares them with DiffTMs
+func DiffByTMIDOrName(ctx context.Context, spec model.RepoSpec, from, to string) (TMDiff, error, []*repos.RepoAccessError) {
+	fromId, fromRaw, err, errs := FetchByTMIDOrName(ctx, spec, from, false)
+	if err != nil {
+		return TMDiff{}, err, errs
+	}
+	toId, toRaw, err, toErrs := FetchByTMIDOrName(ctx, spec, to, false)
+	errs = append(errs, toErrs...)
+	if err != nil {
+		return TMDiff{}, err, errs
+	}
+	diff, err := DiffTMs(fromRaw, toRaw)
+	if err != nil {
+		return TMDiff{}, err, errs
+	}
+	diff.From, diff.To = fromId, toId
+	return diff, nil, errs
+}
+
+// DiffTMs compares two TMs structurally. Properties, actions and events are compared by name, forms by their href and op,
+// so that neither the order of keys nor of forms produce differences. The ids of the TMs are not compared, because
+// they are assigned on import and differ for any two TMs
+func DiffTMs(oldRaw, newRaw []byte) (TMDiff, error) {
+	var oldTM, newTM map[string]any
+	err := json.Unmarshal(oldRaw, &oldTM)
+	if err != nil {
+		return TMDiff{}, err
+	}
+	err = json.Unmarshal(newRaw, &newTM)
+	if err != nil {
+		return TMDiff{}, err
+	}
+	delete(oldTM, "id")
+	delete(newTM, "id")
+
+	d := &tmDiffer{}
+	for _, k := range unionKeys(oldTM, newTM) {
+		path := "/" + escapeJSONPointer(k)
+		ov, oOk := oldTM[k]
+		nv, nOk := newTM[k]
+		kind, isAff := affordanceKinds[k]
+		switch {
+		case isAff && isMapOrMissing(ov, oOk) && isMapOrMissing(nv, nOk):
+			d.diffAffordances(path, kind, ov, oOk, nv, nOk)
+		case k == "forms" && isArrayOrMissing(ov, oOk) && isArrayOrMissing(nv, nOk):
+			d.diffForms(path, ov, oOk, nv, nOk)
+		default:
+			d.diffValues(path, ElementMetadata, ov, oOk, nv, nOk)
+		}
+	}
+	return TMDiff{Changes: d.changes, Patch: d.patch}, nil
+}
+
+type tmDiffer struct {
+	changes []TMChange
+	patch   []JSONPatchOperation
+}
+
+func (d *tmDiffer) addChange(t ChangeType, kind ElementKind, path string, ov, nv any) {
+	d.changes = append(d.changes, TMChange{Type: t, Kind: kind, Path: path, Old: ov, New: nv})
+}
+
+func (d *tmDiffer) addPatch(op, path string, value any) {
+	d.patch = append(d.patch, JSONPatchOperation{Op: op, Path: path, Value: value})
+}
+
+// diffAffordances compares the interaction affordances of one kind. ov and nv are the maps of affordances by name
+func (d *tmDiffer) diffAffordances(path string, kind ElementKind, ov any, oOk bool, nv any, nOk bool) {
+	om, _ := ov.(map[string]any)
+	nm, _ := nv.(map[string]any)
+	switch {
+	case !oOk:
+		d.addPatch("add", path, nv)
+	case !nOk:
+		d.addPatch("remove", path, nil)
+	}
+	for _, name := range unionKeys(om, nm) {
+		aPath := path + "/" + escapeJSONPointer(name)
+		oa, oaOk := om[name]
+		na, naOk := nm[name]
+		switch {
+		case !oaOk:
+			d.addChange(ChangeAdded, kind, aPath, nil, na)
+			if oOk {
+				d.addPatch("add", aPath, na)
+			}
+		case !naOk:
+			d.addChange(ChangeRemoved, kind, aPath, oa, nil)
+			if nOk {
+				d.addPatch("remove", aPath, nil)
+			}
+		default:
+			d.diffAffordance(aPath, kind, oa, na)
+		}
+	}
+}
+
+// diffAffordance compares two versions of a single interaction affordance
+func (d *tmDiffer) diffAffordance(path string, kind ElementKind, oa, na any) {
+	om, oIsMap := oa.(map[string]any)
+	nm, nIsMap := na.(map[string]any)
+	if !oIsMap || !nIsMap {
+		d.diffValues(path, kind, oa, true, na, true)
+		return
+	}
+	for _, k := range unionKeys(om, nm) {
+		ov, oOk := om[k]
+		nv, nOk := nm[k]
+		if k == "forms" && isArrayOrMissing(ov, oOk) && isArrayOrMissing(nv, nOk) {
+			d.diffForms(path+"/forms", ov, oOk, nv, nOk)
+			continue
+		}
+		d.diffValues(path+"/"+escapeJSONPointer(k), kind, ov, oOk, nv, nOk)
+	}
+}
+
+// diffForms compares two arrays of forms, matching forms by their href and op. Because removing and adding single forms
+// by index would depend on the order of operations, the patch replaces the whole array if any of the forms changed
+func (d *tmDiffer) diffForms(path string, ov any, oOk bool, nv any, nOk bool) {
+	oldForms, _ := ov.([]any)
+	newForms, _ := nv.([]any)
+	oldKeys := make([]string, len(oldForms))
+	for i, f := range oldForms {
+		oldKeys[i] = formKey(f)
+	}
+	newKeys := make([]string, len(newForms))
+	for i, f := range newForms {
+		newKeys[i] = formKey(f)
+	}
+
+	changed := false
+	for i, f := range oldForms {
+		if !slices.Contains(newKeys, oldKeys[i]) {
+			d.addChange(ChangeRemoved, ElementForm, jsonPointerIndex(path, i), f, nil)
+			changed = true
+		}
+	}
+	for i, f := range newForms {
+		oi := slices.Index(oldKeys, newKeys[i])
+		switch {
+		case oi < 0:
+			d.addChange(ChangeAdded, ElementForm, jsonPointerIndex(path, i), nil, f)
+			changed = true
+		case !sameForm(oldForms[oi], f):
+			d.addChange(ChangeModified, ElementForm, jsonPointerIndex(path, i), oldForms[oi], f)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	switch {
+	case !oOk:
+		d.addPatch("add", path, nv)
+	case !nOk:
+		d.addPatch("remove", path, nil)
+	default:
+		d.addPatch("replace", path, nv)
+	}
+}
+
+// diffValues compares two arbitrary JSON values. Objects are compared recursively, all other values as a whole
+func (d *tmDiffer) diffValues(path string, kind ElementKind, ov any, oOk bool, nv any, nOk bool) {
+	switch {
+	case !oOk && !nOk:
+		return
+	case !oOk:
+		d.addChange(ChangeAdded, kind, path, nil, nv)
+		d.addPatch("add", path, nv)
+		return
+	case !nOk:
+		d.addChange(ChangeRemoved, kind, path, ov, nil)
+		d.addPatch("remove", path, nil)
+		return
+	}
+	om, oIsMap := ov.(map[string]any)
+	nm, nIsMap := nv.(map[string]any)
+	if oIsMap && nIsMap {
+		for _, k := range unionKeys(om, nm) {
+			ocv, ocOk := om[k]
+			ncv, ncOk := nm[k]
+			d.diffValues(path+"/"+escapeJSONPointer(k), kind, ocv, ocOk, ncv, ncOk)
+		}
+		return
+	}
+	if !reflect.DeepEqual(ov, nv) {
+		d.addChange(ChangeModified, kind, path, ov, nv)
+		d.addPatch("replace", path, nv)
+	}
+}
+
+// sameForm compares two forms with the same key as computed by formKey. The order of operations in op is irrelevant
+func sameForm(a, b any) bool {
+	am, aOk := a.(map[string]any)
+	bm, bOk := b.(map[string]any)
+	if !aOk || !bOk {
+		return reflect.DeepEqual(a, b)
+	}
+	am, bm = maps.Clone(am), maps.Clone(bm)
+	delete(am, "op")
+	delete(bm, "op")
+	return reflect.DeepEqual(am, bm)
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := sortedKeys(a)
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func isMapOrMissing(v any, ok bool) bool {
+	_, isMap := v.(map[string]any)
+	return !ok || isMap
+}
+
+func isArrayOrMissing(v any, ok bool) bool {
+	_, isArray := v.([]any)
+	return !ok || isArray
+}
+
+func jsonPointerIndex(path string, i int) string {
+	return path + "/" + strconv.Itoa(i)
+}
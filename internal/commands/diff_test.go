@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTMs(t *testing.T) {
+	old := `{
+  "id": "omnicorp/lamp/v1.0.0-20240101120000-aaaaaaaaaaaa.tm.json",
+  "title": "Lamp",
+  "version": {"model": "1.0.0"},
+  "properties": {
+    "status": {"type": "string", "forms": [{"href": "/status", "op": ["readproperty", "observeproperty"]}, {"href": "/status", "op": "writeproperty"}]},
+    "dim": {"type": "integer", "minimum": 0}
+  },
+  "actions": {
+    "toggle": {"forms": [{"href": "/toggle"}]}
+  },
+  "forms": [{"href": "/all", "op": "readallproperties"}]
+}`
+
+	t.Run("no differences apart from id and ordering", func(t *testing.T) {
+		new := `{
+  "forms": [{"op": "readallproperties", "href": "/all"}],
+  "actions": {"toggle": {"forms": [{"href": "/toggle"}]}},
+  "properties": {
+    "dim": {"minimum": 0, "type": "integer"},
+    "status": {"forms": [{"href": "/status", "op": "writeproperty"}, {"href": "/status", "op": ["observeproperty", "readproperty"]}], "type": "string"}
+  },
+  "version": {"model": "1.0.0"},
+  "title": "Lamp",
+  "id": "omnicorp/lamp/v1.0.0-20240202120000-bbbbbbbbbbbb.tm.json"
+}`
+		diff, err := DiffTMs([]byte(old), []byte(new))
+		require.NoError(t, err)
+		assert.Empty(t, diff.Changes)
+		assert.Empty(t, diff.Patch)
+	})
+
+	t.Run("affordances, forms and metadata changed", func(t *testing.T) {
+		new := `{
+  "title": "Lamp",
+  "description": "A lamp",
+  "version": {"model": "1.1.0"},
+  "properties": {
+    "status": {"type": "string", "forms": [{"href": "/status", "op": ["readproperty", "observeproperty"], "contentType": "text/plain"}]},
+    "color": {"type": "string"}
+  },
+  "actions": {
+    "toggle": {"forms": [{"href": "/toggle"}, {"href": "/switch"}]}
+  },
+  "events": {
+    "overheating": {}
+  },
+  "forms": [{"href": "/all", "op": "readallproperties"}]
+}`
+		diff, err := DiffTMs([]byte(old), []byte(new))
+		require.NoError(t, err)
+		assert.Equal(t, []TMChange{
+			{Type: ChangeAdded, Kind: ElementForm, Path: "/actions/toggle/forms/1", New: map[string]any{"href": "/switch"}},
+			{Type: ChangeAdded, Kind: ElementMetadata, Path: "/description", New: "A lamp"},
+			{Type: ChangeAdded, Kind: ElementEvent, Path: "/events/overheating", New: map[string]any{}},
+			{Type: ChangeAdded, Kind: ElementProperty, Path: "/properties/color", New: map[string]any{"type": "string"}},
+			{Type: ChangeRemoved, Kind: ElementProperty, Path: "/properties/dim", Old: map[string]any{"type": "integer", "minimum": float64(0)}},
+			{Type: ChangeRemoved, Kind: ElementForm, Path: "/properties/status/forms/1", Old: map[string]any{"href": "/status", "op": "writeproperty"}},
+			{Type: ChangeModified, Kind: ElementForm, Path: "/properties/status/forms/0",
+				Old: map[string]any{"href": "/status", "op": []any{"readproperty", "observeproperty"}},
+				New: map[string]any{"href": "/status", "op": []any{"readproperty", "observeproperty"}, "contentType": "text/plain"}},
+			{Type: ChangeModified, Kind: ElementMetadata, Path: "/version/model", Old: "1.0.0", New: "1.1.0"},
+		}, diff.Changes)
+
+		// applying the patch must yield the new TM
+		var oldTM, newTM map[string]any
+		require.NoError(t, json.Unmarshal([]byte(old), &oldTM))
+		require.NoError(t, json.Unmarshal([]byte(new), &newTM))
+		delete(oldTM, "id")
+		patched := applyPatch(t, oldTM, diff.Patch)
+		assert.Equal(t, newTM, patched)
+	})
+
+	t.Run("patch serialization", func(t *testing.T) {
+		new := `{"title": null}`
+		diff, err := DiffTMs([]byte(`{"title": "Lamp", "description": "x"}`), []byte(new))
+		require.NoError(t, err)
+		b, err := json.Marshal(diff.Patch)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"op": "remove", "path": "/description"}, {"op": "replace", "path": "/title", "value": null}]`, string(b))
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := DiffTMs([]byte(old), []byte(`{"title": `))
+		assert.Error(t, err)
+	})
+}
+
+// applyPatch applies add, remove and replace operations on objects, which is enough for the patches created by DiffTMs
+func applyPatch(t *testing.T, doc map[string]any, patch []JSONPatchOperation) map[string]any {
+	t.Helper()
+	for _, op := range patch {
+		ptr, key := op.Path[:len(op.Path)-len(lastPointerToken(op.Path))-1], lastPointerToken(op.Path)
+		parent, err := evalJSONPointer(doc, ptr)
+		require.NoError(t, err)
+		pm, ok := parent.(map[string]any)
+		require.True(t, ok, "parent of %s is not an object", op.Path)
+		switch op.Op {
+		case "add", "replace":
+			pm[key] = op.Value
+		case "remove":
+			delete(pm, key)
+		}
+	}
+	return doc
+}
+
+func lastPointerToken(ptr string) string {
+	for i := len(ptr) - 1; i >= 0; i-- {
+		if ptr[i] == '/' {
+			return ptr[i+1:]
+		}
+	}
+	return ptr
+}
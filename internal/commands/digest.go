@@ -4,7 +4,7 @@ import (
 	"crypto/sha1"
 	"fmt"
 
-	"github.com/buger/jsonparser"
+	"github.com/wot-oss/tmc/internal/repos"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
@@ -14,14 +14,32 @@ import (
 // and setting 'id' to empty string
 // If the file is not a valid json, the function is not guaranteed to return with an error
 func CalculateFileDigest(raw []byte) (string, []byte, error) {
-	raw = utils.NormalizeLineEndings(raw)
-	fileForHashing, err := jsonparser.Set(raw, []byte("\"\""), "id")
+	fileForHashing, err := utils.PrepareForHashing(raw)
 	if err != nil {
-		return "", raw, err
+		return "", utils.NormalizeLineEndings(raw), err
 	}
+	return hashString(fileForHashing), fileForHashing, nil
+}
+
+// CalculateDigest calculates the hash string for TM version according to the digest mode of a repo.
+// With repos.DigestModeRaw, it is the same as CalculateFileDigest. With repos.DigestModeJCS, the hash is calculated over
+// the canonical form (RFC 8785) of the prepared contents, so that formatting and order of keys do not change it.
+// The returned contents are the prepared contents, not their canonical form
+func CalculateDigest(raw []byte, mode string) (string, []byte, error) {
+	hashStr, prepared, err := CalculateFileDigest(raw)
+	if err != nil || mode != repos.DigestModeJCS {
+		return hashStr, prepared, err
+	}
+	canonical, err := utils.CanonicalizeJSON(prepared)
+	if err != nil {
+		return "", prepared, err
+	}
+	return hashString(canonical), prepared, nil
+}
+
+func hashString(content []byte) string {
 	hasher := sha1.New()
-	hasher.Write(fileForHashing)
+	hasher.Write(content)
 	hash := hasher.Sum(nil)
-	hashStr := fmt.Sprintf("%x", hash[:6])
-	return hashStr, fileForHashing, nil
+	return fmt.Sprintf("%x", hash[:6])
 }
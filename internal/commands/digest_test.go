@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/repos"
 )
 
 func TestCalculateFileDigest(t *testing.T) {
@@ -102,3 +103,33 @@ func TestCalculateFileDigest(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateDigest(t *testing.T) {
+	formatted := []byte("{\n  \"title\": \"test\",\n  \"id\": \"some-id\",\n  \"version\": {\"model\": \"1.0.0\"}\n}")
+	reformatted := []byte("{\"version\":{\"model\":\"1.0.0\"},\r\n\"id\":\"\",\"title\":\"test\"}")
+
+	t.Run("raw", func(t *testing.T) {
+		h1, b1, err := CalculateDigest(formatted, repos.DigestModeRaw)
+		assert.NoError(t, err)
+		h2, _, err := CalculateDigest(reformatted, repos.DigestModeRaw)
+		assert.NoError(t, err)
+		fh, fb, _ := CalculateFileDigest(formatted)
+		assert.Equal(t, fh, h1)
+		assert.Equal(t, fb, b1)
+		assert.NotEqual(t, h1, h2)
+	})
+	t.Run("jcs", func(t *testing.T) {
+		h1, b1, err := CalculateDigest(formatted, repos.DigestModeJCS)
+		assert.NoError(t, err)
+		h2, _, err := CalculateDigest(reformatted, repos.DigestModeJCS)
+		assert.NoError(t, err)
+		assert.Equal(t, h1, h2)
+		assert.Len(t, h1, 12)
+		// the contents are prepared for import, but not canonicalized
+		assert.Equal(t, []byte("{\n  \"title\": \"test\",\n  \"id\": \"\",\n  \"version\": {\"model\": \"1.0.0\"}\n}"), b1)
+	})
+	t.Run("jcs with broken json", func(t *testing.T) {
+		_, _, err := CalculateDigest([]byte("{\"title\":\"test\",}"), repos.DigestModeJCS)
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/buger/jsonparser"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/signing"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
@@ -32,6 +34,15 @@ func FetchByTMID(ctx context.Context, spec model.RepoSpec, tmid string, restoreI
 	}
 
 	fetch, bytes, err, accessErrors := u.Fetch(ctx, tmid)
+	if errors.Is(err, model.ErrTMNotFound) {
+		// a TM refused because of its signature has been found, after all, so report why it has been refused
+		for _, e := range accessErrors {
+			if errors.Is(e, signing.ErrUnsigned) || errors.Is(e, signing.ErrInvalidSignature) {
+				err = e
+				break
+			}
+		}
+	}
 	if err == nil && restoreId {
 		bytes = restoreExternalId(ctx, bytes)
 	}
@@ -124,34 +135,57 @@ func ResolveFetchName(ctx context.Context, spec model.RepoSpec, fn model.FetchNa
 			return id, foundIn, err, errs
 		}
 	} else {
-		if _, err := semver.NewVersion(fn.Semver); err == nil {
-			id, foundIn, err = findMostRecentMatchingVersion(ctx, versions, fn.Semver)
-			if err != nil {
-				return id, foundIn, err, errs
-			}
-		} else {
+		id, foundIn, err = findMostRecentMatchingVersion(ctx, versions, fn.Semver)
+		if err != nil {
 			return id, foundIn, err, errs
 		}
 	}
+	if v := findVersion(versions, id); v != nil && v.State() == model.LifecycleDeprecated {
+		utils.GetLogger(ctx, "commands.ResolveFetchName").Warn("resolved a deprecated TM version", "id", id, "reason", v.Lifecycle.Reason, "successor", v.Lifecycle.Successor)
+	}
 	return id, foundIn, err, errs
 }
 
+func findVersion(versions []model.FoundVersion, id string) *model.FoundVersion {
+	for i, v := range versions {
+		if v.TMID == id {
+			return &versions[i]
+		}
+	}
+	return nil
+}
+
+// findMostRecentVersion finds the most recent version, which has not been yanked
 func findMostRecentVersion(versions []model.FoundVersion) (string, model.RepoSpec, error) {
 	if len(versions) == 0 {
 		return "", model.EmptySpec, fmt.Errorf("%w: no versions found", model.ErrTMNameNotFound)
 	}
+	versions = slices.DeleteFunc(versions, func(v model.FoundVersion) bool {
+		return v.IsYanked()
+	})
+	if len(versions) == 0 {
+		return "", model.EmptySpec, fmt.Errorf("%w: all versions have been yanked", model.ErrTMNotFound)
+	}
 
 	v := versions[0]
 	return v.TMID, model.NewSpecFromFoundSource(v.FoundIn), nil
 }
 
+// findMostRecentMatchingVersion finds the most recent version matching ver, which may be either a full or partial
+// semantic version, or a version range constraint, e.g. ^1.2, ~1.4.0 or >=1.0 <2.0. Yanked versions never match
 func findMostRecentMatchingVersion(ctx context.Context, versions []model.FoundVersion, ver string) (id string, source model.RepoSpec, err error) {
 	ver, _ = strings.CutPrefix(ver, "v")
 
 	// figure out how to match versions with ver
 	var matcher func(*semver.Version) bool
 	dots := strings.Count(ver, ".")
-	if dots == 2 { // ver contains major.minor.patch
+	if _, vErr := semver.NewVersion(ver); vErr != nil { // ver is not a version, so it must be a range
+		c, err := semver.NewConstraint(ver)
+		if err != nil {
+			return "", model.EmptySpec, fmt.Errorf("%w: %s is neither a semantic version nor a version range", model.ErrInvalidFetchName, ver)
+		}
+		matcher = c.Check
+	} else if dots == 2 { // ver contains major.minor.patch
 		sv := semver.MustParse(ver)
 		matcher = sv.Equal
 	} else { // at least one semver part is missing in ver
@@ -162,8 +196,11 @@ func findMostRecentMatchingVersion(ctx context.Context, versions []model.FoundVe
 		matcher = c.Check
 	}
 
-	// delete versions not matching ver from the list
+	// delete yanked versions and versions not matching ver from the list
 	versions = slices.DeleteFunc(versions, func(version model.FoundVersion) bool {
+		if version.IsYanked() {
+			return true
+		}
 		semVersion, err := semver.NewVersion(version.Version.Model)
 		if err != nil {
 			log := utils.GetLogger(ctx, "commands.findMostRecentMatchingVersion")
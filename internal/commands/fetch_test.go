@@ -56,6 +56,14 @@ func TestFetchCommand_FetchByTMIDOrName(t *testing.T) {
 		{"author/manufacturer/mpn:1.2", nil, "", "v1.2.3"},
 		{"author/manufacturer/mpn:3", model.ErrTMNotFound, "no version 3 found", ""},
 		{"author/manufacturer/mpn:v1", nil, "", "v1.2.3"},
+		{"author/manufacturer/mpn:^1.0", nil, "", "v1.2.3"},
+		{"author/manufacturer/mpn:^2", nil, "", "v2.0.0"},
+		{"author/manufacturer/mpn:~1.0.0", nil, "", "v1.0.4"},
+		{"author/manufacturer/mpn:>=1.0 <1.2", nil, "", "v1.0.4"},
+		{"author/manufacturer/mpn:>=1.0.1, <1.2", nil, "", "v1.0.4"},
+		{"author/manufacturer/mpn:1.2.x", nil, "", "v1.2.3"},
+		{"author/manufacturer/mpn:^3", model.ErrTMNotFound, "no version ^3 found", ""},
+		{"author/manufacturer/mpn:>=1.0 <", model.ErrInvalidFetchName, "invalid semantic version or version range", ""},
 		{"author/manufacturer/mpn/folder/sub", nil, "", "v1.0.0"},
 		{"author/manufacturer/mpn/folder/sub:v1.0.0", nil, "", "v1.0.0"},
 		{"author/manufacturer/mpn/folder/sub/v1.0.0-20231205123243-c49617d2e4fc.tm.json", nil, "", "v1.0.0"},
@@ -331,6 +339,55 @@ func TestFetchCommand_FetchByName(t *testing.T) {
 	})
 }
 
+func TestFetchCommand_FetchByName_SkipsYanked(t *testing.T) {
+	r1 := mocks.NewRepo(t)
+	r1Spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, r1Spec, r1, nil))
+	r1.On("Spec").Return(r1Spec).Maybe()
+	idYanked := "author/manufacturer/mpn/v1.1.0-20231006123243-b49617d2e4fc.tm.json"
+	idDeprecated := "author/manufacturer/mpn/v1.0.0-20231005123243-a49617d2e4fc.tm.json"
+	versions := []model.FoundVersion{
+		{
+			IndexVersion: &model.IndexVersion{
+				Version:   model.Version{Model: "1.1.0"},
+				TMID:      idYanked,
+				Lifecycle: &model.Lifecycle{State: model.LifecycleYanked, Reason: "broken"},
+			},
+			FoundIn: model.FoundSource{RepoName: "r1"},
+		},
+		{
+			IndexVersion: &model.IndexVersion{
+				Version:   model.Version{Model: "1.0.0"},
+				TMID:      idDeprecated,
+				Lifecycle: &model.Lifecycle{State: model.LifecycleDeprecated},
+			},
+			FoundIn: model.FoundSource{RepoName: "r1"},
+		},
+	}
+	r1.On("Versions", mock.Anything, "author/manufacturer/mpn").Return(versions, nil)
+	r1.On("Fetch", mock.Anything, idDeprecated).Return(idDeprecated, []byte("{}"), nil)
+
+	t.Run("latest skips yanked", func(t *testing.T) {
+		id, _, err, _ := FetchByName(context.Background(), r1Spec, model.FetchName{Name: "author/manufacturer/mpn"}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, idDeprecated, id)
+	})
+	t.Run("range skips yanked", func(t *testing.T) {
+		id, _, err, _ := FetchByName(context.Background(), r1Spec, model.FetchName{Name: "author/manufacturer/mpn", Semver: ">=1.0"}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, idDeprecated, id)
+	})
+	t.Run("exact yanked version", func(t *testing.T) {
+		_, _, err, _ := FetchByName(context.Background(), r1Spec, model.FetchName{Name: "author/manufacturer/mpn", Semver: "1.1.0"}, false)
+		assert.ErrorIs(t, err, model.ErrTMNotFound)
+	})
+	t.Run("all versions yanked", func(t *testing.T) {
+		_, _, err := findMostRecentVersion(versions[:1])
+		assert.ErrorIs(t, err, model.ErrTMNotFound)
+		assert.ErrorContains(t, err, "yanked")
+	})
+}
+
 func TestFetchCommand_FetchByTMIDOrName_RestoresId(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"mime"
 	"path"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/buger/jsonparser"
@@ -23,34 +27,139 @@ const (
 var ErrTMNameTooLong = fmt.Errorf("TM name too long (max %d allowed)", maxNameLength)
 
 type Now func() time.Time
+
+// ImportCommand imports TMs to repositories. The custom validators of the target repositories are created once per
+// ImportCommand, so an ImportCommand should be used for a single import run, e.g. of a directory or a copy operation
 type ImportCommand struct {
 	now Now
+
+	mu         sync.Mutex
+	validators map[string]*validate.CustomValidator
 }
 
 func NewImportCommand(now Now) *ImportCommand {
 	return &ImportCommand{
-		now: now,
+		now:        now,
+		validators: make(map[string]*validate.CustomValidator),
 	}
 }
 
 // ImportFile prepares file contents for importing (generates id if necessary, etc.) and imports to repo.
 // Returns ImportResult which includes the ID that the TM has been stored under, and error.
-// If the repo already contains the same TM, the error will be an instance of repos.ErrTMIDConflict
+// If the repo already contains the same TM, the error will be an instance of repos.ErrTMIDConflict.
+// If the TM contains breaking changes compared to the previous version without increasing the major version, the
+// result is a warning carrying *ErrBreakingChanges, or an error, depending on opts.BreakingChanges.
+// The TM is validated against the custom schemas and rules of repo, if it has any.
+// The version digest in the generated ID is calculated according to the digest mode configured for repo
 func (c *ImportCommand) ImportFile(ctx context.Context, raw []byte, repo repos.Repo, opts repos.ImportOptions) (repos.ImportResult, error) {
-	tm, err := validate.ValidateThingModel(raw)
+	custom, err := c.customValidator(ctx, repo)
+	if err != nil {
+		return repos.ImportResultFromError(err)
+	}
+	tm, err := validate.ValidateThingModel(raw, custom)
+	if err != nil {
+		return repos.ImportResultFromError(err)
+	}
+	digestMode, err := repos.GetDigestMode(repo.Spec())
 	if err != nil {
 		return repos.ImportResultFromError(err)
 	}
-	prepared, id, err := prepareToImport(ctx, c.now, tm, raw, opts.OptPath)
+	prepared, id, err := prepareToImport(ctx, c.now, tm, raw, opts.OptPath, digestMode)
 	if err != nil {
 		return repos.ImportResultFromError(err)
 	}
 
+	var bcErr *ErrBreakingChanges
+	if opts.BreakingChanges != repos.BreakingChangesIgnore {
+		bErr := checkBreakingChanges(ctx, repo, id, prepared)
+		if bErr != nil && (opts.BreakingChanges == repos.BreakingChangesReject || !errors.As(bErr, &bcErr)) {
+			return repos.ImportResultFromError(bErr)
+		}
+	}
+
 	res, err := repo.Import(ctx, id, prepared, opts)
-	return res, err
+	if err != nil || bcErr == nil {
+		return res, err
+	}
+	switch res.Type {
+	case repos.ImportResultOK:
+		res = repos.ImportResult{Type: repos.ImportResultWarning, TmID: res.TmID, Message: bcErr.Error(), Err: bcErr}
+	case repos.ImportResultWarning:
+		res.Message = res.Message + "; " + bcErr.Error()
+		res.Err = errors.Join(res.Err, bcErr)
+	}
+	return res, nil
+}
+
+// ValidationResult is the outcome of validating a TM for import
+type ValidationResult struct {
+	Valid bool `json:"valid"`
+	// TMID is the id the TM would be imported under. Empty if the TM lacks the fields needed to generate one
+	TMID     string             `json:"tmID,omitempty"`
+	Findings []validate.Finding `json:"findings"`
+}
+
+// Validate performs the same validations of raw as ImportFile, but reports all findings instead of stopping at the
+// first one, and determines the id the TM would be imported under with optPath, without importing it.
+// If repo is nil, the TM is neither validated against custom schemas and rules nor is its id calculated according
+// to a repository's digest mode
+func (c *ImportCommand) Validate(ctx context.Context, raw []byte, repo repos.Repo, optPath string) (ValidationResult, error) {
+	var custom *validate.CustomValidator
+	digestMode := repos.DigestModeRaw
+	if repo != nil {
+		var err error
+		custom, err = c.customValidator(ctx, repo)
+		if err != nil {
+			return ValidationResult{}, err
+		}
+		digestMode, err = repos.GetDigestMode(repo.Spec())
+		if err != nil {
+			return ValidationResult{}, err
+		}
+	}
+
+	res := ValidationResult{Findings: []validate.Finding{}}
+	tm, findings := validate.CheckThingModel(raw, custom)
+	res.Findings = append(res.Findings, findings...)
+	if tm != nil {
+		_, id, err := prepareToImport(ctx, c.now, tm, raw, optPath, digestMode)
+		if err != nil {
+			res.Findings = append(res.Findings, validate.Finding{Source: validate.SourceTmcMandatory, Message: err.Error()})
+		} else {
+			res.TMID = id.String()
+		}
+	}
+	res.Valid = len(res.Findings) == 0
+	return res, nil
+}
+
+// customValidator returns the validator for the custom schemas and rules of repo, creating it on first use.
+// Returns nil if repo has none
+func (c *ImportCommand) customValidator(ctx context.Context, repo repos.Repo) (*validate.CustomValidator, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := repo.Spec().String()
+	if v, ok := c.validators[key]; ok {
+		return v, nil
+	}
+	v, err := CustomValidator(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	c.validators[key] = v
+	return v, nil
+}
+
+// CustomValidator creates a validator for the custom schemas and rules of repo. Returns nil if repo has none
+func CustomValidator(ctx context.Context, repo repos.Repo) (*validate.CustomValidator, error) {
+	files, err := repo.ValidationFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read validation files of repo %s: %w", repo.Spec(), err)
+	}
+	return validate.NewCustomValidator(files)
 }
 
-func prepareToImport(ctx context.Context, now Now, tm *model.ThingModel, raw []byte, optPath string) ([]byte, model.TMID, error) {
+func prepareToImport(ctx context.Context, now Now, tm *model.ThingModel, raw []byte, optPath string, digestMode string) ([]byte, model.TMID, error) {
 	var intermediate = make([]byte, len(raw))
 	copy(intermediate, raw)
 
@@ -77,7 +186,7 @@ func prepareToImport(ctx context.Context, now Now, tm *model.ThingModel, raw []b
 	}
 
 	// generate a new id for the file
-	generatedId, normalized := generateNewId(now, tm, intermediate, optPath)
+	generatedId, normalized := generateNewId(now, tm, intermediate, optPath, digestMode)
 	finalId := idFromFile
 	// overwrite the id from file with the newly generated if idFromFile is invalid for given content
 	if !generatedId.Equals(idFromFile) {
@@ -146,8 +255,8 @@ func moveIdToOriginalLink(ctx context.Context, raw []byte, id string) []byte {
 // generateNewId normalizes file content for digest calculation and generates a new id for the file with current timestamp
 // normalized file has the "id" set to empty string
 // returns the generated id and normalized file content that the id was generated for
-func generateNewId(now Now, tm *model.ThingModel, raw []byte, optPath string) (model.TMID, []byte) {
-	hashStr, raw, _ := CalculateFileDigest(raw) // ignore the error, because the file has been validated already
+func generateNewId(now Now, tm *model.ThingModel, raw []byte, optPath string, digestMode string) (model.TMID, []byte) {
+	hashStr, raw, _ := CalculateDigest(raw, digestMode) // ignore the error, because the file has been validated already
 	ver := model.TMVersionFromOriginal(tm.Version.Model)
 	ver.Hash = hashStr
 	ver.Timestamp = now().UTC().Format(model.PseudoVersionTimestampFormat)
@@ -170,3 +279,178 @@ func sanitizePathForID(p string) string {
 	p = strings.Join(parts, "/")
 	return p
 }
+
+// ImportNamedFile works like ImportFile for the contents raw of the file with given name, and describes the outcome in the
+// result's message with reference to name. If opts.IgnoreExisting is set, a TM which already exists in repo is not
+// reported as error
+func (c *ImportCommand) ImportNamedFile(ctx context.Context, name string, raw []byte, repo repos.Repo, opts repos.ImportOptions) (repos.ImportResult, error) {
+	res, err := c.ImportFile(ctx, raw, repo, opts)
+	if err != nil {
+		var errExists *repos.ErrTMIDConflict
+		if errors.As(err, &errExists) {
+			res.Message = fmt.Sprintf("file %s already exists as %s", name, errExists.ExistingId)
+			if opts.IgnoreExisting {
+				return res, nil
+			}
+			return res, err
+		}
+		return repos.ImportResultFromError(fmt.Errorf("error importing file %s: %w", name, err))
+	}
+	switch res.Type {
+	case repos.ImportResultWarning:
+		warn := res.Message
+		var cErr *repos.ErrTMIDConflict
+		if errors.As(res.Err, &cErr) {
+			warn = fmt.Sprintf("TM's version and timestamp clash with existing one %s", cErr.ExistingId)
+		}
+		res.Message = fmt.Sprintf("file %s imported as %s with warning: %s", name, res.TmID, warn)
+	case repos.ImportResultOK:
+		res.Message = fmt.Sprintf("file %s imported as %s", name, res.TmID)
+	default:
+		return repos.ImportResultFromError(fmt.Errorf("unexpected ImportResult type %v when importing file %s", res.Type, name))
+	}
+	return res, nil
+}
+
+// ImportFS imports all TM files (*.json) found in fsys to repo and updates the index of repo once for all imported TMs.
+// If optTree is set, the directory of each file is used as its optional path.
+//
+// With opts.WithAttachments, the files in the '.attachments' directories of fsys are imported as attachments afterwards.
+// Both the layout produced by export ('<name>/.attachments/<file>' and '<name>/.attachments/<version>/<file>') and
+// the layout '.attachments/<TM file name>/<file>', which attaches files to the TM name of a TM file next to the
+// '.attachments' directory, are recognized.
+//
+// Returns one result per TM file and one per attachment which could not be imported, and the first encountered error
+func (c *ImportCommand) ImportFS(ctx context.Context, fsys fs.FS, repo repos.Repo, optTree bool, opts repos.ImportOptions) ([]repos.ImportResult, error) {
+	var results []repos.ImportResult
+	var firstErr error
+	var successfulIds []string
+	// ids of imported TMs by file path
+	imported := make(map[string]model.TMID)
+	var attachments []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == model.AttachmentsDir && !opts.WithAttachments {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if isInAttachmentsDir(p) {
+			attachments = append(attachments, p)
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+
+		fileOpts := opts
+		if optTree {
+			fileOpts.OptPath = "/"
+			if dir := path.Dir(p); dir != "." {
+				fileOpts.OptPath += dir
+			}
+		}
+		var res repos.ImportResult
+		raw, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			res, err = repos.ImportResultFromError(fmt.Errorf("error reading file %s for import: %w", p, err))
+		} else {
+			res, err = c.ImportNamedFile(ctx, p, raw, repo, fileOpts)
+		}
+		results = append(results, res)
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		var cErr *repos.ErrTMIDConflict
+		if res.IsSuccessful() {
+			successfulIds = append(successfulIds, res.TmID)
+			imported[p], _ = model.ParseTMID(res.TmID)
+		} else if errors.As(res.Err, &cErr) {
+			imported[p], _ = model.ParseTMID(cErr.ExistingId)
+		}
+		return nil
+	})
+	if err != nil {
+		return results, err
+	}
+
+	if len(successfulIds) > 0 {
+		err = repo.Index(ctx, successfulIds...)
+		if err != nil {
+			return results, fmt.Errorf("cannot update index: %w", err)
+		}
+	}
+
+	for _, p := range attachments {
+		res, err := importFSAttachment(ctx, fsys, p, repo, imported)
+		if err != nil {
+			results = append(results, res)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return results, firstErr
+}
+
+func isInAttachmentsDir(p string) bool {
+	return slices.Contains(strings.Split(path.Dir(p), "/"), model.AttachmentsDir)
+}
+
+// importFSAttachment imports the file at p in fsys as attachment to the TM name or TM id it is mapped to by its location
+func importFSAttachment(ctx context.Context, fsys fs.FS, p string, repo repos.Repo, imported map[string]model.TMID) (repos.ImportResult, error) {
+	ref, err := fsAttachmentContainer(p, imported)
+	if err != nil {
+		return repos.ImportResultFromError(fmt.Errorf("error importing attachment %s: %w", p, err))
+	}
+	content, err := fs.ReadFile(fsys, p)
+	if err != nil {
+		return repos.ImportResultFromError(fmt.Errorf("error reading attachment %s for import: %w", p, err))
+	}
+	mediaType := mime.TypeByExtension(path.Ext(p))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	att := model.Attachment{Name: path.Base(p), MediaType: mediaType}
+	err = repo.ImportAttachment(ctx, ref, att, content, true, "")
+	if err != nil {
+		return repos.ImportResultFromError(fmt.Errorf("error importing attachment %s to %s: %w", p, ref, err))
+	}
+	return repos.ImportResult{Type: repos.ImportResultOK}, nil
+}
+
+// fsAttachmentContainer finds the attachment container the attachment file at p belongs to
+func fsAttachmentContainer(p string, imported map[string]model.TMID) (model.AttachmentContainerRef, error) {
+	parent := path.Dir(p)
+	if path.Base(parent) == model.AttachmentsDir {
+		// <name>/.attachments/<file>
+		dir := path.Dir(parent)
+		for f, id := range imported {
+			if path.Dir(f) == dir {
+				return model.NewTMNameAttachmentContainerRef(id.Name), nil
+			}
+		}
+		if fn, err := model.ParseFetchName(dir); err == nil && fn.Semver == "" {
+			return model.NewTMNameAttachmentContainerRef(fn.Name), nil
+		}
+	} else if grandParent := path.Dir(parent); path.Base(grandParent) == model.AttachmentsDir {
+		dir, base := path.Dir(grandParent), path.Base(parent)
+		// .attachments/<TM file name>/<file>
+		if id, ok := imported[path.Join(dir, base)]; ok {
+			return model.NewTMNameAttachmentContainerRef(id.Name), nil
+		}
+		// <name>/.attachments/<version>/<file>
+		tmFile := path.Join(dir, base+model.TMFileExtension)
+		if id, ok := imported[tmFile]; ok {
+			return model.NewTMIDAttachmentContainerRef(id.String()), nil
+		}
+		if _, err := model.ParseTMID(tmFile); err == nil {
+			return model.NewTMIDAttachmentContainerRef(tmFile), nil
+		}
+	}
+	return model.AttachmentContainerRef{}, errors.New("cannot map attachment to a TM")
+}
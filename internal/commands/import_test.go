@@ -9,12 +9,17 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/commands/validate"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
 	"github.com/wot-oss/tmc/internal/testutils"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
 	"github.com/wot-oss/tmc/internal/utils"
 )
 
@@ -108,7 +113,7 @@ func TestGenerateNewID(t *testing.T) {
 		Mpn:          "senseall",
 		Author:       model.SchemaAuthor{Name: "author"},
 		Version:      model.Version{Model: "v3.2.1"},
-	}, []byte("{\n\"title\":\"test\"\n}"), "opt/dir")
+	}, []byte("{\n\"title\":\"test\"\n}"), "opt/dir", repos.DigestModeRaw)
 
 	assert.Equal(t, "author/omnicorp/senseall/opt/dir/v3.2.1-20231110123243-7ae21a619c71.tm.json", id.String())
 }
@@ -123,7 +128,7 @@ func TestPrepareToImport(t *testing.T) {
 			Mpn:          "senseall",
 			Author:       model.SchemaAuthor{Name: "author"},
 			Version:      model.Version{Model: "v3.2.1"},
-		}, []byte("{\r\n\"title\":\"test\"\r\n}"), "opt/dir")
+		}, []byte("{\r\n\"title\":\"test\"\r\n}"), "opt/dir", repos.DigestModeRaw)
 		assert.NoError(t, err)
 		assert.False(t, bytes.Contains(b, []byte{'\r'})) // make sure line endings were normalized
 		assert.True(t, bytes.Contains(b, []byte("author/omnicorp/senseall/opt/dir/v3.2.1-20231110123243-7ae21a619c71.tm.json")))
@@ -134,7 +139,7 @@ func TestPrepareToImport(t *testing.T) {
 			Mpn:          strings.Repeat("senseall", 10),                                   // 80 chars
 			Author:       model.SchemaAuthor{Name: strings.Repeat("author", 10)},           // 60 chars
 			Version:      model.Version{Model: "v3.2.1"},
-		}, []byte("{\r\n\"title\":\"test\"\r\n}"), "optional/fldr") // 13 chars
+		}, []byte("{\r\n\"title\":\"test\"\r\n}"), "optional/fldr", repos.DigestModeRaw) // 13 chars
 		assert.ErrorIs(t, err, ErrTMNameTooLong) // 100 + 80 + 60 + 13 + 3 slashes in between = 256 chars
 	})
 	t.Run("foreign string id in original", func(t *testing.T) {
@@ -143,7 +148,7 @@ func TestPrepareToImport(t *testing.T) {
 			Mpn:          "senseall",
 			Author:       model.SchemaAuthor{Name: "author"},
 			Version:      model.Version{Model: "v3.2.1"},
-		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"<foreign&id>\"}"), "opt/dir")
+		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"<foreign&id>\"}"), "opt/dir", repos.DigestModeRaw)
 		assert.NoError(t, err)
 		assert.True(t, bytes.Contains(b, []byte("\"href\":\"<foreign&id>\"")))
 		assert.True(t, bytes.Contains(b, []byte("author/omnicorp/senseall/opt/dir/v3.2.1-20231110123243-09788aa7b98d.tm.json")))
@@ -154,7 +159,7 @@ func TestPrepareToImport(t *testing.T) {
 			Mpn:          "senseall",
 			Author:       model.SchemaAuthor{Name: "author"},
 			Version:      model.Version{Model: "v3.2.1"},
-		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"author/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-7ae21a619c71.tm.json\"}"), "opt/dir")
+		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"author/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-7ae21a619c71.tm.json\"}"), "opt/dir", repos.DigestModeRaw)
 		assert.NoError(t, err)
 		// no change in id
 		assert.True(t, bytes.Contains(b, []byte("author/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-7ae21a619c71.tm.json")))
@@ -165,7 +170,7 @@ func TestPrepareToImport(t *testing.T) {
 			Mpn:          "senseall",
 			Author:       model.SchemaAuthor{Name: "author"},
 			Version:      model.Version{Model: "v3.2.1"},
-		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"publisher/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-7ae21a619c71.tm.json\"}"), "opt/dir")
+		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"publisher/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-7ae21a619c71.tm.json\"}"), "opt/dir", repos.DigestModeRaw)
 		assert.NoError(t, err)
 		// new generated id
 		assert.True(t, bytes.Contains(b, []byte("author/omnicorp/senseall/opt/dir/v3.2.1-20231110123243-7ae21a619c71.tm.json")))
@@ -176,7 +181,7 @@ func TestPrepareToImport(t *testing.T) {
 			Mpn:          "senseall",
 			Author:       model.SchemaAuthor{Name: "author"},
 			Version:      model.Version{Model: "v3.2.1"},
-		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"author/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-863e9f0f950a.tm.json\"}"), "opt/dir")
+		}, []byte("{\r\n\"title\":\"test\"\r\n,\"id\":\"author/omnicorp/senseall/opt/dir/v3.2.1-20221010123243-863e9f0f950a.tm.json\"}"), "opt/dir", repos.DigestModeRaw)
 		assert.NoError(t, err)
 		// new generated id
 		assert.True(t, bytes.Contains(b, []byte("author/omnicorp/senseall/opt/dir/v3.2.1-20231110123243-7ae21a619c71.tm.json")))
@@ -335,6 +340,165 @@ func TestImportToRepoVersioned(t *testing.T) {
 
 }
 
+func TestImportToRepoWithoutRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "new-dir")
+	repo, err := repos.NewFileRepo(map[string]any{
+		"type": "file",
+		"loc":  root,
+	}, model.NewRepoSpec("new"))
+	assert.NoError(t, err)
+
+	clk := testutils.NewTestClock(time.Now(), 1050*time.Millisecond)
+	c := NewImportCommand(clk.Now)
+
+	_, raw, err := utils.ReadRequiredFile("../../test/data/import/omnilamp.json")
+	assert.NoError(t, err)
+	res, err := c.ImportFile(context.Background(), raw, repo, repos.ImportOptions{})
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccessful())
+	_, err = os.Stat(filepath.Join(root, res.TmID))
+	assert.NoError(t, err)
+}
+
+func TestImportToRepoWithJCSDigest(t *testing.T) {
+	root, err := os.MkdirTemp(os.TempDir(), "tm-catalog")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	repo, err := repos.NewFileRepo(map[string]any{
+		"type": "file",
+		"loc":  root,
+	}, model.NewRepoSpec("jcs"))
+	assert.NoError(t, err)
+	rMocks.MockReposGetDigestMode(t, repos.DigestModeJCS)
+
+	clk := testutils.NewTestClock(time.Now(), 1050*time.Millisecond)
+	c := NewImportCommand(clk.Now)
+
+	_, raw, err := utils.ReadRequiredFile("../../test/data/import/omnilamp.json")
+	assert.NoError(t, err)
+	res, err := c.ImportFile(context.Background(), raw, repo, repos.ImportOptions{})
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccessful())
+
+	// reformat the stored file and reorder its keys, as a JSON formatter would
+	_, stored, err := repo.Fetch(context.Background(), res.TmID)
+	assert.NoError(t, err)
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal(stored, &m))
+	reformatted, err := json.MarshalIndent(m, "", "\t")
+	assert.NoError(t, err)
+
+	res, err = c.ImportFile(context.Background(), reformatted, repo, repos.ImportOptions{})
+	var cErr *repos.ErrTMIDConflict
+	if assert.ErrorAs(t, err, &cErr) {
+		assert.Equal(t, repos.IdConflictType(repos.IdConflictSameContent), cErr.Type)
+		entries, _ := os.ReadDir(filepath.Join(root, filepath.Dir(cErr.ExistingId)))
+		assert.Len(t, entries, 1)
+	}
+}
+
+func TestImportFS(t *testing.T) {
+	repo, err := repos.NewFileRepo(map[string]any{
+		"type": "file",
+		"loc":  t.TempDir(),
+	}, model.EmptySpec)
+	assert.NoError(t, err)
+	_, lamp, err := utils.ReadRequiredFile("../../test/data/import/omnilamp.json")
+	assert.NoError(t, err)
+	_, versioned, err := utils.ReadRequiredFile("../../test/data/import/omnilamp-versioned.json")
+	assert.NoError(t, err)
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"omnilamp.json":                                                    {Data: lamp},
+		".attachments/omnilamp.json/README.md":                             {Data: []byte("# Lamp")},
+		"export/v3.2.1-20231110123243-98b3fbd291f4.tm.json":                {Data: versioned},
+		"export/.attachments/v3.2.1-20231110123243-98b3fbd291f4/data.csv":  {Data: []byte("a,b")},
+		"omnicorp-tm-department/omnicorp/omnilamp/.attachments/manual.pdf": {Data: []byte("%PDF")},
+		"other/.attachments/v1.0.0/notes.txt":                              {Data: []byte("unmapped")},
+		"other/readme.txt":                                                 {Data: []byte("ignored")},
+	}
+	c := NewImportCommand(testutils.NewTestClock(time.Date(2023, time.November, 10, 12, 32, 43, 0, time.UTC), time.Second).Now)
+
+	res, err := c.ImportFS(ctx, fsys, repo, false, repos.ImportOptions{WithAttachments: true})
+	assert.Error(t, err)
+	if assert.Len(t, res, 3) {
+		assert.Equal(t, repos.ImportResultOK, res[0].Type)
+		assert.Equal(t, "file export/v3.2.1-20231110123243-98b3fbd291f4.tm.json imported as omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20231110123243-98b3fbd291f4.tm.json", res[0].Message)
+		assert.Equal(t, repos.ImportResultOK, res[1].Type)
+		assert.Equal(t, repos.ImportResultError, res[2].Type)
+		assert.Contains(t, res[2].Message, "other/.attachments/v1.0.0/notes.txt")
+	}
+
+	list, err := repo.List(ctx, &model.Filters{})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Entries, 1) {
+		names := []string{}
+		for _, a := range list.Entries[0].Attachments {
+			names = append(names, a.Name)
+		}
+		assert.ElementsMatch(t, []string{"README.md", "manual.pdf"}, names)
+		for _, v := range list.Entries[0].Versions {
+			if v.TMID == res[0].TmID && assert.Len(t, v.Attachments, 1) {
+				assert.Equal(t, "data.csv", v.Attachments[0].Name)
+			}
+		}
+	}
+
+	t.Run("ignore existing and optTree", func(t *testing.T) {
+		res, err := c.ImportFS(ctx, fstest.MapFS{"sub/omnilamp.json": {Data: lamp}, "omnilamp.json": {Data: lamp}}, repo, true, repos.ImportOptions{IgnoreExisting: true})
+		assert.NoError(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, repos.ImportResultError, res[0].Type)
+			assert.True(t, strings.HasPrefix(res[0].Message, "file omnilamp.json already exists as"))
+			assert.Equal(t, repos.ImportResultOK, res[1].Type)
+			assert.True(t, strings.HasPrefix(res[1].TmID, "omnicorp-tm-department/omnicorp/omnilamp/sub/"))
+		}
+	})
+}
+
+func TestImportCommand_Validate(t *testing.T) {
+	c := NewImportCommand(func() time.Time { return time.Date(2023, time.November, 10, 12, 32, 43, 0, time.UTC) })
+	_, raw, err := utils.ReadRequiredFile("../../test/data/import/omnilamp.json")
+	assert.NoError(t, err)
+
+	t.Run("valid TM", func(t *testing.T) {
+		res, err := c.Validate(context.Background(), raw, nil, "opt")
+		assert.NoError(t, err)
+		assert.True(t, res.Valid)
+		assert.Empty(t, res.Findings)
+		assert.Equal(t, "omnicorp-tm-department/omnicorp/omnilamp/opt/v0.0.0-20231110123243-575dfac219e2.tm.json", res.TMID)
+	})
+	t.Run("with custom rules of repo", func(t *testing.T) {
+		r := mocks.NewRepo(t)
+		r.On("ValidationFiles", mock.Anything).Return(map[string][]byte{
+			"title.schema.json": []byte(`{"properties": {"title": {"maxLength": 3}}}`),
+		}, nil).Once()
+		r.On("Spec").Return(model.NewRepoSpec("r"))
+		rMocks.MockReposGetDigestMode(t, repos.DigestModeRaw)
+		res, err := c.Validate(context.Background(), raw, r, "")
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Equal(t, "omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20231110123243-575dfac219e2.tm.json", res.TMID)
+		if assert.Len(t, res.Findings, 1) {
+			assert.Equal(t, validate.Finding{Source: "title.schema.json", Pointer: "/title", Keyword: "maxLength", Message: "length must be <= 3, but got 16"}, res.Findings[0])
+		}
+
+		// when: validating another TM with the same command
+		res, err = c.Validate(context.Background(), raw, r, "")
+		// then: the validation files are not read again
+		assert.NoError(t, err)
+		assert.Len(t, res.Findings, 1)
+	})
+	t.Run("without mandatory fields", func(t *testing.T) {
+		res, err := c.Validate(context.Background(), []byte(`{"title": "Lamp"}`), nil, "")
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Empty(t, res.TMID)
+		assert.NotEmpty(t, res.Findings)
+	})
+}
+
 func TestSanitizePath(t *testing.T) {
 	tests := []struct {
 		in  string
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wot-oss/tmc/internal/commands/validate"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	tmPrefix         = "tm:"
+	tmTypeThingModel = "tm:ThingModel"
+	relType          = "type"
+	mimeTMJSON       = "application/tm+json"
+)
+
+// ErrInvalidTD is returned when the Thing Description instantiated from a TM does not validate against the TD schema
+var ErrInvalidTD = errors.New("instantiated Thing Description is invalid")
+
+// ErrMissingPlaceholders is returned when the TM to be instantiated contains placeholders for which no values were given
+type ErrMissingPlaceholders struct {
+	Names []string
+}
+
+func (e *ErrMissingPlaceholders) Error() string {
+	return fmt.Sprintf("missing values for placeholders: %s", strings.Join(e.Names, ", "))
+}
+
+type InstantiateOptions struct {
+	// Placeholders maps placeholder names to their values
+	Placeholders map[string]any
+	// ID is the id of the Thing Description. A random urn:uuid id is generated, if empty
+	ID string
+	// Base is the base URI of the Thing Description. The TM's base is kept, if empty
+	Base string
+	// Namespaces restricts the TMs which may be referenced by the TM. See ResolveTM
+	Namespaces []string
+}
+
+// InstantiateTM turns the TM given as raw into a Thing Description. References to other TMs are resolved with
+// ResolveTM first. Then all {{PLACEHOLDER}} values are substituted, the TM terms are dropped, and the id and base are set.
+// The result is validated against the TD schema.
+// Returns *ErrMissingPlaceholders if values are missing for any of the TM's placeholders,
+// ErrInvalidTD if the result is not a valid Thing Description
+func InstantiateTM(ctx context.Context, spec model.RepoSpec, id string, raw []byte, opts InstantiateOptions) ([]byte, error) {
+	resolved, err := ResolveTM(ctx, spec, id, raw, opts.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	err = json.Unmarshal(resolved, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	td := substitutePlaceholders(doc, opts.Placeholders, &missing).(map[string]any)
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, &ErrMissingPlaceholders{Names: slices.Compact(missing)}
+	}
+
+	td = dropTMTerms(td).(map[string]any)
+	setTDType(td)
+	removeTMContext(td)
+	if opts.ID != "" {
+		td["id"] = opts.ID
+	} else {
+		td["id"] = "urn:uuid:" + uuid.NewString()
+	}
+	if opts.Base != "" {
+		td["base"] = opts.Base
+	}
+	// keep a reference to the TM the TD has been instantiated from
+	links := utils.JsGetArray(td, "links")
+	td["links"] = append(links, map[string]any{"rel": relType, "href": id, "type": mimeTMJSON})
+
+	res, err := marshalIndent(td)
+	if err != nil {
+		return nil, err
+	}
+	// validate the marshalled result, because placeholder values may be of types the schema validator does not know
+	var parsed any
+	err = json.Unmarshal(res, &parsed)
+	if err != nil {
+		return nil, err
+	}
+	err = validate.ValidateAsTD(res, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTD, err)
+	}
+	return res, nil
+}
+
+// substitutePlaceholders replaces all placeholders in string values within node with the given values. A string consisting
+// of a single placeholder is replaced with the value as is, so that placeholders can be used for non-string values.
+// The names of placeholders without a value are appended to missing
+func substitutePlaceholders(node any, vals map[string]any, missing *[]string) any {
+	switch n := node.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(n))
+		for k, v := range n {
+			res[k] = substitutePlaceholders(v, vals, missing)
+		}
+		return res
+	case []any:
+		res := make([]any, len(n))
+		for i, v := range n {
+			res[i] = substitutePlaceholders(v, vals, missing)
+		}
+		return res
+	case string:
+		if m := model.PlaceholdersRegexp.FindStringSubmatch(n); m != nil && m[0] == n {
+			v, ok := vals[m[1]]
+			if !ok {
+				*missing = append(*missing, m[1])
+				return n
+			}
+			return v
+		}
+		return model.PlaceholdersRegexp.ReplaceAllStringFunc(n, func(s string) string {
+			name := s[2 : len(s)-2]
+			v, ok := vals[name]
+			if !ok {
+				*missing = append(*missing, name)
+				return s
+			}
+			if vs, ok := v.(string); ok {
+				return vs
+			}
+			b, _ := json.Marshal(v)
+			return string(b)
+		})
+	default:
+		return node
+	}
+}
+
+// dropTMTerms removes all members with names in the tm: namespace from the objects within node
+func dropTMTerms(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(n))
+		for k, v := range n {
+			if strings.HasPrefix(k, tmPrefix) {
+				continue
+			}
+			res[k] = dropTMTerms(v)
+		}
+		return res
+	case []any:
+		res := make([]any, len(n))
+		for i, v := range n {
+			res[i] = dropTMTerms(v)
+		}
+		return res
+	default:
+		return node
+	}
+}
+
+// setTDType removes tm:ThingModel from the top-level @type of td and removes @type altogether if nothing else remains
+func setTDType(td map[string]any) {
+	var types []any
+	switch t := td["@type"].(type) {
+	case string:
+		types = []any{t}
+	case []any:
+		types = t
+	}
+	types = slices.DeleteFunc(slices.Clone(types), func(t any) bool { return t == tmTypeThingModel })
+	switch len(types) {
+	case 0:
+		delete(td, "@type")
+	case 1:
+		td["@type"] = types[0]
+	default:
+		td["@type"] = types
+	}
+}
+
+// removeTMContext removes the definition of the tm prefix from td's @context
+func removeTMContext(td map[string]any) {
+	ctx, ok := td["@context"].([]any)
+	if !ok {
+		return
+	}
+	var res []any
+	for _, c := range ctx {
+		if cm, ok := c.(map[string]any); ok {
+			delete(cm, strings.TrimSuffix(tmPrefix, ":"))
+			if len(cm) == 0 {
+				continue
+			}
+		}
+		res = append(res, c)
+	}
+	td["@context"] = res
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const instantiateLampId = "tmc-test/corp/lamp/v1.0.0-20240101000000-ffffffffffff.tm.json"
+
+func TestInstantiateTM(t *testing.T) {
+	lamp := `{
+  "@context": ["https://www.w3.org/2022/wot/td/v1.1", {"schema": "https://schema.org/", "tm": "https://www.w3.org/2022/wot/tm"}],
+  "@type": "tm:ThingModel",
+  "id": "` + instantiateLampId + `",
+  "title": "Lamp {{SERIAL}}",
+  "schema:author": {"schema:name": "tmc-test"},
+  "schema:manufacturer": {"schema:name": "corp"},
+  "schema:mpn": "lamp",
+  "version": {"model": "1.0.0"},
+  "base": "http://{{HOST}}:{{PORT}}/",
+  "tm:optional": ["/properties/status"],
+  "securityDefinitions": {"nosec_sc": {"scheme": "nosec"}},
+  "security": "nosec_sc",
+  "properties": {
+    "status": {
+      "type": "string",
+      "readOnly": true,
+      "forms": [{"href": "properties/status"}]
+    },
+    "brightness": {
+      "tm:ref": "tmc-test/corp/base#/definitions/level",
+      "maximum": "{{MAX_BRIGHTNESS}}",
+      "forms": [{"href": "properties/brightness"}]
+    }
+  }
+}`
+	base := resolveTestTM(resolveBaseId, "base", `,
+  "definitions": {"level": {"type": "integer", "minimum": 0}}`)
+	spec := setupResolveRepo(t, map[string]string{
+		resolveBaseId:     base,
+		instantiateLampId: lamp,
+	})
+
+	t.Run("valid TD", func(t *testing.T) {
+		res, err := InstantiateTM(context.Background(), spec, instantiateLampId, []byte(lamp), InstantiateOptions{
+			Placeholders: map[string]any{"SERIAL": "1234", "HOST": "192.168.0.10", "PORT": 8080, "MAX_BRIGHTNESS": 100},
+			ID:           "urn:dev:lamp-1234",
+		})
+		require.NoError(t, err)
+		var td map[string]any
+		require.NoError(t, json.Unmarshal(res, &td))
+		assert.Equal(t, "urn:dev:lamp-1234", td["id"])
+		assert.Equal(t, "Lamp 1234", td["title"])
+		assert.Equal(t, "http://192.168.0.10:8080/", td["base"])
+		assert.NotContains(t, td, "@type")
+		assert.NotContains(t, td, "tm:optional")
+		assert.Equal(t, []any{"https://www.w3.org/2022/wot/td/v1.1", map[string]any{"schema": "https://schema.org/"}}, td["@context"])
+		assert.Equal(t, []any{map[string]any{"rel": "type", "href": instantiateLampId, "type": "application/tm+json"}}, td["links"])
+		props := td["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{
+			"type":    "integer",
+			"minimum": float64(0),
+			"maximum": float64(100),
+			"forms":   []any{map[string]any{"href": "properties/brightness"}},
+		}, props["brightness"])
+	})
+
+	t.Run("generated id and base", func(t *testing.T) {
+		res, err := InstantiateTM(context.Background(), spec, instantiateLampId, []byte(lamp), InstantiateOptions{
+			Placeholders: map[string]any{"SERIAL": "1234", "HOST": "192.168.0.10", "PORT": 8080, "MAX_BRIGHTNESS": 100},
+			Base:         "coap://lamp.local/",
+		})
+		require.NoError(t, err)
+		var td map[string]any
+		require.NoError(t, json.Unmarshal(res, &td))
+		assert.Regexp(t, "^urn:uuid:[0-9a-f-]{36}$", td["id"])
+		assert.Equal(t, "coap://lamp.local/", td["base"])
+	})
+
+	t.Run("missing placeholders", func(t *testing.T) {
+		_, err := InstantiateTM(context.Background(), spec, instantiateLampId, []byte(lamp), InstantiateOptions{
+			Placeholders: map[string]any{"HOST": "192.168.0.10"},
+		})
+		var mErr *ErrMissingPlaceholders
+		if assert.ErrorAs(t, err, &mErr) {
+			assert.Equal(t, []string{"MAX_BRIGHTNESS", "PORT", "SERIAL"}, mErr.Names)
+		}
+	})
+
+	t.Run("invalid TD", func(t *testing.T) {
+		raw := resolveTestTM(resolveBaseId, "base", "")
+		_, err := InstantiateTM(context.Background(), spec, resolveBaseId, []byte(raw), InstantiateOptions{})
+		assert.True(t, errors.Is(err, ErrInvalidTD))
+		assert.ErrorContains(t, err, "securityDefinitions")
+	})
+}
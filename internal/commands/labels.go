@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+// UpdateLabels adds the labels in add to and removes the labels in remove from the TM name or TM version given by ref
+// in the repo given by spec
+func UpdateLabels(ctx context.Context, spec model.RepoSpec, ref model.AttachmentContainerRef, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return fmt.Errorf("%w: no labels given", model.ErrInvalidLabel)
+	}
+	for _, l := range add {
+		if err := model.ValidateLabel(l); err != nil {
+			return err
+		}
+	}
+	repo, err := repos.Get(spec)
+	if err != nil {
+		return err
+	}
+	return repo.UpdateLabels(ctx, ref, add, remove)
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestUpdateLabels(t *testing.T) {
+	r := mocks.NewRepo(t)
+	spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	ref := model.NewTMNameAttachmentContainerRef("author/manufacturer/mpn")
+
+	t.Run("ok", func(t *testing.T) {
+		r.On("UpdateLabels", mock.Anything, ref, []string{"certified"}, []string{"beta"}).Return(nil).Once()
+		err := UpdateLabels(context.Background(), spec, ref, []string{"certified"}, []string{"beta"})
+		assert.NoError(t, err)
+	})
+	t.Run("not found", func(t *testing.T) {
+		r.On("UpdateLabels", mock.Anything, ref, []string{"certified"}, []string(nil)).Return(model.ErrTMNameNotFound).Once()
+		err := UpdateLabels(context.Background(), spec, ref, []string{"certified"}, nil)
+		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
+	})
+	t.Run("invalid label", func(t *testing.T) {
+		err := UpdateLabels(context.Background(), spec, ref, []string{"certified,beta"}, nil)
+		assert.ErrorIs(t, err, model.ErrInvalidLabel)
+	})
+	t.Run("no labels", func(t *testing.T) {
+		err := UpdateLabels(context.Background(), spec, ref, nil, nil)
+		assert.ErrorIs(t, err, model.ErrInvalidLabel)
+	})
+}
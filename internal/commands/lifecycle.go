@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+// SetLifecycle sets the lifecycle metadata of the TM version with given id in the repo given by spec.
+// Setting the state model.LifecycleActive removes any previously set metadata
+func SetLifecycle(ctx context.Context, spec model.RepoSpec, id string, lc model.Lifecycle) error {
+	_, err := model.ParseTMID(id)
+	if err != nil {
+		return err
+	}
+	err = lc.Validate(id)
+	if err != nil {
+		return err
+	}
+	r, err := repos.Get(spec)
+	if err != nil {
+		return err
+	}
+	return r.SetLifecycle(ctx, id, lc)
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestSetLifecycle(t *testing.T) {
+	r := mocks.NewRepo(t)
+	spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	id := "author/manufacturer/mpn/v1.0.0-20231005123243-a49617d2e4fc.tm.json"
+
+	t.Run("ok", func(t *testing.T) {
+		lc := model.Lifecycle{State: model.LifecycleDeprecated, Successor: "author/manufacturer/mpn/v1.0.1-20231005123243-b49617d2e4fc.tm.json"}
+		r.On("SetLifecycle", mock.Anything, id, lc).Return(nil).Once()
+		err := SetLifecycle(context.Background(), spec, id, lc)
+		assert.NoError(t, err)
+	})
+	t.Run("not found", func(t *testing.T) {
+		lc := model.Lifecycle{State: model.LifecycleYanked}
+		r.On("SetLifecycle", mock.Anything, id, lc).Return(model.ErrTMNotFound).Once()
+		err := SetLifecycle(context.Background(), spec, id, lc)
+		assert.ErrorIs(t, err, model.ErrTMNotFound)
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		err := SetLifecycle(context.Background(), spec, "author/manufacturer/mpn", model.Lifecycle{State: model.LifecycleYanked})
+		assert.ErrorIs(t, err, model.ErrInvalidId)
+	})
+	t.Run("invalid state", func(t *testing.T) {
+		err := SetLifecycle(context.Background(), spec, id, model.Lifecycle{State: "retired"})
+		assert.ErrorIs(t, err, model.ErrInvalidLifecycle)
+	})
+}
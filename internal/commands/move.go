@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+var ErrDisplayNameMismatch = errors.New("display name does not match TM name")
+
+// Move moves all versions and attachments of the TM name oldName to newName in the repo given by spec, e.g. when a
+// manufacturer has been renamed or acquired. oldName remains usable as an alias of newName.
+// newName must consist of author, manufacturer, mpn and an optional path in the sanitized form used in TM ids.
+// When author, manufacturer or mpn change, their display names in the TMs and the index are replaced by those in
+// names, or by the new parts of the name, if not given. The digests in the ids of the TMs are recalculated accordingly.
+// The signatures of versions whose content has been changed thereby no longer verify and are removed.
+// Returns the ids of the moved versions whose signatures have been removed, which must be signed again
+func Move(ctx context.Context, spec model.RepoSpec, oldName, newName string, names model.DisplayNames) ([]string, error) {
+	err := checkTMName(oldName)
+	if err != nil {
+		return nil, err
+	}
+	err = checkTMName(newName)
+	if err != nil {
+		return nil, err
+	}
+	if oldName == newName {
+		return nil, fmt.Errorf("%w: %s", model.ErrTMNameExists, newName)
+	}
+	names, err = movedDisplayNames(oldName, newName, names)
+	if err != nil {
+		return nil, err
+	}
+	digestMode, err := repos.GetDigestMode(spec)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := repos.Get(spec)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := signedVersions(ctx, repo, oldName)
+	if err != nil {
+		return nil, err
+	}
+	opts := repos.MoveOptions{
+		Names: names,
+		Digest: func(raw []byte) (string, error) {
+			digest, _, err := CalculateDigest(raw, digestMode)
+			return digest, err
+		},
+	}
+	err = repo.Move(ctx, oldName, newName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return removeInvalidatedSignatures(ctx, repo, newName, signed)
+}
+
+// signedVersions returns the ids of the versions of the TM name name, which have a signature attachment
+func signedVersions(ctx context.Context, repo repos.Repo, name string) ([]model.TMID, error) {
+	versions, err := repo.Versions(ctx, name)
+	if err != nil {
+		if errors.Is(err, model.ErrTMNameNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var res []model.TMID
+	for _, v := range versions {
+		if _, found := v.FindAttachment(model.SignatureAttachmentName); !found {
+			continue
+		}
+		tmid, err := model.ParseTMID(v.TMID)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, tmid)
+	}
+	return res, nil
+}
+
+// removeInvalidatedSignatures removes the signatures of the versions of the TM name moved to newName, whose content
+// has been changed by the move, as indicated by a changed digest. signed are the ids of the signed versions before the
+// move. Returns the ids of the versions whose signatures have been removed
+func removeInvalidatedSignatures(ctx context.Context, repo repos.Repo, newName string, signed []model.TMID) ([]string, error) {
+	if len(signed) == 0 {
+		return nil, nil
+	}
+	versions, err := repo.Versions(ctx, newName)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, v := range versions {
+		tmid, err := model.ParseTMID(v.TMID)
+		if err != nil {
+			return res, err
+		}
+		// moving keeps the versions and timestamps of the TMs
+		i := slices.IndexFunc(signed, func(s model.TMID) bool {
+			return s.Version.BaseString() == tmid.Version.BaseString() && s.Version.Timestamp == tmid.Version.Timestamp
+		})
+		if i < 0 || signed[i].Version.Hash == tmid.Version.Hash {
+			continue
+		}
+		err = repo.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(v.TMID), model.SignatureAttachmentName, "")
+		if err != nil {
+			return res, fmt.Errorf("could not remove invalidated signature of %s: %w", v.TMID, err)
+		}
+		res = append(res, v.TMID)
+	}
+	return res, nil
+}
+
+// movedDisplayNames returns the display names of author, manufacturer and mpn after moving oldName to newName.
+// Given names must match the corresponding parts of newName when sanitized. Names not given are derived from the
+// parts of newName which differ from oldName and left empty for unchanged parts
+func movedDisplayNames(oldName, newName string, names model.DisplayNames) (model.DisplayNames, error) {
+	oldParts := strings.Split(oldName, "/")
+	newParts := strings.Split(newName, "/")
+	for i, n := range []*string{&names.Author, &names.Manufacturer, &names.Mpn} {
+		if *n == "" {
+			if oldParts[i] != newParts[i] {
+				*n = newParts[i]
+			}
+			continue
+		}
+		if utils.SanitizeName(*n) != newParts[i] {
+			return names, fmt.Errorf("%w: '%s' is not '%s' when sanitized", ErrDisplayNameMismatch, *n, newParts[i])
+		}
+	}
+	return names, nil
+}
+
+// ResolveMovedName returns the name the TM name name has been moved to in the repos given by spec.
+// Returns an empty string if name has not been moved
+func ResolveMovedName(ctx context.Context, spec model.RepoSpec, name string) (string, error) {
+	versions, err, _ := NewVersionsCommand().ListVersions(ctx, spec, name)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range versions {
+		id, err := model.ParseTMID(v.TMID)
+		if err == nil && id.Name != name {
+			return id.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// checkTMName checks that name is a TM name without version, which is in the sanitized form used in TM ids
+func checkTMName(name string) error {
+	fn, err := model.ParseFetchName(name)
+	if err != nil {
+		return err
+	}
+	if fn.Semver != "" {
+		return fmt.Errorf("%w: %s - must not contain a version", model.ErrInvalidFetchName, name)
+	}
+	if s := sanitizePathForID(name); s != name {
+		return fmt.Errorf("%w: %s - must be sanitized, i.e. %s", model.ErrInvalidFetchName, name, s)
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("%w: %s", ErrTMNameTooLong, name)
+	}
+	return nil
+}
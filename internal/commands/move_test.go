@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestMove(t *testing.T) {
+	r := mocks.NewRepo(t)
+	spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	oldName := "author/manufacturer/mpn"
+	newName := "author/new-manufacturer/mpn/sub"
+	r.On("Versions", mock.Anything, oldName).Return([]model.FoundVersion{}, nil).Maybe()
+
+	t.Run("ok", func(t *testing.T) {
+		r.On("Move", mock.Anything, oldName, newName, mock.MatchedBy(func(opts repos.MoveOptions) bool {
+			return opts.Names == model.DisplayNames{Manufacturer: "new-manufacturer"} && opts.Digest != nil
+		})).Return(nil).Once()
+		_, err := Move(context.Background(), spec, oldName, newName, model.DisplayNames{})
+		assert.NoError(t, err)
+	})
+	t.Run("with display names", func(t *testing.T) {
+		names := model.DisplayNames{Manufacturer: "New Manufacturer", Mpn: "MPN"}
+		r.On("Move", mock.Anything, oldName, newName, mock.MatchedBy(func(opts repos.MoveOptions) bool {
+			return opts.Names == names
+		})).Return(nil).Once()
+		_, err := Move(context.Background(), spec, oldName, newName, names)
+		assert.NoError(t, err)
+	})
+	t.Run("with mismatching display name", func(t *testing.T) {
+		_, err := Move(context.Background(), spec, oldName, newName, model.DisplayNames{Manufacturer: "Other Manufacturer"})
+		assert.ErrorIs(t, err, ErrDisplayNameMismatch)
+	})
+	t.Run("not found", func(t *testing.T) {
+		r.On("Move", mock.Anything, oldName, newName, mock.Anything).Return(model.ErrTMNameNotFound).Once()
+		_, err := Move(context.Background(), spec, oldName, newName, model.DisplayNames{})
+		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
+	})
+	t.Run("same name", func(t *testing.T) {
+		_, err := Move(context.Background(), spec, oldName, oldName, model.DisplayNames{})
+		assert.ErrorIs(t, err, model.ErrTMNameExists)
+	})
+	t.Run("invalid names", func(t *testing.T) {
+		for _, n := range []string{"author/manufacturer", "author/manufacturer/mpn:1.0.0", "author/New Manufacturer/mpn", "author/Manufacturer/mpn", "author/manufacturer/mpn/"} {
+			_, err := Move(context.Background(), spec, oldName, n, model.DisplayNames{})
+			assert.ErrorIs(t, err, model.ErrInvalidFetchName, n)
+			_, err = Move(context.Background(), spec, n, newName, model.DisplayNames{})
+			assert.ErrorIs(t, err, model.ErrInvalidFetchName, n)
+		}
+	})
+}
+
+func TestMove_SignedVersions(t *testing.T) {
+	r := mocks.NewRepo(t)
+	spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	oldName := "author/manufacturer/mpn"
+	newName := "author/new-manufacturer/mpn"
+	signature := model.AttachmentContainer{Attachments: []model.Attachment{{Name: model.SignatureAttachmentName}}}
+	version := func(id string, att model.AttachmentContainer) model.FoundVersion {
+		return model.FoundVersion{IndexVersion: &model.IndexVersion{TMID: id, AttachmentContainer: att}}
+	}
+	r.On("Versions", mock.Anything, oldName).Return([]model.FoundVersion{
+		version(oldName+"/v1.0.0-20231005123243-a49617d2e4fc.tm.json", signature),
+		version(oldName+"/v1.1.0-20231006123243-b49617d2e4fc.tm.json", signature),
+		version(oldName+"/v1.2.0-20231007123243-c49617d2e4fc.tm.json", model.AttachmentContainer{}),
+	}, nil).Once()
+	r.On("Move", mock.Anything, oldName, newName, mock.Anything).Return(nil).Once()
+	r.On("Versions", mock.Anything, newName).Return([]model.FoundVersion{
+		// the content of the first version has been changed, that of the second one has not
+		version(newName+"/v1.0.0-20231005123243-f00000000000.tm.json", signature),
+		version(newName+"/v1.1.0-20231006123243-b49617d2e4fc.tm.json", signature),
+		version(newName+"/v1.2.0-20231007123243-e00000000000.tm.json", model.AttachmentContainer{}),
+	}, nil).Once()
+	changedId := newName + "/v1.0.0-20231005123243-f00000000000.tm.json"
+	r.On("DeleteAttachment", mock.Anything, model.NewTMIDAttachmentContainerRef(changedId), model.SignatureAttachmentName, "").Return(nil).Once()
+
+	unsigned, err := Move(context.Background(), spec, oldName, newName, model.DisplayNames{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{changedId}, unsigned)
+}
+
+func TestResolveMovedName(t *testing.T) {
+	r := mocks.NewRepo(t)
+	spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	r.On("Spec").Return(spec).Maybe()
+	oldName := "author/manufacturer/mpn"
+	newName := "author/new-manufacturer/mpn"
+	versions := []model.FoundVersion{
+		{
+			IndexVersion: &model.IndexVersion{
+				Version: model.Version{Model: "1.0.0"},
+				TMID:    newName + "/v1.0.0-20231005123243-a49617d2e4fc.tm.json",
+			},
+			FoundIn: model.FoundSource{RepoName: "r1"},
+		},
+	}
+	r.On("Versions", mock.Anything, oldName).Return(versions, nil)
+	r.On("Versions", mock.Anything, newName).Return(versions, nil)
+	r.On("Versions", mock.Anything, "author/manufacturer/other").Return(nil, model.ErrTMNameNotFound)
+
+	t.Run("moved", func(t *testing.T) {
+		res, err := ResolveMovedName(context.Background(), spec, oldName)
+		assert.NoError(t, err)
+		assert.Equal(t, newName, res)
+	})
+	t.Run("not moved", func(t *testing.T) {
+		res, err := ResolveMovedName(context.Background(), spec, newName)
+		assert.NoError(t, err)
+		assert.Equal(t, "", res)
+	})
+	t.Run("not found", func(t *testing.T) {
+		res, err := ResolveMovedName(context.Background(), spec, "author/manufacturer/other")
+		assert.NoError(t, err)
+		assert.Equal(t, "", res)
+	})
+}
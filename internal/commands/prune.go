@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+// PruneCandidates returns the ids of TM versions in the repo given by spec, which are not kept by policy as of now.
+// Returns an error wrapping model.ErrInvalidRetentionPolicy if the policy is empty
+func PruneCandidates(ctx context.Context, spec model.RepoSpec, policy model.RetentionPolicy, now time.Time) ([]string, error) {
+	if policy.IsEmpty() {
+		return nil, fmt.Errorf("%w: no retention rules given", model.ErrInvalidRetentionPolicy)
+	}
+	r, err := repos.Get(spec)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	successors := make(map[string]bool)
+	for _, e := range res.Entries {
+		for _, v := range e.Versions {
+			if v.Lifecycle != nil && v.Lifecycle.Successor != "" {
+				successors[v.Lifecycle.Successor] = true
+			}
+		}
+	}
+	var ids []string
+	for _, e := range res.Entries {
+		versions := make([]*model.IndexVersion, 0, len(e.Versions))
+		for _, v := range e.Versions {
+			versions = append(versions, v.IndexVersion)
+		}
+		for _, v := range policy.Expired(versions, successors, now) {
+			ids = append(ids, v.TMID)
+		}
+	}
+	return ids, nil
+}
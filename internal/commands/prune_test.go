@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	r := mocks.NewRepo(t)
+	spec := model.NewRepoSpec("r1")
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	const (
+		id1 = "author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"
+		id2 = "author/manufacturer/mpn/v1.0.0-20240201000000-a1b2c3d4e5f6.tm.json"
+		id3 = "author/manufacturer/mpn/v1.0.0-20240301000000-a1b2c3d4e5f6.tm.json"
+		id4 = "author/manufacturer/other/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json"
+		id5 = "author/manufacturer/other/v1.0.0-20240201000000-a1b2c3d4e5f6.tm.json"
+	)
+	version := func(id string) model.FoundVersion {
+		return model.FoundVersion{IndexVersion: &model.IndexVersion{TMID: id}}
+	}
+	deprecated := version(id1)
+	deprecated.Lifecycle = &model.Lifecycle{State: model.LifecycleDeprecated, Successor: id4}
+	res := model.SearchResult{Entries: []model.FoundEntry{
+		{Name: "author/manufacturer/mpn", Versions: []model.FoundVersion{version(id3), version(id2), deprecated}},
+		{Name: "author/manufacturer/other", Versions: []model.FoundVersion{version(id5), version(id4)}},
+	}}
+
+	t.Run("ok", func(t *testing.T) {
+		r.On("List", mock.Anything, (*model.Filters)(nil)).Return(res, nil).Once()
+		ids, err := PruneCandidates(context.Background(), spec, model.RetentionPolicy{KeepLatest: 1}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{id2, id1}, ids)
+	})
+	t.Run("empty policy", func(t *testing.T) {
+		_, err := PruneCandidates(context.Background(), spec, model.RetentionPolicy{}, now)
+		assert.ErrorIs(t, err, model.ErrInvalidRetentionPolicy)
+	})
+}
@@ -0,0 +1,370 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	relTMExtends = "tm:extends"
+	keyTMRef     = "tm:ref"
+)
+
+type ResolveErrorType string
+
+const (
+	// ResolveErrCycle means that a TM directly or indirectly references itself
+	ResolveErrCycle = ResolveErrorType("cycle")
+	// ResolveErrNotFound means that the referenced TM or the part of it referenced by a JSON pointer does not exist
+	ResolveErrNotFound = ResolveErrorType("notFound")
+	// ResolveErrVersionNotFound means that the reference pins a version of a TM, which does not exist in the catalog,
+	// although other versions of that TM do
+	ResolveErrVersionNotFound = ResolveErrorType("versionNotFound")
+	// ResolveErrInvalidRef means that the reference cannot be resolved from the catalog, e.g. because it is an external URL
+	ResolveErrInvalidRef = ResolveErrorType("invalidRef")
+	// ResolveErrForbidden means that the referenced TM is outside the namespaces the resolution is restricted to
+	ResolveErrForbidden = ResolveErrorType("forbidden")
+)
+
+// ErrResolve is returned when a TM's references via tm:extends links or tm:ref cannot be resolved
+type ErrResolve struct {
+	Type ResolveErrorType
+	// Ref is the unresolvable reference as found in the TM
+	Ref string
+	// Chain contains the references followed from the requested TM up to the TM containing Ref
+	Chain []string
+	Err   error
+}
+
+func (e *ErrResolve) Error() string {
+	msg := fmt.Sprintf("could not resolve reference %s (%s)", e.Ref, e.Type)
+	if len(e.Chain) > 0 {
+		msg += fmt.Sprintf(" in %s", strings.Join(e.Chain, " -> "))
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ErrResolve) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrResolve) Code() string {
+	return string(e.Type)
+}
+
+// ResolveTM returns a self-contained version of the TM given as raw, in which all definitions inherited via tm:extends
+// links and all tm:ref references are merged in. Referenced TMs are fetched from the repos given by spec.
+// Definitions in the referencing TM take precedence over the referenced ones.
+// If namespaces is not nil, only TMs whose authors are among namespaces may be referenced.
+// Returns *ErrResolve, if any of the references cannot be resolved
+func ResolveTM(ctx context.Context, spec model.RepoSpec, id string, raw []byte, namespaces []string) ([]byte, error) {
+	var doc map[string]any
+	err := json.Unmarshal(raw, &doc)
+	if err != nil {
+		return nil, err
+	}
+	r := &tmResolver{
+		ctx:        ctx,
+		spec:       spec,
+		namespaces: namespaces,
+		docs:       map[string]map[string]any{id: doc},
+	}
+	res, err := r.resolveDoc(id, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return marshalIndent(res)
+}
+
+// marshalIndent marshals a TM or TD in a human-readable form and without escaping of HTML characters, so that
+// the result stays close to the way TMs are usually written
+func marshalIndent(v any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+type tmResolver struct {
+	ctx  context.Context
+	spec model.RepoSpec
+	// namespaces restricts the authors of referenced TMs, unless nil
+	namespaces []string
+	// docs caches fetched TMs by their id
+	docs map[string]map[string]any
+}
+
+// resolveDoc resolves all references in the TM with given id. chain contains the references that led to this TM,
+// including the TM itself
+func (r *tmResolver) resolveDoc(id string, chain []string) (map[string]any, error) {
+	doc := r.docs[id]
+	resolved, err := r.resolveRefs(id, doc, chain)
+	if err != nil {
+		return nil, err
+	}
+	res := resolved.(map[string]any)
+
+	var links, extends []any
+	for _, l := range utils.JsGetArray(res, "links") {
+		lm, _ := l.(map[string]any)
+		if rel, _ := utils.JsGetString(lm, "rel"); rel == relTMExtends {
+			extends = append(extends, lm)
+		} else {
+			links = append(links, l)
+		}
+	}
+	if len(extends) == 0 {
+		return res, nil
+	}
+
+	var base any = map[string]any{}
+	for _, e := range extends {
+		href, _ := utils.JsGetString(e.(map[string]any), "href")
+		extId, ptr, err := r.fetchRef(id, href, chain)
+		if err != nil {
+			return nil, err
+		}
+		if ptr != "" {
+			return nil, &ErrResolve{Type: ResolveErrInvalidRef, Ref: href, Chain: chain, Err: errors.New("tm:extends link must not contain a JSON pointer")}
+		}
+		if slices.Contains(chain, extId) {
+			return nil, &ErrResolve{Type: ResolveErrCycle, Ref: href, Chain: chain}
+		}
+		extDoc, err := r.resolveDoc(extId, appendChain(chain, extId))
+		if err != nil {
+			return nil, err
+		}
+		// identity and links of the extended TM do not belong to the extending one
+		delete(extDoc, "id")
+		delete(extDoc, "links")
+		base = mergeJSON(base, extDoc)
+	}
+	delete(res, "links")
+	if len(links) > 0 {
+		res["links"] = links
+	}
+	return mergeJSON(base, res).(map[string]any), nil
+}
+
+// resolveRefs replaces all objects containing tm:ref within node by the referenced definitions, merged with the
+// remaining members of these objects. docId is the id of the TM which contains node
+func (r *tmResolver) resolveRefs(docId string, node any, chain []string) (any, error) {
+	switch n := node.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(n))
+		var target any
+		for k, v := range n {
+			if k == keyTMRef {
+				ref, ok := v.(string)
+				if !ok {
+					return nil, &ErrResolve{Type: ResolveErrInvalidRef, Ref: fmt.Sprintf("%v", v), Chain: chain, Err: errors.New("tm:ref must be a string")}
+				}
+				var err error
+				target, err = r.resolveRef(docId, ref, chain)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			rv, err := r.resolveRefs(docId, v, chain)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = rv
+		}
+		if target != nil {
+			return mergeJSON(target, res), nil
+		}
+		return res, nil
+	case []any:
+		res := make([]any, len(n))
+		for i, v := range n {
+			rv, err := r.resolveRefs(docId, v, chain)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = rv
+		}
+		return res, nil
+	default:
+		return node, nil
+	}
+}
+
+// resolveRef returns the fully resolved definition referenced by a tm:ref
+func (r *tmResolver) resolveRef(docId, ref string, chain []string) (any, error) {
+	targetId, ptr, err := r.fetchRef(docId, ref, chain)
+	if err != nil {
+		return nil, err
+	}
+	key := targetId + "#" + ptr
+	if slices.Contains(chain, key) {
+		return nil, &ErrResolve{Type: ResolveErrCycle, Ref: ref, Chain: chain}
+	}
+	target, err := evalJSONPointer(r.docs[targetId], ptr)
+	if err != nil {
+		return nil, &ErrResolve{Type: ResolveErrNotFound, Ref: ref, Chain: chain, Err: err}
+	}
+	return r.resolveRefs(targetId, target, appendChain(chain, key))
+}
+
+// fetchRef fetches the TM referenced by ref, unless already fetched, and returns its id and the JSON pointer part of ref.
+// Relative references are resolved against the id of the referencing TM. chain is used only for error reporting
+func (r *tmResolver) fetchRef(docId, ref string, chain []string) (string, string, error) {
+	loc, ptr, _ := strings.Cut(ref, "#")
+	if loc == "" {
+		return docId, ptr, nil
+	}
+	if strings.HasPrefix(loc, "./") || strings.HasPrefix(loc, "../") {
+		loc = path.Join(path.Dir(docId), loc)
+	}
+
+	tmid, fn, err := model.ParseAsTMIDOrFetchName(loc)
+	if err != nil {
+		return "", "", &ErrResolve{Type: ResolveErrInvalidRef, Ref: ref, Chain: chain, Err: errors.New("only references to TMs in the catalog by id or fetch name are supported")}
+	}
+	var name string
+	if tmid != nil {
+		name = tmid.Name
+	} else {
+		name = fn.Name
+	}
+	if !r.inNamespaces(name) {
+		return "", "", &ErrResolve{Type: ResolveErrForbidden, Ref: ref, Chain: chain, Err: errors.New("referenced TM is outside the accessible namespaces")}
+	}
+	var id string
+	var foundIn model.RepoSpec
+	if tmid != nil {
+		id, foundIn = loc, r.spec
+	} else {
+		id, foundIn, err, _ = ResolveFetchName(r.ctx, r.spec, *fn)
+		if err != nil {
+			return "", "", r.notFoundError(ref, fn.Name, fn.Semver != "", chain, err)
+		}
+	}
+	if _, ok := r.docs[id]; ok {
+		return id, ptr, nil
+	}
+
+	fetchedId, raw, err, _ := FetchByTMID(r.ctx, foundIn, id, false)
+	if err != nil {
+		return "", "", r.notFoundError(ref, name, tmid != nil, chain, err)
+	}
+	if !r.inNamespaces(fetchedId) {
+		// the reference is an alias of a TM which has been moved to another namespace
+		return "", "", &ErrResolve{Type: ResolveErrForbidden, Ref: ref, Chain: chain, Err: errors.New("referenced TM is outside the accessible namespaces")}
+	}
+	var doc map[string]any
+	err = json.Unmarshal(raw, &doc)
+	if err != nil {
+		return "", "", &ErrResolve{Type: ResolveErrInvalidRef, Ref: ref, Chain: chain, Err: err}
+	}
+	r.docs[id] = doc
+	if fetchedId != id {
+		r.docs[fetchedId] = doc
+	}
+	return id, ptr, nil
+}
+
+// inNamespaces reports whether the author of the TM name is among r.namespaces, or r.namespaces is nil
+func (r *tmResolver) inNamespaces(name string) bool {
+	if r.namespaces == nil {
+		return true
+	}
+	author, _, _ := strings.Cut(name, "/")
+	return slices.ContainsFunc(r.namespaces, func(ns string) bool {
+		return strings.EqualFold(utils.SanitizeName(ns), utils.SanitizeName(author))
+	})
+}
+
+// notFoundError creates an error for a reference which could not be fetched. If the reference pins a version, checks
+// whether the TM name exists to differentiate between a missing TM and a missing version
+func (r *tmResolver) notFoundError(ref, name string, pinned bool, chain []string, err error) error {
+	var nfErr *model.ErrNotFound
+	if !errors.As(err, &nfErr) {
+		return &ErrResolve{Type: ResolveErrNotFound, Ref: ref, Chain: chain, Err: err}
+	}
+	if pinned && name != "" {
+		vs, vErr, _ := NewVersionsCommand().ListVersions(r.ctx, r.spec, name)
+		if vErr == nil && len(vs) > 0 {
+			return &ErrResolve{Type: ResolveErrVersionNotFound, Ref: ref, Chain: chain, Err: err}
+		}
+	}
+	return &ErrResolve{Type: ResolveErrNotFound, Ref: ref, Chain: chain, Err: err}
+}
+
+// appendChain returns a copy of chain with ref appended, so that branches of resolution do not share the backing array
+func appendChain(chain []string, ref string) []string {
+	return append(slices.Clone(chain), ref)
+}
+
+// evalJSONPointer returns the value within doc pointed to by a JSON pointer as defined in RFC 6901
+func evalJSONPointer(doc any, ptr string) (any, error) {
+	if ptr == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %s", ptr)
+	}
+	cur := doc
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[tok]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", ptr)
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("%s not found", ptr)
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("%s not found", ptr)
+		}
+	}
+	return cur, nil
+}
+
+// mergeJSON merges patch into base without modifying either. Objects are merged recursively, all other values in
+// patch replace the values in base
+func mergeJSON(base, patch any) any {
+	bm, bOk := base.(map[string]any)
+	pm, pOk := patch.(map[string]any)
+	if !bOk || !pOk {
+		return patch
+	}
+	res := make(map[string]any, len(bm)+len(pm))
+	for k, v := range bm {
+		res[k] = v
+	}
+	for k, v := range pm {
+		if bv, ok := res[k]; ok {
+			res[k] = mergeJSON(bv, v)
+		} else {
+			res[k] = v
+		}
+	}
+	return res
+}
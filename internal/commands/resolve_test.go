@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+)
+
+const (
+	resolveBaseId  = "tmc-test/corp/base/v1.0.0-20240101000000-aaaaaaaaaaaa.tm.json"
+	resolveChildId = "tmc-test/corp/child/v1.0.0-20240101000000-bbbbbbbbbbbb.tm.json"
+	resolveLoopAId = "tmc-test/corp/loop-a/v1.0.0-20240101000000-cccccccccccc.tm.json"
+	resolveLoopBId = "tmc-test/corp/loop-b/v1.0.0-20240101000000-dddddddddddd.tm.json"
+)
+
+func resolveTestTM(id, mpn string, extra string) string {
+	return fmt.Sprintf(`{
+  "@context": ["https://www.w3.org/2022/wot/td/v1.1"],
+  "@type": "tm:ThingModel",
+  "id": %q,
+  "title": %q,
+  "schema:author": {"schema:name": "tmc-test"},
+  "schema:manufacturer": {"schema:name": "corp"},
+  "schema:mpn": %q,
+  "version": {"model": "1.0.0"}%s
+}`, id, mpn, mpn, extra)
+}
+
+func setupResolveRepo(t *testing.T, tms map[string]string) model.RepoSpec {
+	t.Helper()
+	dir := t.TempDir()
+	for id, content := range tms {
+		p := filepath.Join(dir, id)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0770))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0660))
+	}
+	spec := model.NewDirSpec(dir)
+	r, err := repos.Get(spec)
+	require.NoError(t, err)
+	require.NoError(t, r.Index(context.Background()))
+	return spec
+}
+
+func resolveForTest(t *testing.T, spec model.RepoSpec, id, raw string) (map[string]any, error) {
+	t.Helper()
+	res, err := ResolveTM(context.Background(), spec, id, []byte(raw), nil)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(res, &m))
+	return m, nil
+}
+
+func TestResolveTM(t *testing.T) {
+	base := resolveTestTM(resolveBaseId, "base", `,
+  "links": [{"rel": "original", "href": "http://example.com/base"}],
+  "properties": {
+    "status": {"type": "string", "description": "base status", "readOnly": true},
+    "level": {"tm:ref": "#/definitions/level"}
+  },
+  "definitions": {"level": {"type": "integer", "minimum": 0}}`)
+	child := resolveTestTM(resolveChildId, "child", `,
+  "links": [{"rel": "tm:extends", "href": "../base/v1.0.0-20240101000000-aaaaaaaaaaaa.tm.json"}, {"rel": "manual", "href": "http://example.com/manual"}],
+  "properties": {
+    "status": {"description": "child status"},
+    "brightness": {"tm:ref": "tmc-test/corp/base#/properties/level", "maximum": 100}
+  }`)
+	loopA := resolveTestTM(resolveLoopAId, "loop-a", `,
+  "links": [{"rel": "tm:extends", "href": "tmc-test/corp/loop-b"}]`)
+	loopB := resolveTestTM(resolveLoopBId, "loop-b", `,
+  "links": [{"rel": "tm:extends", "href": "tmc-test/corp/loop-a"}]`)
+	spec := setupResolveRepo(t, map[string]string{
+		resolveBaseId:  base,
+		resolveChildId: child,
+		resolveLoopAId: loopA,
+		resolveLoopBId: loopB,
+	})
+
+	t.Run("extends and refs", func(t *testing.T) {
+		res, err := resolveForTest(t, spec, resolveChildId, child)
+		require.NoError(t, err)
+		assert.Equal(t, resolveChildId, res["id"])
+		assert.Equal(t, "child", res["title"])
+		assert.Contains(t, res, "definitions")
+		assert.Equal(t, []any{map[string]any{"rel": "manual", "href": "http://example.com/manual"}}, res["links"])
+		props := res["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "string", "description": "child status", "readOnly": true}, props["status"])
+		assert.Equal(t, map[string]any{"type": "integer", "minimum": float64(0)}, props["level"])
+		assert.Equal(t, map[string]any{"type": "integer", "minimum": float64(0), "maximum": float64(100)}, props["brightness"])
+	})
+
+	t.Run("without references", func(t *testing.T) {
+		raw := resolveTestTM(resolveChildId, "child", "")
+		res, err := resolveForTest(t, spec, resolveChildId, raw)
+		require.NoError(t, err)
+		var exp map[string]any
+		require.NoError(t, json.Unmarshal([]byte(raw), &exp))
+		assert.Equal(t, exp, res)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := resolveForTest(t, spec, resolveLoopAId, loopA)
+		var rErr *ErrResolve
+		if assert.ErrorAs(t, err, &rErr) {
+			assert.Equal(t, ResolveErrCycle, rErr.Type)
+			assert.Equal(t, "tmc-test/corp/loop-a", rErr.Ref)
+			assert.Equal(t, []string{resolveLoopAId, resolveLoopBId}, rErr.Chain)
+		}
+	})
+
+	t.Run("ref cycle", func(t *testing.T) {
+		raw := resolveTestTM(resolveChildId, "child", `, "properties": {"p": {"tm:ref": "#/properties/q"}, "q": {"tm:ref": "#/properties/p"}}`)
+		_, err := resolveForTest(t, spec, resolveChildId, raw)
+		var rErr *ErrResolve
+		if assert.ErrorAs(t, err, &rErr) {
+			assert.Equal(t, ResolveErrCycle, rErr.Type)
+		}
+	})
+
+	t.Run("restricted to namespaces", func(t *testing.T) {
+		_, err := ResolveTM(context.Background(), spec, resolveChildId, []byte(child), []string{"TMC-Test"})
+		assert.NoError(t, err)
+
+		_, err = ResolveTM(context.Background(), spec, resolveChildId, []byte(child), []string{"other"})
+		var rErr *ErrResolve
+		if assert.ErrorAs(t, err, &rErr) {
+			assert.Equal(t, ResolveErrForbidden, rErr.Type)
+			assert.Equal(t, "tmc-test/corp/base#/properties/level", rErr.Ref)
+		}
+	})
+
+	tests := []struct {
+		ref     string
+		expType ResolveErrorType
+	}{
+		{"tmc-test/corp/missing#/properties/status", ResolveErrNotFound},
+		{"tmc-test/corp/base#/properties/missing", ResolveErrNotFound},
+		{"tmc-test/corp/missing/v1.0.0-20240101000000-aaaaaaaaaaaa.tm.json#/properties/status", ResolveErrNotFound},
+		{"tmc-test/corp/base:2#/properties/status", ResolveErrVersionNotFound},
+		{"tmc-test/corp/base/v2.0.0-20240101000000-eeeeeeeeeeee.tm.json#/properties/status", ResolveErrVersionNotFound},
+		{"http://example.com/other.tm.json#/properties/status", ResolveErrInvalidRef},
+	}
+	for _, test := range tests {
+		t.Run(test.ref, func(t *testing.T) {
+			raw := resolveTestTM(resolveChildId, "child", fmt.Sprintf(`, "properties": {"p": {"tm:ref": %q}}`, test.ref))
+			_, err := resolveForTest(t, spec, resolveChildId, raw)
+			var rErr *ErrResolve
+			if assert.ErrorAs(t, err, &rErr) {
+				assert.Equal(t, test.expType, rErr.Type)
+				assert.Equal(t, test.ref, rErr.Ref)
+				assert.Equal(t, string(test.expType), rErr.Code())
+			}
+		})
+	}
+}
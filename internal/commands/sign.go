@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/signing"
+)
+
+var ErrNoTrustedKeys = errors.New("no trusted keys given or configured for the repo")
+
+// SignTM signs the TM with given id in the repo given by spec with key and stores the signature as reserved attachment
+// of the TM version. Returns ErrAttachmentExists if the TM is already signed, unless force is true
+func SignTM(ctx context.Context, spec model.RepoSpec, id string, key ed25519.PrivateKey, force bool) error {
+	repo, err := repos.Get(spec)
+	if err != nil {
+		return err
+	}
+	// the TM may not be signed yet, so it must be fetched without verifying
+	fid, raw, err := repos.Unwrap(repo).Fetch(ctx, id)
+	if err != nil {
+		return err
+	}
+	sig, err := signing.Sign(raw, key)
+	if err != nil {
+		return err
+	}
+	att := model.Attachment{Name: model.SignatureAttachmentName, MediaType: signing.MediaType}
+	return repo.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(fid), att, sig, force, "")
+}
+
+// VerifyTM verifies the signature of the TM with given id in the repo given by spec with the given keys. If no keys
+// are given, the trusted keys configured for the repo are used.
+// Returns the id of the key the signature has been verified with
+func VerifyTM(ctx context.Context, spec model.RepoSpec, id string, keys []ed25519.PublicKey) (string, error) {
+	repo, err := repos.Get(spec)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		if v, ok := repos.AsVerifyingRepo(repo); ok {
+			keys = v.TrustedKeys()
+		}
+	}
+	if len(keys) == 0 {
+		return "", ErrNoTrustedKeys
+	}
+	r := repos.Unwrap(repo)
+	fid, raw, err := r.Fetch(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	sig, err := r.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef(fid), model.SignatureAttachmentName)
+	if err != nil {
+		if errors.Is(err, model.ErrAttachmentNotFound) {
+			return "", signing.ErrUnsigned
+		}
+		return "", err
+	}
+	return signing.Verify(raw, sig, keys)
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/repos"
+	"github.com/wot-oss/tmc/internal/repos/mocks"
+	"github.com/wot-oss/tmc/internal/signing"
+	rMocks "github.com/wot-oss/tmc/internal/testutils/reposmocks"
+)
+
+func TestSignAndVerifyTM(t *testing.T) {
+	id := "author/manufacturer/mpn/v1.0.0-20231205123243-c49617d2e4fc.tm.json"
+	raw := []byte(`{"id": "` + id + `", "title": "Lamp"}`)
+	ref := model.NewTMIDAttachmentContainerRef(id)
+	spec := model.NewRepoSpec("r1")
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	r := mocks.NewRepo(t)
+	rMocks.MockReposGet(t, rMocks.CreateMockGetFunction(t, spec, r, nil))
+	r.On("Fetch", mock.Anything, id).Return(id, raw, nil)
+
+	var sig []byte
+	t.Run("sign", func(t *testing.T) {
+		r.On("ImportAttachment", mock.Anything, ref, model.Attachment{Name: model.SignatureAttachmentName, MediaType: signing.MediaType}, mock.Anything, false, "").
+			Run(func(args mock.Arguments) {
+				sig = args.Get(3).([]byte)
+			}).Return(nil).Once()
+
+		err := SignTM(context.Background(), spec, id, priv, false)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, sig)
+	})
+	t.Run("sign already signed", func(t *testing.T) {
+		r.On("ImportAttachment", mock.Anything, ref, mock.Anything, mock.Anything, false, "").Return(repos.ErrAttachmentExists).Once()
+
+		err := SignTM(context.Background(), spec, id, priv, false)
+
+		assert.ErrorIs(t, err, repos.ErrAttachmentExists)
+	})
+	t.Run("verify", func(t *testing.T) {
+		r.On("FetchAttachment", mock.Anything, ref, model.SignatureAttachmentName).Return(sig, nil).Once()
+
+		kid, err := VerifyTM(context.Background(), spec, id, []ed25519.PublicKey{pub})
+
+		assert.NoError(t, err)
+		assert.Equal(t, signing.KeyID(pub), kid)
+	})
+	t.Run("verify with other key", func(t *testing.T) {
+		r.On("FetchAttachment", mock.Anything, ref, model.SignatureAttachmentName).Return(sig, nil).Once()
+
+		_, err := VerifyTM(context.Background(), spec, id, []ed25519.PublicKey{otherPub})
+
+		assert.ErrorIs(t, err, signing.ErrInvalidSignature)
+	})
+	t.Run("verify unsigned", func(t *testing.T) {
+		r.On("FetchAttachment", mock.Anything, ref, model.SignatureAttachmentName).Return(nil, model.ErrAttachmentNotFound).Once()
+
+		_, err := VerifyTM(context.Background(), spec, id, []ed25519.PublicKey{pub})
+
+		assert.ErrorIs(t, err, signing.ErrUnsigned)
+	})
+	t.Run("verify without keys", func(t *testing.T) {
+		_, err := VerifyTM(context.Background(), spec, id, nil)
+
+		assert.ErrorIs(t, err, ErrNoTrustedKeys)
+	})
+}
@@ -0,0 +1,128 @@
+package validate
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+//go:embed modbus-old.schema.json
+var modbusOldValidationSchema string
+
+//go:embed modbus.schema.json
+var modbusValidationSchema string
+
+const (
+	modbusOldSchemaUrl = "resource://modbus-old.schema.json"
+	modbusSchemaUrl    = "resource://modbus.schema.json" // https://w3c.github.io/wot-binding-templates/bindings/protocols/modbus/modbus.schema.json
+)
+
+// BindingValidator validates a TM against the JSON schema of a protocol binding template
+type BindingValidator struct {
+	// Name is the name of the protocol binding
+	Name string
+	// Prefixes are the prefixes of the binding's vocabulary terms, e.g. "mqv". The validator applies to a TM which uses
+	// any term with one of these prefixes
+	Prefixes []string
+	// Schemes are URI schemes, e.g. "mqtt". The validator applies to a TM which has a form href or base with one of
+	// these schemes
+	Schemes []string
+	schema  *jsonschema.Schema
+}
+
+var bindingValidators []*BindingValidator
+
+// only bindings for which the W3C binding templates publish a JSON schema are registered, so that TMs are not judged
+// by rules which no specification defines
+func init() {
+	RegisterBindingValidator(MustNewBindingValidator("modbus", []string{"modv"}, nil, modbusSchemaUrl, modbusValidationSchema))
+	// modbus prefix and the respective schema are kept for backwards compatibility
+	RegisterBindingValidator(MustNewBindingValidator("modbus (legacy)", []string{"modbus"}, nil, modbusOldSchemaUrl, modbusOldValidationSchema))
+}
+
+// NewBindingValidator compiles schema into a BindingValidator, which applies to TMs using one of the given vocabulary
+// prefixes or URI schemes. The schema may reference the TM JSON schema by its canonical URL
+func NewBindingValidator(name string, prefixes, schemes []string, schemaUrl, schema string) (*BindingValidator, error) {
+	compiler := jsonschema.NewCompiler()
+	err := compiler.AddResource(tmSchemaUrl, strings.NewReader(tmValidationSchema))
+	if err != nil {
+		return nil, err
+	}
+	err = compiler.AddResource(schemaUrl, strings.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+	s, err := compiler.Compile(schemaUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &BindingValidator{Name: name, Prefixes: prefixes, Schemes: schemes, schema: s}, nil
+}
+
+// MustNewBindingValidator is like NewBindingValidator but panics if the schema cannot be compiled
+func MustNewBindingValidator(name string, prefixes, schemes []string, schemaUrl, schema string) *BindingValidator {
+	v, err := NewBindingValidator(name, prefixes, schemes, schemaUrl, schema)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// RegisterBindingValidator adds v to the validators used by ValidateBindings
+func RegisterBindingValidator(v *BindingValidator) {
+	bindingValidators = append(bindingValidators, v)
+}
+
+// BindingValidators returns all registered binding validators
+func BindingValidators() []*BindingValidator {
+	return slices.Clone(bindingValidators)
+}
+
+// Applies determines whether the validator applies to the TM given as raw bytes, which contains URIs with the given
+// protocol schemes
+func (v *BindingValidator) Applies(raw []byte, protocols []string) bool {
+	for _, p := range v.Prefixes {
+		if bytes.Contains(raw, []byte("\""+p+":")) {
+			return true
+		}
+	}
+	for _, s := range v.Schemes {
+		if slices.Contains(protocols, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the parsed TM against the binding's schema
+func (v *BindingValidator) Validate(parsed any) error {
+	err := v.schema.Validate(parsed)
+	if err != nil {
+		return fmt.Errorf("%s binding: %w", v.Name, err)
+	}
+	return nil
+}
+
+// ValidateBindings validates a TM against the schemas of all registered binding validators that apply to it.
+// Returns the names of the bindings the TM has been validated against and an error if any of the validations was
+// not successful
+func ValidateBindings(raw []byte, parsed any) ([]string, error) {
+	protocols, _ := model.CollectProtocols(raw)
+	var validated []string
+	for _, v := range bindingValidators {
+		if !v.Applies(raw, protocols) {
+			continue
+		}
+		validated = append(validated, v.Name)
+		err := v.Validate(parsed)
+		if err != nil {
+			return validated, err
+		}
+	}
+	return validated, nil
+}
@@ -0,0 +1,61 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindingValidator_Applies(t *testing.T) {
+	v := MustNewBindingValidator("test", []string{"tst"}, []string{"test"}, "resource://test.schema.json", `{}`)
+
+	assert.True(t, v.Applies([]byte(`{"forms": [{"tst:term": 1}]}`), nil))
+	assert.True(t, v.Applies([]byte(`{}`), []string{"http", "test"}))
+	assert.False(t, v.Applies([]byte(`{"description": "no tst: terms"}`), []string{"http"}))
+}
+
+func TestNewBindingValidator(t *testing.T) {
+	_, err := NewBindingValidator("broken", nil, nil, "resource://broken.schema.json", `{"type": 42}`)
+	assert.Error(t, err)
+}
+
+func TestValidateBindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		tm        string
+		validated []string
+		errPath   string
+	}{
+		{
+			name:      "no bindings",
+			tm:        `{"properties": {"on": {"type": "boolean"}}}`,
+			validated: nil,
+		},
+		{
+			name:      "binding without published schema",
+			tm:        `{"properties": {"on": {"forms": [{"href": "mqtt://broker/lamp/on", "mqv:qos": 1}]}}}`,
+			validated: nil,
+		},
+		{
+			name:      "invalid modbus",
+			tm:        `{"properties": {"on": {"forms": [{"href": "modbus+tcp://lamp/1", "modv:entity": "Register"}]}}}`,
+			validated: []string{"modbus"},
+			errPath:   "/properties/on/forms/0/modv:entity",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, parsed, err := parseString(test.tm)
+			assert.NoError(t, err)
+
+			validated, err := ValidateBindings(raw, parsed)
+
+			assert.Equal(t, test.validated, validated)
+			if test.errPath == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.errPath)
+			}
+		})
+	}
+}
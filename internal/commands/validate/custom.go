@@ -0,0 +1,257 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const (
+	// CustomSchemaSuffix is the file name suffix of custom JSON schemas, which every TM must validate against
+	CustomSchemaSuffix = ".schema.json"
+	// CustomRulesSuffix is the file name suffix of custom rule files
+	CustomRulesSuffix = ".rules.json"
+
+	customSchemaUrlPrefix = "resource://validation/"
+	codeCustomValidation  = "customValidation"
+)
+
+// Rule is a lint rule, which validates all parts of a TM selected by Target against Schema
+type Rule struct {
+	Name string `json:"name"`
+	// Description is reported instead of the schema validation errors, when the rule is violated
+	Description string `json:"description,omitempty"`
+	// Target is a JSON pointer selecting the parts of the TM the rule applies to. A '*' token selects all members of
+	// an object or all elements of an array. The rule applies to the whole TM, if Target is empty.
+	// Parts of the TM that do not exist are not validated
+	Target string          `json:"target,omitempty"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+type customSchema struct {
+	source string
+	schema *jsonschema.Schema
+}
+
+type customRule struct {
+	Rule
+	schema *jsonschema.Schema
+}
+
+// Violation is a single violation of a custom schema or rule
+type Violation struct {
+	// Source is the file name of the violated schema or the name of the violated rule
+	Source string
+	// Path is the JSON pointer to the violating part of the TM
+	Path string
+	// Keyword is the JSON schema keyword whose assertion failed. Empty if the violation is reported with the description
+	// of a rule
+	Keyword string
+	Message string
+}
+
+func (v Violation) String() string {
+	p := v.Path
+	if p == "" {
+		p = "/"
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Source, p, v.Message)
+}
+
+// ErrCustomValidation is returned when a TM violates the custom schemas or rules of a repository
+type ErrCustomValidation struct {
+	Violations []Violation
+}
+
+func (e *ErrCustomValidation) Error() string {
+	var vs []string
+	for _, v := range e.Violations {
+		vs = append(vs, v.String())
+	}
+	return fmt.Sprintf("TM violates the validation rules of the repository: %s", strings.Join(vs, "; "))
+}
+
+func (e *ErrCustomValidation) Code() string {
+	return codeCustomValidation
+}
+
+// CustomValidator validates TMs against the custom JSON schemas and rules of a repository
+type CustomValidator struct {
+	schemas []customSchema
+	rules   []customRule
+}
+
+// NewCustomValidator compiles the custom JSON schemas (*.schema.json) and rule files (*.rules.json) given as contents
+// by file name. Other files are ignored. Custom schemas may reference the TM JSON schema by its canonical URL.
+// Returns nil if there are neither schemas nor rules
+func NewCustomValidator(files map[string][]byte) (*CustomValidator, error) {
+	var names []string
+	for n := range files {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	// all schemas share one compiler, so that the TM schema they may reference is compiled only once
+	compiler := jsonschema.NewCompiler()
+	err := compiler.AddResource(tmSchemaUrl, strings.NewReader(tmValidationSchema))
+	if err != nil {
+		return nil, err
+	}
+	v := &CustomValidator{}
+	for _, name := range names {
+		switch {
+		case strings.HasSuffix(name, CustomSchemaSuffix):
+			s, err := compileCustomSchema(compiler, name, files[name])
+			if err != nil {
+				return nil, fmt.Errorf("invalid validation schema %s: %w", name, err)
+			}
+			v.schemas = append(v.schemas, customSchema{source: name, schema: s})
+		case strings.HasSuffix(name, CustomRulesSuffix):
+			rules, err := compileRules(compiler, name, files[name])
+			if err != nil {
+				return nil, fmt.Errorf("invalid validation rules file %s: %w", name, err)
+			}
+			v.rules = append(v.rules, rules...)
+		}
+	}
+	if len(v.schemas) == 0 && len(v.rules) == 0 {
+		return nil, nil
+	}
+	return v, nil
+}
+
+func compileCustomSchema(compiler *jsonschema.Compiler, name string, content []byte) (*jsonschema.Schema, error) {
+	url := customSchemaUrlPrefix + name
+	err := compiler.AddResource(url, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	return compiler.Compile(url)
+}
+
+func compileRules(compiler *jsonschema.Compiler, name string, content []byte) ([]customRule, error) {
+	var rf rulesFile
+	err := json.Unmarshal(content, &rf)
+	if err != nil {
+		return nil, err
+	}
+	var res []customRule
+	for i, r := range rf.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("%s#/rules/%d", name, i)
+		}
+		if len(r.Schema) == 0 {
+			return nil, fmt.Errorf("rule %s has no schema", r.Name)
+		}
+		if r.Target != "" && !strings.HasPrefix(r.Target, "/") {
+			return nil, fmt.Errorf("rule %s has an invalid target %s", r.Name, r.Target)
+		}
+		s, err := compileCustomSchema(compiler, fmt.Sprintf("%s/rules/%d", name, i), r.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		res = append(res, customRule{Rule: r, schema: s})
+	}
+	return res, nil
+}
+
+// Validate validates the parsed TM against all custom schemas and rules.
+// Returns *ErrCustomValidation listing all violations
+func (v *CustomValidator) Validate(parsed any) error {
+	var violations []Violation
+	for _, s := range v.schemas {
+		violations = append(violations, toViolations(s.source, "", "", s.schema.Validate(parsed))...)
+	}
+	for _, r := range v.rules {
+		for _, t := range selectTargets(parsed, r.Target) {
+			violations = append(violations, toViolations(r.Name, t.path, r.Description, r.schema.Validate(t.value))...)
+		}
+	}
+	if len(violations) > 0 {
+		return &ErrCustomValidation{Violations: violations}
+	}
+	return nil
+}
+
+// toViolations converts a schema validation error into violations. If msg is not empty, a single violation with msg is
+// returned instead of one for every failed assertion
+func toViolations(source, basePath, msg string, err error) []Violation {
+	if err == nil {
+		return nil
+	}
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []Violation{{Source: source, Path: basePath, Message: err.Error()}}
+	}
+	if msg != "" {
+		return []Violation{{Source: source, Path: basePath, Message: msg}}
+	}
+	var res []Violation
+	for _, l := range leafErrors(ve) {
+		res = append(res, Violation{Source: source, Path: basePath + l.InstanceLocation, Keyword: keyword(l.KeywordLocation), Message: l.Message})
+	}
+	return res
+}
+
+type target struct {
+	path  string
+	value any
+}
+
+// selectTargets returns all values within doc selected by a JSON pointer, which may contain '*' as wildcard token
+func selectTargets(doc any, ptr string) []target {
+	if ptr == "" {
+		return []target{{path: "", value: doc}}
+	}
+	res := []target{{path: "", value: doc}}
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		var next []target
+		for _, t := range res {
+			switch c := t.value.(type) {
+			case map[string]any:
+				if tok == "*" {
+					var keys []string
+					for k := range c {
+						keys = append(keys, k)
+					}
+					slices.Sort(keys)
+					for _, k := range keys {
+						next = append(next, target{path: t.path + "/" + escapeToken(k), value: c[k]})
+					}
+					continue
+				}
+				key := strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+				if v, ok := c[key]; ok {
+					next = append(next, target{path: t.path + "/" + tok, value: v})
+				}
+			case []any:
+				if tok == "*" {
+					for i, v := range c {
+						next = append(next, target{path: t.path + "/" + strconv.Itoa(i), value: v})
+					}
+					continue
+				}
+				i, err := strconv.Atoi(tok)
+				if err == nil && i >= 0 && i < len(c) {
+					next = append(next, target{path: t.path + "/" + tok, value: c[i]})
+				}
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+func escapeToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,169 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	manufacturerSchema = `{
+  "type": "object",
+  "properties": {
+    "schema:manufacturer": {
+      "type": "object",
+      "properties": {
+        "schema:name": {"enum": ["omnicorp", "Omni Corp"]}
+      }
+    }
+  }
+}`
+	unitRules = `{
+  "rules": [
+    {
+      "name": "numeric-properties-have-unit",
+      "description": "numeric properties must declare a unit",
+      "target": "/properties/*",
+      "schema": {
+        "if": {"properties": {"type": {"enum": ["number", "integer"]}}, "required": ["type"]},
+        "then": {"required": ["unit"]}
+      }
+    },
+    {
+      "target": "/actions/*",
+      "schema": {"required": ["description"]}
+    }
+  ]
+}`
+)
+
+func TestNewCustomValidator(t *testing.T) {
+	t.Run("no files", func(t *testing.T) {
+		v, err := NewCustomValidator(map[string][]byte{})
+		assert.NoError(t, err)
+		assert.Nil(t, v)
+	})
+	t.Run("only unrelated files", func(t *testing.T) {
+		v, err := NewCustomValidator(map[string][]byte{"README.md": []byte("# rules")})
+		assert.NoError(t, err)
+		assert.Nil(t, v)
+	})
+	t.Run("valid files", func(t *testing.T) {
+		v, err := NewCustomValidator(map[string][]byte{
+			"manufacturer.schema.json": []byte(manufacturerSchema),
+			"units.rules.json":         []byte(unitRules),
+		})
+		assert.NoError(t, err)
+		if assert.NotNil(t, v) {
+			assert.Len(t, v.schemas, 1)
+			assert.Len(t, v.rules, 2)
+			assert.Equal(t, "units.rules.json#/rules/1", v.rules[1].Name)
+		}
+	})
+	t.Run("schema referencing the TM schema", func(t *testing.T) {
+		v, err := NewCustomValidator(map[string][]byte{
+			"tm.schema.json": []byte(`{"$ref": "` + tmSchemaUrl + `"}`),
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, v)
+	})
+	t.Run("invalid schema", func(t *testing.T) {
+		_, err := NewCustomValidator(map[string][]byte{"broken.schema.json": []byte(`{"type": 42}`)})
+		assert.ErrorContains(t, err, "invalid validation schema broken.schema.json")
+	})
+	t.Run("invalid rules file", func(t *testing.T) {
+		_, err := NewCustomValidator(map[string][]byte{"broken.rules.json": []byte(`{"rules": {}}`)})
+		assert.ErrorContains(t, err, "invalid validation rules file broken.rules.json")
+	})
+	t.Run("rule without schema", func(t *testing.T) {
+		_, err := NewCustomValidator(map[string][]byte{"r.rules.json": []byte(`{"rules": [{"name": "empty"}]}`)})
+		assert.ErrorContains(t, err, "rule empty has no schema")
+	})
+	t.Run("rule with invalid target", func(t *testing.T) {
+		_, err := NewCustomValidator(map[string][]byte{"r.rules.json": []byte(`{"rules": [{"name": "t", "target": "properties", "schema": {}}]}`)})
+		assert.ErrorContains(t, err, "rule t has an invalid target properties")
+	})
+}
+
+func TestCustomValidator_Validate(t *testing.T) {
+	v, err := NewCustomValidator(map[string][]byte{
+		"manufacturer.schema.json": []byte(manufacturerSchema),
+		"units.rules.json":         []byte(unitRules),
+	})
+	assert.NoError(t, err)
+
+	t.Run("valid TM", func(t *testing.T) {
+		_, parsed, _ := parseString(`{
+  "schema:manufacturer": {"schema:name": "omnicorp"},
+  "properties": {
+    "dim": {"type": "integer", "unit": "percent"},
+    "on": {"type": "boolean"}
+  },
+  "actions": {"toggle": {"description": "toggles the lamp"}}
+}`)
+		assert.NoError(t, v.Validate(parsed))
+	})
+	t.Run("no affordances", func(t *testing.T) {
+		_, parsed, _ := parseString(`{"schema:manufacturer": {"schema:name": "Omni Corp"}}`)
+		assert.NoError(t, v.Validate(parsed))
+	})
+	t.Run("violations", func(t *testing.T) {
+		_, parsed, _ := parseString(`{
+  "schema:manufacturer": {"schema:name": "acme"},
+  "properties": {
+    "dim": {"type": "integer"},
+    "temp": {"type": "number"},
+    "on": {"type": "boolean"}
+  },
+  "actions": {"toggle": {}}
+}`)
+		err := v.Validate(parsed)
+		var cErr *ErrCustomValidation
+		if assert.True(t, errors.As(err, &cErr)) {
+			assert.Equal(t, "customValidation", cErr.Code())
+			if assert.Len(t, cErr.Violations, 4) {
+				assert.Equal(t, "manufacturer.schema.json", cErr.Violations[0].Source)
+				assert.Equal(t, "/schema:manufacturer/schema:name", cErr.Violations[0].Path)
+				assert.Equal(t, Violation{Source: "numeric-properties-have-unit", Path: "/properties/dim", Message: "numeric properties must declare a unit"}, cErr.Violations[1])
+				assert.Equal(t, Violation{Source: "numeric-properties-have-unit", Path: "/properties/temp", Message: "numeric properties must declare a unit"}, cErr.Violations[2])
+				assert.Equal(t, "units.rules.json#/rules/1", cErr.Violations[3].Source)
+				assert.Equal(t, "/actions/toggle", cErr.Violations[3].Path)
+				assert.Contains(t, cErr.Violations[3].Message, "description")
+			}
+			assert.Contains(t, err.Error(), "numeric-properties-have-unit: /properties/dim: numeric properties must declare a unit")
+		}
+	})
+}
+
+func TestSelectTargets(t *testing.T) {
+	_, doc, _ := parseString(`{"a": {"x/y": [1, 2], "b": {"c": 3}}}`)
+
+	assert.Equal(t, []target{{path: "", value: doc}}, selectTargets(doc, ""))
+	assert.Equal(t, []target{{path: "/a/b/c", value: float64(3)}}, selectTargets(doc, "/a/b/c"))
+	assert.Equal(t, []target{{path: "/a/x~1y/1", value: float64(2)}}, selectTargets(doc, "/a/x~1y/1"))
+	assert.Equal(t, []target{
+		{path: "/a/b/c", value: float64(3)},
+		{path: "/a/x~1y/0", value: float64(1)},
+		{path: "/a/x~1y/1", value: float64(2)},
+	}, selectTargets(doc, "/a/*/*"))
+	assert.Empty(t, selectTargets(doc, "/a/missing"))
+	assert.Empty(t, selectTargets(doc, "/a/x~1y/5"))
+}
+
+func TestValidateThingModel_WithCustomValidator(t *testing.T) {
+	raw, _, err := parseJsonFile("../../../test/data/validate/omnilamp.json")
+	assert.NoError(t, err)
+
+	v, err := NewCustomValidator(map[string][]byte{
+		"title.schema.json": []byte(`{"properties": {"title": {"pattern": "^Acme"}}}`),
+	})
+	assert.NoError(t, err)
+
+	_, err = ValidateThingModel(raw, nil)
+	assert.NoError(t, err)
+	tm, err := ValidateThingModel(raw, v)
+	assert.NotNil(t, tm)
+	var cErr *ErrCustomValidation
+	assert.True(t, errors.As(err, &cErr))
+}
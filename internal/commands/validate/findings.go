@@ -0,0 +1,105 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+const (
+	// SourceJSON is the source of findings about a TM which is not well-formed JSON
+	SourceJSON = "json"
+	// SourceTmcMandatory is the source of findings about fields missing for importing a TM into a catalog
+	SourceTmcMandatory = "tmc-mandatory"
+	// SourceTMSchema is the source of findings of the W3C Thing Model JSON schema
+	SourceTMSchema = "tm-schema"
+)
+
+// Finding is a single problem found while validating a TM
+type Finding struct {
+	// Source names what reported the finding: one of the Source* constants, the name of a binding validator
+	// followed by " binding", or the file name of a custom schema or name of a custom rule of the repository
+	Source string `json:"source"`
+	// Pointer is the JSON pointer to the offending part of the TM. Empty for the TM as a whole
+	Pointer string `json:"pointer"`
+	// Keyword is the JSON schema keyword whose assertion failed, e.g. 'required' or 'type'
+	Keyword string `json:"keyword,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	p := f.Pointer
+	if p == "" {
+		p = "/"
+	}
+	return fmt.Sprintf("%s: %s: %s", f.Source, p, f.Message)
+}
+
+// CheckThingModel validates raw like ValidateThingModel, but does not stop at the first failed validation. Instead, it
+// collects the findings of all validations which can be performed. Returns the parsed *model.ThingModel, if raw
+// contains the fields mandatory for import, and the findings. A TM without findings is valid
+func CheckThingModel(raw []byte, custom *CustomValidator) (*model.ThingModel, []Finding) {
+	var parsed any
+	err := json.Unmarshal(raw, &parsed)
+	if err != nil {
+		return nil, []Finding{{Source: SourceJSON, Message: err.Error()}}
+	}
+
+	tm, err := ValidateAsTmcImportable(raw, parsed)
+	findings := toFindings(SourceTmcMandatory, err)
+	findings = append(findings, toFindings(SourceTMSchema, ValidateAsTM(raw, parsed))...)
+
+	protocols, _ := model.CollectProtocols(raw)
+	for _, v := range bindingValidators {
+		if v.Applies(raw, protocols) {
+			findings = append(findings, toFindings(v.Name+" binding", v.schema.Validate(parsed))...)
+		}
+	}
+
+	if custom != nil {
+		var cErr *ErrCustomValidation
+		if err := custom.Validate(parsed); errors.As(err, &cErr) {
+			for _, v := range cErr.Violations {
+				findings = append(findings, Finding{Source: v.Source, Pointer: v.Path, Keyword: v.Keyword, Message: v.Message})
+			}
+		}
+	}
+	return tm, findings
+}
+
+// toFindings converts the error of a validation reported by source into findings, one for every failed assertion
+func toFindings(source string, err error) []Finding {
+	if err == nil {
+		return nil
+	}
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []Finding{{Source: source, Message: err.Error()}}
+	}
+	var res []Finding
+	for _, l := range leafErrors(ve) {
+		res = append(res, Finding{Source: source, Pointer: l.InstanceLocation, Keyword: keyword(l.KeywordLocation), Message: l.Message})
+	}
+	return res
+}
+
+// leafErrors returns the validation errors without causes within e
+func leafErrors(e *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(e.Causes) == 0 {
+		return []*jsonschema.ValidationError{e}
+	}
+	var res []*jsonschema.ValidationError
+	for _, c := range e.Causes {
+		res = append(res, leafErrors(c)...)
+	}
+	return res
+}
+
+// keyword returns the failed keyword, i.e. the last token, of a schema keyword location
+func keyword(location string) string {
+	return location[strings.LastIndex(location, "/")+1:]
+}
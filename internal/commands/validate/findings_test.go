@@ -0,0 +1,67 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+func TestCheckThingModel(t *testing.T) {
+	t.Run("valid TM", func(t *testing.T) {
+		_, raw, err := utils.ReadRequiredFile("../../../test/data/validate/omnilamp.json")
+		assert.NoError(t, err)
+		tm, findings := CheckThingModel(raw, nil)
+		assert.NotNil(t, tm)
+		assert.Empty(t, findings)
+	})
+	t.Run("invalid JSON", func(t *testing.T) {
+		tm, findings := CheckThingModel([]byte("{"), nil)
+		assert.Nil(t, tm)
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, SourceJSON, findings[0].Source)
+		}
+	})
+	t.Run("violating the TM schema", func(t *testing.T) {
+		_, raw, err := utils.ReadRequiredFile("../../../test/data/validate/omnilamp-broken.json")
+		assert.NoError(t, err)
+		tm, findings := CheckThingModel(raw, nil)
+		assert.NotNil(t, tm)
+		assert.Contains(t, findings, Finding{Source: SourceTMSchema, Pointer: "/properties/status/readOnly", Keyword: "type", Message: "expected boolean, but got string"})
+	})
+	t.Run("missing mandatory fields", func(t *testing.T) {
+		tm, findings := CheckThingModel([]byte(`{"@context": "https://www.w3.org/2022/wot/td/v1.1", "@type": "tm:ThingModel", "title": "Lamp"}`), nil)
+		assert.Nil(t, tm)
+		if assert.NotEmpty(t, findings) {
+			assert.Equal(t, SourceTmcMandatory, findings[0].Source)
+			assert.Equal(t, "required", findings[0].Keyword)
+		}
+	})
+	t.Run("violating a binding", func(t *testing.T) {
+		_, raw, err := utils.ReadRequiredFile("../../../test/data/validate/modbus-senseall-broken.json")
+		assert.NoError(t, err)
+		_, findings := CheckThingModel(raw, nil)
+		var sources []string
+		for _, f := range findings {
+			sources = append(sources, f.Source)
+			if f.Source == "modbus binding" {
+				assert.Equal(t, "/properties/SERIAL_NUMBER/forms/0/modv:zeroBasedAddressing", f.Pointer)
+			}
+		}
+		assert.Contains(t, sources, "modbus binding")
+	})
+	t.Run("custom rules", func(t *testing.T) {
+		_, raw, err := utils.ReadRequiredFile("../../../test/data/validate/omnilamp.json")
+		assert.NoError(t, err)
+		custom, err := NewCustomValidator(map[string][]byte{
+			"title.schema.json": []byte(`{"properties": {"title": {"maxLength": 3}}}`),
+		})
+		assert.NoError(t, err)
+		_, findings := CheckThingModel(raw, custom)
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, "title.schema.json", findings[0].Source)
+			assert.Equal(t, "/title", findings[0].Pointer)
+			assert.Equal(t, "maxLength", findings[0].Keyword)
+		}
+	})
+}
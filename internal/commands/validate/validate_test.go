@@ -36,36 +36,60 @@ func TestValidateAsTM(t *testing.T) {
 	assert.Contains(t, err.Error(), "/properties/status/readOnly")
 
 }
-func TestValidateAsModbus(t *testing.T) {
+func TestValidateAsTD(t *testing.T) {
+	raw, parsed, err := parseJsonFile("../../../test/data/validate/omnilamp-td.json")
+	assert.NoError(t, err)
+	err = ValidateAsTD(raw, parsed)
+	assert.NoError(t, err)
+
+	raw, parsed, err = parseJsonFile("../../../test/data/validate/omnilamp.json")
+	assert.NoError(t, err)
+	err = ValidateAsTD(raw, parsed)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing properties: 'security', 'securityDefinitions'")
+
+	raw, parsed, err = parseString(`{
+  "@context": "https://www.w3.org/2022/wot/td/v1.1",
+  "@type": "tm:ThingModel",
+  "title": "Lamp",
+  "securityDefinitions": {"nosec_sc": {"scheme": "nosec"}},
+  "security": "nosec_sc"
+}`)
+	assert.NoError(t, err)
+	err = ValidateAsTD(raw, parsed)
+	assert.Error(t, err)
+}
+func TestValidateBindings_Modbus(t *testing.T) {
 	raw, parsed, err := parseJsonFile("../../../test/data/validate/omnilamp.json")
 	assert.NoError(t, err)
-	v, err := ValidateAsModbus(raw, parsed)
-	assert.False(t, v)
+	v, err := ValidateBindings(raw, parsed)
+	assert.NotContains(t, v, "modbus")
+	assert.NotContains(t, v, "modbus (legacy)")
 	assert.NoError(t, err)
 
 	raw, parsed, err = parseJsonFile("../../../test/data/validate/modbus-senseall.json")
 	assert.NoError(t, err)
-	v, err = ValidateAsModbus(raw, parsed)
-	assert.True(t, v)
+	v, err = ValidateBindings(raw, parsed)
+	assert.Contains(t, v, "modbus")
 	assert.NoError(t, err)
 
 	raw, parsed, err = parseJsonFile("../../../test/data/validate/modbus-senseall-broken.json")
 	assert.NoError(t, err)
-	v, err = ValidateAsModbus(raw, parsed)
-	assert.True(t, v)
+	v, err = ValidateBindings(raw, parsed)
+	assert.Contains(t, v, "modbus")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "/properties/SERIAL_NUMBER/forms/0/modv:zeroBasedAddressing")
 
 	raw, parsed, err = parseJsonFile("../../../test/data/validate/modbus-old-senseall.json")
 	assert.NoError(t, err)
-	v, err = ValidateAsModbus(raw, parsed)
-	assert.True(t, v)
+	v, err = ValidateBindings(raw, parsed)
+	assert.Contains(t, v, "modbus (legacy)")
 	assert.NoError(t, err)
 
 	raw, parsed, err = parseJsonFile("../../../test/data/validate/modbus-old-senseall-broken.json")
 	assert.NoError(t, err)
-	v, err = ValidateAsModbus(raw, parsed)
-	assert.True(t, v)
+	v, err = ValidateBindings(raw, parsed)
+	assert.Contains(t, v, "modbus (legacy)")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "/properties/SERIAL_NUMBER/forms/0/modbus:zeroBasedAddressing")
 
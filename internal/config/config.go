@@ -23,6 +23,14 @@ const (
 	KeyJWTScopesPrefix      = "jwtScopesPrefix"
 	KeyJWKSURL              = "jwksURL"
 	KeyDefaultScopes        = "defaultScopesPath"
+	KeyAPIKeyValidation     = "apiKeyValidation"
+	KeyAPIKeysFile          = "apiKeysFile"
+	KeyWebhookURLs          = "webhookURLs"
+	KeyWebhookSecret        = "webhookSecret"
+	KeyTracingExporter      = "tracingExporter"
+	KeyTracingFile          = "tracingFile"
+	KeyImportMaxSize        = "importMaxSize"
+	KeyImportMaxFiles       = "importMaxFiles"
 	KeyColumnWidth          = "columnWidth"
 	EnvPrefix               = "tmc"
 	LogLevelOff             = "off"
@@ -36,6 +44,7 @@ var DefaultScopesFile string
 
 const DefaultConfigDir = "~/.tm-catalog"
 const DefaultScopesPath = ""
+const DefaultAPIKeysFileName = "apikeys.json"
 
 func init() {
 	viper.SetDefault(KeyLogLevel, LogLevelOff)
@@ -61,7 +70,15 @@ func init() {
 	_ = viper.BindEnv(KeyJWTServiceID)         // env variable name = tmc_jwtvalidation
 	_ = viper.BindEnv(KeyJWTScopesPrefix)      // env variable name = tmc_jwtScopesPrefix
 	_ = viper.BindEnv(KeyJWKSURL)              // env variable name = tmc_jwksurl
+	_ = viper.BindEnv(KeyAPIKeyValidation)     // env variable name = tmc_apikeyvalidation
+	_ = viper.BindEnv(KeyAPIKeysFile)          // env variable name = tmc_apikeysfile
 	_ = viper.BindEnv(KeyColumnWidth)          // env variable name = tmc_columnwidth
+	_ = viper.BindEnv(KeyWebhookURLs)          // env variable name = tmc_webhookurls
+	_ = viper.BindEnv(KeyWebhookSecret)        // env variable name = tmc_webhooksecret
+	_ = viper.BindEnv(KeyTracingExporter)      // env variable name = tmc_tracingexporter
+	_ = viper.BindEnv(KeyTracingFile)          // env variable name = tmc_tracingfile
+	_ = viper.BindEnv(KeyImportMaxSize)        // env variable name = tmc_importmaxsize
+	_ = viper.BindEnv(KeyImportMaxFiles)       // env variable name = tmc_importmaxfiles
 	_ = viper.BindEnv(KeyDefaultScopes)
 }
 
@@ -34,6 +34,7 @@ func (m *IndexToSearchResultMapper) ToFoundEntry(e *IndexEntry) FoundEntry {
 		Mpn:                 e.Mpn,
 		Author:              e.Author,
 		Versions:            m.ToFoundVersions(e.Versions),
+		Labels:              e.Labels,
 		FoundIn:             m.foundIn,
 		AttachmentContainer: e.AttachmentContainer,
 	}
@@ -91,6 +92,7 @@ func (m *InventoryResponseToSearchResultMapper) ToFoundEntry(e server.InventoryE
 		Mpn:          e.SchemaMpn,
 		Author:       SchemaAuthor{Name: e.SchemaAuthor.SchemaName},
 		Versions:     m.ToFoundVersions(e.Versions),
+		Labels:       m.ToLabels(e.Labels),
 		AttachmentContainer: AttachmentContainer{
 			Attachments: m.ToFoundVersionAttachments(e.Attachments),
 		},
@@ -116,6 +118,8 @@ func (m *InventoryResponseToSearchResultMapper) ToFoundVersion(v server.Inventor
 			Digest:      v.Digest,
 			TimeStamp:   v.Timestamp,
 			ExternalID:  v.ExternalID,
+			Lifecycle:   m.ToLifecycle(v.Lifecycle),
+			Labels:      m.ToLabels(v.Labels),
 			AttachmentContainer: AttachmentContainer{
 				Attachments: m.ToFoundVersionAttachments(v.Attachments),
 			},
@@ -125,6 +129,27 @@ func (m *InventoryResponseToSearchResultMapper) ToFoundVersion(v server.Inventor
 	return version
 }
 
+func (m *InventoryResponseToSearchResultMapper) ToLifecycle(lc *server.TMLifecycle) *Lifecycle {
+	if lc == nil || lc.State == server.Active {
+		return nil
+	}
+	res := &Lifecycle{State: LifecycleState(lc.State)}
+	if lc.Reason != nil {
+		res.Reason = *lc.Reason
+	}
+	if lc.Successor != nil {
+		res.Successor = *lc.Successor
+	}
+	return res
+}
+
+func (m *InventoryResponseToSearchResultMapper) ToLabels(labels *server.Labels) []string {
+	if labels == nil {
+		return nil
+	}
+	return *labels
+}
+
 func (m *InventoryResponseToSearchResultMapper) ToFoundVersionAttachments(al *server.AttachmentsList) []Attachment {
 	if al == nil {
 		return nil
@@ -45,13 +45,13 @@ func ParseThingModel(data []byte) (*ThingModel, error) {
 		return nil, err
 	}
 
-	protos, _ := collectProtocols(data)
+	protos, _ := CollectProtocols(data)
 	tm.protocols = protos
 	return &tm, nil
 }
 
-// collectProtocols parses byte array containing a TM and returns all URL protocol schemes contained in the TM
-func collectProtocols(data []byte) ([]string, error) {
+// CollectProtocols parses byte array containing a TM and returns all URL protocol schemes contained in the TM
+func CollectProtocols(data []byte) ([]string, error) {
 	var tm map[string]any
 	err := json.Unmarshal(data, &tm)
 	if err != nil {
@@ -99,15 +99,17 @@ func extractFormsProtocols(m map[string]any) []string {
 	return protos
 }
 
-var placeholdersRegexp = regexp.MustCompile("{{.+}}")
+// PlaceholdersRegexp matches a placeholder '{{NAME}}' in a TM, capturing its name
+var PlaceholdersRegexp = regexp.MustCompile(`{{([^{}]+)}}`)
 
 func extractProtocol(uri string) string {
 	if uri == "" {
 		return ""
 	}
 
-	// replace any placeholders in the URI with a string that will most probably make the resulting URI a valid one for parsing
-	uri = placeholdersRegexp.ReplaceAllString(uri, "example.com")
+	// replace any placeholders in the URI with a string that will most probably make the resulting URI a valid one for parsing,
+	// whether the placeholder stands for a host, a port or a path segment
+	uri = PlaceholdersRegexp.ReplaceAllString(uri, "0")
 
 	u, err := url.Parse(uri)
 	if err != nil { //skip unparseable hrefs
@@ -117,7 +119,8 @@ func extractProtocol(uri string) string {
 }
 
 type FetchName struct {
-	Name   string
+	Name string
+	// Semver is either a full or partial semantic version, e.g. v1.2, or a version range constraint, e.g. ^1.2 or >=1.0 <2.0
 	Semver string
 }
 
@@ -131,7 +134,7 @@ func ParseFetchName(fetchName string) (FetchName, error) {
 
 	// Check if there are enough submatches
 	if len(matches) < 2 {
-		err := fmt.Errorf("%w: %s - must be NAME[:SEMVER]", ErrInvalidFetchName, fetchName)
+		err := fmt.Errorf("%w: %s - must be NAME[:SEMVER] or NAME[:RANGE]", ErrInvalidFetchName, fetchName)
 		return FetchName{}, err
 	}
 
@@ -140,14 +143,23 @@ func ParseFetchName(fetchName string) (FetchName, error) {
 	fn.Name = matches[1]
 	if len(matches) > 4 && matches[4] != "" {
 		fn.Semver = matches[4]
-		_, err := semver.NewVersion(fn.Semver)
-		if err != nil {
-			return FetchName{}, fmt.Errorf("%w: %s - invalid semantic version", ErrInvalidFetchName, fetchName)
+		if !isValidSemverOrRange(fn.Semver) {
+			return FetchName{}, fmt.Errorf("%w: %s - invalid semantic version or version range", ErrInvalidFetchName, fetchName)
 		}
 	}
 	return fn, nil
 }
 
+// isValidSemverOrRange checks whether s is a full or partial semantic version or a version range constraint
+// as understood by github.com/Masterminds/semver
+func isValidSemverOrRange(s string) bool {
+	if _, err := semver.NewVersion(s); err == nil {
+		return true
+	}
+	_, err := semver.NewConstraint(s)
+	return err == nil
+}
+
 // ParseAsTMIDOrFetchName parses idOrName as model.TMID. If that fails, parses it as FetchName.
 // Returns error is idOrName is not valid as either. Only one of returned pointers may be not nil
 func ParseAsTMIDOrFetchName(idOrName string) (*TMID, *FetchName, error) {
@@ -239,6 +251,7 @@ type CheckResultType int
 const (
 	CheckOK = CheckResultType(iota)
 	CheckErr
+	CheckWarn
 )
 
 func (t CheckResultType) String() string {
@@ -247,6 +260,8 @@ func (t CheckResultType) String() string {
 		return "OK"
 	case CheckErr:
 		return "error"
+	case CheckWarn:
+		return "warning"
 	default:
 		return "unknown"
 	}
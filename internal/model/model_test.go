@@ -27,6 +27,12 @@ func TestParseFetchName(t *testing.T) {
 		{"author/manufacturer/mpn:v1.2.3", false, "author/manufacturer/mpn", "v1.2.3"},
 		{"author/manufacturer/mpn/folder/structure:1.2.3", false, "author/manufacturer/mpn/folder/structure", "1.2.3"},
 		{"author/manufacturer/mpn/folder/structure:v1.2.3-alpha1", false, "author/manufacturer/mpn/folder/structure", "v1.2.3-alpha1"},
+		{"author/manufacturer/mpn:^1.2", false, "author/manufacturer/mpn", "^1.2"},
+		{"author/manufacturer/mpn:~1.4.0", false, "author/manufacturer/mpn", "~1.4.0"},
+		{"author/manufacturer/mpn:>=1.0 <2.0", false, "author/manufacturer/mpn", ">=1.0 <2.0"},
+		{"author/manufacturer/mpn:1.x", false, "author/manufacturer/mpn", "1.x"},
+		{"author/manufacturer/mpn:>=1.0 <", true, "", ""},
+		{"author/manufacturer/mpn:^^1", true, "", ""},
 	}
 
 	for _, test := range tests {
@@ -45,7 +51,7 @@ func TestParseFetchName(t *testing.T) {
 func TestCollectProtocols(t *testing.T) {
 	_, data, err := utils.ReadRequiredFile("../../test/data/model/lightall-with-protocols.json")
 	assert.NoError(t, err)
-	protos, err := collectProtocols(data)
+	protos, err := CollectProtocols(data)
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"coap", "coaps", "https", "modbus+tcp", "modbus+tls", "opcua+tcp", "opcua+tls"}, protos)
 }
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRetentionPolicy = errors.New("invalid retention policy")
+
+// RetentionPolicy determines which versions of a TM name are kept when pruning a repository. A version is kept if
+// any of the rules keeps it. Regardless of the rules, the latest version of a TM name, labelled versions and versions
+// which are named as successor of another version are always kept
+type RetentionPolicy struct {
+	// KeepLatest is the number of latest pseudo-versions to keep per semver base, e.g. v1.0.0. Zero disables the rule
+	KeepLatest int `json:"keep_latest,omitempty"`
+	// KeepMinorHeads keeps the latest version of each major.minor release
+	KeepMinorHeads bool `json:"keep_minor_heads,omitempty"`
+	// KeepYoungerThan keeps versions whose timestamp is younger than the given duration. Zero disables the rule
+	KeepYoungerThan time.Duration `json:"-"`
+}
+
+// IsEmpty returns true if the policy has no rules. Applying an empty policy would remove all but the protected versions
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.KeepLatest <= 0 && !p.KeepMinorHeads && p.KeepYoungerThan <= 0
+}
+
+func (p RetentionPolicy) String() string {
+	var rules []string
+	if p.KeepLatest > 0 {
+		rules = append(rules, fmt.Sprintf("keep latest %d per version", p.KeepLatest))
+	}
+	if p.KeepMinorHeads {
+		rules = append(rules, "keep major.minor heads")
+	}
+	if p.KeepYoungerThan > 0 {
+		rules = append(rules, fmt.Sprintf("keep younger than %s", p.KeepYoungerThan))
+	}
+	if len(rules) == 0 {
+		return "none"
+	}
+	return strings.Join(rules, ", ")
+}
+
+// ParseRetentionAge parses an age like "90d", "12h" or "1h30m". In addition to the units of time.ParseDuration,
+// the unit "d" for days is accepted
+func ParseRetentionAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: invalid age %s", ErrInvalidRetentionPolicy, s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%w: invalid age %s", ErrInvalidRetentionPolicy, s)
+	}
+	return d, nil
+}
+
+// Expired returns the versions of a TM name which are not kept by the policy as of now. The versions are expected to
+// belong to the same TM name. successors holds the ids of versions which are referenced as successor and must be kept
+func (p RetentionPolicy) Expired(versions []*IndexVersion, successors map[string]bool, now time.Time) []*IndexVersion {
+	if len(versions) == 0 {
+		return nil
+	}
+	type parsedVersion struct {
+		*IndexVersion
+		id TMID
+	}
+	keep := make(map[string]bool)
+	var sorted []parsedVersion
+	for _, v := range versions {
+		id, err := ParseTMID(v.TMID)
+		if err != nil {
+			// keep what we don't understand
+			keep[v.TMID] = true
+			continue
+		}
+		sorted = append(sorted, parsedVersion{IndexVersion: v, id: id})
+	}
+	if len(sorted) == 0 {
+		return nil
+	}
+	// latest first
+	slices.SortStableFunc(sorted, func(a, b parsedVersion) int {
+		return b.id.Version.Compare(a.id.Version)
+	})
+
+	keep[sorted[0].TMID] = true
+	perBase := make(map[string]int)
+	heads := make(map[string]bool)
+	for _, v := range sorted {
+		if len(v.Labels) > 0 || successors[v.TMID] {
+			keep[v.TMID] = true
+		}
+		if p.KeepLatest > 0 {
+			base := v.id.Version.BaseString()
+			if perBase[base] < p.KeepLatest {
+				keep[v.TMID] = true
+			}
+			perBase[base]++
+		}
+		if p.KeepMinorHeads {
+			mm := fmt.Sprintf("%d.%d", v.id.Version.Base.Major(), v.id.Version.Base.Minor())
+			if !heads[mm] {
+				heads[mm] = true
+				keep[v.TMID] = true
+			}
+		}
+		if p.KeepYoungerThan > 0 {
+			ts, err := time.Parse(PseudoVersionTimestampFormat, v.id.Version.Timestamp)
+			if err != nil || now.Sub(ts) < p.KeepYoungerThan {
+				keep[v.TMID] = true
+			}
+		}
+	}
+
+	var res []*IndexVersion
+	for _, v := range sorted {
+		if !keep[v.TMID] {
+			res = append(res, v.IndexVersion)
+		}
+	}
+	return res
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetentionAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		exp     time.Duration
+		wantErr bool
+	}{
+		{"90d", 90 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"36h", 36 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"-1d", 0, true},
+		{"-5h", 0, true},
+		{"d", 0, true},
+		{"1w", 0, true},
+	}
+	for _, test := range tests {
+		d, err := ParseRetentionAge(test.in)
+		if test.wantErr {
+			assert.ErrorIs(t, err, ErrInvalidRetentionPolicy, test.in)
+		} else {
+			assert.NoError(t, err, test.in)
+			assert.Equal(t, test.exp, d, test.in)
+		}
+	}
+}
+
+func TestRetentionPolicy_Expired(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	v := func(ver, ts string, labels ...string) *IndexVersion {
+		return &IndexVersion{
+			TMID:   "author/manufacturer/mpn/" + ver + "-" + ts + "-a1b2c3d4e5f6.tm.json",
+			Labels: labels,
+		}
+	}
+	v100a := v("v1.0.0", "20240101000000")
+	v100b := v("v1.0.0", "20240201000000")
+	v100c := v("v1.0.0", "20240301000000")
+	v110a := v("v1.1.0", "20240401000000")
+	v110b := v("v1.1.0", "20240501000000")
+	v111 := v("v1.1.1", "20240520000000")
+	all := []*IndexVersion{v110a, v100a, v111, v100c, v110b, v100b}
+
+	ids := func(vs []*IndexVersion) []string {
+		var res []string
+		for _, v := range vs {
+			res = append(res, v.TMID)
+		}
+		return res
+	}
+
+	t.Run("keep latest", func(t *testing.T) {
+		res := RetentionPolicy{KeepLatest: 1}.Expired(all, nil, now)
+		assert.Equal(t, ids([]*IndexVersion{v110a, v100b, v100a}), ids(res))
+	})
+	t.Run("keep latest 2", func(t *testing.T) {
+		res := RetentionPolicy{KeepLatest: 2}.Expired(all, nil, now)
+		assert.Equal(t, ids([]*IndexVersion{v100a}), ids(res))
+	})
+	t.Run("keep minor heads", func(t *testing.T) {
+		res := RetentionPolicy{KeepMinorHeads: true}.Expired(all, nil, now)
+		assert.Equal(t, ids([]*IndexVersion{v110b, v110a, v100b, v100a}), ids(res))
+	})
+	t.Run("keep younger than", func(t *testing.T) {
+		res := RetentionPolicy{KeepYoungerThan: 70 * 24 * time.Hour}.Expired(all, nil, now)
+		assert.Equal(t, ids([]*IndexVersion{v100c, v100b, v100a}), ids(res))
+	})
+	t.Run("rules are combined", func(t *testing.T) {
+		res := RetentionPolicy{KeepLatest: 1, KeepYoungerThan: 70 * 24 * time.Hour}.Expired(all, nil, now)
+		assert.Equal(t, ids([]*IndexVersion{v100b, v100a}), ids(res))
+	})
+	t.Run("latest version is always kept", func(t *testing.T) {
+		res := RetentionPolicy{KeepYoungerThan: time.Hour}.Expired(all, nil, now)
+		assert.NotContains(t, ids(res), v111.TMID)
+		assert.Len(t, res, 5)
+	})
+	t.Run("labelled versions and successors are kept", func(t *testing.T) {
+		labelled := v("v1.0.0", "20231201000000", "pinned")
+		res := RetentionPolicy{KeepLatest: 1}.Expired(append(all, labelled), map[string]bool{v100a.TMID: true}, now)
+		assert.Equal(t, ids([]*IndexVersion{v110a, v100b}), ids(res))
+	})
+	t.Run("invalid ids are kept", func(t *testing.T) {
+		invalid := &IndexVersion{TMID: "author/manufacturer/mpn/invalid.tm.json"}
+		res := RetentionPolicy{KeepLatest: 1}.Expired([]*IndexVersion{invalid, v100a, v100b}, nil, now)
+		assert.Equal(t, ids([]*IndexVersion{v100a}), ids(res))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, RetentionPolicy{KeepLatest: 1}.Expired(nil, nil, now))
+	})
+}
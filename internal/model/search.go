@@ -35,10 +35,29 @@ type FoundEntry struct {
 	Mpn          string
 	Author       SchemaAuthor
 	Versions     []FoundVersion
+	Labels       []string
 	FoundIn      FoundSource
 	AttachmentContainer
 }
 
+// State returns the lifecycle state of the entry as a whole: LifecycleYanked if all of its versions have been yanked,
+// LifecycleDeprecated if all versions which have not been yanked are deprecated, and LifecycleActive otherwise
+func (e FoundEntry) State() LifecycleState {
+	state := LifecycleYanked
+	for _, v := range e.Versions {
+		switch v.State() {
+		case LifecycleActive:
+			return LifecycleActive
+		case LifecycleDeprecated:
+			state = LifecycleDeprecated
+		}
+	}
+	if len(e.Versions) == 0 {
+		return LifecycleActive
+	}
+	return state
+}
+
 type FoundVersion struct {
 	*IndexVersion
 	FoundIn FoundSource
@@ -107,6 +126,7 @@ type Filters struct {
 	Manufacturer []string
 	Mpn          []string
 	Protocol     []string
+	Label        []string
 	Name         string
 	ChangedSince string
 	Options      FilterOptions
@@ -155,11 +175,20 @@ func (sr *SearchResult) Filter(filters *Filters) error {
 			return true
 		}
 
+		if !matchesLabelFilter(filters.Label, entry) {
+			return true
+		}
+
 		return false
 	}
 	sr.Entries = slices.DeleteFunc(sr.Entries, func(entry FoundEntry) bool {
 		return exclude(entry)
 	})
+	if len(filters.Label) > 0 {
+		for i := range sr.Entries {
+			sr.Entries[i].Versions = labeledVersions(filters.Label, sr.Entries[i])
+		}
+	}
 	return nil
 }
 
@@ -252,6 +281,28 @@ func matchesProtocolFilter(protos []string, entry FoundEntry) bool {
 	return false
 }
 
+// matchesLabelFilter returns true if at least one of labels is attached to the entry or to one of its versions
+func matchesLabelFilter(labels []string, entry FoundEntry) bool {
+	return len(labels) == 0 || len(labeledVersions(labels, entry)) > 0
+}
+
+// labeledVersions returns all versions of the entry if at least one of labels is attached to the entry itself, or else
+// the versions to which at least one of labels is attached
+func labeledVersions(labels []string, entry FoundEntry) []FoundVersion {
+	for _, l := range labels {
+		if slices.Contains(entry.Labels, l) {
+			return entry.Versions
+		}
+	}
+	var res []FoundVersion
+	for _, v := range entry.Versions {
+		if slices.ContainsFunc(labels, func(l string) bool { return slices.Contains(v.Labels, l) }) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 func matchesChangedSinceFilter(changedSince string, entry FoundEntry) bool {
 	if changedSince == "" {
 		return true
@@ -299,10 +350,10 @@ func matchesFilter(acceptedValues []string, value string) bool {
 	return slices.Contains(acceptedValues, utils.SanitizeName(value))
 }
 
-func ToFilters(author, manufacturer, mpn, protocol, name, changedSince *string, opts *FilterOptions) *Filters {
+func ToFilters(author, manufacturer, mpn, protocol, label, name, changedSince *string, opts *FilterOptions) *Filters {
 	var search *Filters
 	isSet := func(s *string) bool { return s != nil && *s != "" }
-	if isSet(author) || isSet(manufacturer) || isSet(mpn) || isSet(protocol) || isSet(name) || isSet(changedSince) {
+	if isSet(author) || isSet(manufacturer) || isSet(mpn) || isSet(protocol) || isSet(label) || isSet(name) || isSet(changedSince) {
 		search = &Filters{}
 		if isSet(author) {
 			search.Author = strings.Split(*author, DefaultListSeparator)
@@ -316,6 +367,9 @@ func ToFilters(author, manufacturer, mpn, protocol, name, changedSince *string,
 		if isSet(protocol) {
 			search.Protocol = strings.Split(*protocol, DefaultListSeparator)
 		}
+		if isSet(label) {
+			search.Label = strings.Split(*label, DefaultListSeparator)
+		}
 		if isSet(name) {
 			search.Name = *name
 		}
@@ -123,6 +123,45 @@ func TestSearchResult_Filter(t *testing.T) {
 			assert.Equal(t, "aut2/man/mpn", sr.Entries[0].Name)
 		}
 	})
+	t.Run("filter by label", func(t *testing.T) {
+		labeled := func() *SearchResult {
+			sr := prepareSearchResult()
+			for i, e := range sr.Entries {
+				switch e.Name {
+				case "aut/man/mpn":
+					sr.Entries[i].Labels = []string{"certified"}
+				case "aut2/man/mpn":
+					e.Versions[1].Labels = []string{"beta", "certified"}
+				case "aut/man2/mpn":
+					e.Versions[0].Labels = []string{"beta"}
+				}
+			}
+			return sr
+		}
+		sr := labeled()
+		_ = sr.Filter(&Filters{Label: []string{"certified"}})
+		if assert.Len(t, sr.Entries, 2) {
+			assert.Equal(t, "aut/man/mpn", sr.Entries[0].Name)
+			assert.Len(t, sr.Entries[0].Versions, len(labeled().Entries[0].Versions))
+			assert.Equal(t, "aut2/man/mpn", sr.Entries[1].Name)
+			if assert.Len(t, sr.Entries[1].Versions, 1) {
+				assert.Equal(t, []string{"beta", "certified"}, sr.Entries[1].Versions[0].Labels)
+			}
+		}
+
+		sr = labeled()
+		_ = sr.Filter(&Filters{Label: []string{"beta", "deprecated"}, Author: []string{"aut"}})
+		if assert.Len(t, sr.Entries, 1) {
+			assert.Equal(t, "aut/man2/mpn", sr.Entries[0].Name)
+			if assert.Len(t, sr.Entries[0].Versions, 1) {
+				assert.Equal(t, []string{"beta"}, sr.Entries[0].Versions[0].Labels)
+			}
+		}
+
+		sr = labeled()
+		_ = sr.Filter(&Filters{Label: []string{"unknown"}})
+		assert.Len(t, sr.Entries, 0)
+	})
 	t.Run("filter by author and manufacturer", func(t *testing.T) {
 		sr := prepareSearchResult()
 		_ = sr.Filter(&Filters{Manufacturer: []string{"man"}, Author: []string{"aut"}})
@@ -165,13 +204,13 @@ func TestSearchResult_Filter(t *testing.T) {
 		mpn := "M/PN"
 		r := NewIndexToFoundMapper(EmptySpec.ToFoundSource()).ToSearchResult(*idx)
 		sr := &r
-		_ = sr.Filter(ToFilters(&author, &manuf, &mpn, nil, nil, nil, nil))
+		_ = sr.Filter(ToFilters(&author, &manuf, &mpn, nil, nil, nil, nil, nil))
 		assert.Len(t, sr.Entries, 1)
 
 		author = "Aut%hor"
 		manuf = "Man-ufacturer"
 		mpn = "M&pN"
-		_ = sr.Filter(ToFilters(&author, &manuf, &mpn, nil, nil, nil, nil))
+		_ = sr.Filter(ToFilters(&author, &manuf, &mpn, nil, nil, nil, nil, nil))
 		assert.Len(t, sr.Entries, 1)
 	})
 }
@@ -181,3 +220,30 @@ func prepareSearchResult() *SearchResult {
 	sr := NewIndexToFoundMapper(EmptySpec.ToFoundSource()).ToSearchResult(*idx)
 	return &sr
 }
+
+func TestFoundEntry_State(t *testing.T) {
+	ver := func(state LifecycleState) FoundVersion {
+		v := &IndexVersion{}
+		if state != LifecycleActive {
+			v.Lifecycle = &Lifecycle{State: state}
+		}
+		return FoundVersion{IndexVersion: v}
+	}
+	tests := []struct {
+		states []LifecycleState
+		exp    LifecycleState
+	}{
+		{nil, LifecycleActive},
+		{[]LifecycleState{LifecycleActive}, LifecycleActive},
+		{[]LifecycleState{LifecycleDeprecated, LifecycleActive}, LifecycleActive},
+		{[]LifecycleState{LifecycleDeprecated, LifecycleYanked}, LifecycleDeprecated},
+		{[]LifecycleState{LifecycleYanked, LifecycleYanked}, LifecycleYanked},
+	}
+	for _, test := range tests {
+		e := FoundEntry{}
+		for _, s := range test.states {
+			e.Versions = append(e.Versions, ver(s))
+		}
+		assert.Equal(t, test.exp, e.State(), test.states)
+	}
+}
@@ -1,16 +1,21 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
 )
 
 type Index struct {
-	Meta       IndexMeta     `json:"meta"`
-	Data       []*IndexEntry `json:"data"`
+	Meta IndexMeta     `json:"meta"`
+	Data []*IndexEntry `json:"data"`
+	// Aliases maps TM names which have been moved to their current names
+	Aliases    map[string]string `json:"aliases,omitempty"`
 	dataByName map[string]*IndexEntry
 }
 
@@ -47,6 +52,7 @@ type IndexEntry struct {
 	Mpn          string             `json:"schema:mpn" validate:"required"`
 	Author       SchemaAuthor       `json:"schema:author" validate:"required"`
 	Versions     []*IndexVersion    `json:"versions"`
+	Labels       []string           `json:"labels,omitempty"`
 	AttachmentContainer
 }
 
@@ -109,9 +115,86 @@ type IndexVersion struct {
 	ExternalID  string            `json:"externalID"`
 	Protocols   []string          `json:"protocols,omitempty"`
 	SearchMatch *SearchMatch      `json:"searchMatch,omitempty"`
+	Lifecycle   *Lifecycle        `json:"lifecycle,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
 	AttachmentContainer
 }
 
+// State returns the lifecycle state of the version. Versions without lifecycle metadata are active
+func (v *IndexVersion) State() LifecycleState {
+	if v == nil || v.Lifecycle == nil || v.Lifecycle.State == "" {
+		return LifecycleActive
+	}
+	return v.Lifecycle.State
+}
+
+// IsYanked returns true if the version has been yanked
+func (v *IndexVersion) IsYanked() bool {
+	return v.State() == LifecycleYanked
+}
+
+// LifecycleState is the state of a TM version in its lifecycle
+type LifecycleState string
+
+const (
+	LifecycleActive     LifecycleState = "active"
+	LifecycleDeprecated LifecycleState = "deprecated" // the version is still resolved by name, but should not be used for new devices
+	LifecycleYanked     LifecycleState = "yanked"     // the version is never resolved by name, but can still be fetched by its id
+)
+
+var ErrInvalidLifecycle = errors.New("invalid lifecycle")
+
+// ParseLifecycleState parses s as a LifecycleState. An empty string is parsed as LifecycleActive
+func ParseLifecycleState(s string) (LifecycleState, error) {
+	switch st := LifecycleState(s); st {
+	case "":
+		return LifecycleActive, nil
+	case LifecycleActive, LifecycleDeprecated, LifecycleYanked:
+		return st, nil
+	default:
+		return "", fmt.Errorf("%w: unknown state %s. Must be one of %s, %s, %s", ErrInvalidLifecycle, s, LifecycleActive, LifecycleDeprecated, LifecycleYanked)
+	}
+}
+
+// Lifecycle holds the lifecycle metadata of a TM version
+type Lifecycle struct {
+	State LifecycleState `json:"state"`
+	// Reason explains why the version has been deprecated or yanked
+	Reason string `json:"reason,omitempty"`
+	// Successor is the id of the TM version, which should be used instead
+	Successor string `json:"successor,omitempty"`
+}
+
+// Validate checks that the lifecycle has a known state and that the successor, if any, is a valid TM id
+// other than tmID
+func (l Lifecycle) Validate(tmID string) error {
+	if _, err := ParseLifecycleState(string(l.State)); err != nil {
+		return err
+	}
+	if l.Successor != "" {
+		if _, err := ParseTMID(l.Successor); err != nil {
+			return fmt.Errorf("%w: successor: %w", ErrInvalidLifecycle, err)
+		}
+		if l.Successor == tmID {
+			return fmt.Errorf("%w: a TM version cannot be its own successor", ErrInvalidLifecycle)
+		}
+	}
+	return nil
+}
+
+var ErrInvalidLabel = errors.New("invalid label")
+
+var labelRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._:-]{0,63}$`)
+
+// ValidateLabel checks that label starts with a letter or digit and consists of at most 64 letters, digits, '.', '_',
+// ':' and '-'. In particular, labels cannot contain DefaultListSeparator, so that they can be used in filters
+func ValidateLabel(label string) error {
+	if !labelRegex.MatchString(label) {
+		return fmt.Errorf("%w: %q", ErrInvalidLabel, label)
+	}
+	return nil
+}
+
 type SearchMatch struct {
 	Score     float32  `json:"score,omitempty"`
 	Locations []string `json:"locations,omitempty"`
@@ -184,6 +267,8 @@ func (idx *Index) Insert(ctm *ThingModel) error {
 		}
 		idx.Data = append(idx.Data, idxEntry)
 		idx.dataByName[idxEntry.Name] = idxEntry
+		// a TM name which is in use again is no longer an alias
+		delete(idx.Aliases, idxEntry.Name)
 	}
 	// TODO: check if id already exists?
 	// Append version information to entry
@@ -207,11 +292,231 @@ func (idx *Index) Insert(ctm *ThingModel) error {
 	}); idx == -1 {
 		idxEntry.Versions = append(idxEntry.Versions, tv)
 	} else {
+		// lifecycle metadata and labels are not part of the TM file and must survive re-indexing
+		tv.Lifecycle = idxEntry.Versions[idx].Lifecycle
+		tv.Labels = idxEntry.Versions[idx].Labels
 		idxEntry.Versions[idx] = tv
 	}
 	return nil
 }
 
+// SetLifecycle sets the lifecycle metadata of the version with given id. Setting LifecycleActive removes the metadata.
+// Returns ErrTMNotFound if the version is not in the index
+func (idx *Index) SetLifecycle(tmID string, lc Lifecycle) error {
+	err := lc.Validate(tmID)
+	if err != nil {
+		return err
+	}
+	v := idx.FindByTMID(tmID)
+	if v == nil {
+		return ErrTMNotFound
+	}
+	if lc.State == "" || lc.State == LifecycleActive {
+		v.Lifecycle = nil
+	} else {
+		v.Lifecycle = &lc
+	}
+	return nil
+}
+
+// CopyIndexMetadata copies the metadata which is kept only in the index, i.e. lifecycle metadata and labels, of all
+// entries and versions found in both from and idx from the former to the latter. Aliases are copied unless they
+// collide with a TM name in idx
+func (idx *Index) CopyIndexMetadata(from *Index) {
+	if from == nil {
+		return
+	}
+	for alias, name := range from.Aliases {
+		if idx.FindByName(alias) != nil {
+			continue
+		}
+		if idx.Aliases == nil {
+			idx.Aliases = make(map[string]string)
+		}
+		idx.Aliases[alias] = name
+	}
+	for _, e := range idx.Data {
+		if oe := from.FindByName(e.Name); oe != nil {
+			e.Labels = slices.Clone(oe.Labels)
+		}
+		for _, v := range e.Versions {
+			if ov := from.FindByTMID(v.TMID); ov != nil {
+				if ov.Lifecycle != nil {
+					lc := *ov.Lifecycle
+					v.Lifecycle = &lc
+				}
+				v.Labels = slices.Clone(ov.Labels)
+			}
+		}
+	}
+}
+
+// MergeIndexMetadata applies the changes of the metadata kept only in the index, which have been made between base and
+// changed, to idx. Metadata of entries and versions which has not been changed in changed is left as is in idx.
+// A nil base is treated like an empty index
+func (idx *Index) MergeIndexMetadata(base, changed *Index) {
+	if changed == nil {
+		return
+	}
+	if base == nil {
+		base = &Index{}
+	}
+	for alias, name := range changed.Aliases {
+		if base.Aliases[alias] == name || idx.FindByName(alias) != nil {
+			continue
+		}
+		if idx.Aliases == nil {
+			idx.Aliases = make(map[string]string)
+		}
+		idx.Aliases[alias] = name
+	}
+	for alias := range base.Aliases {
+		if _, ok := changed.Aliases[alias]; !ok {
+			delete(idx.Aliases, alias)
+		}
+	}
+	for _, e := range idx.Data {
+		ce := changed.FindByName(e.Name)
+		if ce == nil {
+			continue
+		}
+		var baseLabels []string
+		if be := base.FindByName(e.Name); be != nil {
+			baseLabels = be.Labels
+		}
+		if !slices.Equal(baseLabels, ce.Labels) {
+			e.Labels = slices.Clone(ce.Labels)
+		}
+		for _, v := range e.Versions {
+			cv := changed.FindByTMID(v.TMID)
+			if cv == nil {
+				continue
+			}
+			bv := base.FindByTMID(v.TMID)
+			if !slices.Equal(bv.labels(), cv.Labels) {
+				v.Labels = slices.Clone(cv.Labels)
+			}
+			if bv.lifecycle() != cv.lifecycle() {
+				v.Lifecycle = nil
+				if cv.Lifecycle != nil {
+					lc := *cv.Lifecycle
+					v.Lifecycle = &lc
+				}
+			}
+		}
+	}
+}
+
+func (v *IndexVersion) labels() []string {
+	if v == nil {
+		return nil
+	}
+	return v.Labels
+}
+
+func (v *IndexVersion) lifecycle() Lifecycle {
+	if v == nil || v.Lifecycle == nil {
+		return Lifecycle{}
+	}
+	return *v.Lifecycle
+}
+
+// UpdateLabels adds the labels in add to and removes the labels in remove from the TM name or TM version given by ref.
+// The resulting labels are sorted and free of duplicates
+func (idx *Index) UpdateLabels(ref AttachmentContainerRef, add, remove []string) error {
+	for _, l := range add {
+		if err := ValidateLabel(l); err != nil {
+			return err
+		}
+	}
+	_, entry, err := idx.FindAttachmentContainer(ref)
+	if err != nil {
+		return err
+	}
+	labels := &entry.Labels
+	if ref.Kind() == AttachmentContainerKindTMID {
+		labels = &idx.FindByTMID(ref.TMID).Labels
+	}
+	res := slices.DeleteFunc(append(slices.Clone(*labels), add...), func(l string) bool {
+		return slices.Contains(remove, l)
+	})
+	slices.Sort(res)
+	res = slices.Compact(res)
+	if len(res) == 0 {
+		res = nil
+	}
+	*labels = res
+	return nil
+}
+
+var ErrTMNameExists = errors.New("TM name already exists")
+
+// DisplayNames are the names of author, manufacturer and mpn as given in the content of a TM, before they are sanitized
+// to become part of its TM name
+type DisplayNames struct {
+	Author       string
+	Manufacturer string
+	Mpn          string
+}
+
+// Move renames the entry oldName with all its versions to newName and records oldName as an alias of newName.
+// The versions get the ids given by their old ids in newIDs. Versions missing in newIDs keep the version part of their ids.
+// The non-empty names in names replace the display names of the entry.
+// Aliases pointing to oldName are redirected to newName.
+// Returns ErrTMNameNotFound if oldName is not in the index and ErrTMNameExists if newName is
+func (idx *Index) Move(oldName, newName string, newIDs map[string]string, names DisplayNames) error {
+	entry := idx.FindByName(oldName)
+	if entry == nil {
+		return ErrTMNameNotFound
+	}
+	if idx.FindByName(newName) != nil {
+		return fmt.Errorf("%w: %s", ErrTMNameExists, newName)
+	}
+	entry.Name = newName
+	if names.Author != "" {
+		entry.Author.Name = names.Author
+	}
+	if names.Manufacturer != "" {
+		entry.Manufacturer.Name = names.Manufacturer
+	}
+	if names.Mpn != "" {
+		entry.Mpn = names.Mpn
+	}
+	for _, v := range entry.Versions {
+		id, ok := newIDs[v.TMID]
+		if !ok {
+			id = newName + "/" + path.Base(v.TMID)
+		}
+		v.TMID = id
+		if v.Links != nil {
+			v.Links["content"] = id
+		}
+	}
+	idx.reindexData()
+
+	if idx.Aliases == nil {
+		idx.Aliases = make(map[string]string)
+	}
+	for alias, name := range idx.Aliases {
+		if name == oldName {
+			idx.Aliases[alias] = newName
+		}
+	}
+	idx.Aliases[oldName] = newName
+	delete(idx.Aliases, newName)
+	return nil
+}
+
+// ResolveAlias returns the current name of a TM name which has been moved. Returns false if name is not an alias.
+// An existing TM name is never resolved as an alias
+func (idx *Index) ResolveAlias(name string) (string, bool) {
+	if idx.FindByName(name) != nil {
+		return "", false
+	}
+	newName, ok := idx.Aliases[name]
+	return newName, ok
+}
+
 func (idx *Index) InsertAttachments(ref AttachmentContainerRef, atts ...Attachment) error {
 	container, _, err := idx.FindAttachmentContainer(ref)
 	if err != nil {
@@ -307,6 +612,9 @@ func (idx *Index) FindAttachmentContainer(ref AttachmentContainerRef) (*Attachme
 
 const AttachmentsDir = ".attachments"
 
+// SignatureAttachmentName is the reserved name of the attachment of a TM version, which holds the TM's signature
+const SignatureAttachmentName = "tm-signature.jws"
+
 // RelAttachmentsDir is a helper function which calculates the relative path of the attachments directory for
 // given attachment container. That is, e.g. 'author/manufacturer/mpn/.attachments' for a TMName ref and
 // 'author/manufacturer/mpn/.attachments/v1.0.0-20240108112117-2cd14601ef09' for a TMID ref
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -338,3 +339,187 @@ func TestIndex_Sort(t *testing.T) {
 
 	assert.Equal(t, expIdxData, idx.Data)
 }
+
+func TestIndex_SetLifecycle(t *testing.T) {
+	id1 := "aut/man/mpn/v1.0.0-20231023121314-abcd12345678.tm.json"
+	id2 := "aut/man/mpn/v1.0.1-20231024121314-abcd12345690.tm.json"
+	idx := &Index{}
+	assert.NoError(t, idx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: id1}))
+	assert.NoError(t, idx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: id2}))
+
+	t.Run("deprecate", func(t *testing.T) {
+		err := idx.SetLifecycle(id1, Lifecycle{State: LifecycleDeprecated, Reason: "wrong unit", Successor: id2})
+		assert.NoError(t, err)
+		v := idx.FindByTMID(id1)
+		assert.Equal(t, LifecycleDeprecated, v.State())
+		assert.Equal(t, &Lifecycle{State: LifecycleDeprecated, Reason: "wrong unit", Successor: id2}, v.Lifecycle)
+	})
+	t.Run("survives re-insert", func(t *testing.T) {
+		assert.NoError(t, idx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: id1}))
+		assert.Equal(t, LifecycleDeprecated, idx.FindByTMID(id1).State())
+	})
+	t.Run("copy to new index", func(t *testing.T) {
+		newIdx := &Index{}
+		assert.NoError(t, newIdx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: id1}))
+		newIdx.CopyIndexMetadata(idx)
+		assert.Equal(t, LifecycleDeprecated, newIdx.FindByTMID(id1).State())
+		assert.NotSame(t, idx.FindByTMID(id1).Lifecycle, newIdx.FindByTMID(id1).Lifecycle)
+	})
+	t.Run("back to active", func(t *testing.T) {
+		err := idx.SetLifecycle(id1, Lifecycle{State: LifecycleActive})
+		assert.NoError(t, err)
+		v := idx.FindByTMID(id1)
+		assert.Nil(t, v.Lifecycle)
+		assert.Equal(t, LifecycleActive, v.State())
+	})
+	t.Run("yank", func(t *testing.T) {
+		err := idx.SetLifecycle(id2, Lifecycle{State: LifecycleYanked})
+		assert.NoError(t, err)
+		assert.True(t, idx.FindByTMID(id2).IsYanked())
+	})
+	t.Run("not found", func(t *testing.T) {
+		err := idx.SetLifecycle("aut/man/mpn/v1.0.2-20231024121314-abcd12345690.tm.json", Lifecycle{State: LifecycleYanked})
+		assert.ErrorIs(t, err, ErrTMNotFound)
+	})
+	t.Run("invalid state", func(t *testing.T) {
+		err := idx.SetLifecycle(id1, Lifecycle{State: "retired"})
+		assert.ErrorIs(t, err, ErrInvalidLifecycle)
+	})
+	t.Run("invalid successor", func(t *testing.T) {
+		err := idx.SetLifecycle(id1, Lifecycle{State: LifecycleDeprecated, Successor: "aut/man/mpn"})
+		assert.ErrorIs(t, err, ErrInvalidLifecycle)
+		err = idx.SetLifecycle(id1, Lifecycle{State: LifecycleDeprecated, Successor: id1})
+		assert.ErrorIs(t, err, ErrInvalidLifecycle)
+	})
+}
+
+func TestIndex_UpdateLabels(t *testing.T) {
+	id := "aut/man/mpn/v1.0.0-20231023121314-abcd12345680.tm.json"
+	idx := prepareIndex()
+
+	t.Run("add to name", func(t *testing.T) {
+		err := idx.UpdateLabels(NewTMNameAttachmentContainerRef("aut/man/mpn"), []string{"certified", "beta", "certified"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"beta", "certified"}, idx.FindByName("aut/man/mpn").Labels)
+		assert.Nil(t, idx.FindByTMID(id).Labels)
+	})
+	t.Run("add to version", func(t *testing.T) {
+		err := idx.UpdateLabels(NewTMIDAttachmentContainerRef(id), []string{"building-automation"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"building-automation"}, idx.FindByTMID(id).Labels)
+	})
+	t.Run("add and remove", func(t *testing.T) {
+		err := idx.UpdateLabels(NewTMNameAttachmentContainerRef("aut/man/mpn"), []string{"alpha"}, []string{"beta", "nonexistent"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alpha", "certified"}, idx.FindByName("aut/man/mpn").Labels)
+	})
+	t.Run("remove all", func(t *testing.T) {
+		err := idx.UpdateLabels(NewTMIDAttachmentContainerRef(id), nil, []string{"building-automation"})
+		assert.NoError(t, err)
+		assert.Nil(t, idx.FindByTMID(id).Labels)
+	})
+	t.Run("survives re-insert", func(t *testing.T) {
+		assert.NoError(t, idx.UpdateLabels(NewTMIDAttachmentContainerRef(id), []string{"beta"}, nil))
+		assert.NoError(t, idx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: id}))
+		assert.Equal(t, []string{"beta"}, idx.FindByTMID(id).Labels)
+	})
+	t.Run("copy to new index", func(t *testing.T) {
+		newIdx := &Index{}
+		assert.NoError(t, newIdx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: id}))
+		newIdx.CopyIndexMetadata(idx)
+		assert.Equal(t, []string{"alpha", "certified"}, newIdx.FindByName("aut/man/mpn").Labels)
+		assert.Equal(t, []string{"beta"}, newIdx.FindByTMID(id).Labels)
+	})
+	t.Run("not found", func(t *testing.T) {
+		err := idx.UpdateLabels(NewTMNameAttachmentContainerRef("aut/man/nothing"), []string{"beta"}, nil)
+		assert.ErrorIs(t, err, ErrTMNameNotFound)
+		err = idx.UpdateLabels(NewTMIDAttachmentContainerRef("aut/man/mpn/v9.0.0-20231023121314-abcd12345680.tm.json"), []string{"beta"}, nil)
+		assert.ErrorIs(t, err, ErrTMNotFound)
+	})
+	t.Run("invalid label", func(t *testing.T) {
+		for _, l := range []string{"", "a,b", "-beta", "with space", strings.Repeat("x", 65)} {
+			err := idx.UpdateLabels(NewTMIDAttachmentContainerRef(id), []string{l}, nil)
+			assert.ErrorIs(t, err, ErrInvalidLabel, l)
+		}
+	})
+}
+
+func TestIndex_MergeIndexMetadata(t *testing.T) {
+	id1 := "aut/man/mpn/v1.0.0-20231023121314-abcd12345680.tm.json"
+	id2 := "aut2/man/mpn/v1.0.0-20231023121314-abcd12345678.tm.json"
+	id3 := "aut2/man/mpn/v1.0.1-20231024121314-abcd12345679.tm.json"
+	base := prepareIndex()
+	assert.NoError(t, base.SetLifecycle(id3, Lifecycle{State: LifecycleDeprecated}))
+	base.Aliases = map[string]string{"aut/man/gone": "aut/man/mpn"}
+
+	local := prepareIndex()
+	assert.NoError(t, local.SetLifecycle(id1, Lifecycle{State: LifecycleYanked, Reason: "broken"}))
+	assert.NoError(t, local.UpdateLabels(NewTMNameAttachmentContainerRef("aut/man/mpn"), []string{"local"}, nil))
+	local.Aliases = map[string]string{"aut/man/old": "aut/man/mpn"}
+
+	remote := prepareIndex()
+	assert.NoError(t, remote.SetLifecycle(id3, Lifecycle{State: LifecycleDeprecated}))
+	assert.NoError(t, remote.UpdateLabels(NewTMIDAttachmentContainerRef(id2), []string{"remote"}, nil))
+	remote.Aliases = map[string]string{"aut/man/gone": "aut/man/mpn", "aut2/man/old": "aut2/man/mpn"}
+
+	remote.MergeIndexMetadata(base, local)
+	assert.Equal(t, LifecycleYanked, remote.FindByTMID(id1).State())
+	assert.Equal(t, []string{"local"}, remote.FindByName("aut/man/mpn").Labels)
+	assert.Equal(t, []string{"remote"}, remote.FindByTMID(id2).Labels)
+	// lifecycle reset to active locally
+	assert.Equal(t, LifecycleActive, remote.FindByTMID(id3).State())
+	assert.Equal(t, map[string]string{"aut/man/old": "aut/man/mpn", "aut2/man/old": "aut2/man/mpn"}, remote.Aliases)
+}
+
+func TestIndex_Move(t *testing.T) {
+	idx := prepareIndex()
+
+	t.Run("move", func(t *testing.T) {
+		newIDs := map[string]string{"aut/man/mpn/v1.0.1-20231024121314-abcd12345681.tm.json": "aut/newman/mpn/v1.0.1-20231024121314-bcde12345681.tm.json"}
+		err := idx.Move("aut/man/mpn", "aut/newman/mpn", newIDs, DisplayNames{Manufacturer: "NewMan"})
+		assert.NoError(t, err)
+		assert.Nil(t, idx.FindByName("aut/man/mpn"))
+		e := idx.FindByName("aut/newman/mpn")
+		if assert.NotNil(t, e) && assert.Len(t, e.Versions, 2) {
+			assert.Equal(t, "aut/newman/mpn/v1.0.1-20231024121314-bcde12345681.tm.json", e.Versions[0].TMID)
+			assert.Equal(t, "aut/newman/mpn/v1.0.0-20231023121314-abcd12345680.tm.json", e.Versions[1].TMID)
+			assert.Equal(t, "NewMan", e.Manufacturer.Name)
+			assert.Equal(t, "aut", e.Author.Name)
+		}
+		assert.NotNil(t, idx.FindByTMID("aut/newman/mpn/v1.0.0-20231023121314-abcd12345680.tm.json"))
+		newName, ok := idx.ResolveAlias("aut/man/mpn")
+		assert.True(t, ok)
+		assert.Equal(t, "aut/newman/mpn", newName)
+	})
+	t.Run("chained move", func(t *testing.T) {
+		err := idx.Move("aut/newman/mpn", "aut/newerman/mpn/sub", nil, DisplayNames{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"aut/man/mpn": "aut/newerman/mpn/sub", "aut/newman/mpn": "aut/newerman/mpn/sub"}, idx.Aliases)
+	})
+	t.Run("move back", func(t *testing.T) {
+		err := idx.Move("aut/newerman/mpn/sub", "aut/man/mpn", nil, DisplayNames{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"aut/newman/mpn": "aut/man/mpn", "aut/newerman/mpn/sub": "aut/man/mpn"}, idx.Aliases)
+		_, ok := idx.ResolveAlias("aut/man/mpn")
+		assert.False(t, ok)
+	})
+	t.Run("target exists", func(t *testing.T) {
+		err := idx.Move("aut/man/mpn", "aut2/man/mpn", nil, DisplayNames{})
+		assert.ErrorIs(t, err, ErrTMNameExists)
+	})
+	t.Run("not found", func(t *testing.T) {
+		err := idx.Move("aut/newman/mpn", "aut3/man/mpn", nil, DisplayNames{})
+		assert.ErrorIs(t, err, ErrTMNameNotFound)
+	})
+	t.Run("copy to new index", func(t *testing.T) {
+		newIdx := &Index{}
+		assert.NoError(t, newIdx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "man"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: "aut/newman/mpn/v1.0.0-20231023121314-abcd12345680.tm.json"}))
+		newIdx.CopyIndexMetadata(idx)
+		assert.Equal(t, map[string]string{"aut/newerman/mpn/sub": "aut/man/mpn"}, newIdx.Aliases)
+	})
+	t.Run("alias removed on insert", func(t *testing.T) {
+		assert.NoError(t, idx.Insert(&ThingModel{Manufacturer: SchemaManufacturer{Name: "newman"}, Mpn: "mpn", Author: SchemaAuthor{Name: "aut"}, ID: "aut/newman/mpn/v1.0.0-20231023121314-abcd12345682.tm.json"}))
+		_, ok := idx.Aliases["aut/newman/mpn"]
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,498 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	KeyRepoCacheUpstream = "upstream"
+	KeyRepoCacheIndexTTL = "indexTTL"
+	KeyRepoCachePrefetch = "prefetch"
+
+	upstreamIndexFilename = "upstream.toc.json"
+)
+
+// CacheRepo implements a read-through cache for another Repo, the upstream.
+// Every TM and attachment fetched from upstream is stored in a local FileRepo layout, which keeps working when the
+// upstream is not reachable. The upstream's index is stored alongside and refreshed after indexTTL has passed.
+// Write operations are passed to the upstream.
+type CacheRepo struct {
+	upstream Repo
+	local    *FileRepo
+	spec     model.RepoSpec
+	indexTTL time.Duration
+	prefetch bool
+}
+
+func NewCacheRepo(config ConfigMap, spec model.RepoSpec) (*CacheRepo, error) {
+	loc, found := config.GetString(KeyRepoLoc)
+	if !found {
+		return nil, fmt.Errorf("cannot create a cache repo from spec %v. Invalid config. loc is either not found or not a string", spec)
+	}
+	rootPath, err := utils.ExpandHome(loc)
+	if err != nil {
+		return nil, err
+	}
+	upConf, ok := config[KeyRepoCacheUpstream].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot create a cache repo from spec %v. Invalid config. upstream is either not found or not a map", spec)
+	}
+	if upConf[KeyRepoType] == RepoTypeCache {
+		return nil, fmt.Errorf("cannot create a cache repo from spec %v. Invalid config. upstream cannot be a cache", spec)
+	}
+	upstream, err := createRepo(upConf, spec)
+	if err != nil {
+		return nil, err
+	}
+	var ttl time.Duration
+	if ttlS, found := config.GetString(KeyRepoCacheIndexTTL); found {
+		ttl, err = time.ParseDuration(ttlS)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create a cache repo from spec %v. Invalid config. %s is not a valid duration: %w", spec, KeyRepoCacheIndexTTL, err)
+		}
+	}
+	prefetch, _ := config.GetBool(KeyRepoCachePrefetch)
+	return &CacheRepo{
+		upstream: upstream,
+		local: &FileRepo{
+			root: rootPath,
+			spec: spec,
+		},
+		spec:     spec,
+		indexTTL: ttl,
+		prefetch: prefetch,
+	}, nil
+}
+
+func (c *CacheRepo) Import(ctx context.Context, id model.TMID, raw []byte, opts ImportOptions) (ImportResult, error) {
+	defer c.invalidateUpstreamIndex()
+	return c.upstream.Import(ctx, id, raw, opts)
+}
+
+func (c *CacheRepo) Fetch(ctx context.Context, id string) (string, []byte, error) {
+	actualId, raw, err := c.local.Fetch(ctx, id)
+	if err == nil {
+		return actualId, raw, nil
+	}
+	actualId, raw, err = c.upstream.Fetch(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if sErr := c.storeTM(ctx, actualId, raw); sErr != nil {
+		utils.GetLogger(ctx, "CacheRepo").Warn("could not store TM in cache", "id", actualId, "error", sErr)
+	}
+	return actualId, raw, nil
+}
+
+// Index passes the request to the upstream if ids are given. Otherwise, refreshes the cached copy of the upstream's
+// index, stores all TMs and attachments listed in it in prefetch mode, and rebuilds the index of locally stored TMs.
+// Failures to prefetch single TMs or attachments are returned after everything else has been done
+func (c *CacheRepo) Index(ctx context.Context, ids ...string) error {
+	if len(ids) > 0 {
+		defer c.invalidateUpstreamIndex()
+		return c.upstream.Index(ctx, ids...)
+	}
+	idx, err := c.refreshUpstreamIndex(ctx)
+	if err != nil {
+		return err
+	}
+	var prefetchErr error
+	if c.prefetch {
+		prefetchErr = c.prefetchAll(ctx, idx)
+	}
+	if c.local.checkRootValid() == nil {
+		err = c.local.Index(ctx)
+	}
+	return errors.Join(err, prefetchErr)
+}
+
+// CheckIntegrity checks the locally stored copies of TMs and attachments
+func (c *CacheRepo) CheckIntegrity(ctx context.Context, filter model.ResourceFilter) ([]model.CheckResult, error) {
+	return c.local.CheckIntegrity(ctx, filter)
+}
+
+func (c *CacheRepo) List(ctx context.Context, search *model.Filters) (model.SearchResult, error) {
+	idx, err := c.upstreamIndex(ctx)
+	if err != nil {
+		utils.GetLogger(ctx, "CacheRepo").Debug("upstream index unavailable, listing cached TMs only", "error", err)
+		return c.local.List(ctx, search)
+	}
+	sr := model.NewIndexToFoundMapper(c.spec.ToFoundSource()).ToSearchResult(*idx)
+	filtered := &sr
+	err = filtered.Filter(search)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+	return *filtered, nil
+}
+
+func (c *CacheRepo) Versions(ctx context.Context, name string) ([]model.FoundVersion, error) {
+	name = strings.TrimSpace(name)
+	res, err := c.List(ctx, &model.Filters{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Entries) != 1 {
+		// the name may have been moved upstream, which only the upstream can resolve
+		if vs, uErr := c.upstream.Versions(ctx, name); uErr == nil {
+			for i := range vs {
+				vs[i].FoundIn = c.spec.ToFoundSource()
+			}
+			return vs, nil
+		}
+		err := fmt.Errorf("%w: %s", model.ErrTMNameNotFound, name)
+		return nil, err
+	}
+
+	return res.Entries[0].Versions, nil
+}
+
+func (c *CacheRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, error) {
+	id, err := model.ParseTMID(tmID)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := c.Versions(ctx, id.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		// versions belong to the name id.Name has been moved to, if it has been moved
+		if isVersionOf(tmID, v.TMID) {
+			return []model.FoundVersion{v}, nil
+		}
+	}
+	return nil, model.ErrTMNotFound
+}
+
+func (c *CacheRepo) Spec() model.RepoSpec {
+	return c.spec
+}
+
+func (c *CacheRepo) CanonicalRoot() string {
+	return c.local.CanonicalRoot()
+}
+
+func (c *CacheRepo) Delete(ctx context.Context, id string) error {
+	defer c.invalidateUpstreamIndex()
+	err := c.upstream.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.local.checkRootValid() == nil {
+		if lErr := c.local.Delete(ctx, id); lErr != nil && !errors.Is(lErr, model.ErrTMNotFound) {
+			utils.GetLogger(ctx, "CacheRepo").Warn("could not delete TM from cache", "id", id, "error", lErr)
+		}
+	}
+	return nil
+}
+
+func (c *CacheRepo) ListCompletions(ctx context.Context, kind string, args []string, toComplete string) ([]string, error) {
+	res, err := c.upstream.ListCompletions(ctx, kind, args, toComplete)
+	if err == nil {
+		return res, nil
+	}
+	return c.local.ListCompletions(ctx, kind, args, toComplete)
+}
+
+func (c *CacheRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
+	defer c.invalidateUpstreamIndex()
+	err := c.upstream.ImportAttachment(ctx, container, attachment, content, force, ifMatch)
+	if err != nil {
+		return err
+	}
+	c.deleteCachedAttachment(ctx, container, attachment.Name)
+	return nil
+}
+
+func (c *CacheRepo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	defer c.invalidateUpstreamIndex()
+	err := c.upstream.SetLifecycle(ctx, id, lc)
+	if err != nil {
+		return err
+	}
+	if c.local.checkRootValid() == nil {
+		if lErr := c.local.SetLifecycle(ctx, id, lc); lErr != nil && !errors.Is(lErr, model.ErrTMNotFound) {
+			utils.GetLogger(ctx, "CacheRepo").Warn("could not set lifecycle of TM in cache", "id", id, "error", lErr)
+		}
+	}
+	return nil
+}
+
+func (c *CacheRepo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error {
+	defer c.invalidateUpstreamIndex()
+	err := c.upstream.UpdateLabels(ctx, ref, add, remove)
+	if err != nil {
+		return err
+	}
+	if c.local.checkRootValid() == nil {
+		if lErr := c.local.UpdateLabels(ctx, ref, add, remove); lErr != nil && !errors.Is(lErr, model.ErrTMNotFound) && !errors.Is(lErr, model.ErrTMNameNotFound) {
+			utils.GetLogger(ctx, "CacheRepo").Warn("could not update labels of TM in cache", "ref", ref, "error", lErr)
+		}
+	}
+	return nil
+}
+
+func (c *CacheRepo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) error {
+	defer c.invalidateUpstreamIndex()
+	err := c.upstream.Move(ctx, oldName, newName, opts)
+	if err != nil {
+		return err
+	}
+	if c.local.checkRootValid() == nil {
+		if lErr := c.local.Move(ctx, oldName, newName, opts); lErr != nil && !errors.Is(lErr, model.ErrTMNameNotFound) {
+			utils.GetLogger(ctx, "CacheRepo").Warn("could not move TM name in cache", "name", oldName, "error", lErr)
+		}
+	}
+	return nil
+}
+
+func (c *CacheRepo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	return c.upstream.ValidationFiles(ctx)
+}
+
+func (c *CacheRepo) FetchAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) ([]byte, error) {
+	if c.local.checkRootValid() == nil {
+		content, err := c.local.FetchAttachment(ctx, container, attachmentName)
+		if err == nil {
+			return content, nil
+		}
+	}
+	content, err := c.upstream.FetchAttachment(ctx, container, attachmentName)
+	if err != nil {
+		return nil, err
+	}
+	if sErr := c.storeAttachment(ctx, container, attachmentName, content); sErr != nil {
+		utils.GetLogger(ctx, "CacheRepo").Warn("could not store attachment in cache", "container", container, "attachment", attachmentName, "error", sErr)
+	}
+	return content, nil
+}
+
+func (c *CacheRepo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName, ifMatch string) error {
+	defer c.invalidateUpstreamIndex()
+	err := c.upstream.DeleteAttachment(ctx, container, attachmentName, ifMatch)
+	if err != nil {
+		return err
+	}
+	c.deleteCachedAttachment(ctx, container, attachmentName)
+	return nil
+}
+
+func (c *CacheRepo) deleteCachedAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) {
+	if c.local.checkRootValid() != nil {
+		return
+	}
+	err := c.local.DeleteAttachment(ctx, container, attachmentName, "")
+	var nfErr *model.ErrNotFound
+	if err != nil && !errors.As(err, &nfErr) {
+		utils.GetLogger(ctx, "CacheRepo").Warn("could not delete attachment from cache", "container", container, "attachment", attachmentName, "error", err)
+	}
+}
+
+// storeTM writes the TM into the local storage and updates the local index
+func (c *CacheRepo) storeTM(ctx context.Context, id string, raw []byte) error {
+	tmID, err := model.ParseTMID(id)
+	if err != nil {
+		return err
+	}
+	res, err := c.local.Import(ctx, tmID, raw, ImportOptions{Force: true})
+	if err != nil {
+		return err
+	}
+	return c.local.Index(ctx, res.TmID)
+}
+
+// storeAttachment writes the attachment into the local storage. An attachment to a TM id is stored along with the TM
+func (c *CacheRepo) storeAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string, content []byte) error {
+	if ref.Kind() == model.AttachmentContainerKindTMID {
+		if _, _, err := c.Fetch(ctx, ref.TMID); err != nil {
+			return err
+		}
+	}
+	att := model.Attachment{Name: attachmentName}
+	if idx, err := c.readUpstreamIndex(); err == nil {
+		if cont, _, _ := idx.FindAttachmentContainer(ref); cont != nil {
+			att, _ = cont.FindAttachment(attachmentName)
+		}
+	}
+	return c.local.ImportAttachment(ctx, ref, att, content, true, "")
+}
+
+// upstreamIndex returns the cached copy of the upstream's index, if it's younger than indexTTL. Otherwise,
+// fetches the index from upstream. Falls back to the stale cached copy if the upstream is unreachable
+func (c *CacheRepo) upstreamIndex(ctx context.Context) (*model.Index, error) {
+	stat, err := os.Stat(c.upstreamIndexFilename())
+	if err == nil && time.Since(stat.ModTime()) < c.indexTTL {
+		idx, err := c.readUpstreamIndex()
+		if err == nil {
+			return idx, nil
+		}
+	}
+	idx, err := c.refreshUpstreamIndex(ctx)
+	if err != nil {
+		stale, sErr := c.readUpstreamIndex()
+		if sErr != nil {
+			return nil, err
+		}
+		utils.GetLogger(ctx, "CacheRepo").Warn("could not refresh upstream index, using stale copy", "error", err)
+		return stale, nil
+	}
+	return idx, nil
+}
+
+// refreshUpstreamIndex fetches the index from upstream and stores it locally
+func (c *CacheRepo) refreshUpstreamIndex(ctx context.Context) (*model.Index, error) {
+	res, err := c.upstream.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	idx := searchResultToIndex(res)
+	err = os.MkdirAll(filepath.Dir(c.upstreamIndexFilename()), defaultDirPermissions)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(idx, "", "  ")
+	err = utils.AtomicWriteFile(c.upstreamIndexFilename(), b, defaultFilePermissions)
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// prefetchAll stores all TMs and attachments from the index, which have not been stored yet. Continues past failures
+// to prefetch single TMs or attachments and returns them joined
+func (c *CacheRepo) prefetchAll(ctx context.Context, idx *model.Index) error {
+	log := utils.GetLogger(ctx, "CacheRepo")
+	var errs []error
+	addErr := func(err error) {
+		log.Warn("prefetch failed", "error", err)
+		errs = append(errs, err)
+	}
+	for _, e := range idx.Data {
+		for _, v := range e.Versions {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			ref := model.NewTMIDAttachmentContainerRef(v.TMID)
+			for _, a := range v.Attachments {
+				if err := c.prefetchAttachment(ctx, ref, a.Name); err != nil {
+					addErr(err)
+				}
+			}
+			if _, _, err := c.Fetch(ctx, v.TMID); err != nil {
+				addErr(fmt.Errorf("could not prefetch %s: %w", v.TMID, err))
+			}
+		}
+		for _, a := range e.Attachments {
+			if err := c.prefetchAttachment(ctx, model.NewTMNameAttachmentContainerRef(e.Name), a.Name); err != nil {
+				addErr(err)
+			}
+		}
+	}
+	log.Debug("prefetched upstream", "entries", len(idx.Data), "failures", len(errs))
+	return errors.Join(errs...)
+}
+
+func (c *CacheRepo) prefetchAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string) error {
+	_, err := c.FetchAttachment(ctx, ref, attachmentName)
+	if err != nil {
+		return fmt.Errorf("could not prefetch attachment %s of %s: %w", attachmentName, ref, err)
+	}
+	return nil
+}
+
+func (c *CacheRepo) readUpstreamIndex() (*model.Index, error) {
+	data, err := os.ReadFile(c.upstreamIndexFilename())
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = ErrNoIndex
+		}
+		return nil, err
+	}
+	var idx model.Index
+	err = json.Unmarshal(data, &idx)
+	return &idx, err
+}
+
+// invalidateUpstreamIndex removes the cached copy of the upstream's index, so that it is fetched again on next access
+func (c *CacheRepo) invalidateUpstreamIndex() {
+	_ = os.Remove(c.upstreamIndexFilename())
+}
+
+func (c *CacheRepo) upstreamIndexFilename() string {
+	return filepath.Join(c.local.root, RepoConfDir, upstreamIndexFilename)
+}
+
+func searchResultToIndex(res model.SearchResult) *model.Index {
+	idx := &model.Index{
+		Meta: model.IndexMeta{Created: res.LastUpdated},
+		Data: []*model.IndexEntry{},
+	}
+	for _, e := range res.Entries {
+		entry := &model.IndexEntry{
+			Name:                e.Name,
+			Manufacturer:        e.Manufacturer,
+			Mpn:                 e.Mpn,
+			Author:              e.Author,
+			AttachmentContainer: e.AttachmentContainer,
+		}
+		for _, v := range e.Versions {
+			entry.Versions = append(entry.Versions, v.IndexVersion)
+		}
+		idx.Data = append(idx.Data, entry)
+	}
+	return idx
+}
+
+func createCacheRepoConfig(bytes []byte) (ConfigMap, error) {
+	rc, err := AsRepoConfig(bytes)
+	if err != nil {
+		return nil, err
+	}
+	if rType, found := utils.JsGetString(rc, KeyRepoType); found {
+		if rType != RepoTypeCache {
+			return nil, fmt.Errorf("invalid json config. type must be \"cache\" or absent")
+		}
+	}
+	rc[KeyRepoType] = RepoTypeCache
+	l, found := utils.JsGetString(rc, KeyRepoLoc)
+	if !found {
+		return nil, fmt.Errorf("invalid json config. must have string \"loc\"")
+	}
+	la, err := makeAbs(l)
+	if err != nil {
+		return nil, err
+	}
+	rc[KeyRepoLoc] = la
+	if ttl, found := utils.JsGetString(rc, KeyRepoCacheIndexTTL); found {
+		if _, err := time.ParseDuration(ttl); err != nil && !isEnvReference(ttl) {
+			return nil, fmt.Errorf("invalid json config. \"%s\" must be a duration, e.g. \"1h\": %w", KeyRepoCacheIndexTTL, err)
+		}
+	}
+	up, ok := rc[KeyRepoCacheUpstream].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid json config. must have object \"upstream\"")
+	}
+	upType, _ := utils.JsGetString(up, KeyRepoType)
+	if upType == RepoTypeCache {
+		return nil, fmt.Errorf("invalid json config. upstream cannot be a cache")
+	}
+	upBytes, _ := json.Marshal(up)
+	upConf, err := NewRepoConfig(upType, upBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream config: %w", err)
+	}
+	rc[KeyRepoCacheUpstream] = map[string]any(upConf)
+	return rc, nil
+}
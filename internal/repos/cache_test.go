@@ -0,0 +1,213 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/testutils"
+)
+
+func setupCacheTest(t *testing.T, conf ConfigMap) (*CacheRepo, string) {
+	t.Helper()
+	upstreamDir := t.TempDir()
+	require.NoError(t, testutils.CopyDir("../../test/data/index", upstreamDir))
+	upstream := &FileRepo{root: upstreamDir, spec: model.NewRepoSpec("up")}
+	require.NoError(t, upstream.Index(context.Background()))
+
+	if conf == nil {
+		conf = ConfigMap{}
+	}
+	conf[KeyRepoType] = RepoTypeCache
+	conf[KeyRepoLoc] = filepath.Join(t.TempDir(), "cache")
+	conf[KeyRepoCacheUpstream] = map[string]any{KeyRepoType: RepoTypeFile, KeyRepoLoc: upstreamDir}
+	r, err := NewCacheRepo(conf, model.NewRepoSpec("cache"))
+	require.NoError(t, err)
+	return r, upstreamDir
+}
+
+func TestCreateCacheRepoConfig(t *testing.T) {
+	rc, err := createCacheRepoConfig([]byte(`{"loc":"/tmp/cache","indexTTL":"1h","prefetch":true,"upstream":{"type":"http","loc":"http://example.com/catalog"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, RepoTypeCache, rc[KeyRepoType])
+	assert.Equal(t, filepath.Clean("/tmp/cache"), rc[KeyRepoLoc])
+	assert.Equal(t, map[string]any{KeyRepoType: RepoTypeHttp, KeyRepoLoc: "http://example.com/catalog"}, rc[KeyRepoCacheUpstream])
+
+	tests := []string{
+		`{"type":"file","loc":"/tmp/cache","upstream":{"type":"http","loc":"http://example.com/catalog"}}`,
+		`{"upstream":{"type":"http","loc":"http://example.com/catalog"}}`,
+		`{"loc":"/tmp/cache"}`,
+		`{"loc":"/tmp/cache","upstream":{"type":"unknown"}}`,
+		`{"loc":"/tmp/cache","upstream":{"type":"cache","loc":"/tmp/cache2","upstream":{"type":"http","loc":"http://example.com/catalog"}}}`,
+		`{"loc":"/tmp/cache","indexTTL":"one hour","upstream":{"type":"http","loc":"http://example.com/catalog"}}`,
+	}
+	for _, test := range tests {
+		_, err := createCacheRepoConfig([]byte(test))
+		assert.Error(t, err, test)
+	}
+}
+
+func TestCacheRepo_Fetch(t *testing.T) {
+	r, upstreamDir := setupCacheTest(t, nil)
+	ctx := context.Background()
+	id := "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"
+
+	// when: fetching a TM
+	actualId, raw, err := r.Fetch(ctx, id)
+	// then: the TM is returned
+	assert.NoError(t, err)
+	assert.Equal(t, id, actualId)
+	// and then: the TM is stored and indexed locally
+	assert.FileExists(t, filepath.Join(r.local.root, id))
+	vs, err := r.local.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+	assert.NoError(t, err)
+	assert.Len(t, vs, 1)
+
+	// when: the upstream is gone
+	require.NoError(t, os.RemoveAll(upstreamDir))
+	// then: the cached TM can still be fetched
+	actualId, raw2, err := r.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, actualId)
+	assert.Equal(t, raw, raw2)
+	// and then: a TM that has never been fetched cannot
+	_, _, err = r.Fetch(ctx, "omnicorp-tm-department/omnicorp/omnilamp/v3.11.1-20240409155220-da7dbd7ed830.tm.json")
+	assert.Error(t, err)
+	// and then: the cached TMs pass the integrity check
+	res, err := r.CheckIntegrity(ctx, nil)
+	assert.NoError(t, err)
+	for _, cr := range res {
+		assert.Equal(t, model.CheckOK, cr.Typ, cr.String())
+	}
+}
+
+func TestCacheRepo_List(t *testing.T) {
+	ctx := context.Background()
+	deleteUpstreamTM := func(t *testing.T, upstreamDir string) {
+		up := &FileRepo{root: upstreamDir}
+		require.NoError(t, up.Delete(ctx, "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"))
+	}
+
+	t.Run("without ttl", func(t *testing.T) {
+		r, upstreamDir := setupCacheTest(t, nil)
+		res, err := r.List(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, res.Entries, 2)
+		assert.Equal(t, "cache", res.Entries[0].FoundIn.RepoName)
+
+		deleteUpstreamTM(t, upstreamDir)
+		vs, err := r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		assert.NoError(t, err)
+		assert.Len(t, vs, 2)
+
+		// when: the upstream is gone
+		require.NoError(t, os.RemoveAll(upstreamDir))
+		// then: the stale copy of the upstream's index is used
+		vs, err = r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		assert.NoError(t, err)
+		assert.Len(t, vs, 2)
+	})
+	t.Run("with ttl", func(t *testing.T) {
+		r, upstreamDir := setupCacheTest(t, ConfigMap{KeyRepoCacheIndexTTL: "1h"})
+		vs, err := r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		assert.NoError(t, err)
+		assert.Len(t, vs, 3)
+
+		deleteUpstreamTM(t, upstreamDir)
+		vs, err = r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		assert.NoError(t, err)
+		assert.Len(t, vs, 3)
+
+		// when: the ttl has expired
+		past := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(r.upstreamIndexFilename(), past, past))
+		// then: the upstream's index is fetched again
+		vs, err = r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		assert.NoError(t, err)
+		assert.Len(t, vs, 2)
+	})
+	t.Run("offline without upstream index", func(t *testing.T) {
+		r, upstreamDir := setupCacheTest(t, nil)
+		id := "omnicorp-tm-department/omnicorp/omnilamp/subfolder/v3.2.1-20240409155220-3f779458e453.tm.json"
+		_, _, err := r.Fetch(ctx, id)
+		require.NoError(t, err)
+		require.NoError(t, os.RemoveAll(upstreamDir))
+
+		// when: listing the cache, which has never fetched the upstream's index
+		res, err := r.List(ctx, nil)
+		// then: the locally stored TMs are listed
+		assert.NoError(t, err)
+		if assert.Len(t, res.Entries, 1) && assert.Len(t, res.Entries[0].Versions, 1) {
+			assert.Equal(t, id, res.Entries[0].Versions[0].TMID)
+		}
+	})
+}
+
+func TestCacheRepo_FetchAttachment(t *testing.T) {
+	r, upstreamDir := setupCacheTest(t, nil)
+	ctx := context.Background()
+	ref := model.NewTMIDAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20240409155220-e414b33a9edf.tm.json")
+
+	content, err := r.FetchAttachment(ctx, ref, "manual.txt")
+	assert.NoError(t, err)
+
+	require.NoError(t, os.RemoveAll(upstreamDir))
+	cached, err := r.FetchAttachment(ctx, ref, "manual.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, content, cached)
+	// and then: the TM the attachment belongs to is cached as well
+	_, _, err = r.Fetch(ctx, ref.TMID)
+	assert.NoError(t, err)
+}
+
+func TestCacheRepo_Prefetch(t *testing.T) {
+	r, upstreamDir := setupCacheTest(t, ConfigMap{KeyRepoCachePrefetch: true})
+	ctx := context.Background()
+
+	// when: refreshing the index in prefetch mode
+	err := r.Index(ctx)
+	assert.NoError(t, err)
+
+	// then: everything is available offline
+	require.NoError(t, os.RemoveAll(upstreamDir))
+	res, err := r.local.List(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, res.Entries, 2)
+	assert.Equal(t, 5, len(res.Entries[0].Versions)+len(res.Entries[1].Versions))
+	_, err = r.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20240409155220-e414b33a9edf.tm.json"), "manual.txt")
+	assert.NoError(t, err)
+}
+
+func TestCacheRepo_PrefetchOnlyOnIndex(t *testing.T) {
+	r, upstreamDir := setupCacheTest(t, ConfigMap{KeyRepoCachePrefetch: true})
+	ctx := context.Background()
+
+	// when: listing in prefetch mode
+	res, err := r.List(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, res.Entries, 2)
+	// then: nothing has been prefetched
+	assert.NoError(t, r.local.Index(ctx))
+	local, err := r.local.List(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, local.Entries)
+
+	// when: a TM listed in the upstream's index is missing and the index is refreshed
+	missing := "omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20240409155220-e414b33a9edf.tm.json"
+	require.NoError(t, os.Remove(filepath.Join(upstreamDir, missing)))
+	err = r.Index(ctx)
+	// then: the failure is reported
+	assert.ErrorContains(t, err, missing)
+	// and then: the refreshed index is kept and the other TMs are prefetched anyway
+	idx, err := r.readUpstreamIndex()
+	assert.NoError(t, err)
+	assert.Len(t, idx.Data, 2)
+	local, err = r.local.List(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, local.Entries, 2)
+}
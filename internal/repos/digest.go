@@ -0,0 +1,62 @@
+package repos
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+const (
+	KeyRepoDigest = "digest"
+
+	DigestModeRaw = "raw" // hash the TM file as is, after normalizing line endings and blanking 'id'
+	DigestModeJCS = "jcs" // hash the canonical form of the TM file (RFC 8785), independent of formatting and order of keys
+)
+
+// GetDigestMode returns the digest mode configured for the repo given by spec.
+// An empty spec refers to the only enabled repo, as with Get.
+// Repos given by a directory and repos without a configured mode use DigestModeRaw, so that existing ids stay stable
+var GetDigestMode = func(spec model.RepoSpec) (string, error) {
+	if spec.Dir() != "" {
+		return DigestModeRaw, nil
+	}
+	rc, ok, err := enabledRepoConfig(spec)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return DigestModeRaw, nil
+	}
+	return digestMode(rc)
+}
+
+// enabledRepoConfig returns the config of the enabled repo given by spec without reading the config with ReadConfig,
+// which may need to migrate and save it. An empty spec refers to the only enabled repo
+func enabledRepoConfig(spec model.RepoSpec) (ConfigMap, bool, error) {
+	reposConfig, _ := viper.Get(KeyRepos).(map[string]any)
+	conf, err := mapToConfig(reposConfig)
+	if err != nil {
+		return nil, false, err
+	}
+	conf = filterEnabled(conf)
+	parent, _ := splitRepoName(spec.RepoName())
+	rc, ok := conf[parent]
+	if parent == "" && len(conf) == 1 {
+		for _, c := range conf {
+			rc, ok = c, true
+		}
+	}
+	return rc, ok, nil
+}
+
+func digestMode(rc ConfigMap) (string, error) {
+	mode, found := rc.GetString(KeyRepoDigest)
+	if !found || mode == "" {
+		return DigestModeRaw, nil
+	}
+	if mode != DigestModeRaw && mode != DigestModeJCS {
+		return "", fmt.Errorf("invalid %s: %s. Must be one of %s, %s", KeyRepoDigest, mode, DigestModeRaw, DigestModeJCS)
+	}
+	return mode, nil
+}
@@ -0,0 +1,70 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+func TestGetDigestMode(t *testing.T) {
+	viper.Set(KeyRepos, map[string]any{
+		"r0": map[string]any{
+			"type":        "file",
+			"loc":         "disabled",
+			"enabled":     false,
+			KeyRepoDigest: DigestModeJCS,
+		},
+		"r1": map[string]any{
+			"type": "file",
+			"loc":  "somewhere",
+		},
+		"r2": map[string]any{
+			"type":        "file",
+			"loc":         "somewhere-else",
+			KeyRepoDigest: DigestModeJCS,
+		},
+		"r3": map[string]any{
+			"type":        "file",
+			"loc":         "elsewhere",
+			KeyRepoDigest: "md5",
+		},
+	})
+	defer viper.Reset()
+
+	tests := []struct {
+		spec    model.RepoSpec
+		exp     string
+		wantErr bool
+	}{
+		{model.NewRepoSpec("r1"), DigestModeRaw, false},
+		{model.NewRepoSpec("r2"), DigestModeJCS, false},
+		{model.NewRepoSpec("r3"), "", true},
+		{model.NewRepoSpec("r4"), DigestModeRaw, false},
+		{model.NewDirSpec("somewhere-else"), DigestModeRaw, false},
+		{model.EmptySpec, DigestModeRaw, false}, // ambiguous
+	}
+	for _, test := range tests {
+		mode, err := GetDigestMode(test.spec)
+		if test.wantErr {
+			assert.ErrorContains(t, err, "invalid digest", test.spec)
+		} else {
+			assert.NoError(t, err, test.spec)
+			assert.Equal(t, test.exp, mode, test.spec)
+		}
+	}
+
+	t.Run("single enabled repo", func(t *testing.T) {
+		viper.Set(KeyRepos, map[string]any{
+			"r2": map[string]any{
+				"type":        "file",
+				"loc":         "somewhere-else",
+				KeyRepoDigest: DigestModeJCS,
+			},
+		})
+		mode, err := GetDigestMode(model.EmptySpec)
+		assert.NoError(t, err)
+		assert.Equal(t, DigestModeJCS, mode)
+	})
+}
@@ -13,6 +13,7 @@ var (
 	ErrInvalidRepoName         = errors.New("invalid repo name")
 	ErrRepoExists              = errors.New("named repo already exists")
 	ErrAttachmentExists        = errors.New("attachment already exists")
+	ErrPreconditionFailed      = errors.New("resource has been modified concurrently")
 	ErrInvalidErrorCode        = errors.New("invalid error code")
 	ErrInvalidCompletionParams = errors.New("invalid completion parameters")
 	ErrNotSupported            = errors.New("method not supported")
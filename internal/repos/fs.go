@@ -252,13 +252,21 @@ func (f *FileRepo) Fetch(ctx context.Context, id string) (string, []byte, error)
 	if err != nil {
 		return "", nil, err
 	}
-	_, err = model.ParseTMID(id)
+	tmid, err := model.ParseTMID(id)
 	if err != nil {
 		return "", nil, err
 	}
 	match, actualId := f.getExistingID(ctx, id)
 	if match != idMatchFull && match != idMatchDigest {
-		return "", nil, model.ErrTMNotFound
+		newName, ok := f.resolveAlias(ctx, tmid.Name)
+		if !ok {
+			return "", nil, model.ErrTMNotFound
+		}
+		// moving may have changed the digest along with the content, but not the timestamp
+		match, actualId = f.getExistingID(ctx, movedTMID(id, newName))
+		if match == idMatchNone {
+			return "", nil, model.ErrTMNotFound
+		}
 	}
 	actualFilename, _, _ := f.filenames(actualId)
 	b, err := os.ReadFile(actualFilename)
@@ -371,6 +379,9 @@ func (f *FileRepo) Versions(ctx context.Context, name string) ([]model.FoundVers
 	}
 
 	if len(res.Entries) != 1 {
+		if newName, ok := f.resolveAlias(ctx, name); ok {
+			return f.Versions(ctx, newName)
+		}
 		err := fmt.Errorf("%w: %s", model.ErrTMNameNotFound, name)
 		return nil, err
 	}
@@ -378,6 +389,20 @@ func (f *FileRepo) Versions(ctx context.Context, name string) ([]model.FoundVers
 	return res.Entries[0].Versions, nil
 }
 
+// resolveAlias returns the current name of the TM name name, if it has been moved
+func (f *FileRepo) resolveAlias(ctx context.Context, name string) (string, bool) {
+	unlock, err := f.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return "", false
+	}
+	idx, err := f.readIndex()
+	if err != nil {
+		return "", false
+	}
+	return idx.ResolveAlias(name)
+}
+
 func (f *FileRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, error) {
 	id, err := model.ParseTMID(tmID)
 	if err != nil {
@@ -388,14 +413,15 @@ func (f *FileRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.Foun
 		return nil, err
 	}
 	for _, v := range versions {
-		if v.TMID == tmID {
+		// versions belong to the name id.Name has been moved to, if it has been moved
+		if isVersionOf(tmID, v.TMID) {
 			return []model.FoundVersion{v}, nil
 		}
 	}
 	return nil, model.ErrTMNotFound
 }
 
-func (f *FileRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool) error {
+func (f *FileRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
 	err := f.checkRootValid()
 	if err != nil {
 		return err
@@ -437,6 +463,152 @@ func (f *FileRepo) ImportAttachment(ctx context.Context, container model.Attachm
 	return nil
 }
 
+// SetLifecycle sets the lifecycle metadata of the TM version with given id in the index.
+// Returns ErrTMNotFound if the version does not exist
+func (f *FileRepo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	err := f.checkRootValid()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := f.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = f.updateIndex(ctx, f.indexUpdaterForLifecycle(id, lc))
+	return err
+}
+
+// UpdateLabels updates the labels of the TM name or TM version given by ref in the index
+func (f *FileRepo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error {
+	err := f.checkRootValid()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := f.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = f.updateIndex(ctx, f.indexUpdaterForLabels(ref, add, remove))
+	return err
+}
+
+// Move moves the files of all versions of the TM name oldName and its attachments directory to newName, rewriting
+// the ids and display names in the TM files, and updates the index accordingly
+func (f *FileRepo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) error {
+	err := f.checkRootValid()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := f.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+	index, err := f.readIndex()
+	if err != nil {
+		return err
+	}
+	entry := index.FindByName(oldName)
+	if entry == nil {
+		return fmt.Errorf("%w: %s", model.ErrTMNameNotFound, oldName)
+	}
+	if index.FindByName(newName) != nil {
+		return fmt.Errorf("%w: %s", model.ErrTMNameExists, newName)
+	}
+
+	// the new files are written and the index is updated before the old files are removed, so that a failed move can
+	// be rolled back
+	var oldFiles, newFiles []string
+	var movedDirs [][2]string
+	rollback := func(err error) error {
+		log := utils.GetLogger(ctx, "FileRepo")
+		for i := len(movedDirs) - 1; i >= 0; i-- {
+			if rErr := os.Rename(movedDirs[i][1], movedDirs[i][0]); rErr != nil {
+				log.Error("could not roll back moving attachments", "dir", movedDirs[i][1], "error", rErr)
+			}
+		}
+		for _, newFile := range newFiles {
+			if rErr := os.Remove(newFile); rErr != nil {
+				log.Error("could not roll back writing moved TM", "file", newFile, "error", rErr)
+			}
+		}
+		_ = rmEmptyDirs(filepath.Join(f.root, newName), f.root)
+		return err
+	}
+
+	newIds := make(map[string]string)
+	for _, v := range entry.Versions {
+		oldFile, _, _ := f.filenames(v.TMID)
+		raw, err := os.ReadFile(oldFile)
+		if err != nil {
+			return rollback(err)
+		}
+		newId, raw, err := movedTM(raw, v.TMID, newName, opts)
+		if err != nil {
+			return rollback(err)
+		}
+		newIds[v.TMID] = newId
+		newFile, newDir, _ := f.filenames(newId)
+		err = os.MkdirAll(newDir, defaultDirPermissions)
+		if err != nil {
+			return rollback(fmt.Errorf("could not create directory %s: %w", newDir, err))
+		}
+		err = utils.AtomicWriteFile(newFile, raw, defaultFilePermissions)
+		if err != nil {
+			return rollback(fmt.Errorf("could not write TM to catalog: %w", err))
+		}
+		oldFiles = append(oldFiles, oldFile)
+		newFiles = append(newFiles, newFile)
+	}
+
+	// the attachments of the TM name and of all its versions are located in the attachments directory of the TM name
+	oldAttDir, _ := f.getAttachmentsDir(model.NewTMNameAttachmentContainerRef(oldName))
+	newAttDir, _ := f.getAttachmentsDir(model.NewTMNameAttachmentContainerRef(newName))
+	if _, err := os.Stat(oldAttDir); err == nil {
+		err = os.MkdirAll(filepath.Dir(newAttDir), defaultDirPermissions)
+		if err != nil {
+			return rollback(err)
+		}
+		err = os.Rename(oldAttDir, newAttDir)
+		if err != nil {
+			return rollback(fmt.Errorf("could not move attachments of %s: %w", oldName, err))
+		}
+		movedDirs = append(movedDirs, [2]string{oldAttDir, newAttDir})
+		for from, to := range movedVersionAttachmentDirs(newName, newIds) {
+			from, to = filepath.Join(f.root, from), filepath.Join(f.root, to)
+			if _, err := os.Stat(from); err != nil {
+				continue
+			}
+			err = os.Rename(from, to)
+			if err != nil {
+				return rollback(fmt.Errorf("could not move attachments of %s: %w", oldName, err))
+			}
+			movedDirs = append(movedDirs, [2]string{from, to})
+		}
+	}
+
+	_, err = f.updateIndex(ctx, f.indexUpdaterForMove(oldName, newName, newIds, opts.Names))
+	if err != nil {
+		return rollback(err)
+	}
+
+	// the move is complete with the index updated. Leftovers of the old name are reported by 'check'
+	for _, oldFile := range oldFiles {
+		if rErr := os.Remove(oldFile); rErr != nil {
+			utils.GetLogger(ctx, "FileRepo").Warn("could not remove moved TM", "file", oldFile, "error", rErr)
+		}
+	}
+	_ = rmEmptyDirs(filepath.Join(f.root, oldName), f.root)
+	return nil
+}
+
 // prepareAttachmentOperation prepares for a CRUD operation on attachments
 // Must be called after the index lock has been acquired with lockIndex
 func (f *FileRepo) prepareAttachmentOperation(ref model.AttachmentContainerRef) (string, error) {
@@ -452,6 +624,37 @@ func (f *FileRepo) prepareAttachmentOperation(ref model.AttachmentContainerRef)
 	return attDir, nil
 }
 
+func (f *FileRepo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	if _, err := os.Stat(f.root); errors.Is(err, os.ErrNotExist) {
+		// a repo without root has no validation files. The root is created by the first import
+		return map[string][]byte{}, nil
+	}
+	err := f.checkRootValid()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(f.root, RepoConfDir, ValidationDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[e.Name()] = content
+	}
+	return files, nil
+}
+
 func (f *FileRepo) FetchAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string) ([]byte, error) {
 	err := f.checkRootValid()
 	if err != nil {
@@ -494,7 +697,7 @@ func (f *FileRepo) verifyAttachmentExistsInIndex(ref model.AttachmentContainerRe
 	}
 	return nil
 }
-func (f *FileRepo) DeleteAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string) error {
+func (f *FileRepo) DeleteAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName, ifMatch string) error {
 	err := f.checkRootValid()
 	if err != nil {
 		return err
@@ -808,6 +1011,45 @@ func (f *FileRepo) indexUpdaterForImportAttachment(ref model.AttachmentContainer
 	}
 }
 
+func (f *FileRepo) indexUpdaterForLifecycle(id string, lc model.Lifecycle) indexUpdater {
+	return func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		default:
+		}
+		err := oldIndex.SetLifecycle(id, lc)
+		return oldIndex, oldNames, 1, err
+	}
+}
+
+func (f *FileRepo) indexUpdaterForLabels(ref model.AttachmentContainerRef, add, remove []string) indexUpdater {
+	return func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		default:
+		}
+		err := oldIndex.UpdateLabels(ref, add, remove)
+		return oldIndex, oldNames, 1, err
+	}
+}
+
+func (f *FileRepo) indexUpdaterForMove(oldName, newName string, newIds map[string]string, names model.DisplayNames) indexUpdater {
+	return func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		default:
+		}
+		err := oldIndex.Move(oldName, newName, newIds, names)
+		newNames := append(slices.DeleteFunc(oldNames, func(s string) bool {
+			return s == oldName
+		}), newName)
+		return oldIndex, newNames, 1, err
+	}
+}
+
 func (f *FileRepo) fullIndexRebuild(ctx context.Context, oldIndex *model.Index, _ []string) (*model.Index, []string, int, error) {
 	fileCount := 0
 	updatedAttContainers := make(map[model.AttachmentContainerRef]struct{})
@@ -841,6 +1083,7 @@ func (f *FileRepo) fullIndexRebuild(ctx context.Context, oldIndex *model.Index,
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	newIndex.CopyIndexMetadata(oldIndex)
 
 	return newIndex, names, fileCount, nil
 }
@@ -1182,7 +1425,8 @@ func isTmcConfigFile(p string) bool {
 	return p == path.Join(RepoConfDir, IndexFilename) ||
 		p == path.Join(RepoConfDir, IndexFilename+".lock") ||
 		p == path.Join(RepoConfDir, TmIgnoreFile) ||
-		p == path.Join(RepoConfDir, TmNamesFile)
+		p == path.Join(RepoConfDir, TmNamesFile) ||
+		path.Dir(p) == path.Join(RepoConfDir, ValidationDir)
 
 }
 
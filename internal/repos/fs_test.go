@@ -878,7 +878,7 @@ func TestFileRepo_ImportAttachment(t *testing.T) {
 	r2Content := []byte("# read this, too")
 	t.Run("tm name attachment without media type provided", func(t *testing.T) {
 		ref := model.NewTMNameAttachmentContainerRef(tmName)
-		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, tmName, model.AttachmentsDir, r2Name))
 		index, err := r.readIndex()
@@ -891,7 +891,7 @@ func TestFileRepo_ImportAttachment(t *testing.T) {
 	})
 	t.Run("tm name attachment with media type", func(t *testing.T) {
 		ref := model.NewTMNameAttachmentContainerRef(tmName)
-		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name, MediaType: "text/html"}, r2Content, true)
+		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name, MediaType: "text/html"}, r2Content, true, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, tmName, model.AttachmentsDir, r2Name))
 		index, err := r.readIndex()
@@ -904,7 +904,7 @@ func TestFileRepo_ImportAttachment(t *testing.T) {
 	})
 	t.Run("tm id attachment with media type provided by user", func(t *testing.T) {
 		ref := model.NewTMIDAttachmentContainerRef(id)
-		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, false, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, tmName, model.AttachmentsDir, ver, r2Name))
 		index, err := r.readIndex()
@@ -917,7 +917,7 @@ func TestFileRepo_ImportAttachment(t *testing.T) {
 	})
 	t.Run("tm id attachment without media type", func(t *testing.T) {
 		ref := model.NewTMIDAttachmentContainerRef(id)
-		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, true)
+		err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, true, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, tmName, model.AttachmentsDir, ver, r2Name))
 		index, err := r.readIndex()
@@ -929,23 +929,23 @@ func TestFileRepo_ImportAttachment(t *testing.T) {
 		}
 	})
 	t.Run("tm id attachment conflict", func(t *testing.T) {
-		err := r.ImportAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(id), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(id), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, ErrAttachmentExists)
 	})
 	t.Run("non existent tm name", func(t *testing.T) {
-		err := r.ImportAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
 	})
 	t.Run("non existent tm id", func(t *testing.T) {
-		err := r.ImportAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrTMNotFound)
 	})
 	t.Run("invalid tm name", func(t *testing.T) {
-		err := r.ImportAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrInvalidIdOrName)
 	})
 	t.Run("invalid tm id", func(t *testing.T) {
-		err := r.ImportAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrInvalidId)
 	})
 }
@@ -965,27 +965,27 @@ func TestFileRepo_DeleteAttachment(t *testing.T) {
 	attNameB := "cfg.json"
 
 	t.Run("non existent attachment", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef(tmName), "nothing-here")
+		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef(tmName), "nothing-here", "")
 		assert.ErrorIs(t, err, model.ErrAttachmentNotFound)
 	})
 	t.Run("non existent tm name", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), attNameA)
+		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
 	})
 	t.Run("non existent tm id", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), attNameA)
+		err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrTMNotFound)
 	})
 	t.Run("invalid tm name", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), attNameA)
+		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrInvalidIdOrName)
 	})
 	t.Run("invalid tm id", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), attNameA)
+		err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrInvalidId)
 	})
 	t.Run("tm id attachment", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(idA), attNameB)
+		err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(idA), attNameB, "")
 		assert.NoError(t, err)
 		_, err = os.Stat(filepath.Join(temp, tmName, model.AttachmentsDir, ver, attNameA))
 		assert.True(t, os.IsNotExist(err))
@@ -993,7 +993,7 @@ func TestFileRepo_DeleteAttachment(t *testing.T) {
 		assert.True(t, os.IsNotExist(err))
 	})
 	t.Run("tm name attachment", func(t *testing.T) {
-		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef(tmName), attNameA)
+		err := r.DeleteAttachment(context.Background(), model.NewTMNameAttachmentContainerRef(tmName), attNameA, "")
 		assert.NoError(t, err)
 		_, err = os.Stat(filepath.Join(temp, tmName, model.AttachmentsDir, attNameA))
 		assert.True(t, os.IsNotExist(err))
@@ -1070,3 +1070,251 @@ func (f fakeFileInfo) IsDir() bool {
 func (f fakeFileInfo) Sys() any {
 	return nil
 }
+
+func TestFileRepo_SetLifecycle(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "fr")
+	defer os.RemoveAll(temp)
+	r := &FileRepo{
+		root: temp,
+		spec: model.NewRepoSpec("fr"),
+	}
+	assert.NoError(t, testutils.CopyDir("../../test/data/repos/file/attachments", temp))
+	id := "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"
+	ctx := context.Background()
+
+	t.Run("non existent tm id", func(t *testing.T) {
+		err := r.SetLifecycle(ctx, "omnicorp-tm-department/omnicorp/omnilamp/v1.2.3-20240409155220-3f779458e453.tm.json", model.Lifecycle{State: model.LifecycleYanked})
+		assert.ErrorIs(t, err, model.ErrTMNotFound)
+	})
+	t.Run("yank", func(t *testing.T) {
+		lc := model.Lifecycle{State: model.LifecycleYanked, Reason: "broken"}
+		err := r.SetLifecycle(ctx, id, lc)
+		assert.NoError(t, err)
+		vers, err := r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		assert.NoError(t, err)
+		if assert.Len(t, vers, 1) {
+			assert.Equal(t, &lc, vers[0].Lifecycle)
+		}
+	})
+	t.Run("survives full reindex", func(t *testing.T) {
+		err := r.Index(ctx)
+		assert.NoError(t, err)
+		idx, err := r.readIndex()
+		assert.NoError(t, err)
+		assert.Equal(t, model.LifecycleYanked, idx.FindByTMID(id).State())
+	})
+	t.Run("back to active", func(t *testing.T) {
+		err := r.SetLifecycle(ctx, id, model.Lifecycle{State: model.LifecycleActive})
+		assert.NoError(t, err)
+		idx, err := r.readIndex()
+		assert.NoError(t, err)
+		assert.Nil(t, idx.FindByTMID(id).Lifecycle)
+	})
+}
+
+func TestFileRepo_UpdateLabels(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "fr")
+	defer os.RemoveAll(temp)
+	r := &FileRepo{
+		root: temp,
+		spec: model.NewRepoSpec("fr"),
+	}
+	assert.NoError(t, testutils.CopyDir("../../test/data/repos/file/attachments", temp))
+	tmName := "omnicorp-tm-department/omnicorp/omnilamp"
+	id := tmName + "/v3.2.1-20240409155220-3f779458e453.tm.json"
+	ctx := context.Background()
+
+	t.Run("non existent tm name", func(t *testing.T) {
+		err := r.UpdateLabels(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), []string{"beta"}, nil)
+		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
+	})
+	t.Run("label name and version", func(t *testing.T) {
+		err := r.UpdateLabels(ctx, model.NewTMNameAttachmentContainerRef(tmName), []string{"certified"}, nil)
+		assert.NoError(t, err)
+		err = r.UpdateLabels(ctx, model.NewTMIDAttachmentContainerRef(id), []string{"beta"}, nil)
+		assert.NoError(t, err)
+
+		res, err := r.List(ctx, &model.Filters{Label: []string{"certified"}})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Entries, 1) {
+			assert.Equal(t, []string{"certified"}, res.Entries[0].Labels)
+			assert.Equal(t, []string{"beta"}, res.Entries[0].Versions[0].Labels)
+		}
+	})
+	t.Run("survives full reindex", func(t *testing.T) {
+		err := r.Index(ctx)
+		assert.NoError(t, err)
+		idx, err := r.readIndex()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"certified"}, idx.FindByName(tmName).Labels)
+		assert.Equal(t, []string{"beta"}, idx.FindByTMID(id).Labels)
+	})
+	t.Run("remove", func(t *testing.T) {
+		err := r.UpdateLabels(ctx, model.NewTMNameAttachmentContainerRef(tmName), nil, []string{"certified"})
+		assert.NoError(t, err)
+		res, err := r.List(ctx, &model.Filters{Label: []string{"certified"}})
+		assert.NoError(t, err)
+		assert.Len(t, res.Entries, 0)
+	})
+}
+
+func TestFileRepo_Move(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "fr")
+	defer os.RemoveAll(temp)
+	r := &FileRepo{
+		root: temp,
+		spec: model.NewRepoSpec("fr"),
+	}
+	assert.NoError(t, testutils.CopyDir("../../test/data/repos/file/attachments", temp))
+	oldName := "omnicorp-tm-department/omnicorp/omnilamp"
+	newName := "omnicorp-tm-department/megacorp/omnilamp/legacy"
+	oldId := oldName + "/v3.2.1-20240409155220-3f779458e453.tm.json"
+	newId := newName + "/v3.2.1-20240409155220-a1b2c3d4e5f6.tm.json"
+	opts := MoveOptions{
+		Names:  model.DisplayNames{Manufacturer: "MegaCorp"},
+		Digest: func(raw []byte) (string, error) { return "a1b2c3d4e5f6", nil },
+	}
+	ctx := context.Background()
+	_, oldRaw, _ := r.Fetch(ctx, oldId)
+
+	t.Run("non existent tm name", func(t *testing.T) {
+		err := r.Move(ctx, "omnicorp-tm-department/omnicorp/omnidarkness", newName, opts)
+		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
+	})
+	t.Run("failing move is rolled back", func(t *testing.T) {
+		// given: a directory blocking the attachments of the version from being moved to the new digest
+		blocker := filepath.Join(temp, oldName, ".attachments", "v3.2.1-20240409155220-a1b2c3d4e5f6", "blocker")
+		assert.NoError(t, os.MkdirAll(blocker, defaultDirPermissions))
+
+		// when: moving the TM name
+		err := r.Move(ctx, oldName, newName, opts)
+		// then: the move fails after the TM file and the attachments directory have been moved
+		assert.Error(t, err)
+		// and then: everything is back at the old name
+		_, raw, err := r.Fetch(ctx, oldId)
+		assert.NoError(t, err)
+		assert.Equal(t, oldRaw, raw)
+		assert.NoDirExists(t, filepath.Join(temp, newName))
+		assert.FileExists(t, filepath.Join(temp, oldName, ".attachments", "README.md"))
+		assert.FileExists(t, filepath.Join(temp, oldName, ".attachments", "v3.2.1-20240409155220-3f779458e453", "cfg.json"))
+		assert.Equal(t, []string{oldName}, r.readNamesFile())
+		_, err = r.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef(oldId), "cfg.json")
+		assert.NoError(t, err)
+
+		assert.NoError(t, os.RemoveAll(filepath.Dir(blocker)))
+	})
+	t.Run("move", func(t *testing.T) {
+		err := r.Move(ctx, oldName, newName, opts)
+		assert.NoError(t, err)
+
+		assert.NoFileExists(t, filepath.Join(temp, oldId))
+		assert.NoDirExists(t, filepath.Join(temp, "omnicorp-tm-department/omnicorp"))
+		assert.FileExists(t, filepath.Join(temp, newName, ".attachments", "README.md"))
+		assert.FileExists(t, filepath.Join(temp, newName, ".attachments", "v3.2.1-20240409155220-a1b2c3d4e5f6", "cfg.json"))
+		assert.NoDirExists(t, filepath.Join(temp, newName, ".attachments", "v3.2.1-20240409155220-3f779458e453"))
+		assert.Equal(t, []string{newName}, r.readNamesFile())
+		_, err = r.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef(newId), "cfg.json")
+		assert.NoError(t, err)
+
+		id, raw, err := r.Fetch(ctx, newId)
+		assert.NoError(t, err)
+		assert.Equal(t, newId, id)
+		tm, err := model.ParseThingModel(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, newId, tm.ID)
+		assert.Equal(t, "MegaCorp", tm.Manufacturer.Name)
+		assert.Equal(t, "omnilamp", tm.Mpn)
+		assert.Equal(t, "omnicorp-tm-department", tm.Author.Name)
+		expRaw := bytes.Replace(oldRaw, []byte(oldId), []byte(newId), 1)
+		expRaw = bytes.Replace(expRaw, []byte(`"schema:name": "omnicorp"`), []byte(`"schema:name": "MegaCorp"`), 1)
+		assert.Equal(t, string(expRaw), string(raw))
+
+		res, err := r.List(ctx, &model.Filters{})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Entries, 1) {
+			assert.Equal(t, newName, res.Entries[0].Name)
+			assert.Equal(t, "MegaCorp", res.Entries[0].Manufacturer.Name)
+			assert.Equal(t, newId, res.Entries[0].Versions[0].TMID)
+			assert.Len(t, res.Entries[0].Attachments, 1)
+			assert.Len(t, res.Entries[0].Versions[0].Attachments, 1)
+		}
+	})
+	t.Run("filter by new manufacturer", func(t *testing.T) {
+		res, err := r.List(ctx, &model.Filters{Manufacturer: []string{"MegaCorp"}})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Entries, 1) {
+			assert.Equal(t, newName, res.Entries[0].Name)
+		}
+		res, err = r.List(ctx, &model.Filters{Manufacturer: []string{"omnicorp"}})
+		assert.NoError(t, err)
+		assert.Len(t, res.Entries, 0)
+	})
+	t.Run("old name and id redirect", func(t *testing.T) {
+		id, _, err := r.Fetch(ctx, oldId)
+		assert.NoError(t, err)
+		assert.Equal(t, newId, id)
+
+		vs, err := r.Versions(ctx, oldName)
+		assert.NoError(t, err)
+		if assert.Len(t, vs, 1) {
+			assert.Equal(t, newId, vs[0].TMID)
+		}
+
+		meta, err := r.GetTMMetadata(ctx, oldId)
+		assert.NoError(t, err)
+		if assert.Len(t, meta, 1) {
+			assert.Equal(t, newId, meta[0].TMID)
+		}
+	})
+	t.Run("alias survives full reindex", func(t *testing.T) {
+		err := r.Index(ctx)
+		assert.NoError(t, err)
+		vs, err := r.Versions(ctx, oldName)
+		assert.NoError(t, err)
+		assert.Len(t, vs, 1)
+	})
+	t.Run("target exists", func(t *testing.T) {
+		_, err := r.Import(ctx, model.MustParseTMID(oldId), oldRaw, ImportOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, r.Index(ctx, oldId))
+		err = r.Move(ctx, oldName, newName, opts)
+		assert.ErrorIs(t, err, model.ErrTMNameExists)
+	})
+}
+
+func TestFileRepo_ValidationFiles(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "fr")
+	defer os.RemoveAll(temp)
+	r := &FileRepo{
+		root: temp,
+		spec: model.NewRepoSpec("fr"),
+	}
+	_ = os.MkdirAll(filepath.Join(temp, ".tmc"), defaultDirPermissions)
+
+	t.Run("no validation dir", func(t *testing.T) {
+		files, err := r.ValidationFiles(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("no root", func(t *testing.T) {
+		r := &FileRepo{root: filepath.Join(temp, "missing"), spec: model.NewRepoSpec("fr")}
+		files, err := r.ValidationFiles(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("with validation files", func(t *testing.T) {
+		vDir := filepath.Join(temp, ".tmc", "validation")
+		_ = os.MkdirAll(filepath.Join(vDir, "subdir"), defaultDirPermissions)
+		_ = os.WriteFile(filepath.Join(vDir, "manufacturer.schema.json"), []byte(`{"type": "object"}`), defaultFilePermissions)
+		_ = os.WriteFile(filepath.Join(vDir, "units.rules.json"), []byte(`{"rules": []}`), defaultFilePermissions)
+
+		files, err := r.ValidationFiles(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{
+			"manufacturer.schema.json": []byte(`{"type": "object"}`),
+			"units.rules.json":         []byte(`{"rules": []}`),
+		}, files)
+	})
+}
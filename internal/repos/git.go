@@ -0,0 +1,496 @@
+package repos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	gitLockFile       = "git.lock"
+	gitLockTimeout    = 30 * time.Second
+	gitPushRetries    = 3
+	gitDefaultBranch  = "main"
+	gitExcludeLocks   = ":(exclude,glob)" + RepoConfDir + "/*.lock"
+	gitRebaseMsgIndex = "Rebuild index after rebasing onto remote changes"
+)
+
+var ErrGitConflict = errors.New("unresolvable conflict with remote changes")
+var gitBinary = "git" // mockable for testing
+
+// GitRepo implements a Repo TM repository backed by a git working tree. The working tree is laid out exactly like
+// a FileRepo. Every modifying operation becomes a commit, which is pushed to the remote, if one is configured.
+type GitRepo struct {
+	*FileRepo
+	remote string
+	branch string
+	// workTreeReady is set once root is known to be a git working tree
+	workTreeReady bool
+}
+
+func NewGitRepo(config ConfigMap, spec model.RepoSpec) (*GitRepo, error) {
+	loc, found := config.GetString(KeyRepoLoc)
+	if !found {
+		return nil, fmt.Errorf("cannot create a git repo from spec %v. Invalid config. loc is either not found or not a string", spec)
+	}
+	rootPath, err := utils.ExpandHome(loc)
+	if err != nil {
+		return nil, err
+	}
+	remote, _ := config.GetString(KeyRepoGitRemote)
+	branch, _ := config.GetString(KeyRepoGitBranch)
+	return &GitRepo{
+		FileRepo: &FileRepo{
+			root: rootPath,
+			spec: spec,
+		},
+		remote: remote,
+		branch: branch,
+	}, nil
+}
+
+func (g *GitRepo) Import(ctx context.Context, id model.TMID, raw []byte, opts ImportOptions) (ImportResult, error) {
+	var res ImportResult
+	err := g.commitChanges(ctx, []string{fmt.Sprintf("Import %s", id)}, func() error {
+		var err error
+		res, err = g.FileRepo.Import(ctx, id, raw, opts)
+		return err
+	})
+	if err != nil && res.IsSuccessful() {
+		return ImportResultFromError(err)
+	}
+	return res, err
+}
+
+func (g *GitRepo) Delete(ctx context.Context, id string) error {
+	return g.commitChanges(ctx, []string{fmt.Sprintf("Delete %s", id)}, func() error {
+		return g.FileRepo.Delete(ctx, id)
+	})
+}
+
+func (g *GitRepo) Index(ctx context.Context, ids ...string) error {
+	msg := []string{"Rebuild index"}
+	if len(ids) > 0 {
+		msg = []string{"Update index", strings.Join(ids, "\n")}
+	}
+	return g.commitChanges(ctx, msg, func() error {
+		return g.FileRepo.Index(ctx, ids...)
+	})
+}
+
+func (g *GitRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
+	msg := []string{fmt.Sprintf("Import attachment %s to %s", attachment.Name, container)}
+	return g.commitChanges(ctx, msg, func() error {
+		return g.FileRepo.ImportAttachment(ctx, container, attachment, content, force, ifMatch)
+	})
+}
+
+func (g *GitRepo) DeleteAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName, ifMatch string) error {
+	msg := []string{fmt.Sprintf("Delete attachment %s from %s", attachmentName, ref)}
+	return g.commitChanges(ctx, msg, func() error {
+		return g.FileRepo.DeleteAttachment(ctx, ref, attachmentName, ifMatch)
+	})
+}
+
+func (g *GitRepo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	msg := []string{fmt.Sprintf("Set lifecycle state of %s to %s", id, lc.State)}
+	if lc.Reason != "" {
+		msg = append(msg, lc.Reason)
+	}
+	return g.commitChanges(ctx, msg, func() error {
+		return g.FileRepo.SetLifecycle(ctx, id, lc)
+	})
+}
+
+func (g *GitRepo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error {
+	msg := []string{fmt.Sprintf("Update labels of %s", ref)}
+	if len(add) > 0 {
+		msg = append(msg, fmt.Sprintf("Add: %s", strings.Join(add, ", ")))
+	}
+	if len(remove) > 0 {
+		msg = append(msg, fmt.Sprintf("Remove: %s", strings.Join(remove, ", ")))
+	}
+	return g.commitChanges(ctx, msg, func() error {
+		return g.FileRepo.UpdateLabels(ctx, ref, add, remove)
+	})
+}
+
+func (g *GitRepo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) error {
+	msg := []string{fmt.Sprintf("Move %s to %s", oldName, newName)}
+	return g.commitChanges(ctx, msg, func() error {
+		return g.FileRepo.Move(ctx, oldName, newName, opts)
+	})
+}
+
+func (g *GitRepo) Fetch(ctx context.Context, id string) (string, []byte, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return "", nil, err
+	}
+	return g.FileRepo.Fetch(ctx, id)
+}
+
+func (g *GitRepo) CheckIntegrity(ctx context.Context, filter model.ResourceFilter) ([]model.CheckResult, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return nil, err
+	}
+	return g.FileRepo.CheckIntegrity(ctx, filter)
+}
+
+func (g *GitRepo) List(ctx context.Context, search *model.Filters) (model.SearchResult, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return model.SearchResult{}, err
+	}
+	return g.FileRepo.List(ctx, search)
+}
+
+func (g *GitRepo) Versions(ctx context.Context, name string) ([]model.FoundVersion, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return nil, err
+	}
+	return g.FileRepo.Versions(ctx, name)
+}
+
+func (g *GitRepo) ListCompletions(ctx context.Context, kind string, args []string, toComplete string) ([]string, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return nil, err
+	}
+	return g.FileRepo.ListCompletions(ctx, kind, args, toComplete)
+}
+
+func (g *GitRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return nil, err
+	}
+	return g.FileRepo.GetTMMetadata(ctx, tmID)
+}
+
+func (g *GitRepo) FetchAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string) ([]byte, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return nil, err
+	}
+	return g.FileRepo.FetchAttachment(ctx, ref, attachmentName)
+}
+
+func (g *GitRepo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	if err := g.ensureClone(ctx); err != nil {
+		return nil, err
+	}
+	return g.FileRepo.ValidationFiles(ctx)
+}
+
+// ensureClone clones the remote before the first read operation, which would otherwise leave lock files in root and
+// prevent cloning into it later. Without a remote, the working tree is initialized by the first modifying operation
+func (g *GitRepo) ensureClone(ctx context.Context) error {
+	if g.remote == "" {
+		return nil
+	}
+	return g.ensureWorkTree(ctx)
+}
+
+// commitChanges runs op on the working tree, commits all changes produced by op with the given message paragraphs
+// and pushes the commit to the remote. If op or the commit fails, the changes are discarded, so that they do not end
+// up in the commit of a later operation
+func (g *GitRepo) commitChanges(ctx context.Context, msg []string, op func() error) error {
+	err := g.ensureWorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	unlock, err := g.lockWorkTree(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+	err = op()
+	if err != nil {
+		g.discardChanges(ctx)
+		return err
+	}
+	committed, err := g.commit(ctx, msg)
+	if err != nil {
+		g.discardChanges(ctx)
+		return err
+	}
+	if !committed || g.remote == "" {
+		return nil
+	}
+	return g.push(ctx)
+}
+
+// discardChanges restores the working tree under root to the last commit, removing all uncommitted changes and
+// untracked files except lock files
+func (g *GitRepo) discardChanges(ctx context.Context) {
+	log := utils.GetLogger(ctx, "GitRepo")
+	if _, err := g.git(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		_, err = g.git(ctx, "reset", "--quiet", "HEAD", "--", ".")
+		if err == nil {
+			_, err = g.git(ctx, "checkout", "--quiet", "HEAD", "--", ".")
+		}
+		if err != nil {
+			log.Error("could not reset working tree", "root", g.root, "error", err)
+		}
+	} else {
+		// nothing has been committed yet
+		_, _ = g.git(ctx, "rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "--", ".")
+	}
+	_, err := g.git(ctx, "clean", "-f", "-d", "--quiet", "--", ".", gitExcludeLocks)
+	if err != nil {
+		log.Error("could not clean working tree", "root", g.root, "error", err)
+	}
+	// the cached index may contain the discarded changes
+	g.FileRepo.idx = nil
+}
+
+// ensureWorkTree makes sure that root is a git working tree. Clones the remote if root does not exist or is an empty
+// directory, or initializes a new git repository if there is no remote
+func (g *GitRepo) ensureWorkTree(ctx context.Context) error {
+	if g.workTreeReady {
+		return nil
+	}
+	if _, err := g.git(ctx, "rev-parse", "--is-inside-work-tree"); err == nil {
+		g.workTreeReady = true
+		return nil
+	}
+	entries, err := os.ReadDir(g.root)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(entries) > 0 && g.remote != "" {
+		return fmt.Errorf("%s: %w: %s is not empty and is not a git working tree", g.Spec(), ErrRootInvalid, g.root)
+	}
+	err = os.MkdirAll(g.root, defaultDirPermissions)
+	if err != nil {
+		return err
+	}
+	if g.remote == "" {
+		_, err = g.git(ctx, "init", "--initial-branch", g.branchName())
+		g.workTreeReady = err == nil
+		return err
+	}
+	args := []string{"clone"}
+	if g.branch != "" {
+		args = append(args, "--branch", g.branch)
+	}
+	_, err = g.git(ctx, append(args, "--", g.remote, ".")...)
+	if err == nil {
+		g.workTreeReady = true
+	}
+	return err
+}
+
+// lockWorkTree prevents concurrent modifications of the working tree by other instances
+// of GitRepo, including those in other processes
+func (g *GitRepo) lockWorkTree(ctx context.Context) (unlockFunc, error) {
+	rd := filepath.Join(g.root, RepoConfDir)
+	err := os.MkdirAll(rd, defaultDirPermissions)
+	if err != nil {
+		return func() {}, fmt.Errorf("couldn't create repo config dir %s: %w", rd, err)
+	}
+	lockFile := filepath.Join(rd, gitLockFile)
+	fl := flock.New(lockFile)
+	ctx, cancel := context.WithTimeout(ctx, gitLockTimeout)
+	unlock := func() {
+		cancel()
+		_ = fl.Unlock()
+	}
+	locked, err := fl.TryLockContext(ctx, indexLocRetryDelay)
+	if err != nil || !locked {
+		return unlock, fmt.Errorf("failed to lock git working tree %s: %w", g.root, err)
+	}
+	return unlock, nil
+}
+
+// commit stages all changes under root and commits them. Returns false if there was nothing to commit
+func (g *GitRepo) commit(ctx context.Context, msg []string) (bool, error) {
+	_, err := g.git(ctx, "add", "--all", "--", ".", gitExcludeLocks)
+	if err != nil {
+		return false, err
+	}
+	if _, err := g.git(ctx, "diff", "--cached", "--quiet", "--", "."); err == nil {
+		return false, nil
+	}
+	args := []string{"commit", "--quiet"}
+	for _, m := range msg {
+		args = append(args, "-m", m)
+	}
+	_, err = g.git(ctx, append(args, "--", ".")...)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// push pushes the current branch to the remote. If the push is rejected, because the remote contains commits that
+// are not present locally, rebases local commits onto the remote branch and retries
+func (g *GitRepo) push(ctx context.Context) error {
+	for i := 0; ; i++ {
+		_, err := g.git(ctx, "push", "--quiet", g.remote, "HEAD:refs/heads/"+g.branchName())
+		if err == nil {
+			return nil
+		}
+		if i >= gitPushRetries {
+			return fmt.Errorf("could not push to %s: %w", g.remote, err)
+		}
+		utils.GetLogger(ctx, "GitRepo").Debug("push rejected, rebasing onto remote changes", "remote", g.remote, "attempt", i+1)
+		err = g.rebase(ctx)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// rebase fetches the remote branch and rebases local commits onto it. Conflicts in the index and other files
+// generated by tmc are resolved by taking the remote's version and rebuilding the index on the merged tree afterward.
+// Conflicts in any other files abort the rebase and result in ErrGitConflict
+func (g *GitRepo) rebase(ctx context.Context) error {
+	_, err := g.git(ctx, "fetch", "--quiet", g.remote, g.branchName())
+	if err != nil {
+		return err
+	}
+	// metadata kept only in the index, such as lifecycle states and labels, cannot be restored from the merged tree,
+	// so the local changes to it are carried over from the index files before rebasing
+	var baseIndex *model.Index
+	if mb, err := g.git(ctx, "merge-base", "HEAD", "FETCH_HEAD"); err == nil {
+		baseIndex = g.indexAt(ctx, strings.TrimSpace(mb))
+	}
+	localIndex := g.indexAt(ctx, "HEAD")
+
+	_, err = g.git(ctx, "rebase", "--quiet", "FETCH_HEAD")
+	for err != nil {
+		rErr := g.resolveRebaseConflicts(ctx)
+		if rErr != nil {
+			_, _ = g.git(ctx, "rebase", "--abort")
+			return rErr
+		}
+		_, err = g.git(ctx, "-c", "core.editor=true", "rebase", "--continue")
+	}
+
+	// the merged tree may contain TMs which are missing from the index taken over from remote
+	g.FileRepo.idx = nil
+	err = g.rebuildIndexAfterRebase(ctx, baseIndex, localIndex)
+	if err != nil {
+		return err
+	}
+	_, err = g.commit(ctx, []string{gitRebaseMsgIndex})
+	return err
+}
+
+// rebuildIndexAfterRebase rebuilds the index from the merged tree, keeping the metadata of the remote's index and
+// applying the local changes to it, which have been made between baseIndex and localIndex
+func (g *GitRepo) rebuildIndexAfterRebase(ctx context.Context, baseIndex, localIndex *model.Index) error {
+	unlock, err := g.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+	_, err = g.updateIndex(ctx, func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		newIndex, names, count, err := g.fullIndexRebuild(ctx, oldIndex, oldNames)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		newIndex.MergeIndexMetadata(baseIndex, localIndex)
+		return newIndex, names, count, nil
+	})
+	return err
+}
+
+// indexAt returns the index committed in revision rev or nil, if there is none
+func (g *GitRepo) indexAt(ctx context.Context, rev string) *model.Index {
+	out, err := g.git(ctx, "show", rev+":"+RepoConfDir+"/"+IndexFilename)
+	if err != nil {
+		return nil
+	}
+	var idx model.Index
+	if err := json.Unmarshal([]byte(out), &idx); err != nil {
+		return nil
+	}
+	return &idx
+}
+
+func (g *GitRepo) resolveRebaseConflicts(ctx context.Context) error {
+	out, err := g.git(ctx, "diff", "--name-only", "--relative", "--diff-filter=U")
+	if err != nil {
+		return err
+	}
+	conflicts := strings.Fields(out)
+	if len(conflicts) == 0 {
+		return fmt.Errorf("%w: rebase onto %s failed", ErrGitConflict, g.remote)
+	}
+	for _, c := range conflicts {
+		if path.Dir(c) != RepoConfDir {
+			return fmt.Errorf("%w: %s", ErrGitConflict, c)
+		}
+	}
+	// during a rebase, 'ours' refers to the upstream branch
+	_, err = g.git(ctx, append([]string{"checkout", "--ours", "--"}, conflicts...)...)
+	if err != nil {
+		return err
+	}
+	_, err = g.git(ctx, append([]string{"add", "--"}, conflicts...)...)
+	return err
+}
+
+func (g *GitRepo) branchName() string {
+	if g.branch != "" {
+		return g.branch
+	}
+	if b, err := g.git(context.Background(), "symbolic-ref", "--short", "HEAD"); err == nil && strings.TrimSpace(b) != "" {
+		return strings.TrimSpace(b)
+	}
+	return gitDefaultBranch
+}
+
+// git runs the git binary with given args in the repository's root directory and returns its standard output
+func (g *GitRepo) git(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, gitBinary, append([]string{"-C", g.root}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	utils.GetLogger(ctx, "GitRepo").Debug("running git", "args", args)
+	err := cmd.Run()
+	if err != nil {
+		return stdout.String(), fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func createGitRepoConfig(bytes []byte) (ConfigMap, error) {
+	rc, err := AsRepoConfig(bytes)
+	if err != nil {
+		return nil, err
+	}
+	if rType, found := utils.JsGetString(rc, KeyRepoType); found {
+		if rType != RepoTypeGit {
+			return nil, fmt.Errorf("invalid json config. type must be \"git\" or absent")
+		}
+	}
+	rc[KeyRepoType] = RepoTypeGit
+	l, found := utils.JsGetString(rc, KeyRepoLoc)
+	if !found {
+		return nil, fmt.Errorf("invalid json config. must have string \"loc\"")
+	}
+	la, err := makeAbs(l)
+	if err != nil {
+		return nil, err
+	}
+	rc[KeyRepoLoc] = la
+	for _, k := range []string{KeyRepoGitRemote, KeyRepoGitBranch} {
+		if v, ok := rc[k]; ok {
+			if _, ok := v.(string); !ok {
+				return nil, fmt.Errorf("invalid json config. %q must be a string", k)
+			}
+		}
+	}
+	return rc, nil
+}
@@ -0,0 +1,296 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+const (
+	gitTestId1 = "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"
+	gitTestId2 = "omnicorp-tm-department/omnicorp/omnilamp/v0.0.0-20240409155220-e414b33a9edf.tm.json"
+)
+
+func setupGitTest(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath(gitBinary); err != nil {
+		t.Skip("git binary not available")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "tmc test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "tmc@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "tmc test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "tmc@example.com")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+}
+
+func newBareRemote(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	out, err := exec.Command(gitBinary, "init", "--bare", "--initial-branch", "main", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return dir
+}
+
+func gitLog(t *testing.T, dir string, args ...string) []string {
+	t.Helper()
+	out, err := exec.Command(gitBinary, append([]string{"-C", dir, "log", "--format=%s"}, args...)...).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
+
+func importTestTM(t *testing.T, r Repo, id string) {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("../../test/data/index", id))
+	require.NoError(t, err)
+	res, err := r.Import(context.Background(), model.MustParseTMID(id), raw, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, ImportResultOK, res.Type)
+	require.NoError(t, r.Index(context.Background(), id))
+}
+
+func TestCreateGitRepoConfig(t *testing.T) {
+	rc, err := createGitRepoConfig([]byte(`{"loc":"/tmp/catalog","remote":"https://example.com/catalog.git","branch":"tms"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, ConfigMap{
+		KeyRepoType:      RepoTypeGit,
+		KeyRepoLoc:       filepath.Clean("/tmp/catalog"),
+		KeyRepoGitRemote: "https://example.com/catalog.git",
+		KeyRepoGitBranch: "tms",
+	}, rc)
+
+	_, err = createGitRepoConfig([]byte(`{"type":"file","loc":"/tmp/catalog"}`))
+	assert.Error(t, err)
+	_, err = createGitRepoConfig([]byte(`{"remote":"https://example.com/catalog.git"}`))
+	assert.Error(t, err)
+	_, err = createGitRepoConfig([]byte(`{"loc":"/tmp/catalog","branch":1}`))
+	assert.Error(t, err)
+}
+
+func TestGitRepo_LocalOnly(t *testing.T) {
+	setupGitTest(t)
+	root := filepath.Join(t.TempDir(), "catalog")
+	r, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root}, model.NewRepoSpec("git"))
+	require.NoError(t, err)
+
+	importTestTM(t, r, gitTestId1)
+	assert.Equal(t, []string{"Update index", "Import " + gitTestId1}, gitLog(t, root))
+
+	_, raw, err := r.Fetch(context.Background(), gitTestId1)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, raw)
+
+	ref := model.NewTMIDAttachmentContainerRef(gitTestId1)
+	err = r.ImportAttachment(context.Background(), ref, model.Attachment{Name: "README.md"}, []byte("# readme"), false, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Import attachment README.md to "+ref.String(), gitLog(t, root, "-1")[0])
+
+	err = r.DeleteAttachment(context.Background(), ref, "README.md", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Delete attachment README.md from "+ref.String(), gitLog(t, root, "-1")[0])
+
+	err = r.Delete(context.Background(), gitTestId1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Delete "+gitTestId1, gitLog(t, root, "-1")[0])
+
+	out, err := exec.Command(gitBinary, "-C", root, "status", "--porcelain", "--untracked-files=all").CombinedOutput()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(out), ".tmc/tm-catalog.toc.json\n")
+	assert.NotContains(t, string(out), ".tm.json")
+}
+
+func TestGitRepo_PushesToRemote(t *testing.T) {
+	setupGitTest(t)
+	remote := newBareRemote(t)
+	root := filepath.Join(t.TempDir(), "catalog")
+	r, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root, KeyRepoGitRemote: remote}, model.NewRepoSpec("git"))
+	require.NoError(t, err)
+
+	importTestTM(t, r, gitTestId1)
+	assert.Equal(t, []string{"Update index", "Import " + gitTestId1}, gitLog(t, remote, "main"))
+
+	t.Run("non-empty directory which is not a working tree", func(t *testing.T) {
+		root := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "file.txt"), []byte("text"), defaultFilePermissions))
+		r, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root, KeyRepoGitRemote: remote}, model.NewRepoSpec("git"))
+		require.NoError(t, err)
+		err = r.Index(context.Background())
+		assert.ErrorIs(t, err, ErrRootInvalid)
+	})
+}
+
+func TestGitRepo_ResolvesPushConflicts(t *testing.T) {
+	setupGitTest(t)
+	remote := newBareRemote(t)
+	root1 := filepath.Join(t.TempDir(), "catalog1")
+	root2 := filepath.Join(t.TempDir(), "catalog2")
+	r1, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root1, KeyRepoGitRemote: remote}, model.NewRepoSpec("git1"))
+	require.NoError(t, err)
+	r2, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root2, KeyRepoGitRemote: remote}, model.NewRepoSpec("git2"))
+	require.NoError(t, err)
+
+	// both clones start from the same state, then diverge with conflicting index changes
+	require.NoError(t, r1.Index(context.Background()))
+	require.NoError(t, r2.Index(context.Background()))
+	importTestTM(t, r1, gitTestId1)
+	importTestTM(t, r2, gitTestId2)
+
+	log := gitLog(t, remote, "main")
+	assert.Contains(t, log, gitRebaseMsgIndex)
+	assert.Contains(t, log, "Import "+gitTestId1)
+	assert.Contains(t, log, "Import "+gitTestId2)
+
+	vs, err := r2.Versions(context.Background(), "omnicorp-tm-department/omnicorp/omnilamp")
+	assert.NoError(t, err)
+	assert.Len(t, vs, 2)
+
+	t.Run("conflicting TM files", func(t *testing.T) {
+		tmDir := filepath.Join(root1, filepath.Dir(gitTestId1))
+		require.NoError(t, os.WriteFile(filepath.Join(tmDir, "notes.txt"), []byte("one"), defaultFilePermissions))
+		require.NoError(t, r1.Index(context.Background()))
+		require.NoError(t, os.WriteFile(filepath.Join(root2, filepath.Dir(gitTestId1), "notes.txt"), []byte("two"), defaultFilePermissions))
+		err := r2.Index(context.Background())
+		assert.ErrorIs(t, err, ErrGitConflict)
+		_, err = os.Stat(filepath.Join(root2, ".git", "rebase-merge"))
+		assert.True(t, os.IsNotExist(err), "rebase must have been aborted")
+	})
+}
+
+func TestGitRepo_ClonesOnFirstRead(t *testing.T) {
+	setupGitTest(t)
+	remote := newBareRemote(t)
+	root1 := filepath.Join(t.TempDir(), "catalog1")
+	r1, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root1, KeyRepoGitRemote: remote}, model.NewRepoSpec("git1"))
+	require.NoError(t, err)
+	importTestTM(t, r1, gitTestId1)
+
+	root2 := t.TempDir()
+	r2, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root2, KeyRepoGitRemote: remote}, model.NewRepoSpec("git2"))
+	require.NoError(t, err)
+	// a read, as done by import before importing, must not leave files behind, which prevent the clone
+	vs, err := r2.Versions(context.Background(), "omnicorp-tm-department/omnicorp/omnilamp")
+	assert.NoError(t, err)
+	assert.Len(t, vs, 1)
+	importTestTM(t, r2, gitTestId2)
+	assert.Contains(t, gitLog(t, remote, "main"), "Import "+gitTestId2)
+}
+
+func TestGitRepo_KeepsIndexMetadataOnPushConflicts(t *testing.T) {
+	setupGitTest(t)
+	ctx := context.Background()
+	remote := newBareRemote(t)
+	const tmName = "omnicorp-tm-department/omnicorp/omnilamp"
+	r1 := newGitTestClone(t, remote, "git1")
+	importTestTM(t, r1, gitTestId1)
+	require.NoError(t, r1.UpdateLabels(ctx, model.NewTMNameAttachmentContainerRef(tmName), []string{"old"}, nil))
+	r2 := newGitTestClone(t, remote, "git2")
+	_, err := r2.List(ctx, nil)
+	require.NoError(t, err)
+
+	// clone 1 pushes a new TM and changes metadata, while clone 2 changes other metadata of its outdated index
+	importTestTM(t, r1, gitTestId2)
+	require.NoError(t, r1.UpdateLabels(ctx, model.NewTMIDAttachmentContainerRef(gitTestId2), []string{"remote"}, nil))
+	require.NoError(t, r2.UpdateLabels(ctx, model.NewTMNameAttachmentContainerRef(tmName), []string{"local"}, []string{"old"}))
+	require.NoError(t, r2.UpdateLabels(ctx, model.NewTMIDAttachmentContainerRef(gitTestId1), []string{"local"}, nil))
+	assert.Contains(t, gitLog(t, remote, "main"), gitRebaseMsgIndex)
+
+	for _, r := range []*GitRepo{r2, newGitTestClone(t, remote, "git3")} {
+		res, err := r.List(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Entries, 1)
+		assert.Equal(t, []string{"local"}, res.Entries[0].Labels)
+		vs, err := r.Versions(ctx, tmName)
+		require.NoError(t, err)
+		require.Len(t, vs, 2)
+		for _, v := range vs {
+			switch v.TMID {
+			case gitTestId1:
+				assert.Equal(t, []string{"local"}, v.Labels)
+			case gitTestId2:
+				assert.Equal(t, []string{"remote"}, v.Labels)
+			}
+		}
+	}
+}
+
+func newGitTestClone(t *testing.T, remote, name string) *GitRepo {
+	t.Helper()
+	r, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: filepath.Join(t.TempDir(), name), KeyRepoGitRemote: remote}, model.NewRepoSpec(name))
+	require.NoError(t, err)
+	return r
+}
+
+func TestGitRepo_SetLifecycleWithConcurrentPush(t *testing.T) {
+	setupGitTest(t)
+	ctx := context.Background()
+	remote := newBareRemote(t)
+	r1 := newGitTestClone(t, remote, "git1")
+	importTestTM(t, r1, gitTestId1)
+	r2 := newGitTestClone(t, remote, "git2")
+	_, err := r2.List(ctx, nil)
+	require.NoError(t, err)
+
+	// clone 1 pushes a new TM, while clone 2 yanks and deprecates versions in its outdated index
+	importTestTM(t, r1, gitTestId2)
+	yanked := model.Lifecycle{State: model.LifecycleYanked, Reason: "broken"}
+	require.NoError(t, r2.SetLifecycle(ctx, gitTestId1, yanked))
+	assert.Contains(t, gitLog(t, remote, "main"), gitRebaseMsgIndex)
+	deprecated := model.Lifecycle{State: model.LifecycleDeprecated, Successor: gitTestId1}
+	require.NoError(t, r1.SetLifecycle(ctx, gitTestId2, deprecated))
+
+	for _, r := range []*GitRepo{r1, newGitTestClone(t, remote, "git3")} {
+		vs, err := r.Versions(ctx, "omnicorp-tm-department/omnicorp/omnilamp")
+		require.NoError(t, err)
+		require.Len(t, vs, 2)
+		for _, v := range vs {
+			switch v.TMID {
+			case gitTestId1:
+				assert.Equal(t, &yanked, v.Lifecycle)
+			case gitTestId2:
+				assert.Equal(t, &deprecated, v.Lifecycle)
+			}
+		}
+	}
+}
+
+func TestGitRepo_DiscardsChangesOfFailedOperations(t *testing.T) {
+	setupGitTest(t)
+	root := filepath.Join(t.TempDir(), "catalog")
+	r, err := NewGitRepo(ConfigMap{KeyRepoType: RepoTypeGit, KeyRepoLoc: root}, model.NewRepoSpec("git"))
+	require.NoError(t, err)
+	importTestTM(t, r, gitTestId1)
+	ctx := context.Background()
+
+	// when: an operation fails after changing a tracked file and adding an untracked one
+	err = r.commitChanges(ctx, []string{"Failing operation"}, func() error {
+		require.NoError(t, os.WriteFile(filepath.Join(root, gitTestId1), []byte("{}"), defaultFilePermissions))
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "half", "written"), defaultDirPermissions))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "half", "written", "file.tm.json"), []byte("{}"), defaultFilePermissions))
+		return errors.New("operation failed")
+	})
+	assert.Error(t, err)
+
+	// then: the working tree is back at the last commit
+	out, err := exec.Command(gitBinary, "-C", root, "status", "--porcelain", "--untracked-files=all", "--", ".", gitExcludeLocks).CombinedOutput()
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(string(out)))
+	assert.NoDirExists(t, filepath.Join(root, "half"))
+	assert.FileExists(t, filepath.Join(root, RepoConfDir, gitLockFile))
+	_, raw, err := r.Fetch(ctx, gitTestId1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "{}", string(raw))
+
+	// and: the next operation commits only its own changes
+	importTestTM(t, r, gitTestId2)
+	out, err = exec.Command(gitBinary, "-C", root, "show", "--name-only", "--format=", "HEAD~1").CombinedOutput()
+	assert.NoError(t, err)
+	assert.Equal(t, gitTestId2, strings.TrimSpace(string(out)))
+}
@@ -157,14 +157,31 @@ func (h *HttpRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.Foun
 	return []model.FoundVersion{fv}, nil
 }
 
-func (h *HttpRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool) error {
+func (h *HttpRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
 	return ErrNotSupported
 }
 
-func (h *HttpRepo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) error {
+func (h *HttpRepo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName, ifMatch string) error {
 	return ErrNotSupported
 }
 
+func (h *HttpRepo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	return ErrNotSupported
+}
+
+func (h *HttpRepo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error {
+	return ErrNotSupported
+}
+
+func (h *HttpRepo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) error {
+	return ErrNotSupported
+}
+
+func (h *HttpRepo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	// HTTP repositories are read-only, so there is nothing to validate
+	return map[string][]byte{}, nil
+}
+
 func (h *HttpRepo) FetchAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) ([]byte, error) {
 	attDir, err := model.RelAttachmentsDir(container)
 	if err != nil {
@@ -178,7 +195,7 @@ func (h *HttpRepo) ListCompletions(ctx context.Context, kind string, args []stri
 	switch kind {
 	case CompletionKindNames:
 		namePrefix, seg := longestPath(toComplete)
-		sr, err := h.List(ctx, model.ToFilters(nil, nil, nil, nil, &namePrefix, nil,
+		sr, err := h.List(ctx, model.ToFilters(nil, nil, nil, nil, nil, &namePrefix, nil,
 			&model.FilterOptions{NameFilterType: model.PrefixMatch}))
 		if err != nil {
 			return nil, err
@@ -206,7 +223,7 @@ func (h *HttpRepo) ListCompletions(ctx context.Context, kind string, args []stri
 		return vs, nil
 	case CompletionKindNamesOrIds:
 		namePrefix, seg := longestPath(toComplete)
-		sr, err := h.List(ctx, model.ToFilters(nil, nil, nil, nil, &namePrefix, nil,
+		sr, err := h.List(ctx, model.ToFilters(nil, nil, nil, nil, nil, &namePrefix, nil,
 			&model.FilterOptions{NameFilterType: model.PrefixMatch}))
 		if err != nil {
 			return nil, err
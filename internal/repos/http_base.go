@@ -18,7 +18,13 @@ import (
 	"github.com/wot-oss/tmc/internal/app/http/server"
 	"github.com/wot-oss/tmc/internal/config"
 	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/tracing"
 	"github.com/wot-oss/tmc/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var httpTransport http.RoundTripper
@@ -108,7 +114,25 @@ func (b *baseHttpRepo) doGet(ctx context.Context, reqUrl string) (*http.Response
 	return b.doHttp(req)
 }
 
-func (b *baseHttpRepo) doHttp(req *http.Request) (*http.Response, error) {
+func (b *baseHttpRepo) doHttp(req *http.Request) (resp *http.Response, err error) {
+	// propagate the trace context to the remote, so that its spans become children of the span of this request
+	ctx, span := tracing.Start(req.Context(), "HTTP "+req.Method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+		))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, resp.Status)
+			}
+		}
+		tracing.End(span, err)
+	}()
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
 	if b.auth != nil {
 		basicAuth, found := utils.JsGetMap(b.auth, AuthMethodBasic)
 		if found {
@@ -134,7 +158,7 @@ func (b *baseHttpRepo) doHttp(req *http.Request) (*http.Response, error) {
 		}
 	}
 
-	resp, err := b.client.Do(req)
+	resp, err = b.client.Do(req)
 	if err != nil {
 		utils.GetLogger(req.Context(), "baseHttpRepo").Error(err.Error())
 	}
@@ -0,0 +1,189 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/wot-oss/tmc/internal/metrics"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	metricsOpFetch  = "fetch"
+	metricsOpImport = "import"
+	metricsOpList   = "list"
+
+	indexMetricsTimeout = 10 * time.Second
+)
+
+var (
+	repoOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "repo",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of fetch, import and list operations per repository.",
+	}, []string{"repo", "operation"})
+	repoOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "repo",
+		Name:      "operation_errors_total",
+		Help:      "Number of failed fetch, import and list operations per repository.",
+	}, []string{"repo", "operation"})
+	repoAccessErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "repo",
+		Name:      "access_errors_total",
+		Help:      "Number of repository errors encountered while reading from a union of repositories.",
+	}, []string{"repo"})
+
+	metricsEnabled      atomic.Bool
+	registerMetricsOnce sync.Once
+)
+
+// EnableMetrics makes Get and All return repos which record the duration and errors of fetch, import and list operations,
+// and registers the collectors of repository metrics for the repos given by spec with metrics.Registry
+func EnableMetrics(spec model.RepoSpec) {
+	registerMetricsOnce.Do(func() {
+		metrics.MustRegister(repoOperationDuration, repoOperationErrors, repoAccessErrors, newIndexCollector(spec))
+	})
+	metricsEnabled.Store(true)
+}
+
+// metricsRepoLabel returns the value of the 'repo' label of metrics about the repo given by spec
+func metricsRepoLabel(spec model.RepoSpec) string {
+	if spec.Dir() != "" {
+		return spec.Dir()
+	}
+	return spec.RepoName()
+}
+
+// MeasuringRepo is a Repo which records the duration and the errors of fetch, import and list operations
+type MeasuringRepo struct {
+	Repo
+}
+
+// withMetrics wraps r into a MeasuringRepo, if metrics have been enabled. Returns r otherwise
+func withMetrics(r Repo) Repo {
+	if !metricsEnabled.Load() {
+		return r
+	}
+	return &MeasuringRepo{Repo: r}
+}
+
+func (m *MeasuringRepo) observe(op string, start time.Time, err error) {
+	repo := metricsRepoLabel(m.Spec())
+	repoOperationDuration.WithLabelValues(repo, op).Observe(time.Since(start).Seconds())
+	if err != nil {
+		repoOperationErrors.WithLabelValues(repo, op).Inc()
+	}
+}
+
+func (m *MeasuringRepo) Fetch(ctx context.Context, id string) (string, []byte, error) {
+	start := time.Now()
+	fid, raw, err := m.Repo.Fetch(ctx, id)
+	m.observe(metricsOpFetch, start, err)
+	return fid, raw, err
+}
+
+func (m *MeasuringRepo) Import(ctx context.Context, id model.TMID, raw []byte, opts ImportOptions) (ImportResult, error) {
+	start := time.Now()
+	res, err := m.Repo.Import(ctx, id, raw, opts)
+	m.observe(metricsOpImport, start, err)
+	return res, err
+}
+
+func (m *MeasuringRepo) List(ctx context.Context, search *model.Filters) (model.SearchResult, error) {
+	start := time.Now()
+	res, err := m.Repo.List(ctx, search)
+	m.observe(metricsOpList, start, err)
+	return res, err
+}
+
+// Unwrap returns the repo, which does not record metrics
+func (m *MeasuringRepo) Unwrap() Repo {
+	return m.Repo
+}
+
+// indexCollector reports the size and the age of the indexes of repos at the time of collection
+type indexCollector struct {
+	spec           model.RepoSpec
+	entries        *prometheus.Desc
+	versions       *prometheus.Desc
+	age            *prometheus.Desc
+	searchIndexAge *prometheus.Desc
+}
+
+func newIndexCollector(spec model.RepoSpec) *indexCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "repo", name), help, []string{"repo"}, nil)
+	}
+	return &indexCollector{
+		spec:           spec,
+		entries:        desc("index_entries", "Number of TM names in the repository's index."),
+		versions:       desc("index_versions", "Number of TM versions in the repository's index."),
+		age:            desc("index_age_seconds", "Time since the repository's index has been last updated."),
+		searchIndexAge: desc("search_index_age_seconds", "Time since the local full-text search index of the repository has been last updated."),
+	}
+}
+
+func (c *indexCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entries
+	ch <- c.versions
+	ch <- c.age
+	ch <- c.searchIndexAge
+}
+
+func (c *indexCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), indexMetricsTimeout)
+	defer cancel()
+	log := utils.GetLogger(ctx, "repos.indexCollector")
+	u, err := GetUnion(c.spec)
+	if err != nil {
+		log.Warn("could not collect index metrics", "error", err)
+		return
+	}
+	for _, r := range u.rs {
+		repo := metricsRepoLabel(r.Spec())
+		if fi, err := os.Stat(filepath.Join(BleveIndexPath(r), "updated")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.searchIndexAge, prometheus.GaugeValue, time.Since(fi.ModTime()).Seconds(), repo)
+		}
+		idxRepo := indexedRepo(r)
+		if idxRepo == nil {
+			continue
+		}
+		res, err := idxRepo.List(ctx, nil)
+		if err != nil {
+			log.Warn("could not read index", "repo", repo, "error", err)
+			continue
+		}
+		versions := 0
+		for _, e := range res.Entries {
+			versions += len(e.Versions)
+		}
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(len(res.Entries)), repo)
+		ch <- prometheus.MustNewConstMetric(c.versions, prometheus.GaugeValue, float64(versions), repo)
+		if !res.LastUpdated.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.age, prometheus.GaugeValue, time.Since(res.LastUpdated).Seconds(), repo)
+		}
+	}
+}
+
+// indexedRepo returns the repo which maintains the index of r, or nil if r is a remote repo, which is indexed by the
+// server it is accessed through
+func indexedRepo(r Repo) Repo {
+	switch ur := Unwrap(r).(type) {
+	case *FileRepo, *GitRepo, *S3Repo:
+		return ur
+	case *CacheRepo:
+		// avoid refreshing the cached index from upstream
+		return ur.local
+	default:
+		return nil
+	}
+}
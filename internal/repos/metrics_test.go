@@ -0,0 +1,88 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/config"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/testutils"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+func TestMeasuringRepo(t *testing.T) {
+	r := &FileRepo{root: t.TempDir(), spec: model.NewRepoSpec("measured")}
+
+	t.Run("metrics disabled", func(t *testing.T) {
+		assert.Equal(t, r, withMetrics(r))
+	})
+
+	metricsEnabled.Store(true)
+	defer metricsEnabled.Store(false)
+
+	res := withMetrics(r)
+	if assert.IsType(t, &MeasuringRepo{}, res) {
+		assert.Equal(t, r, Unwrap(res))
+		assert.Equal(t, r, Unwrap(&VerifyingRepo{Repo: res}))
+	}
+
+	t.Run("counts errors", func(t *testing.T) {
+		before := testutil.ToFloat64(repoOperationErrors.WithLabelValues("measured", metricsOpFetch))
+		_, _, err := res.Fetch(context.Background(), "author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json")
+		assert.Error(t, err)
+		assert.Equal(t, before+1, testutil.ToFloat64(repoOperationErrors.WithLabelValues("measured", metricsOpFetch)))
+		assert.Equal(t, 0.0, testutil.ToFloat64(repoOperationErrors.WithLabelValues("measured", metricsOpList)))
+	})
+}
+
+func TestUnion_CountsRepoAccessErrors(t *testing.T) {
+	r := &FileRepo{root: t.TempDir(), spec: model.NewRepoSpec("unindexed")}
+	before := testutil.ToFloat64(repoAccessErrors.WithLabelValues("unindexed"))
+
+	_, errs := NewUnion(r).List(context.Background(), nil)
+
+	assert.Len(t, errs, 1)
+	assert.Equal(t, before+1, testutil.ToFloat64(repoAccessErrors.WithLabelValues("unindexed")))
+}
+
+func TestIndexCollector(t *testing.T) {
+	tempDir := t.TempDir()
+	old := config.ConfigDir
+	config.ConfigDir = filepath.Join(tempDir, "config")
+	defer func() { config.ConfigDir = old }()
+	repoRoot := filepath.Join(tempDir, "repo")
+	assert.NoError(t, testutils.CopyDir("../../test/data/repos/file/attachments", repoRoot))
+
+	c := newIndexCollector(model.NewDirSpec(repoRoot))
+
+	t.Run("without search index", func(t *testing.T) {
+		exp := fmt.Sprintf(`
+# HELP tmc_repo_index_entries Number of TM names in the repository's index.
+# TYPE tmc_repo_index_entries gauge
+tmc_repo_index_entries{repo="%[1]s"} 1
+# HELP tmc_repo_index_versions Number of TM versions in the repository's index.
+# TYPE tmc_repo_index_versions gauge
+tmc_repo_index_versions{repo="%[1]s"} 1
+`, repoRoot)
+		assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(exp), "tmc_repo_index_entries", "tmc_repo_index_versions"))
+		assert.Equal(t, 3, testutil.CollectAndCount(c))
+	})
+	t.Run("with search index", func(t *testing.T) {
+		indexPath := BleveIndexPath(&FileRepo{root: repoRoot})
+		assert.NoError(t, os.MkdirAll(indexPath, 0755))
+		assert.NoError(t, utils.WriteFileLines([]string{"2024-05-03T15:20:26Z"}, filepath.Join(indexPath, "updated"), 0664))
+
+		assert.Equal(t, 1, testutil.CollectAndCount(c, "tmc_repo_search_index_age_seconds"))
+		assert.Equal(t, 4, testutil.CollectAndCount(c))
+	})
+	t.Run("remote repo", func(t *testing.T) {
+		assert.Nil(t, indexedRepo(&TmcRepo{}))
+		assert.Nil(t, indexedRepo(&HttpRepo{}))
+	})
+}
@@ -82,17 +82,17 @@ func (_m *Repo) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
-// DeleteAttachment provides a mock function with given fields: ctx, container, attachmentName
-func (_m *Repo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) error {
-	ret := _m.Called(ctx, container, attachmentName)
+// DeleteAttachment provides a mock function with given fields: ctx, container, attachmentName, ifMatch
+func (_m *Repo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string, ifMatch string) error {
+	ret := _m.Called(ctx, container, attachmentName, ifMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteAttachment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.AttachmentContainerRef, string) error); ok {
-		r0 = rf(ctx, container, attachmentName)
+	if rf, ok := ret.Get(0).(func(context.Context, model.AttachmentContainerRef, string, string) error); ok {
+		r0 = rf(ctx, container, attachmentName, ifMatch)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -225,17 +225,17 @@ func (_m *Repo) Import(ctx context.Context, id model.TMID, raw []byte, opts repo
 	return r0, r1
 }
 
-// ImportAttachment provides a mock function with given fields: ctx, container, attachment, content, force
-func (_m *Repo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool) error {
-	ret := _m.Called(ctx, container, attachment, content, force)
+// ImportAttachment provides a mock function with given fields: ctx, container, attachment, content, force, ifMatch
+func (_m *Repo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
+	ret := _m.Called(ctx, container, attachment, content, force, ifMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ImportAttachment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.AttachmentContainerRef, model.Attachment, []byte, bool) error); ok {
-		r0 = rf(ctx, container, attachment, content, force)
+	if rf, ok := ret.Get(0).(func(context.Context, model.AttachmentContainerRef, model.Attachment, []byte, bool, string) error); ok {
+		r0 = rf(ctx, container, attachment, content, force, ifMatch)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -326,6 +326,42 @@ func (_m *Repo) ListCompletions(ctx context.Context, kind string, args []string,
 	return r0, r1
 }
 
+// Move provides a mock function with given fields: ctx, oldName, newName, opts
+func (_m *Repo) Move(ctx context.Context, oldName string, newName string, opts repos.MoveOptions) error {
+	ret := _m.Called(ctx, oldName, newName, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, repos.MoveOptions) error); ok {
+		r0 = rf(ctx, oldName, newName, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLifecycle provides a mock function with given fields: ctx, id, lc
+func (_m *Repo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	ret := _m.Called(ctx, id, lc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLifecycle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Lifecycle) error); ok {
+		r0 = rf(ctx, id, lc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Spec provides a mock function with no fields
 func (_m *Repo) Spec() model.RepoSpec {
 	ret := _m.Called()
@@ -344,6 +380,54 @@ func (_m *Repo) Spec() model.RepoSpec {
 	return r0
 }
 
+// UpdateLabels provides a mock function with given fields: ctx, ref, add, remove
+func (_m *Repo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add []string, remove []string) error {
+	ret := _m.Called(ctx, ref, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLabels")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.AttachmentContainerRef, []string, []string) error); ok {
+		r0 = rf(ctx, ref, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidationFiles provides a mock function with given fields: ctx
+func (_m *Repo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidationFiles")
+	}
+
+	var r0 map[string][]byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string][]byte, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string][]byte); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Versions provides a mock function with given fields: ctx, name
 func (_m *Repo) Versions(ctx context.Context, name string) ([]model.FoundVersion, error) {
 	ret := _m.Called(ctx, name)
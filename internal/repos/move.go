@@ -0,0 +1,130 @@
+package repos
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/buger/jsonparser"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+// MoveOptions determine how the TMs are rewritten when their TM name is moved
+type MoveOptions struct {
+	// Names are the display names of author, manufacturer and mpn after the move. The non-empty ones replace
+	// 'schema:author', 'schema:manufacturer' and 'schema:mpn' in the TMs and the display names in the index
+	Names model.DisplayNames
+	// Digest calculates the digest of a rewritten TM for its id. If nil, the digests in the ids are kept
+	Digest func(raw []byte) (string, error)
+}
+
+// movedTMID returns the id the TM version with given id has after its TM name has been moved to newName, when the
+// content of the TM has not been changed by the move
+func movedTMID(id, newName string) string {
+	return newName + "/" + path.Base(id)
+}
+
+// isVersionOf reports whether id refers to the TM version with versionID, which belongs to the name id's TM name has
+// been moved to, if it has been moved. Moving may have changed the digest in the id along with the content, but keeps
+// the version and timestamp
+func isVersionOf(id, versionID string) bool {
+	if versionID == movedTMID(id, path.Dir(versionID)) {
+		return true
+	}
+	if path.Dir(id) == path.Dir(versionID) {
+		return false
+	}
+	tmid, err := model.ParseTMID(id)
+	if err != nil {
+		return false
+	}
+	vid, err := model.ParseTMID(versionID)
+	if err != nil {
+		return false
+	}
+	return tmid.Version.BaseString() == vid.Version.BaseString() && tmid.Version.Timestamp == vid.Version.Timestamp
+}
+
+// movedTM rewrites the TM file raw with given id according to opts for moving it to newName. Returns the id and
+// the content of the TM after the move
+func movedTM(raw []byte, id, newName string, opts MoveOptions) (string, []byte, error) {
+	raw, err := withDisplayNames(raw, opts.Names)
+	if err != nil {
+		return "", nil, err
+	}
+	newId := movedTMID(id, newName)
+	if opts.Digest != nil {
+		digest, err := opts.Digest(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("could not calculate digest of %s: %w", newId, err)
+		}
+		tmid, err := model.ParseTMID(newId)
+		if err != nil {
+			return "", nil, err
+		}
+		tmid.Version.Hash = digest
+		newId = tmid.String()
+	}
+	raw, err = withTMID(raw, newId)
+	if err != nil {
+		return "", nil, err
+	}
+	return newId, raw, nil
+}
+
+// movedVersionAttachmentDirs returns the attachment directories of TM versions whose ids have been changed beyond the
+// TM name by moving it to newName, mapped to the directories they have to be renamed to. The directories are relative
+// to the repository root and located below the attachments directory of newName
+func movedVersionAttachmentDirs(newName string, newIds map[string]string) map[string]string {
+	res := make(map[string]string)
+	for oldId, newId := range newIds {
+		movedId := movedTMID(oldId, newName)
+		if movedId == newId {
+			continue
+		}
+		from, err := model.RelAttachmentsDir(model.NewTMIDAttachmentContainerRef(movedId))
+		if err != nil {
+			continue
+		}
+		to, err := model.RelAttachmentsDir(model.NewTMIDAttachmentContainerRef(newId))
+		if err != nil {
+			continue
+		}
+		res[from] = to
+	}
+	return res
+}
+
+// withTMID replaces the 'id' of the TM file raw with id. The digest of the file does not change by doing so,
+// because the 'id' is blanked when calculating it
+func withTMID(raw []byte, id string) ([]byte, error) {
+	idString, _ := json.Marshal(id)
+	res, err := jsonparser.Set(raw, idString, "id")
+	if err != nil {
+		return nil, fmt.Errorf("could not set id %s: %w", id, err)
+	}
+	return res, nil
+}
+
+// withDisplayNames replaces the names of author, manufacturer and mpn of the TM file raw with the non-empty names in names
+func withDisplayNames(raw []byte, names model.DisplayNames) ([]byte, error) {
+	for _, n := range []struct {
+		value string
+		keys  []string
+	}{
+		{names.Author, []string{"schema:author", "schema:name"}},
+		{names.Manufacturer, []string{"schema:manufacturer", "schema:name"}},
+		{names.Mpn, []string{"schema:mpn"}},
+	} {
+		if n.value == "" {
+			continue
+		}
+		v, _ := json.Marshal(n.value)
+		var err error
+		raw, err = jsonparser.Set(raw, v, n.keys...)
+		if err != nil {
+			return nil, fmt.Errorf("could not set %s: %w", n.keys[0], err)
+		}
+	}
+	return raw, nil
+}
@@ -36,6 +36,8 @@ const (
 	KeyRepoAWSEndpoint        = "aws_endpoint"
 	KeyRepoAWSAccessKeyId     = "aws_access_key_id"
 	KeyRepoAWSSecretAccessKey = "aws_secret_access_key"
+	KeyRepoGitRemote          = "remote"
+	KeyRepoGitBranch          = "branch"
 	AuthMethodNone            = "none"
 	AuthMethodBearerToken     = "bearer"
 	AuthMethodBasic           = "basic"
@@ -45,6 +47,8 @@ const (
 	RepoTypeHttp              = "http"
 	RepoTypeTmc               = "tmc"
 	RepoTypeS3                = "s3"
+	RepoTypeGit               = "git"
+	RepoTypeCache             = "cache"
 	CompletionKindNames       = "names"
 	CompletionKindFetchNames  = "fetchNames"
 	CompletionKindNamesOrIds  = "namesOrIds"
@@ -56,6 +60,7 @@ const (
 	TmManufacturersFile       = "manufacturers.txt"
 	TmMpnsFile                = "mpns.txt"
 	TmIgnoreFile              = ".tmcignore"
+	ValidationDir             = "validation"
 
 	maxIndexingBatchSize = math.MaxInt
 )
@@ -73,7 +78,7 @@ var repoDefaultIgnore = []string{
 
 type Config map[string]map[string]any
 
-var SupportedTypes = []string{RepoTypeFile, RepoTypeHttp, RepoTypeTmc, RepoTypeS3}
+var SupportedTypes = []string{RepoTypeFile, RepoTypeHttp, RepoTypeTmc, RepoTypeS3, RepoTypeGit, RepoTypeCache}
 
 type ImportResultType int
 
@@ -152,9 +157,27 @@ type Repo interface {
 	ListCompletions(ctx context.Context, kind string, args []string, toComplete string) ([]string, error)
 
 	GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, error)
-	ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool) error
+	// ImportAttachment imports the attachment to the container. ifMatch is the entity tag the existing attachment is
+	// expected to have, if any. Repos backed by a remote TM catalog send it along, so that the remote rejects the change
+	// with ErrPreconditionFailed if the attachment has been modified. Other repos ignore it
+	ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error
 	FetchAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) ([]byte, error)
-	DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) error
+	// DeleteAttachment deletes the attachment from the container. ifMatch is handled as in ImportAttachment
+	DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName, ifMatch string) error
+	// SetLifecycle sets the lifecycle metadata of the TM version with given id, e.g. to deprecate or yank it.
+	// Returns ErrTMNotFound if the version does not exist
+	SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error
+	// UpdateLabels adds the labels in add to and removes the labels in remove from the TM name or TM version given by ref.
+	// Returns ErrTMNameNotFound or ErrTMNotFound if ref does not exist
+	UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error
+	// Move moves all versions and attachments of the TM name oldName to newName, rewriting the ids and, according to
+	// opts, the display names of the versions, and records oldName as an alias, so that Fetch and Versions
+	// transparently redirect to newName.
+	// Returns ErrTMNameNotFound if oldName does not exist and ErrTMNameExists if newName does
+	Move(ctx context.Context, oldName, newName string, opts MoveOptions) error
+	// ValidationFiles returns the contents of the repo's custom validation schemas and rule files by file name.
+	// The files are located in the directory .tmc/validation. Returns an empty map if the repo has none
+	ValidationFiles(ctx context.Context) (map[string][]byte, error)
 }
 
 type ImportOptions struct {
@@ -162,14 +185,29 @@ type ImportOptions struct {
 	OptPath         string
 	IgnoreExisting  bool
 	WithAttachments bool
+	// BreakingChanges determines how breaking changes compared to the previous version of the TM are handled,
+	// when the major version has not been increased
+	BreakingChanges BreakingChangesPolicy
 }
 
+type BreakingChangesPolicy int
+
+const (
+	BreakingChangesWarn   = BreakingChangesPolicy(iota) // import with a warning
+	BreakingChangesReject                               // do not import
+	BreakingChangesIgnore                               // do not check for breaking changes
+)
+
 var Get = func(spec model.RepoSpec) (Repo, error) {
 	if spec.Dir() != "" {
 		if spec.RepoName() != "" {
 			return nil, fmt.Errorf("could not initialize a repo instance for %s: %w\ncheck config", spec, model.ErrInvalidSpec)
 		}
-		return NewFileRepo(map[string]any{KeyRepoType: "file", KeyRepoLoc: spec.Dir()}, spec)
+		r, err := NewFileRepo(map[string]any{KeyRepoType: "file", KeyRepoLoc: spec.Dir()}, spec)
+		if err != nil {
+			return nil, err
+		}
+		return instrument(r), nil
 	}
 	repos, err := ReadConfig()
 	if err != nil {
@@ -203,7 +241,12 @@ var Get = func(spec model.RepoSpec) (Repo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize a repo instance for %s: %w\ncheck config", spec, err)
 	}
-	return repo, err
+	return instrument(repo), nil
+}
+
+// instrument wraps r into the repos which record metrics and spans, if enabled. Returns r otherwise
+func instrument(r Repo) Repo {
+	return withMetrics(withTracing(r))
 }
 
 func splitRepoName(name string) (string, string) {
@@ -224,18 +267,28 @@ func filterEnabled(repos Config) Config {
 }
 
 func createRepo(rc map[string]any, spec model.RepoSpec) (Repo, error) {
+	var r Repo
+	var err error
 	switch t := rc[KeyRepoType]; t {
 	case RepoTypeFile:
-		return NewFileRepo(rc, spec)
+		r, err = NewFileRepo(rc, spec)
 	case RepoTypeHttp:
-		return NewHttpRepo(rc, spec)
+		r, err = NewHttpRepo(rc, spec)
 	case RepoTypeTmc:
-		return NewTmcRepo(rc, spec)
+		r, err = NewTmcRepo(rc, spec)
 	case RepoTypeS3:
-		return NewS3Repo(rc, spec)
+		r, err = NewS3Repo(rc, spec)
+	case RepoTypeGit:
+		r, err = NewGitRepo(rc, spec)
+	case RepoTypeCache:
+		r, err = NewCacheRepo(rc, spec)
 	default:
 		return nil, fmt.Errorf("unsupported repo type: %v. Supported types are %v", t, SupportedTypes)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return withSignatureVerification(r, rc)
 }
 
 var All = func() ([]Repo, error) {
@@ -251,7 +304,7 @@ var All = func() ([]Repo, error) {
 		if err != nil {
 			return rs, err
 		}
-		rs = append(rs, r)
+		rs = append(rs, instrument(r))
 	}
 	return rs, err
 }
@@ -300,7 +353,7 @@ func expandTmcRepos(ctx context.Context, descriptions []model.RepoDescription) (
 		if err != nil {
 			return nil, err
 		}
-		tmc, _ := repo.(*TmcRepo)
+		tmc, _ := Unwrap(repo).(*TmcRepo)
 		repos, err := tmc.GetSubRepos(ctx)
 		if err != nil {
 			return nil, &RepoAccessError{spec, err}
@@ -436,6 +489,16 @@ func NewRepoConfig(typ string, confFile []byte) (ConfigMap, error) {
 		if err != nil {
 			return nil, err
 		}
+	case RepoTypeGit:
+		rc, err = createGitRepoConfig(confFile)
+		if err != nil {
+			return nil, err
+		}
+	case RepoTypeCache:
+		rc, err = createCacheRepoConfig(confFile)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported repo type: %v. Supported types are %v", typ, SupportedTypes)
 	}
@@ -0,0 +1,64 @@
+package repos
+
+import (
+	"fmt"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	KeyRepoRetention = "retention"
+
+	KeyRetentionKeepLatest      = "keep_latest"
+	KeyRetentionKeepMinorHeads  = "keep_minor_heads"
+	KeyRetentionKeepYoungerThan = "keep_younger_than"
+)
+
+// GetRetentionPolicy returns the retention policy configured for the repo given by spec.
+// An empty spec refers to the only enabled repo, as with Get.
+// Repos given by a directory and repos without a configured policy have an empty policy
+var GetRetentionPolicy = func(spec model.RepoSpec) (model.RetentionPolicy, error) {
+	if spec.Dir() != "" {
+		return model.RetentionPolicy{}, nil
+	}
+	rc, ok, err := enabledRepoConfig(spec)
+	if err != nil || !ok {
+		return model.RetentionPolicy{}, err
+	}
+	return retentionPolicy(rc)
+}
+
+func retentionPolicy(rc ConfigMap) (model.RetentionPolicy, error) {
+	var p model.RetentionPolicy
+	v, found := rc[KeyRepoRetention]
+	if !found || v == nil {
+		return p, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return p, fmt.Errorf("%w: %s must be a map", model.ErrInvalidRetentionPolicy, KeyRepoRetention)
+	}
+	if n, found := m[KeyRetentionKeepLatest]; found {
+		switch nv := n.(type) {
+		case float64:
+			p.KeepLatest = int(nv)
+		case int:
+			p.KeepLatest = nv
+		default:
+			return p, fmt.Errorf("%w: %s must be a number", model.ErrInvalidRetentionPolicy, KeyRetentionKeepLatest)
+		}
+		if p.KeepLatest < 0 {
+			return p, fmt.Errorf("%w: %s must not be negative", model.ErrInvalidRetentionPolicy, KeyRetentionKeepLatest)
+		}
+	}
+	p.KeepMinorHeads, _ = utils.JsGetBool(m, KeyRetentionKeepMinorHeads)
+	if age, found := utils.JsGetString(m, KeyRetentionKeepYoungerThan); found && age != "" {
+		d, err := model.ParseRetentionAge(age)
+		if err != nil {
+			return p, err
+		}
+		p.KeepYoungerThan = d
+	}
+	return p, nil
+}
@@ -0,0 +1,71 @@
+package repos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/model"
+)
+
+func TestGetRetentionPolicy(t *testing.T) {
+	viper.Set(KeyRepos, map[string]any{
+		"r1": map[string]any{
+			"type": "file",
+			"loc":  "somewhere",
+		},
+		"r2": map[string]any{
+			"type": "file",
+			"loc":  "somewhere-else",
+			KeyRepoRetention: map[string]any{
+				KeyRetentionKeepLatest:      float64(3),
+				KeyRetentionKeepMinorHeads:  true,
+				KeyRetentionKeepYoungerThan: "90d",
+			},
+		},
+		"r3": map[string]any{
+			"type": "file",
+			"loc":  "elsewhere",
+			KeyRepoRetention: map[string]any{
+				KeyRetentionKeepYoungerThan: "3 months",
+			},
+		},
+		"r4": map[string]any{
+			"type":           "file",
+			"loc":            "nowhere",
+			KeyRepoRetention: "keep all",
+		},
+		"r5": map[string]any{
+			"type": "file",
+			"loc":  "anywhere",
+			KeyRepoRetention: map[string]any{
+				KeyRetentionKeepLatest: -1,
+			},
+		},
+	})
+	defer viper.Reset()
+
+	tests := []struct {
+		spec    model.RepoSpec
+		exp     model.RetentionPolicy
+		wantErr bool
+	}{
+		{model.NewRepoSpec("r1"), model.RetentionPolicy{}, false},
+		{model.NewRepoSpec("r2"), model.RetentionPolicy{KeepLatest: 3, KeepMinorHeads: true, KeepYoungerThan: 90 * 24 * time.Hour}, false},
+		{model.NewRepoSpec("r3"), model.RetentionPolicy{}, true},
+		{model.NewRepoSpec("r4"), model.RetentionPolicy{}, true},
+		{model.NewRepoSpec("r5"), model.RetentionPolicy{}, true},
+		{model.NewRepoSpec("r6"), model.RetentionPolicy{}, false},
+		{model.NewDirSpec("somewhere-else"), model.RetentionPolicy{}, false},
+	}
+	for _, test := range tests {
+		p, err := GetRetentionPolicy(test.spec)
+		if test.wantErr {
+			assert.ErrorIs(t, err, model.ErrInvalidRetentionPolicy, test.spec)
+		} else {
+			assert.NoError(t, err, test.spec)
+			assert.Equal(t, test.exp, p, test.spec)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/smithy-go"
@@ -20,6 +21,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
 	ignore "github.com/sabhiram/go-gitignore"
 	"github.com/wot-oss/tmc/internal/model"
 	"github.com/wot-oss/tmc/internal/utils"
@@ -28,6 +30,18 @@ import (
 var ErrS3NotExists = errors.New("file does not exist in S3")
 var ErrS3Op = errors.New("operational error")
 var ErrS3Unknown = errors.New("unknown error")
+var ErrS3PreconditionFailed = errors.New("precondition failed")
+var ErrS3LeaseLost = errors.New("lease on the index has been lost")
+
+const (
+	s3LockRetryDelay  = 100 * time.Millisecond
+	s3IndexCASRetries = 5
+)
+
+// s3LockLeaseDuration is how long a lease on the index is valid without being renewed. Leases are renewed while held and
+// waited for twice as long when locking, so that a lease left behind by a crashed writer can be taken over in time.
+// Mockable for testing
+var s3LockLeaseDuration = 10 * time.Second
 
 func createS3RepoConfig(bytes []byte) (ConfigMap, error) {
 	rc, err := AsRepoConfig(bytes)
@@ -75,6 +89,8 @@ type S3Repo struct {
 	client S3Client
 	// cached index
 	idx *model.Index
+	// ETag of the cached index as read from the bucket. Empty if the index does not exist
+	idxETag string
 }
 
 func NewS3Repo(cfg ConfigMap, spec model.RepoSpec) (*S3Repo, error) {
@@ -158,7 +174,7 @@ func (s *S3Repo) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	unlock, err := s.lockIndex(ctx)
+	ctx, unlock, err := s.lockIndex(ctx)
 	if err != nil {
 		return err
 	}
@@ -266,13 +282,21 @@ func findTMS3EntriesByBaseVersion(infos []S3ObjectInfo, version model.TMVersion)
 
 func (s *S3Repo) Fetch(ctx context.Context, id string) (string, []byte, error) {
 
-	_, err := model.ParseTMID(id)
+	tmid, err := model.ParseTMID(id)
 	if err != nil {
 		return "", nil, err
 	}
 	match, actualId := s.getExistingID(ctx, id)
 	if match != idMatchFull && match != idMatchDigest {
-		return "", nil, model.ErrTMNotFound
+		newName, ok := s.resolveAlias(ctx, tmid.Name)
+		if !ok {
+			return "", nil, model.ErrTMNotFound
+		}
+		// moving may have changed the digest along with the content, but not the timestamp
+		match, actualId = s.getExistingID(ctx, movedTMID(id, newName))
+		if match == idMatchNone {
+			return "", nil, model.ErrTMNotFound
+		}
 	}
 	b, err := s3ReadObject(ctx, s.client, s.bucket, actualId)
 	return actualId, b, err
@@ -280,7 +304,7 @@ func (s *S3Repo) Fetch(ctx context.Context, id string) (string, []byte, error) {
 
 func (s *S3Repo) Index(ctx context.Context, ids ...string) error {
 
-	unlock, err := s.lockIndex(ctx)
+	ctx, unlock, err := s.lockIndex(ctx)
 	defer unlock()
 	if err != nil {
 		return err
@@ -296,7 +320,7 @@ func (s *S3Repo) Index(ctx context.Context, ids ...string) error {
 
 func (s *S3Repo) CheckIntegrity(ctx context.Context, filter model.ResourceFilter) (results []model.CheckResult, err error) {
 
-	unlock, err := s.lockIndex(ctx)
+	unlock, err := s.lockIndexForReading(ctx)
 	defer unlock()
 	if err != nil {
 		return nil, err
@@ -319,7 +343,7 @@ func (s *S3Repo) Spec() model.RepoSpec {
 }
 
 func (s *S3Repo) List(ctx context.Context, search *model.Filters) (model.SearchResult, error) {
-	unlock, err := s.lockIndex(ctx)
+	unlock, err := s.lockIndexForReading(ctx)
 	defer unlock()
 	if err != nil {
 		return model.SearchResult{}, err
@@ -345,9 +369,10 @@ func (s *S3Repo) readIndex(ctx context.Context) (*model.Index, error) {
 	if s.idx != nil {
 		return s.idx, nil
 	}
-	data, err := s3ReadObject(ctx, s.client, s.bucket, s.indexFilename())
+	data, etag, err := s3ReadObjectWithETag(ctx, s.client, s.bucket, s.indexFilename())
 	if err != nil {
 		if errors.Is(err, ErrS3NotExists) {
+			s.idxETag = ""
 			err = ErrNoIndex
 		}
 		return nil, err
@@ -357,6 +382,7 @@ func (s *S3Repo) readIndex(ctx context.Context) (*model.Index, error) {
 	err = json.Unmarshal(data, &index)
 	if err == nil {
 		s.idx = &index
+		s.idxETag = etag
 	}
 	return &index, err
 }
@@ -373,6 +399,9 @@ func (s *S3Repo) Versions(ctx context.Context, name string) ([]model.FoundVersio
 	}
 
 	if len(res.Entries) != 1 {
+		if newName, ok := s.resolveAlias(ctx, name); ok {
+			return s.Versions(ctx, newName)
+		}
 		err := fmt.Errorf("%w: %s", model.ErrTMNameNotFound, name)
 		return nil, err
 	}
@@ -380,6 +409,20 @@ func (s *S3Repo) Versions(ctx context.Context, name string) ([]model.FoundVersio
 	return res.Entries[0].Versions, nil
 }
 
+// resolveAlias returns the current name of the TM name name, if it has been moved
+func (s *S3Repo) resolveAlias(ctx context.Context, name string) (string, bool) {
+	unlock, err := s.lockIndexForReading(ctx)
+	defer unlock()
+	if err != nil {
+		return "", false
+	}
+	idx, err := s.readIndex(ctx)
+	if err != nil {
+		return "", false
+	}
+	return idx.ResolveAlias(name)
+}
+
 func (s *S3Repo) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, error) {
 	id, err := model.ParseTMID(tmID)
 	if err != nil {
@@ -390,15 +433,16 @@ func (s *S3Repo) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundV
 		return nil, err
 	}
 	for _, v := range versions {
-		if v.TMID == tmID {
+		// versions belong to the name id.Name has been moved to, if it has been moved
+		if isVersionOf(tmID, v.TMID) {
 			return []model.FoundVersion{v}, nil
 		}
 	}
 	return nil, model.ErrTMNotFound
 }
 
-func (s *S3Repo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool) error {
-	unlock, err := s.lockIndex(ctx)
+func (s *S3Repo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
+	ctx, unlock, err := s.lockIndex(ctx)
 	defer unlock()
 	if err != nil {
 		return err
@@ -430,6 +474,126 @@ func (s *S3Repo) ImportAttachment(ctx context.Context, container model.Attachmen
 	return nil
 }
 
+// SetLifecycle sets the lifecycle metadata of the TM version with given id in the index.
+// Returns ErrTMNotFound if the version does not exist
+func (s *S3Repo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	ctx, unlock, err := s.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateIndex(ctx, s.indexUpdaterForLifecycle(id, lc))
+	return err
+}
+
+// UpdateLabels updates the labels of the TM name or TM version given by ref in the index
+func (s *S3Repo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error {
+	ctx, unlock, err := s.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateIndex(ctx, s.indexUpdaterForLabels(ref, add, remove))
+	return err
+}
+
+// Move copies the objects of all versions of the TM name oldName and its attachments to newName, rewriting the ids
+// and display names in the TM files, removes the original objects and updates the index accordingly
+func (s *S3Repo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) error {
+	ctx, unlock, err := s.lockIndex(ctx)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+	index, err := s.readIndex(ctx)
+	if err != nil {
+		return err
+	}
+	entry := index.FindByName(oldName)
+	if entry == nil {
+		return fmt.Errorf("%w: %s", model.ErrTMNameNotFound, oldName)
+	}
+	if index.FindByName(newName) != nil {
+		return fmt.Errorf("%w: %s", model.ErrTMNameExists, newName)
+	}
+
+	// the new objects are written and the index is updated before the old objects are removed, so that a failed move
+	// can be rolled back
+	var oldIds, written []string
+	rollback := func(err error) error {
+		for _, key := range written {
+			if rErr := s3RemoveObject(ctx, s.client, s.bucket, key); rErr != nil {
+				utils.GetLogger(ctx, "S3Repo").Error("could not roll back writing moved object", "key", key, "error", rErr)
+			}
+		}
+		return err
+	}
+
+	newIds := make(map[string]string)
+	for _, v := range entry.Versions {
+		raw, err := s3ReadObject(ctx, s.client, s.bucket, v.TMID)
+		if err != nil {
+			return rollback(err)
+		}
+		newId, raw, err := movedTM(raw, v.TMID, newName, opts)
+		if err != nil {
+			return rollback(err)
+		}
+		newIds[v.TMID] = newId
+		err = s3WriteObject(ctx, s.client, s.bucket, newId, raw)
+		if err != nil {
+			return rollback(fmt.Errorf("could not write TM to catalog: %w", err))
+		}
+		written = append(written, newId)
+		oldIds = append(oldIds, v.TMID)
+	}
+
+	// the attachments of the TM name and of all its versions are located in the attachments directory of the TM name
+	oldAttDir, _ := s.getAttachmentsDir(model.NewTMNameAttachmentContainerRef(oldName))
+	newAttDir, _ := s.getAttachmentsDir(model.NewTMNameAttachmentContainerRef(newName))
+	atts, err := s3ListObjects(ctx, s.client, s.bucket, toS3Dir(oldAttDir))
+	if err != nil {
+		return rollback(err)
+	}
+	versionDirs := movedVersionAttachmentDirs(newName, newIds)
+	for _, att := range atts {
+		content, err := s3ReadObject(ctx, s.client, s.bucket, att.Path)
+		if err != nil {
+			return rollback(err)
+		}
+		newPath := newAttDir + strings.TrimPrefix(att.Path, oldAttDir)
+		for from, to := range versionDirs {
+			if strings.HasPrefix(newPath, from+"/") {
+				newPath = to + strings.TrimPrefix(newPath, from)
+				break
+			}
+		}
+		err = s3WriteObject(ctx, s.client, s.bucket, newPath, content)
+		if err != nil {
+			return rollback(fmt.Errorf("could not move attachments of %s: %w", oldName, err))
+		}
+		written = append(written, newPath)
+	}
+
+	_, err = s.updateIndex(ctx, s.indexUpdaterForMove(oldName, newName, newIds, opts.Names))
+	if err != nil {
+		return rollback(err)
+	}
+
+	// the move is complete with the index updated. Leftovers of the old name are reported by 'check'
+	if rErr := s3RemoveAll(ctx, s.client, s.bucket, toS3Dir(oldAttDir)); rErr != nil {
+		utils.GetLogger(ctx, "S3Repo").Warn("could not remove moved attachments", "dir", oldAttDir, "error", rErr)
+	}
+	for _, id := range oldIds {
+		if rErr := s3RemoveObject(ctx, s.client, s.bucket, id); rErr != nil {
+			utils.GetLogger(ctx, "S3Repo").Warn("could not remove moved TM", "key", id, "error", rErr)
+		}
+	}
+	return nil
+}
+
 // prepareAttachmentOperation prepares for a CRUD operation on attachments
 // Must be called after the index lock has been acquired with lockIndex
 func (s *S3Repo) prepareAttachmentOperation(ctx context.Context, ref model.AttachmentContainerRef) (string, error) {
@@ -445,8 +609,27 @@ func (s *S3Repo) prepareAttachmentOperation(ctx context.Context, ref model.Attac
 	return attDir, nil
 }
 
+func (s *S3Repo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	entries, err := s3ListObjects(ctx, s.client, s.bucket, toS3Dir(path.Join(RepoConfDir, ValidationDir)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Path, "/") {
+			continue
+		}
+		content, err := s3ReadObject(ctx, s.client, s.bucket, e.Path)
+		if err != nil {
+			return nil, err
+		}
+		files[e.Name] = content
+	}
+	return files, nil
+}
+
 func (s *S3Repo) FetchAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string) ([]byte, error) {
-	unlock, err := s.lockIndex(ctx)
+	unlock, err := s.lockIndexForReading(ctx)
 	defer unlock()
 	if err != nil {
 		return nil, err
@@ -483,8 +666,8 @@ func (s *S3Repo) verifyAttachmentExistsInIndex(ctx context.Context, ref model.At
 	return nil
 }
 
-func (s *S3Repo) DeleteAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName string) error {
-	unlock, err := s.lockIndex(ctx)
+func (s *S3Repo) DeleteAttachment(ctx context.Context, ref model.AttachmentContainerRef, attachmentName, ifMatch string) error {
+	ctx, unlock, err := s.lockIndex(ctx)
 	defer unlock()
 	if err != nil {
 		return err
@@ -549,30 +732,51 @@ func (s *S3Repo) updateIndex(ctx context.Context, updater indexUpdater) (*model.
 	var mpns []string
 	start := time.Now()
 
-	oldNames := s.readNamesFile(ctx)
-	oldIndex, err := s.readIndex(ctx)
-	if err != nil {
-		oldIndex = &model.Index{
-			Meta: model.IndexMeta{Created: time.Now()},
-			Data: []*model.IndexEntry{},
+	var newIndex *model.Index
+	var names []string
+	var fileCount int
+	for attempt := 0; ; attempt++ {
+		oldNames := s.readNamesFile(ctx)
+		oldIndex, err := s.readIndex(ctx)
+		// write the index only if it has not been changed by someone else since we've read it
+		precond := &s3Precondition{ifMatch: s.idxETag, ifNoneMatch: s.idxETag == ""}
+		if err != nil {
+			if !errors.Is(err, ErrNoIndex) {
+				precond = nil
+			}
+			oldIndex = &model.Index{
+				Meta: model.IndexMeta{Created: time.Now()},
+				Data: []*model.IndexEntry{},
+			}
 		}
-	}
 
-	newIndex, names, fileCount, err := updater(ctx, oldIndex, oldNames)
-	if err != nil {
-		return nil, err
-	}
+		newIndex, names, fileCount, err = updater(ctx, oldIndex, oldNames)
+		if err != nil {
+			return nil, err
+		}
 
-	newIndex.Sort()
-	s.idx = newIndex
-	duration := time.Now().Sub(start)
-	// Ignore error as we are sure our struct does not contain channel,
-	// complex or function values that would throw an error.
-	newIndexJson, _ := json.MarshalIndent(newIndex, "", "  ")
-	err = s3WriteObject(ctx, s.client, s.bucket, s.indexFilename(), newIndexJson)
-	if err != nil {
-		return nil, err
+		newIndex.Sort()
+		// Ignore error as we are sure our struct does not contain channel,
+		// complex or function values that would throw an error.
+		newIndexJson, _ := json.MarshalIndent(newIndex, "", "  ")
+		if ctx.Err() != nil {
+			// do not write the index without holding the lease on it
+			return nil, context.Cause(ctx)
+		}
+		etag, err := s3WriteObjectConditional(ctx, s.client, s.bucket, s.indexFilename(), newIndexJson, precond)
+		if errors.Is(err, ErrS3PreconditionFailed) && attempt < s3IndexCASRetries {
+			utils.GetLogger(ctx, "S3Repo").Debug("index has been modified concurrently, retrying update", "attempt", attempt+1)
+			s.idx = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.idx = newIndex
+		s.idxETag = etag
+		break
 	}
+	duration := time.Now().Sub(start)
 	for _, d := range newIndex.Data {
 		if !slices.Contains(authors, d.Author.Name) {
 			authors = append(authors, d.Author.Name)
@@ -584,7 +788,7 @@ func (s *S3Repo) updateIndex(ctx context.Context, updater indexUpdater) (*model.
 			mpns = append(mpns, d.Mpn)
 		}
 	}
-	err = s.writeHelperTxtFile(ctx, names, TmNamesFile)
+	err := s.writeHelperTxtFile(ctx, names, TmNamesFile)
 	if err != nil {
 		return nil, err
 	}
@@ -689,6 +893,45 @@ func (s *S3Repo) indexUpdaterForImportAttachment(ref model.AttachmentContainerRe
 	}
 }
 
+func (s *S3Repo) indexUpdaterForLifecycle(id string, lc model.Lifecycle) indexUpdater {
+	return func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		default:
+		}
+		err := oldIndex.SetLifecycle(id, lc)
+		return oldIndex, oldNames, 1, err
+	}
+}
+
+func (s *S3Repo) indexUpdaterForLabels(ref model.AttachmentContainerRef, add, remove []string) indexUpdater {
+	return func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		default:
+		}
+		err := oldIndex.UpdateLabels(ref, add, remove)
+		return oldIndex, oldNames, 1, err
+	}
+}
+
+func (s *S3Repo) indexUpdaterForMove(oldName, newName string, newIds map[string]string, names model.DisplayNames) indexUpdater {
+	return func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		default:
+		}
+		err := oldIndex.Move(oldName, newName, newIds, names)
+		newNames := append(slices.DeleteFunc(oldNames, func(s string) bool {
+			return s == oldName
+		}), newName)
+		return oldIndex, newNames, 1, err
+	}
+}
+
 func (s *S3Repo) fullIndexRebuild(ctx context.Context, oldIndex *model.Index, _ []string) (*model.Index, []string, int, error) {
 	fileCount := 0
 	updatedAttContainers := make(map[model.AttachmentContainerRef]struct{})
@@ -725,6 +968,7 @@ func (s *S3Repo) fullIndexRebuild(ctx context.Context, oldIndex *model.Index, _
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	newIndex.CopyIndexMetadata(oldIndex)
 
 	return newIndex, names, fileCount, nil
 }
@@ -781,14 +1025,134 @@ func (s *S3Repo) updateIndexWithFile(ctx context.Context, idx *model.Index, id s
 	return true, thingMeta.id, "", nil
 }
 
-func (s *S3Repo) lockIndex(ctx context.Context) (unlockFunc, error) {
-	// todo: locking index not yet implemented for S3, compare with fs_repo.go!
-	ctx, cancel := context.WithTimeout(ctx, indexLockTimeout)
+// lockIndexForReading prepares for reading the index. Reading the index from S3 is atomic, so no lease is acquired,
+// but the returned unlockFunc must still be called to drop the cached index
+func (s *S3Repo) lockIndexForReading(ctx context.Context) (unlockFunc, error) {
+	return func() { s.idx = nil }, nil
+}
+
+type s3Lease struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// lockIndex acquires a lease on the index by creating a lock object with a conditional write, which only succeeds
+// if the lock object does not exist. A lease which has expired is considered stale and is taken over by
+// overwriting the lock object on condition that it has not been changed in the meantime.
+// The lease is renewed periodically until unlocked, so that long-running operations do not lose it.
+// The returned context must be used for the operation performed under the lock. It is cancelled with ErrS3LeaseLost
+// as cause when the lease is lost, because it has been taken over or could not be renewed before it expired
+func (s *S3Repo) lockIndex(ctx context.Context) (context.Context, unlockFunc, error) {
+	lockFile := s.indexFilename() + ".lock"
+	unlockCtx := context.WithoutCancel(ctx)
+	opCtx, cancelOp := context.WithCancelCause(ctx)
+	ctx, cancel := context.WithTimeout(ctx, 2*s3LockLeaseDuration)
+	var mu sync.Mutex
+	var leaseETag string
+	stopRenewal := make(chan struct{})
+	renewalDone := make(chan struct{})
 	unlock := func() {
 		cancel()
+		defer cancelOp(nil)
 		s.idx = nil
+		mu.Lock()
+		etag := leaseETag
+		mu.Unlock()
+		if etag == "" {
+			return
+		}
+		close(stopRenewal)
+		<-renewalDone
+		mu.Lock()
+		etag = leaseETag
+		mu.Unlock()
+		err := s3RemoveObjectConditional(unlockCtx, s.client, s.bucket, lockFile, etag)
+		if err != nil && !errors.Is(err, ErrS3PreconditionFailed) {
+			// fall back for S3 implementations not supporting conditional deletes
+			if _, cur, rErr := s3ReadObjectWithETag(unlockCtx, s.client, s.bucket, lockFile); rErr == nil && cur == etag {
+				_ = s3RemoveObject(unlockCtx, s.client, s.bucket, lockFile)
+			}
+		}
+	}
+
+	owner, _ := uuid.NewRandom()
+	newLease := func() []byte {
+		lease, _ := json.Marshal(s3Lease{Owner: owner.String(), Expires: time.Now().Add(s3LockLeaseDuration)})
+		return lease
+	}
+	acquired := func(etag string) (context.Context, unlockFunc, error) {
+		leaseETag = etag
+		go s.renewIndexLease(unlockCtx, lockFile, newLease, &mu, &leaseETag, cancelOp, stopRenewal, renewalDone)
+		return opCtx, unlock, nil
+	}
+	for {
+		lease := newLease()
+		etag, err := s3WriteObjectConditional(ctx, s.client, s.bucket, lockFile, lease, &s3Precondition{ifNoneMatch: true})
+		if err == nil {
+			return acquired(etag)
+		}
+		if !errors.Is(err, ErrS3PreconditionFailed) {
+			return opCtx, unlock, fmt.Errorf("failed to lock index file %s: %w", s.indexFilename(), err)
+		}
+
+		existing, existingETag, err := s3ReadObjectWithETag(ctx, s.client, s.bucket, lockFile)
+		if err == nil {
+			var l s3Lease
+			if jErr := json.Unmarshal(existing, &l); jErr != nil || time.Now().After(l.Expires) {
+				utils.GetLogger(ctx, "S3Repo").Info("taking over stale index lock", "owner", l.Owner, "expired", l.Expires)
+				etag, err = s3WriteObjectConditional(ctx, s.client, s.bucket, lockFile, lease, &s3Precondition{ifMatch: existingETag})
+				if err == nil {
+					return acquired(etag)
+				}
+			}
+		}
+		if err != nil && !errors.Is(err, ErrS3PreconditionFailed) && !errors.Is(err, ErrS3NotExists) {
+			return opCtx, unlock, fmt.Errorf("failed to lock index file %s: %w", s.indexFilename(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return opCtx, unlock, fmt.Errorf("failed to lock index file %s: %w", s.indexFilename(), ctx.Err())
+		case <-time.After(s3LockRetryDelay):
+		}
+	}
+}
+
+// renewIndexLease extends the lease on the index every third of the lease duration until stop is closed. The lease is
+// only overwritten if it is still the one with the ETag in leaseETag, which is updated with every renewal.
+// When the lease has been taken over or has expired without being renewed, lost is called with ErrS3LeaseLost
+func (s *S3Repo) renewIndexLease(ctx context.Context, lockFile string, newLease func() []byte, mu *sync.Mutex, leaseETag *string, lost context.CancelCauseFunc, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s3LockLeaseDuration / 3)
+	defer ticker.Stop()
+	renewed := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		mu.Lock()
+		etag, err := s3WriteObjectConditional(ctx, s.client, s.bucket, lockFile, newLease(), &s3Precondition{ifMatch: *leaseETag})
+		if err == nil {
+			*leaseETag = etag
+		}
+		mu.Unlock()
+		if err == nil {
+			renewed = time.Now()
+			continue
+		}
+		utils.GetLogger(ctx, "S3Repo").Warn("could not renew index lock", "error", err)
+		if errors.Is(err, ErrS3PreconditionFailed) {
+			// the lease has been taken over
+			lost(fmt.Errorf("%w: taken over by another writer", ErrS3LeaseLost))
+			return
+		}
+		if time.Since(renewed) >= s3LockLeaseDuration {
+			lost(fmt.Errorf("%w: expired without renewal: %v", ErrS3LeaseLost, err))
+			return
+		}
 	}
-	return unlock, nil
 }
 
 func (s *S3Repo) readNamesFile(ctx context.Context) []string {
@@ -848,7 +1212,7 @@ func (s *S3Repo) getThingMetadata(ctx context.Context, id string) (*thingMetadat
 func (s *S3Repo) ListCompletions(ctx context.Context, kind string, args []string, toComplete string) ([]string, error) {
 	switch kind {
 	case CompletionKindNames:
-		unlock, err := s.lockIndex(ctx)
+		unlock, err := s.lockIndexForReading(ctx)
 		defer unlock()
 		if err != nil {
 			return nil, err
@@ -887,7 +1251,7 @@ func (s *S3Repo) ListCompletions(ctx context.Context, kind string, args []string
 		slices.Sort(vs)
 		return vs, nil
 	case CompletionKindNamesOrIds:
-		unlock, err := s.lockIndex(ctx)
+		unlock, err := s.lockIndexForReading(ctx)
 		defer unlock()
 		if err != nil {
 			return nil, err
@@ -1014,29 +1378,71 @@ func s3Stat(ctx context.Context, client S3Client, bucket string, objectKey strin
 }
 
 func s3WriteObject(ctx context.Context, client S3Client, bucket string, objectKey string, data []byte) error {
-	_, err := client.PutObject(ctx, &s3.PutObjectInput{
+	_, err := s3WriteObjectConditional(ctx, client, bucket, objectKey, data, nil)
+	return err
+}
+
+// s3Precondition is a precondition for a conditional write to S3
+type s3Precondition struct {
+	// ifMatch is the ETag the object is expected to have
+	ifMatch string
+	// ifNoneMatch requires that the object does not exist. Takes precedence over ifMatch
+	ifNoneMatch bool
+}
+
+// s3WriteObjectConditional writes the object if the precondition is met, or unconditionally if precond is nil.
+// Returns the ETag of the written object or ErrS3PreconditionFailed if the precondition has not been met
+func s3WriteObjectConditional(ctx context.Context, client S3Client, bucket string, objectKey string, data []byte, precond *s3Precondition) (string, error) {
+	input := &s3.PutObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(objectKey),
 		Body:   bytes.NewReader(data),
-	})
+	}
+	if precond != nil {
+		if precond.ifNoneMatch {
+			input.IfNoneMatch = aws.String("*")
+		} else {
+			input.IfMatch = aws.String(precond.ifMatch)
+		}
+	}
+	out, err := client.PutObject(ctx, input)
 
 	if err != nil {
+		if isS3PreconditionFailed(err) {
+			return "", fmt.Errorf("%w, object: %s error: %s", ErrS3PreconditionFailed, objectKey, err.Error())
+		}
 		utils.GetLogger(ctx, "S3Repo").Warn("failed to write object to S3", "object", objectKey, "bucket", bucket, "error", err.Error())
 
 		var oe *smithy.OperationError
 		if errors.As(err, &oe) {
-			return fmt.Errorf("%w, object: %s error: %s", ErrS3Op, objectKey, err.Error())
+			return "", fmt.Errorf("%w, object: %s error: %s", ErrS3Op, objectKey, err.Error())
 		}
-		return fmt.Errorf("%w, object: %s error: %s", ErrS3Unknown, objectKey, err.Error())
+		return "", fmt.Errorf("%w, object: %s error: %s", ErrS3Unknown, objectKey, err.Error())
 	}
 
 	msg := fmt.Sprintf("object %s successfully written to S3: bucket %s", objectKey, bucket)
 	utils.GetLogger(ctx, "S3Repo").Debug(msg)
 
-	return nil
+	return aws.ToString(out.ETag), nil
+}
+
+// isS3PreconditionFailed checks whether err has been caused by a failed precondition of a conditional request or
+// by a conflicting concurrent conditional request
+func isS3PreconditionFailed(err error) bool {
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		return ae.ErrorCode() == "PreconditionFailed" || ae.ErrorCode() == "ConditionalRequestConflict"
+	}
+	return false
 }
 
 func s3ReadObject(ctx context.Context, client S3Client, bucket string, objectKey string) ([]byte, error) {
+	b, _, err := s3ReadObjectWithETag(ctx, client, bucket, objectKey)
+	return b, err
+}
+
+// s3ReadObjectWithETag reads the object's content and returns it together with the object's ETag
+func s3ReadObjectWithETag(ctx context.Context, client S3Client, bucket string, objectKey string) ([]byte, string, error) {
 
 	result, err := client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
@@ -1050,11 +1456,11 @@ func s3ReadObject(ctx context.Context, client S3Client, bucket string, objectKey
 
 		switch true {
 		case errors.As(err, &noKey):
-			return nil, fmt.Errorf("%w, object: %s error: %s", ErrS3NotExists, objectKey, err.Error())
+			return nil, "", fmt.Errorf("%w, object: %s error: %s", ErrS3NotExists, objectKey, err.Error())
 		case errors.As(err, &oe):
-			return nil, fmt.Errorf("%w, object: %s error: %s", ErrS3Op, objectKey, err.Error())
+			return nil, "", fmt.Errorf("%w, object: %s error: %s", ErrS3Op, objectKey, err.Error())
 		default:
-			return nil, fmt.Errorf("%w, object: %s error: %s", ErrS3Unknown, objectKey, err.Error())
+			return nil, "", fmt.Errorf("%w, object: %s error: %s", ErrS3Unknown, objectKey, err.Error())
 		}
 	}
 
@@ -1062,7 +1468,7 @@ func s3ReadObject(ctx context.Context, client S3Client, bucket string, objectKey
 
 	b, err := io.ReadAll(result.Body)
 
-	return b, err
+	return b, aws.ToString(result.ETag), err
 }
 
 func s3ListObjects(ctx context.Context, client S3Client, bucket, objectPrefix string) ([]S3ObjectInfo, error) {
@@ -1114,6 +1520,24 @@ func s3RemoveObject(ctx context.Context, client S3Client, bucket string, objectK
 	return nil
 }
 
+// s3RemoveObjectConditional removes the object only if it has the given ETag
+func s3RemoveObjectConditional(ctx context.Context, client S3Client, bucket string, objectKey string, etag string) error {
+	_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(bucket),
+		Key:     &objectKey,
+		IfMatch: aws.String(etag),
+	})
+
+	if err != nil {
+		if isS3PreconditionFailed(err) {
+			return fmt.Errorf("%w, object: %s error: %s", ErrS3PreconditionFailed, objectKey, err.Error())
+		}
+		utils.GetLogger(ctx, "S3Repo").Warn("failed to remove object from S3", "object", objectKey, "bucket", bucket, "error", err.Error())
+		return fmt.Errorf("%w, object: %s error: %s", ErrS3Unknown, objectKey, err.Error())
+	}
+	return nil
+}
+
 func s3RemoveAll(ctx context.Context, client S3Client, bucket string, objectPrefix string) error {
 
 	files, err := s3ListObjects(ctx, client, bucket, objectPrefix)
@@ -3,15 +3,19 @@ package repos
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
@@ -305,6 +309,9 @@ func TestS3Repo_Fetch(t *testing.T) {
 			Return(nil, &s3NotFoundErr)
 		c.On("ListObjectsV2", mock.Anything, &s3.ListObjectsV2Input{Bucket: &bucket, Prefix: &tmName}).
 			Return(&s3ListResponse, nil)
+		// the index is consulted for an alias of the TM name
+		c.On("GetObject", mock.Anything, mock.Anything).
+			Return(nil, &types.NoSuchKey{Message: aws.String("Object not found")})
 
 		r := S3Repo{bucket: bucket, client: c}
 		actId, _, err := r.Fetch(ctx, idEfail)
@@ -633,7 +640,7 @@ func TestS3Repo_ImportAttachment(t *testing.T) {
 
 	t.Run("tm name attachment without media type provided", func(t *testing.T) {
 		ref := model.NewTMNameAttachmentContainerRef(tmName)
-		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, toBucketObject(tmName, model.AttachmentsDir, r2Name)))
 		index, err := r.readIndex(ctx)
@@ -646,7 +653,7 @@ func TestS3Repo_ImportAttachment(t *testing.T) {
 	})
 	t.Run("tm name attachment with media type", func(t *testing.T) {
 		ref := model.NewTMNameAttachmentContainerRef(tmName)
-		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name, MediaType: "text/html"}, r2Content, true)
+		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name, MediaType: "text/html"}, r2Content, true, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, toBucketObject(tmName, model.AttachmentsDir, r2Name)))
 		index, err := r.readIndex(ctx)
@@ -659,7 +666,7 @@ func TestS3Repo_ImportAttachment(t *testing.T) {
 	})
 	t.Run("tm id attachment with media type provided by user", func(t *testing.T) {
 		ref := model.NewTMIDAttachmentContainerRef(id)
-		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, false)
+		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, false, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, toBucketObject(tmName, model.AttachmentsDir, ver, r2Name)))
 		index, err := r.readIndex(ctx)
@@ -672,7 +679,7 @@ func TestS3Repo_ImportAttachment(t *testing.T) {
 	})
 	t.Run("tm id attachment without media type", func(t *testing.T) {
 		ref := model.NewTMIDAttachmentContainerRef(id)
-		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, true)
+		err := r.ImportAttachment(ctx, ref, model.Attachment{Name: r2Name, MediaType: "text/markdown"}, r2Content, true, "")
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(temp, toBucketObject(tmName, model.AttachmentsDir, ver, r2Name)))
 		index, err := r.readIndex(ctx)
@@ -684,23 +691,23 @@ func TestS3Repo_ImportAttachment(t *testing.T) {
 		}
 	})
 	t.Run("tm id attachment conflict", func(t *testing.T) {
-		err := r.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(id), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(id), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, ErrAttachmentExists)
 	})
 	t.Run("non existent tm name", func(t *testing.T) {
-		err := r.ImportAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
 	})
 	t.Run("non existent tm id", func(t *testing.T) {
-		err := r.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrTMNotFound)
 	})
 	t.Run("invalid tm name", func(t *testing.T) {
-		err := r.ImportAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrInvalidIdOrName)
 	})
 	t.Run("invalid tm id", func(t *testing.T) {
-		err := r.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), model.Attachment{Name: r2Name}, r2Content, false)
+		err := r.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), model.Attachment{Name: r2Name}, r2Content, false, "")
 		assert.ErrorIs(t, err, model.ErrInvalidId)
 	})
 }
@@ -721,39 +728,123 @@ func TestS3Repo_DeleteAttachment(t *testing.T) {
 	attNameB := "cfg.json"
 
 	t.Run("non existent attachment", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef(tmName), "nothing-here")
+		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef(tmName), "nothing-here", "")
 		assert.ErrorIs(t, err, model.ErrAttachmentNotFound)
 	})
 	t.Run("non existent tm name", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), attNameA)
+		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-department/omnicorp/omnidarkness"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
 	})
 	t.Run("non existent tm id", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), attNameA)
+		err := r.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453.tm.json"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrTMNotFound)
 	})
 	t.Run("invalid tm name", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), attNameA)
+		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef("omnicorp-tm-departmentomnicorp/omnilamp"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrInvalidIdOrName)
 	})
 	t.Run("invalid tm id", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), attNameA)
+		err := r.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(tmName+"/v1.2.3-20240409155220-3f779458e453"), attNameA, "")
 		assert.ErrorIs(t, err, model.ErrInvalidId)
 	})
 	t.Run("tm id attachment", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(idA), attNameB)
+		err := r.DeleteAttachment(ctx, model.NewTMIDAttachmentContainerRef(idA), attNameB, "")
 		assert.NoError(t, err)
 		_, err = os.Stat(filepath.Join(temp, toBucketObject(tmName, model.AttachmentsDir, ver, attNameA)))
 		assert.True(t, os.IsNotExist(err))
 	})
 	t.Run("tm name attachment", func(t *testing.T) {
-		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef(tmName), attNameA)
+		err := r.DeleteAttachment(ctx, model.NewTMNameAttachmentContainerRef(tmName), attNameA, "")
 		assert.NoError(t, err)
 		_, err = os.Stat(filepath.Join(temp, toBucketObject(tmName, model.AttachmentsDir, attNameA)))
 		assert.True(t, os.IsNotExist(err))
 	})
 }
 
+func TestS3Repo_Move(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "s3r")
+	defer os.RemoveAll(temp)
+	assert.NoError(t, prepareS3MockBucket("../../test/data/repos/file/attachments", temp))
+
+	c := getS3Mock(t, temp)
+	r := S3Repo{bucket: bucket, client: c}
+	ctx := context.Background()
+
+	oldName := "omnicorp-tm-department/omnicorp/omnilamp"
+	newName := "omnicorp-tm-department/megacorp/omnilamp"
+	ver := "v3.2.1-20240409155220-3f779458e453"
+	oldId := oldName + "/" + ver + TMExt
+	newVer := "v3.2.1-20240409155220-a1b2c3d4e5f6"
+	newId := newName + "/" + newVer + TMExt
+	opts := MoveOptions{
+		Names:  model.DisplayNames{Manufacturer: "MegaCorp"},
+		Digest: func(raw []byte) (string, error) { return "a1b2c3d4e5f6", nil },
+	}
+
+	t.Run("non existent tm name", func(t *testing.T) {
+		err := r.Move(ctx, "omnicorp-tm-department/omnicorp/omnidarkness", newName, opts)
+		assert.ErrorIs(t, err, model.ErrTMNameNotFound)
+	})
+	for _, failing := range []string{path.Join(newName, model.AttachmentsDir, newVer, "cfg.json"), r.indexFilename()} {
+		t.Run("failing move is rolled back when writing "+failing, func(t *testing.T) {
+			// given: a client failing to write the object failing
+			fr := S3Repo{bucket: bucket, client: &failingPutS3Client{S3Client: c, key: failing}}
+			// when: moving the TM name
+			err := fr.Move(ctx, oldName, newName, opts)
+			// then: the move fails
+			assert.Error(t, err)
+			// and then: the objects written under the new name have been removed
+			assert.NoFileExists(t, filepath.Join(temp, toBucketObject(newId)))
+			assert.NoFileExists(t, filepath.Join(temp, toBucketObject(newName, model.AttachmentsDir, "README.md")))
+			// and then: everything is still available under the old name
+			assert.FileExists(t, filepath.Join(temp, toBucketObject(oldName, model.AttachmentsDir, "README.md")))
+			_, err = r.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef(oldId), "cfg.json")
+			assert.NoError(t, err)
+			id, _, err := r.Fetch(ctx, oldId)
+			assert.NoError(t, err)
+			assert.Equal(t, oldId, id)
+		})
+	}
+	t.Run("move", func(t *testing.T) {
+		err := r.Move(ctx, oldName, newName, opts)
+		assert.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(temp, toBucketObject(oldId)))
+		assert.NoFileExists(t, filepath.Join(temp, toBucketObject(oldName, model.AttachmentsDir, "README.md")))
+		assert.FileExists(t, filepath.Join(temp, toBucketObject(newName, model.AttachmentsDir, "README.md")))
+		assert.FileExists(t, filepath.Join(temp, toBucketObject(newName, model.AttachmentsDir, newVer, "cfg.json")))
+		assert.NoFileExists(t, filepath.Join(temp, toBucketObject(newName, model.AttachmentsDir, ver, "cfg.json")))
+		_, err = r.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef(newId), "cfg.json")
+		assert.NoError(t, err)
+
+		id, raw, err := r.Fetch(ctx, newId)
+		assert.NoError(t, err)
+		assert.Equal(t, newId, id)
+		tm, err := model.ParseThingModel(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, newId, tm.ID)
+		assert.Equal(t, "MegaCorp", tm.Manufacturer.Name)
+	})
+	t.Run("filter by new manufacturer", func(t *testing.T) {
+		res, err := r.List(ctx, &model.Filters{Manufacturer: []string{"MegaCorp"}})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Entries, 1) {
+			assert.Equal(t, newName, res.Entries[0].Name)
+			assert.Equal(t, "MegaCorp", res.Entries[0].Manufacturer.Name)
+		}
+	})
+	t.Run("old name and id redirect", func(t *testing.T) {
+		id, _, err := r.Fetch(ctx, oldId)
+		assert.NoError(t, err)
+		assert.Equal(t, newId, id)
+
+		vs, err := r.Versions(ctx, oldName)
+		assert.NoError(t, err)
+		if assert.Len(t, vs, 1) {
+			assert.Equal(t, newId, vs[0].TMID)
+		}
+	})
+}
+
 func TestS3Repo_CheckIntegrity(t *testing.T) {
 
 	ctx := context.Background()
@@ -908,6 +999,179 @@ func TestS3Repo_ListCompletions(t *testing.T) {
 	})
 }
 
+func TestS3Repo_LockIndex(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "s3r")
+	defer os.RemoveAll(temp)
+
+	c := getS3Mock(t, temp)
+	r := S3Repo{bucket: bucket, client: c}
+	ctx := context.Background()
+	lockObject := filepath.Join(temp, toBucketObject(r.indexFilename()+".lock"))
+
+	t.Run("lock is exclusive", func(t *testing.T) {
+		// given: the index is locked
+		_, unlock, err := r.lockIndex(ctx)
+		assert.NoError(t, err)
+		assert.FileExists(t, lockObject)
+
+		// when: locking the index from another repo instance
+		r2 := S3Repo{bucket: bucket, client: c}
+		tCtx, cancel := context.WithTimeout(ctx, 3*s3LockRetryDelay)
+		defer cancel()
+		_, unlock2, err := r2.lockIndex(tCtx)
+		unlock2()
+		// then: the lock cannot be acquired
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.FileExists(t, lockObject)
+
+		// when: the first lock is released
+		unlock()
+		// then: the lock object is removed
+		assert.NoFileExists(t, lockObject)
+		// and then: the lock can be acquired again
+		_, unlock2, err = r2.lockIndex(ctx)
+		assert.NoError(t, err)
+		unlock2()
+	})
+
+	t.Run("stale lock is taken over", func(t *testing.T) {
+		// given: a lock, whose lease has expired
+		stale, _ := json.Marshal(s3Lease{Owner: "crashed", Expires: time.Now().Add(-time.Second)})
+		assert.NoError(t, os.WriteFile(lockObject, stale, defaultFilePermissions))
+
+		// when: locking the index
+		_, unlock, err := r.lockIndex(ctx)
+		// then: the lock is acquired
+		assert.NoError(t, err)
+		b, _ := os.ReadFile(lockObject)
+		var l s3Lease
+		assert.NoError(t, json.Unmarshal(b, &l))
+		assert.NotEqual(t, "crashed", l.Owner)
+		assert.True(t, l.Expires.After(time.Now()))
+		unlock()
+		assert.NoFileExists(t, lockObject)
+	})
+
+	t.Run("lock taken over by another instance is not removed on unlock", func(t *testing.T) {
+		_, unlock, err := r.lockIndex(ctx)
+		assert.NoError(t, err)
+		// given: the lease has been taken over by someone else
+		other, _ := json.Marshal(s3Lease{Owner: "other", Expires: time.Now().Add(time.Minute)})
+		assert.NoError(t, os.WriteFile(lockObject, other, defaultFilePermissions))
+
+		unlock()
+
+		b, err := os.ReadFile(lockObject)
+		assert.NoError(t, err)
+		assert.Equal(t, other, b)
+	})
+
+	t.Run("lease is renewed while the lock is held", func(t *testing.T) {
+		_ = os.Remove(lockObject)
+		defer func(d time.Duration) { s3LockLeaseDuration = d }(s3LockLeaseDuration)
+		s3LockLeaseDuration = 300 * time.Millisecond
+
+		_, unlock, err := r.lockIndex(ctx)
+		assert.NoError(t, err)
+		// when: holding the lock longer than the lease duration
+		time.Sleep(2 * s3LockLeaseDuration)
+		// then: the lease has not expired
+		b, _, err := s3ReadObjectWithETag(ctx, c, bucket, r.indexFilename()+".lock")
+		assert.NoError(t, err)
+		var l s3Lease
+		assert.NoError(t, json.Unmarshal(b, &l))
+		assert.True(t, l.Expires.After(time.Now()))
+		// and then: the lock cannot be taken over by another instance
+		r2 := S3Repo{bucket: bucket, client: c}
+		tCtx, cancel := context.WithTimeout(ctx, 3*s3LockRetryDelay)
+		defer cancel()
+		_, unlock2, err := r2.lockIndex(tCtx)
+		unlock2()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		unlock()
+		assert.NoFileExists(t, lockObject)
+	})
+
+	t.Run("operation is aborted when the lease is taken over", func(t *testing.T) {
+		_ = os.Remove(lockObject)
+		defer func(d time.Duration) { s3LockLeaseDuration = d }(s3LockLeaseDuration)
+		s3LockLeaseDuration = 300 * time.Millisecond
+
+		lCtx, unlock, err := r.lockIndex(ctx)
+		assert.NoError(t, err)
+		// given: the lease is taken over by someone else while the lock is held
+		other, _ := json.Marshal(s3Lease{Owner: "other", Expires: time.Now().Add(time.Minute)})
+		assert.NoError(t, s3WriteObject(ctx, c, bucket, r.indexFilename()+".lock", other))
+
+		// when: the lease is due for renewal
+		select {
+		case <-lCtx.Done():
+		case <-time.After(2 * s3LockLeaseDuration):
+			assert.Fail(t, "context of the lock has not been cancelled")
+		}
+		// then: the operation's context is cancelled because of the lost lease
+		assert.ErrorIs(t, context.Cause(lCtx), ErrS3LeaseLost)
+		// and then: the index is not written anymore
+		_, err = r.updateIndex(lCtx, r.indexUpdaterForIds())
+		assert.ErrorIs(t, err, ErrS3LeaseLost)
+		assert.NoFileExists(t, filepath.Join(temp, toBucketObject(r.indexFilename())))
+
+		unlock()
+		b, err := os.ReadFile(lockObject)
+		assert.NoError(t, err)
+		assert.Equal(t, other, b)
+	})
+}
+
+func TestS3Repo_UpdateIndex_ConcurrentModification(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "s3r")
+	defer os.RemoveAll(temp)
+	assert.NoError(t, prepareS3MockBucket("../../test/data/index", temp))
+
+	c := getS3Mock(t, temp)
+	r := S3Repo{bucket: bucket, client: c}
+	ctx := context.Background()
+	id1 := "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"
+	id2 := "omnicorp-tm-department/omnicorp/omnilamp/subfolder/v0.0.0-20240409155220-80424c65e4e6.tm.json"
+	assert.NoError(t, r.Index(ctx, id1))
+
+	// given: an updater, which simulates another writer updating the index after we have read it, but before we write it
+	calls := 0
+	concurrentWrite := func(ctx context.Context, oldIndex *model.Index, oldNames []string) (*model.Index, []string, int, error) {
+		calls++
+		if calls == 1 {
+			other := S3Repo{bucket: bucket, client: c}
+			assert.NoError(t, other.Index(ctx, id2))
+		}
+		return r.indexUpdaterForIds(id1)(ctx, oldIndex, oldNames)
+	}
+
+	// when: updating the index
+	_, err := r.updateIndex(ctx, concurrentWrite)
+	// then: the update is retried on top of the concurrently written index
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	r.idx = nil
+	idx, err := r.readIndex(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, idx.FindByTMID(id1))
+	assert.NotNil(t, idx.FindByTMID(id2))
+}
+
+// failingPutS3Client fails to write the object with given key
+type failingPutS3Client struct {
+	S3Client
+	key string
+}
+
+func (c *failingPutS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if *params.Key == c.key {
+		return nil, &smithy.GenericAPIError{Code: "InternalError"}
+	}
+	return c.S3Client.PutObject(ctx, params, optFns...)
+}
+
 func getS3Mock(t *testing.T, filePath string) *s3mocks.S3Client {
 	c := s3mocks.NewS3Client(t)
 
@@ -944,18 +1208,26 @@ func getS3Mock(t *testing.T, filePath string) *s3mocks.S3Client {
 			return &s3.ListObjectsV2Output{Contents: s3Objects}, nil
 		}).Maybe()
 
+	var mu sync.Mutex // makes conditional writes atomic and reads consistent with them
 	c.On("PutObject", mock.Anything, mock.Anything).Return(
 		func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
 			fName := toBucketObject(*params.Key)
+			if err := checkS3MockPrecondition(filepath.Join(filePath, fName), params.IfMatch, params.IfNoneMatch); err != nil {
+				return nil, err
+			}
 
 			buf := new(bytes.Buffer)
 			buf.ReadFrom(params.Body)
 			testutils.CreateFile(filePath, fName, buf.Bytes())
-			return &s3.PutObjectOutput{}, nil
+			return &s3.PutObjectOutput{ETag: aws.String(s3MockETag(buf.Bytes()))}, nil
 		}).Maybe()
 
 	c.On("GetObject", mock.Anything, mock.Anything).Return(
 		func(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
 			fName := filepath.Join(filePath, toBucketObject(*params.Key))
 
 			_, b, err := utils.ReadRequiredFile(fName)
@@ -963,12 +1235,17 @@ func getS3Mock(t *testing.T, filePath string) *s3mocks.S3Client {
 				msg := "Object not found"
 				return nil, &types.NoSuchKey{Message: &msg}
 			}
-			return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewBuffer(b))}, nil
+			return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewBuffer(b)), ETag: aws.String(s3MockETag(b))}, nil
 		}).Maybe()
 
 	c.On("DeleteObject", mock.Anything, mock.Anything).Return(
 		func(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
 			fName := filepath.Join(filePath, toBucketObject(*params.Key))
+			if err := checkS3MockPrecondition(fName, params.IfMatch, nil); err != nil {
+				return nil, err
+			}
 
 			err := os.Remove(fName)
 			if err != nil {
@@ -995,6 +1272,22 @@ func getS3Mock(t *testing.T, filePath string) *s3mocks.S3Client {
 	return c
 }
 
+func s3MockETag(content []byte) string {
+	return fmt.Sprintf("\"%x\"", md5.Sum(content))
+}
+
+func checkS3MockPrecondition(fName string, ifMatch, ifNoneMatch *string) error {
+	b, err := os.ReadFile(fName)
+	exists := err == nil
+	if ifNoneMatch != nil && *ifNoneMatch == "*" && exists {
+		return &smithy.GenericAPIError{Code: "PreconditionFailed"}
+	}
+	if ifMatch != nil && (!exists || s3MockETag(b) != *ifMatch) {
+		return &smithy.GenericAPIError{Code: "PreconditionFailed"}
+	}
+	return nil
+}
+
 func prepareS3MockBucket(fromDir, toDir string) error {
 	err := filepath.Walk(fromDir, func(p string, info os.FileInfo, err error) error {
 		if info.IsDir() {
@@ -19,6 +19,7 @@ import (
 
 const (
 	headerContentType = "Content-Type"
+	headerIfMatch     = "If-Match"
 	mimeJSON          = "application/json"
 	tmNamePath        = ".tmName"
 )
@@ -43,10 +44,27 @@ func NewTmcRepo(config map[string]any, spec model.RepoSpec) (*TmcRepo, error) {
 	return r, nil
 }
 
+// ValidationFiles returns no files, because the remote TMC enforces the validation rules of its repositories itself on import
+func (t *TmcRepo) ValidationFiles(ctx context.Context) (map[string][]byte, error) {
+	return map[string][]byte{}, nil
+}
+
 func (t *TmcRepo) FetchAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) ([]byte, error) {
+	reqUrl := t.attachmentUrl(container, attachmentName)
+	return t.fetchAttachment(ctx, reqUrl.String())
+}
+
+func (t *TmcRepo) attachmentUrl(container model.AttachmentContainerRef, attachmentName string) *url.URL {
 	reqUrl := t.parsedRoot.JoinPath("thing-models", getContainerPath(container), model.AttachmentsDir, attachmentName)
 	t.addRepoParam(reqUrl)
-	return t.fetchAttachment(ctx, reqUrl.String())
+	return reqUrl
+}
+
+// setIfMatch sets the If-Match header of req to ifMatch, unless it's empty
+func setIfMatch(req *http.Request, ifMatch string) {
+	if ifMatch != "" {
+		req.Header.Set(headerIfMatch, ifMatch)
+	}
 }
 
 func (t *TmcRepo) addRepoParam(u *url.URL) {
@@ -69,13 +87,13 @@ func getContainerPath(ref model.AttachmentContainerRef) string {
 	}
 }
 
-func (t *TmcRepo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) error {
-	reqUrl := t.parsedRoot.JoinPath("thing-models", getContainerPath(container), model.AttachmentsDir, attachmentName)
-	t.addRepoParam(reqUrl)
+func (t *TmcRepo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName, ifMatch string) error {
+	reqUrl := t.attachmentUrl(container, attachmentName)
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, reqUrl.String(), nil)
 	if err != nil {
 		return err
 	}
+	setIfMatch(req, ifMatch)
 	resp, err := t.doHttp(req)
 	if err != nil {
 		return err
@@ -98,12 +116,66 @@ func (t *TmcRepo) DeleteAttachment(ctx context.Context, container model.Attachme
 			code = *e.Code
 		}
 		return model.NewErrNotFound(code)
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusUnauthorized, http.StatusInternalServerError:
+		return newErrorFromResponse(b)
+	default:
+		return errors.New(fmt.Sprintf("received unexpected HTTP response from remote TM catalog: %s", resp.Status))
+	}
+
+}
+
+func (t *TmcRepo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) error {
+	reqUrl := t.parsedRoot.JoinPath("thing-models", id, ".lifecycle")
+	t.addRepoParam(reqUrl)
+	body := server.TMLifecycle{State: server.TMLifecycleState(lc.State)}
+	if lc.Reason != "" {
+		body.Reason = &lc.Reason
+	}
+	if lc.Successor != "" {
+		body.Successor = &lc.Successor
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, reqUrl.String(), bytes.NewBuffer(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(headerContentType, mimeJSON)
+	resp, err := t.doHttp(req)
+	if err != nil {
+		return err
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return model.ErrTMNotFound
+	case http.StatusBadRequest:
+		return fmt.Errorf("%w: %w", model.ErrInvalidLifecycle, newErrorFromResponse(b))
 	case http.StatusUnauthorized, http.StatusInternalServerError:
 		return newErrorFromResponse(b)
 	default:
 		return errors.New(fmt.Sprintf("received unexpected HTTP response from remote TM catalog: %s", resp.Status))
 	}
+}
+
+// UpdateLabels is not supported, because the REST API only allows filtering by labels, not managing them
+func (t *TmcRepo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) error {
+	return ErrNotSupported
+}
 
+// Move is not supported, because the REST API does not offer moving TM names
+func (t *TmcRepo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) error {
+	return ErrNotSupported
 }
 
 func (t *TmcRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, error) {
@@ -144,19 +216,20 @@ func (t *TmcRepo) GetTMMetadata(ctx context.Context, tmID string) ([]model.Found
 	}
 }
 
-func (t *TmcRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool) error {
-	reqUrl := t.parsedRoot.JoinPath("thing-models", getContainerPath(container), model.AttachmentsDir, attachment.Name)
+func (t *TmcRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) error {
+	attUrl := t.attachmentUrl(container, attachment.Name)
+	reqUrl := *attUrl
 	vals := reqUrl.Query()
 	if force {
 		vals["force"] = []string{"true"}
 	}
 	reqUrl.RawQuery = vals.Encode()
-	t.addRepoParam(reqUrl)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPut, reqUrl.String(), bytes.NewBuffer(content))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", attachment.MediaType)
+	setIfMatch(req, ifMatch)
 	resp, err := t.doHttp(req)
 	if err != nil {
 		return err
@@ -181,6 +254,8 @@ func (t *TmcRepo) ImportAttachment(ctx context.Context, container model.Attachme
 		return model.ErrInvalidIdOrName
 	case http.StatusConflict:
 		return ErrAttachmentExists
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
 	case http.StatusUnauthorized, http.StatusInternalServerError:
 		return newErrorFromResponse(b)
 	default:
@@ -288,6 +363,9 @@ func (t *TmcRepo) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	if tmID, err := model.ParseTMID(id); err == nil {
+		req.Header.Set(headerIfMatch, `"`+tmID.Version.Hash+`"`)
+	}
 	resp, err := t.doHttp(req)
 	if err != nil {
 		return err
@@ -304,7 +382,8 @@ func (t *TmcRepo) Delete(ctx context.Context, id string) error {
 		// there are two reasons why we could receive a 400 response: invalid 'force' flag or invalid id
 		// we're sure that we've passed a valid 'force' flag, so it must be the id
 		return model.ErrInvalidId
-	case http.StatusNotFound:
+	case http.StatusNotFound, http.StatusPreconditionFailed:
+		// the content of a TM is given by its id, so the precondition can only fail when the TM does not exist
 		return model.ErrTMNotFound
 	case http.StatusInternalServerError, http.StatusUnauthorized:
 		err := newErrorFromResponse(b)
@@ -415,6 +494,7 @@ func addFilters(u *url.URL, search *model.Filters) {
 	appendQueryArray(u, "filter.manufacturer", search.Manufacturer)
 	appendQueryArray(u, "filter.mpn", search.Mpn)
 	appendQueryArray(u, "filter.protocol", search.Protocol)
+	appendQueryArray(u, "filter.label", search.Label)
 }
 
 func appendQueryArray(u *url.URL, key string, values []string) {
@@ -155,10 +155,11 @@ func TestTmcRepo_List(t *testing.T) {
 				Manufacturer: []string{"manuf1", "man&uf2"},
 				Mpn:          []string{"mpn"},
 				Protocol:     []string{"http,https"},
+				Label:        []string{"certified", "beta"},
 				Name:         "autho",
 				Options:      model.FilterOptions{NameFilterType: model.PrefixMatch},
 			},
-			expUrl: "/inventory?filter.name=autho&filter.author=author1%2Cauthor2&filter.manufacturer=manuf1%2Cman%26uf2&filter.mpn=mpn&filter.protocol=http%2Chttps&repo=child",
+			expUrl: "/inventory?filter.name=autho&filter.author=author1%2Cauthor2&filter.manufacturer=manuf1%2Cman%26uf2&filter.mpn=mpn&filter.protocol=http%2Chttps&filter.label=certified%2Cbeta&repo=child",
 			expErr: "",
 			expRes: 3,
 		},
@@ -594,7 +595,7 @@ func TestTmcRepo_DeleteAttachment(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			htc <- test
-			err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(test.tmNameOrId), "README.md")
+			err := r.DeleteAttachment(context.Background(), model.NewTMIDAttachmentContainerRef(test.tmNameOrId), "README.md", "")
 			if test.expErr == "" {
 				assert.NoError(t, err)
 			} else {
@@ -603,6 +604,77 @@ func TestTmcRepo_DeleteAttachment(t *testing.T) {
 		})
 	}
 }
+func TestTmcRepo_SetLifecycle(t *testing.T) {
+	type ht struct {
+		name   string
+		body   []byte
+		status int
+		expErr string
+	}
+	id := "omniauthor/omnicorp/senseall/v0.35.0-20231230153548-243d1b462bbb.tm.json"
+	htc := make(chan ht, 1)
+	defer close(htc)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := <-htc
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/thing-models/"+id+"/.lifecycle", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get(headerContentType))
+		b, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"state":"deprecated","reason":"wrong unit"}`, string(b))
+		w.WriteHeader(h.status)
+		_, _ = w.Write(h.body)
+	}))
+	defer srv.Close()
+
+	config, err := createTmcRepoConfig([]byte(`{"loc":"` + srv.URL + `"}`))
+	assert.NoError(t, err)
+	r, err := NewTmcRepo(config, model.NewRepoSpec("nameless"))
+	assert.NoError(t, err)
+
+	tests := []ht{
+		{
+			name:   "ok",
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "bad request",
+			body:   []byte(`{"detail":"invalid successor"}`),
+			status: http.StatusBadRequest,
+			expErr: "invalid lifecycle: invalid successor",
+		},
+		{
+			name:   "not found",
+			body:   []byte(`{"detail":"TM not found", "code": "TM"}`),
+			status: http.StatusNotFound,
+			expErr: "TM not found",
+		},
+		{
+			name:   "internal server error",
+			body:   []byte(`{"detail":"something bad happened"}`),
+			status: http.StatusInternalServerError,
+			expErr: "something bad happened",
+		},
+		{
+			name:   "unexpected status",
+			body:   []byte(`{"detail":"no coffee for you"}`),
+			status: http.StatusTeapot,
+			expErr: "received unexpected HTTP response",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			htc <- test
+			err := r.SetLifecycle(context.Background(), id, model.Lifecycle{State: model.LifecycleDeprecated, Reason: "wrong unit"})
+			if test.expErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expErr)
+			}
+		})
+	}
+}
+
 func TestTmcRepo_ImportAttachment(t *testing.T) {
 	type ht struct {
 		name    string
@@ -717,7 +789,7 @@ func TestTmcRepo_ImportAttachment(t *testing.T) {
 			} else {
 				ref = model.NewTMNameAttachmentContainerRef(test.tmName)
 			}
-			err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: "README.md"}, test.reqBody, false)
+			err := r.ImportAttachment(context.Background(), ref, model.Attachment{Name: "README.md"}, test.reqBody, false, "")
 			if test.expErr == "" {
 				assert.NoError(t, err)
 			} else {
@@ -898,6 +970,7 @@ func TestTmcRepo_Delete(t *testing.T) {
 		id       string
 		status   int
 		respBody []byte
+		ifMatch  string
 		expErr   error
 	}
 	htc := make(chan ht, 1)
@@ -907,6 +980,7 @@ func TestTmcRepo_Delete(t *testing.T) {
 		assert.Equal(t, http.MethodDelete, r.Method)
 		assert.Equal(t, "/thing-models/"+h.id, r.URL.Path)
 		assert.Equal(t, url.Values{"force": []string{"true"}}, r.URL.Query())
+		assert.Equal(t, h.ifMatch, r.Header.Get("If-Match"))
 		w.WriteHeader(h.status)
 		_, _ = w.Write(h.respBody)
 	}))
@@ -927,30 +1001,42 @@ func TestTmcRepo_Delete(t *testing.T) {
 		},
 		{
 			name:     "non-existing id",
-			id:       "omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
+			id:       "omnicorp/omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
 			status:   http.StatusNotFound,
 			respBody: []byte(`{"detail":"TM not found", "code": "TM"}`),
+			ifMatch:  `"c81be4ed973d"`,
+			expErr:   model.ErrTMNotFound,
+		},
+		{
+			name:     "precondition failed",
+			id:       "omnicorp/omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
+			status:   http.StatusPreconditionFailed,
+			respBody: []byte(`{"detail":"resource has been modified concurrently"}`),
+			ifMatch:  `"c81be4ed973d"`,
 			expErr:   model.ErrTMNotFound,
 		},
 		{
 			name:     "existing id",
-			id:       "omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
+			id:       "omnicorp/omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
 			status:   http.StatusNoContent,
 			respBody: nil,
+			ifMatch:  `"c81be4ed973d"`,
 			expErr:   nil,
 		},
 		{
 			name:     "internal error",
-			id:       "omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
+			id:       "omnicorp/omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
 			status:   http.StatusInternalServerError,
 			respBody: []byte(`{"detail":"something bad happened"}`),
+			ifMatch:  `"c81be4ed973d"`,
 			expErr:   errors.New("something bad happened"),
 		},
 		{
 			name:     "unexpected status",
-			id:       "omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
+			id:       "omnicorp/omnicorp/lightall/v1.0.1-20240104165612-c81be4ed973d.tm.json",
 			status:   http.StatusTeapot,
 			respBody: nil,
+			ifMatch:  `"c81be4ed973d"`,
 			expErr:   errors.New("received unexpected HTTP response from remote TM catalog: 418 I'm a teapot"),
 		},
 	}
@@ -969,6 +1055,50 @@ func TestTmcRepo_Delete(t *testing.T) {
 	}
 }
 
+func TestTmcRepo_AttachmentIfMatch(t *testing.T) {
+	const etag = `"5eb63bbbe01eeed093cb22bb"`
+	var ifMatch string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = r.Header.Get("If-Match")
+		if ifMatch != "" && ifMatch != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	config, err := createTmcRepoConfig([]byte(`{"loc":"` + srv.URL + `"}`))
+	assert.NoError(t, err)
+	r, err := NewTmcRepo(config, model.NewRepoSpec("nameless"))
+	assert.NoError(t, err)
+	ref := model.NewTMNameAttachmentContainerRef("author/manufacturer/mpn")
+	att := model.Attachment{Name: "README.md", MediaType: "text/markdown"}
+
+	t.Run("without entity tag", func(t *testing.T) {
+		err := r.ImportAttachment(context.Background(), ref, att, []byte("new"), true, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "", ifMatch)
+		err = r.DeleteAttachment(context.Background(), ref, att.Name, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "", ifMatch)
+	})
+	t.Run("with matching entity tag", func(t *testing.T) {
+		err := r.ImportAttachment(context.Background(), ref, att, []byte("new"), true, etag)
+		assert.NoError(t, err)
+		assert.Equal(t, etag, ifMatch)
+		err = r.DeleteAttachment(context.Background(), ref, att.Name, etag)
+		assert.NoError(t, err)
+		assert.Equal(t, etag, ifMatch)
+	})
+	t.Run("with outdated entity tag", func(t *testing.T) {
+		err := r.ImportAttachment(context.Background(), ref, att, []byte("new"), true, `"outdated"`)
+		assert.ErrorIs(t, err, ErrPreconditionFailed)
+		err = r.DeleteAttachment(context.Background(), ref, att.Name, `"outdated"`)
+		assert.ErrorIs(t, err, ErrPreconditionFailed)
+	})
+}
+
 func TestTmcRepo_CheckIntegrity(t *testing.T) {
 	// given: a TMC Repo
 	config, err := createTmcRepoConfig([]byte(`{"loc":"http://example.com"}`))
@@ -0,0 +1,143 @@
+package repos
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/tracing"
+)
+
+const (
+	attrTMID       = attribute.Key("tmc.tm_id")
+	attrTMName     = attribute.Key("tmc.tm_name")
+	attrAttachment = attribute.Key("tmc.attachment")
+)
+
+// TracingRepo is a Repo which records a span for each call of a method of the wrapped repo
+type TracingRepo struct {
+	Repo
+}
+
+// withTracing wraps r into a TracingRepo, if tracing has been set up. Returns r otherwise
+func withTracing(r Repo) Repo {
+	if !tracing.Enabled() {
+		return r
+	}
+	return &TracingRepo{Repo: r}
+}
+
+func (t *TracingRepo) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, tracing.AttrRepo.String(metricsRepoLabel(t.Spec())))
+	return tracing.Start(ctx, "Repo."+op, trace.WithAttributes(attrs...))
+}
+
+func containerAttrs(ref model.AttachmentContainerRef) []attribute.KeyValue {
+	if ref.TMID != "" {
+		return []attribute.KeyValue{attrTMID.String(ref.TMID)}
+	}
+	return []attribute.KeyValue{attrTMName.String(ref.TMName)}
+}
+
+func (t *TracingRepo) Import(ctx context.Context, id model.TMID, raw []byte, opts ImportOptions) (res ImportResult, err error) {
+	ctx, span := t.start(ctx, "Import", attrTMID.String(id.String()))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.Import(ctx, id, raw, opts)
+}
+
+func (t *TracingRepo) Fetch(ctx context.Context, id string) (fid string, raw []byte, err error) {
+	ctx, span := t.start(ctx, "Fetch", attrTMID.String(id))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.Fetch(ctx, id)
+}
+
+func (t *TracingRepo) Index(ctx context.Context, updatedIds ...string) (err error) {
+	ctx, span := t.start(ctx, "Index")
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.Index(ctx, updatedIds...)
+}
+
+func (t *TracingRepo) CheckIntegrity(ctx context.Context, filter model.ResourceFilter) (results []model.CheckResult, err error) {
+	ctx, span := t.start(ctx, "CheckIntegrity")
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.CheckIntegrity(ctx, filter)
+}
+
+func (t *TracingRepo) List(ctx context.Context, search *model.Filters) (res model.SearchResult, err error) {
+	ctx, span := t.start(ctx, "List")
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.List(ctx, search)
+}
+
+func (t *TracingRepo) Versions(ctx context.Context, name string) (vers []model.FoundVersion, err error) {
+	ctx, span := t.start(ctx, "Versions", attrTMName.String(name))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.Versions(ctx, name)
+}
+
+func (t *TracingRepo) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := t.start(ctx, "Delete", attrTMID.String(id))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.Delete(ctx, id)
+}
+
+func (t *TracingRepo) ListCompletions(ctx context.Context, kind string, args []string, toComplete string) (res []string, err error) {
+	ctx, span := t.start(ctx, "ListCompletions")
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.ListCompletions(ctx, kind, args, toComplete)
+}
+
+func (t *TracingRepo) GetTMMetadata(ctx context.Context, tmID string) (vers []model.FoundVersion, err error) {
+	ctx, span := t.start(ctx, "GetTMMetadata", attrTMID.String(tmID))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.GetTMMetadata(ctx, tmID)
+}
+
+func (t *TracingRepo) ImportAttachment(ctx context.Context, container model.AttachmentContainerRef, attachment model.Attachment, content []byte, force bool, ifMatch string) (err error) {
+	ctx, span := t.start(ctx, "ImportAttachment", append(containerAttrs(container), attrAttachment.String(attachment.Name))...)
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.ImportAttachment(ctx, container, attachment, content, force, ifMatch)
+}
+
+func (t *TracingRepo) FetchAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName string) (content []byte, err error) {
+	ctx, span := t.start(ctx, "FetchAttachment", append(containerAttrs(container), attrAttachment.String(attachmentName))...)
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.FetchAttachment(ctx, container, attachmentName)
+}
+
+func (t *TracingRepo) DeleteAttachment(ctx context.Context, container model.AttachmentContainerRef, attachmentName, ifMatch string) (err error) {
+	ctx, span := t.start(ctx, "DeleteAttachment", append(containerAttrs(container), attrAttachment.String(attachmentName))...)
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.DeleteAttachment(ctx, container, attachmentName, ifMatch)
+}
+
+func (t *TracingRepo) SetLifecycle(ctx context.Context, id string, lc model.Lifecycle) (err error) {
+	ctx, span := t.start(ctx, "SetLifecycle", attrTMID.String(id))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.SetLifecycle(ctx, id, lc)
+}
+
+func (t *TracingRepo) UpdateLabels(ctx context.Context, ref model.AttachmentContainerRef, add, remove []string) (err error) {
+	ctx, span := t.start(ctx, "UpdateLabels", containerAttrs(ref)...)
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.UpdateLabels(ctx, ref, add, remove)
+}
+
+func (t *TracingRepo) Move(ctx context.Context, oldName, newName string, opts MoveOptions) (err error) {
+	ctx, span := t.start(ctx, "Move", attrTMName.String(oldName))
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.Move(ctx, oldName, newName, opts)
+}
+
+func (t *TracingRepo) ValidationFiles(ctx context.Context) (files map[string][]byte, err error) {
+	ctx, span := t.start(ctx, "ValidationFiles")
+	defer func() { tracing.End(span, err) }()
+	return t.Repo.ValidationFiles(ctx)
+}
+
+// Unwrap returns the repo, which does not record spans
+func (t *TracingRepo) Unwrap() Repo {
+	return t.Repo
+}
@@ -0,0 +1,74 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/testutils"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+func TestWithTracing(t *testing.T) {
+	r := &FileRepo{root: t.TempDir(), spec: model.NewRepoSpec("traced")}
+
+	t.Run("tracing disabled", func(t *testing.T) {
+		assert.Equal(t, r, withTracing(r))
+	})
+	t.Run("tracing enabled", func(t *testing.T) {
+		stop := testutils.SetupTracing(t)
+		res := withTracing(r)
+		if assert.IsType(t, &TracingRepo{}, res) {
+			assert.Equal(t, r, Unwrap(res))
+		}
+
+		_, _, err, errs := NewUnion(res).Fetch(context.Background(), "author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json")
+		assert.ErrorIs(t, err, model.ErrTMNotFound)
+		assert.Len(t, errs, 0)
+
+		spans := stop()
+		if assert.Len(t, spans, 2) {
+			assert.Equal(t, "Repo.Fetch", spans[0].Name)
+			assert.Equal(t, "traced", spans[0].Attr("tmc.repo"))
+			assert.Equal(t, "author/manufacturer/mpn/v1.0.0-20240101000000-a1b2c3d4e5f6.tm.json", spans[0].Attr("tmc.tm_id"))
+			assert.Equal(t, "Union.Fetch", spans[1].Name)
+			assert.Equal(t, spans[1].SpanContext.SpanID, spans[0].Parent.SpanID)
+		}
+	})
+}
+
+func TestTmcRepo_PropagatesTraceContext(t *testing.T) {
+	_, inventory, _ := utils.ReadRequiredFile("../../test/data/repos/inventory_response.json")
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_, _ = w.Write(inventory)
+	}))
+	defer srv.Close()
+	config, err := createTmcRepoConfig([]byte(`{"loc":"` + srv.URL + `"}`))
+	assert.NoError(t, err)
+	r, err := NewTmcRepo(config, model.NewRepoSpec("remote"))
+	assert.NoError(t, err)
+
+	stop := testutils.SetupTracing(t)
+	_, err = withTracing(r).List(context.Background(), nil)
+	assert.NoError(t, err)
+
+	spans := stop()
+	if assert.Len(t, spans, 2) {
+		client, list := spans[0], spans[1]
+		assert.Equal(t, "HTTP GET", client.Name)
+		assert.Equal(t, "Repo.List", list.Name)
+		assert.Equal(t, list.SpanContext.SpanID, client.Parent.SpanID)
+		// traceparent: version-traceID-parentID-flags
+		parts := strings.Split(traceparent, "-")
+		if assert.Len(t, parts, 4) {
+			assert.Equal(t, client.SpanContext.TraceID, parts[1])
+			assert.Equal(t, client.SpanContext.SpanID, parts[2])
+		}
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/tracing"
 	"github.com/wot-oss/tmc/internal/utils"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Union struct {
@@ -63,7 +65,7 @@ func (u *Union) Fetch(ctx context.Context, id string) (string, []byte, error, []
 		err error
 	}
 
-	mapper := func(r Repo) mapResult[fetchRes] {
+	mapper := func(ctx context.Context, r Repo) mapResult[fetchRes] {
 		fid, thing, err := r.Fetch(ctx, id)
 		res := fetchRes{id: fid, b: thing, err: err}
 		if errors.Is(err, model.ErrTMNotFound) {
@@ -74,7 +76,7 @@ func (u *Union) Fetch(ctx context.Context, id string) (string, []byte, error, []
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	results := mapConcurrent(ctx, u.rs, mapper)
+	results := mapConcurrent(ctx, "Fetch", u.rs, mapper)
 	res := fetchRes{err: model.ErrTMNotFound}
 	res, errs := reduce(results, res, func(r1, r2 fetchRes) fetchRes {
 		if r1.err == nil {
@@ -94,7 +96,7 @@ func (u *Union) Fetch(ctx context.Context, id string) (string, []byte, error, []
 }
 
 func (u *Union) Search(ctx context.Context, query string) (model.SearchResult, []*RepoAccessError) {
-	mapper := func(r Repo) mapResult[*model.SearchResult] {
+	mapper := func(ctx context.Context, r Repo) mapResult[*model.SearchResult] {
 		searchResult, err := searchRepo(ctx, r, query)
 		return mapResult[*model.SearchResult]{res: &searchResult, err: newRepoAccessError(r, err)}
 	}
@@ -104,13 +106,13 @@ func (u *Union) Search(ctx context.Context, query string) (model.SearchResult, [
 		return t1
 	}
 
-	results := mapConcurrent(ctx, u.rs, mapper)
+	results := mapConcurrent(ctx, "Search", u.rs, mapper)
 	r, errs := reduce(results, &model.SearchResult{}, reducer)
 	return *r, errs
 }
 
 func (u *Union) List(ctx context.Context, search *model.Filters) (model.SearchResult, []*RepoAccessError) {
-	mapper := func(r Repo) mapResult[*model.SearchResult] {
+	mapper := func(ctx context.Context, r Repo) mapResult[*model.SearchResult] {
 		searchResult, err := r.List(ctx, search)
 		return mapResult[*model.SearchResult]{res: &searchResult, err: newRepoAccessError(r, err)}
 	}
@@ -120,7 +122,7 @@ func (u *Union) List(ctx context.Context, search *model.Filters) (model.SearchRe
 		return t1
 	}
 
-	results := mapConcurrent(ctx, u.rs, mapper)
+	results := mapConcurrent(ctx, "List", u.rs, mapper)
 	r, errs := reduce(results, &model.SearchResult{}, reducer)
 	return *r, errs
 }
@@ -172,7 +174,7 @@ func updateBleveIndexIfOutdated(ctx context.Context, r Repo, indexPath string, l
 }
 
 func (u *Union) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVersion, []*RepoAccessError) {
-	mapper := func(r Repo) mapResult[[]model.FoundVersion] {
+	mapper := func(ctx context.Context, r Repo) mapResult[[]model.FoundVersion] {
 		vers, err := r.GetTMMetadata(ctx, tmID)
 		if errors.Is(err, model.ErrTMNotFound) {
 			return mapResult[[]model.FoundVersion]{res: nil, err: nil}
@@ -180,7 +182,7 @@ func (u *Union) GetTMMetadata(ctx context.Context, tmID string) ([]model.FoundVe
 		return mapResult[[]model.FoundVersion]{res: vers, err: newRepoAccessError(r, err)}
 	}
 	var ident []model.FoundVersion
-	results := mapConcurrent(ctx, u.rs, mapper)
+	results := mapConcurrent(ctx, "GetTMMetadata", u.rs, mapper)
 	res, errs := reduce(results, ident, model.MergeFoundVersions)
 	return res, errs
 }
@@ -193,14 +195,18 @@ func reduce[T any](ch <-chan mapResult[T], identity T, reducer func(t1, t2 T) T)
 		accumulator = reducer(accumulator, res.res)
 		if res.err != nil {
 			errs = append(errs, res.err)
+			if !errors.Is(res.err, context.Canceled) {
+				repoAccessErrors.WithLabelValues(metricsRepoLabel(res.err.spec)).Inc()
+			}
 		}
 	}
 	return accumulator, errs
 }
 
-// mapConcurrent concurrently maps all repo with the mapper to a mapResult.
+// mapConcurrent concurrently maps all repo with the mapper to a mapResult. If tracing is enabled, each mapping is
+// recorded as a span named after op.
 // Returns channel with results
-func mapConcurrent[T any](ctx context.Context, repos []Repo, mapper func(r Repo) mapResult[T]) (results <-chan mapResult[T]) {
+func mapConcurrent[T any](ctx context.Context, op string, repos []Repo, mapper func(ctx context.Context, r Repo) mapResult[T]) (results <-chan mapResult[T]) {
 	res := make(chan mapResult[T])
 	wg := sync.WaitGroup{}
 	wg.Add(len(repos))
@@ -211,7 +217,7 @@ func mapConcurrent[T any](ctx context.Context, repos []Repo, mapper func(r Repo)
 			defer wg.Done()
 			select {
 			case <-ctx.Done():
-			case res <- mapper(r):
+			case res <- tracedMapper(ctx, op, r, mapper):
 			}
 		}(repo)
 	}
@@ -225,8 +231,23 @@ func mapConcurrent[T any](ctx context.Context, repos []Repo, mapper func(r Repo)
 	return res
 }
 
+// tracedMapper calls mapper within a span, if tracing is enabled
+func tracedMapper[T any](ctx context.Context, op string, r Repo, mapper func(ctx context.Context, r Repo) mapResult[T]) mapResult[T] {
+	if !tracing.Enabled() {
+		return mapper(ctx, r)
+	}
+	ctx, span := tracing.Start(ctx, "Union."+op, trace.WithAttributes(tracing.AttrRepo.String(metricsRepoLabel(r.Spec()))))
+	res := mapper(ctx, r)
+	var err error
+	if res.err != nil {
+		err = res.err
+	}
+	tracing.End(span, err)
+	return res
+}
+
 func (u *Union) Versions(ctx context.Context, name string) ([]model.FoundVersion, []*RepoAccessError) {
-	mapper := func(r Repo) mapResult[[]model.FoundVersion] {
+	mapper := func(ctx context.Context, r Repo) mapResult[[]model.FoundVersion] {
 		vers, err := r.Versions(ctx, name)
 		if errors.Is(err, model.ErrTMNameNotFound) {
 			return mapResult[[]model.FoundVersion]{res: vers, err: nil}
@@ -234,13 +255,13 @@ func (u *Union) Versions(ctx context.Context, name string) ([]model.FoundVersion
 		return mapResult[[]model.FoundVersion]{res: vers, err: newRepoAccessError(r, err)}
 	}
 	var ident []model.FoundVersion
-	results := mapConcurrent(ctx, u.rs, mapper)
+	results := mapConcurrent(ctx, "Versions", u.rs, mapper)
 	res, errs := reduce(results, ident, model.MergeFoundVersions)
 	return res, errs
 }
 
 func (u *Union) ListCompletions(ctx context.Context, kind string, args []string, toComplete string) []string {
-	mapper := func(r Repo) mapResult[[]string] {
+	mapper := func(ctx context.Context, r Repo) mapResult[[]string] {
 		rcs, err := r.ListCompletions(ctx, kind, args, toComplete)
 		if err != nil {
 			rcs = nil
@@ -249,7 +270,7 @@ func (u *Union) ListCompletions(ctx context.Context, kind string, args []string,
 	}
 	reducer := func(r1, r2 []string) []string { return append(r1, r2...) }
 	var cs []string
-	results := mapConcurrent(ctx, u.rs, mapper)
+	results := mapConcurrent(ctx, "ListCompletions", u.rs, mapper)
 	res, _ := reduce(results, cs, reducer)
 	slices.Sort(res)
 	return slices.Compact(res)
@@ -0,0 +1,153 @@
+package repos
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/signing"
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	KeyRepoTrustedKeys     = "trusted_keys"
+	KeyRepoSignaturePolicy = "signature_policy"
+
+	SignaturePolicyRequire = "require" // refuse to fetch unsigned TMs and TMs with invalid signatures
+	SignaturePolicyWarn    = "warn"    // fetch unsigned TMs and TMs with invalid signatures, but log a warning
+)
+
+// VerifyingRepo is a Repo which verifies the signatures of fetched TMs with the trusted keys configured for the repo
+type VerifyingRepo struct {
+	Repo
+	keys   []ed25519.PublicKey
+	policy string
+}
+
+// withSignatureVerification wraps r into a VerifyingRepo, if trusted keys are configured in rc. Returns r otherwise
+func withSignatureVerification(r Repo, rc ConfigMap) (Repo, error) {
+	keyFiles, err := trustedKeys(rc)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyFiles) == 0 {
+		return r, nil
+	}
+	keys, err := signing.LoadPublicKeys(keyFiles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", KeyRepoTrustedKeys, err)
+	}
+	policy, found := rc.GetString(KeyRepoSignaturePolicy)
+	if !found {
+		policy = SignaturePolicyRequire
+	}
+	if policy != SignaturePolicyRequire && policy != SignaturePolicyWarn {
+		return nil, fmt.Errorf("invalid %s: %s. Must be one of %s, %s", KeyRepoSignaturePolicy, policy, SignaturePolicyRequire, SignaturePolicyWarn)
+	}
+	return &VerifyingRepo{Repo: r, keys: keys, policy: policy}, nil
+}
+
+func trustedKeys(rc ConfigMap) ([]string, error) {
+	v, found := rc[KeyRepoTrustedKeys]
+	if !found || v == nil {
+		return nil, nil
+	}
+	switch ks := v.(type) {
+	case string:
+		return []string{expandVar(ks)}, nil
+	case []string:
+		var res []string
+		for _, k := range ks {
+			res = append(res, expandVar(k))
+		}
+		return res, nil
+	case []any:
+		var res []string
+		for _, k := range ks {
+			s, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s: must be a list of strings", KeyRepoTrustedKeys)
+			}
+			res = append(res, expandVar(s))
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("invalid %s: must be a list of strings", KeyRepoTrustedKeys)
+	}
+}
+
+// Fetch fetches the TM from the wrapped repo and verifies its signature. Depending on the repo's signature policy,
+// returns an error wrapping signing.ErrUnsigned or signing.ErrInvalidSignature or just logs a warning when the
+// signature cannot be verified
+func (v *VerifyingRepo) Fetch(ctx context.Context, id string) (string, []byte, error) {
+	fid, raw, err := v.Repo.Fetch(ctx, id)
+	if err != nil {
+		return fid, raw, err
+	}
+	_, err = v.Verify(ctx, fid, raw)
+	if err != nil {
+		if v.policy == SignaturePolicyWarn {
+			utils.GetLogger(ctx, "repos.VerifyingRepo.Fetch").Warn("fetched TM with unverified signature", "id", fid, "error", err)
+			return fid, raw, nil
+		}
+		return "", nil, fmt.Errorf("%s: %w", fid, err)
+	}
+	return fid, raw, nil
+}
+
+// Verify verifies the signature of TM with given id and content raw with the repo's trusted keys.
+// Returns the id of the key the signature has been verified with
+func (v *VerifyingRepo) Verify(ctx context.Context, id string, raw []byte) (string, error) {
+	sig, err := v.Repo.FetchAttachment(ctx, model.NewTMIDAttachmentContainerRef(id), model.SignatureAttachmentName)
+	if err != nil {
+		if errors.Is(err, model.ErrAttachmentNotFound) || errors.Is(err, model.ErrTMNotFound) {
+			return "", signing.ErrUnsigned
+		}
+		return "", err
+	}
+	return signing.Verify(raw, sig, v.keys)
+}
+
+// TrustedKeys returns the public keys configured as trusted for the repo
+func (v *VerifyingRepo) TrustedKeys() []ed25519.PublicKey {
+	return v.keys
+}
+
+// Unwrap returns the repo, which does not verify signatures
+func (v *VerifyingRepo) Unwrap() Repo {
+	return v.Repo
+}
+
+// AsVerifyingRepo finds the VerifyingRepo among r and the repos it wraps, if any
+func AsVerifyingRepo(r Repo) (*VerifyingRepo, bool) {
+	for {
+		switch w := r.(type) {
+		case *VerifyingRepo:
+			return w, true
+		case *MeasuringRepo:
+			r = w.Unwrap()
+		case *TracingRepo:
+			r = w.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// Unwrap returns the repo wrapped by r, if r is a VerifyingRepo, a MeasuringRepo or a TracingRepo. Returns r otherwise
+func Unwrap(r Repo) Repo {
+	for {
+		switch w := r.(type) {
+		case *VerifyingRepo:
+			r = w.Unwrap()
+		case *MeasuringRepo:
+			r = w.Unwrap()
+		case *TracingRepo:
+			r = w.Unwrap()
+		default:
+			return r
+		}
+	}
+}
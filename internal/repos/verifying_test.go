@@ -0,0 +1,108 @@
+package repos
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wot-oss/tmc/internal/model"
+	"github.com/wot-oss/tmc/internal/signing"
+	"github.com/wot-oss/tmc/internal/testutils"
+)
+
+func TestWithSignatureVerification(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	pubDer, _ := x509.MarshalPKIXPublicKey(pub)
+	pubPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}))
+	r := &FileRepo{root: "somewhere", spec: model.NewRepoSpec("fr")}
+
+	t.Run("no trusted keys", func(t *testing.T) {
+		res, err := withSignatureVerification(r, ConfigMap{})
+		assert.NoError(t, err)
+		assert.Equal(t, r, res)
+	})
+	t.Run("with trusted keys", func(t *testing.T) {
+		res, err := withSignatureVerification(r, ConfigMap{KeyRepoTrustedKeys: []any{pubPem}})
+		assert.NoError(t, err)
+		if assert.IsType(t, &VerifyingRepo{}, res) {
+			v := res.(*VerifyingRepo)
+			assert.Equal(t, []ed25519.PublicKey{pub}, v.TrustedKeys())
+			assert.Equal(t, SignaturePolicyRequire, v.policy)
+			assert.Equal(t, r, Unwrap(v))
+
+			// wrapped by instrumentation
+			found, ok := AsVerifyingRepo(&MeasuringRepo{Repo: &TracingRepo{Repo: v}})
+			assert.True(t, ok)
+			assert.Same(t, v, found)
+		}
+		_, ok := AsVerifyingRepo(&MeasuringRepo{Repo: r})
+		assert.False(t, ok)
+	})
+	t.Run("with warn policy", func(t *testing.T) {
+		res, err := withSignatureVerification(r, ConfigMap{KeyRepoTrustedKeys: pubPem, KeyRepoSignaturePolicy: SignaturePolicyWarn})
+		assert.NoError(t, err)
+		if assert.IsType(t, &VerifyingRepo{}, res) {
+			assert.Equal(t, SignaturePolicyWarn, res.(*VerifyingRepo).policy)
+		}
+	})
+	t.Run("with invalid policy", func(t *testing.T) {
+		_, err := withSignatureVerification(r, ConfigMap{KeyRepoTrustedKeys: []any{pubPem}, KeyRepoSignaturePolicy: "sometimes"})
+		assert.ErrorContains(t, err, "invalid signature_policy")
+	})
+	t.Run("with invalid keys", func(t *testing.T) {
+		_, err := withSignatureVerification(r, ConfigMap{KeyRepoTrustedKeys: []any{42}})
+		assert.ErrorContains(t, err, "must be a list of strings")
+		_, err = withSignatureVerification(r, ConfigMap{KeyRepoTrustedKeys: []any{"-----BEGIN PUBLIC KEY"}})
+		assert.ErrorIs(t, err, signing.ErrInvalidKey)
+	})
+}
+
+func TestVerifyingRepo_Fetch(t *testing.T) {
+	temp, _ := os.MkdirTemp("", "fr")
+	defer os.RemoveAll(temp)
+	assert.NoError(t, testutils.CopyDir("../../test/data/repos/file/attachments", temp))
+	fr := &FileRepo{root: temp, spec: model.NewRepoSpec("fr")}
+	id := "omnicorp-tm-department/omnicorp/omnilamp/v3.2.1-20240409155220-3f779458e453.tm.json"
+	ctx := context.Background()
+
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	r := &VerifyingRepo{Repo: fr, keys: []ed25519.PublicKey{pub}, policy: SignaturePolicyRequire}
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, _, err := r.Fetch(ctx, id)
+		assert.ErrorIs(t, err, signing.ErrUnsigned)
+	})
+	t.Run("unsigned with warn policy", func(t *testing.T) {
+		wr := &VerifyingRepo{Repo: fr, keys: []ed25519.PublicKey{pub}, policy: SignaturePolicyWarn}
+		fid, raw, err := wr.Fetch(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, fid)
+		assert.NotEmpty(t, raw)
+	})
+
+	_, raw, _ := fr.Fetch(ctx, id)
+	sig, _ := signing.Sign(raw, priv)
+	att := model.Attachment{Name: model.SignatureAttachmentName, MediaType: signing.MediaType}
+	assert.NoError(t, fr.ImportAttachment(ctx, model.NewTMIDAttachmentContainerRef(id), att, sig, false, ""))
+
+	t.Run("signed", func(t *testing.T) {
+		fid, content, err := r.Fetch(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, fid)
+		assert.Equal(t, raw, content)
+	})
+	t.Run("signed with untrusted key", func(t *testing.T) {
+		or := &VerifyingRepo{Repo: fr, keys: []ed25519.PublicKey{otherPub}, policy: SignaturePolicyRequire}
+		_, _, err := or.Fetch(ctx, id)
+		assert.ErrorIs(t, err, signing.ErrInvalidSignature)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := r.Fetch(ctx, "omnicorp-tm-department/omnicorp/omnilamp/v1.0.0-20240409155220-3f779458e453.tm.json")
+		assert.ErrorIs(t, err, model.ErrTMNotFound)
+	})
+}
@@ -0,0 +1,183 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	// Algorithm is the JWS algorithm of TM signatures
+	Algorithm = "EdDSA"
+	// MediaType is the media type of the signature attachment
+	MediaType = "application/jose"
+
+	// CodeUnsigned and CodeInvalidSignature are the error codes reported by the API, when a TM has been refused
+	// because of its signature
+	CodeUnsigned         = "unsigned"
+	CodeInvalidSignature = "invalidSignature"
+)
+
+var (
+	ErrUnsigned         = errors.New("TM is not signed")
+	ErrInvalidSignature = errors.New("TM signature is invalid")
+	ErrInvalidKey       = errors.New("invalid key")
+)
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Sign creates a detached JWS (RFC 7515, Appendix F) in compact serialization over the contents of the TM file raw,
+// which are prepared the same way as for calculating the TM's digest
+func Sign(raw []byte, key ed25519.PrivateKey) ([]byte, error) {
+	payload, err := utils.PrepareForHashing(raw)
+	if err != nil {
+		return nil, err
+	}
+	pub, _ := key.Public().(ed25519.PublicKey)
+	h, err := json.Marshal(jwsHeader{Alg: Algorithm, Kid: KeyID(pub)})
+	if err != nil {
+		return nil, err
+	}
+	header := base64.RawURLEncoding.EncodeToString(h)
+	sig := ed25519.Sign(key, signingInput(header, payload))
+	return []byte(header + ".." + base64.RawURLEncoding.EncodeToString(sig)), nil
+}
+
+// Verify verifies the detached JWS sig over the contents of the TM file raw with the given trusted keys.
+// Returns the id of the key the signature has been verified with.
+// Returns ErrUnsigned if sig is empty and ErrInvalidSignature if sig cannot be verified with any of the keys
+func Verify(raw []byte, sig []byte, keys []ed25519.PublicKey) (string, error) {
+	s := strings.TrimSpace(string(sig))
+	if s == "" {
+		return "", ErrUnsigned
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return "", fmt.Errorf("%w: not a detached JWS in compact serialization", ErrInvalidSignature)
+	}
+	h, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid header: %v", ErrInvalidSignature, err)
+	}
+	var header jwsHeader
+	err = json.Unmarshal(h, &header)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid header: %v", ErrInvalidSignature, err)
+	}
+	if header.Alg != Algorithm {
+		return "", fmt.Errorf("%w: unsupported algorithm %s", ErrInvalidSignature, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid signature encoding: %v", ErrInvalidSignature, err)
+	}
+	payload, err := utils.PrepareForHashing(raw)
+	if err != nil {
+		return "", err
+	}
+	input := signingInput(parts[0], payload)
+	for _, k := range keys {
+		kid := KeyID(k)
+		if header.Kid != "" && header.Kid != kid {
+			continue
+		}
+		if ed25519.Verify(k, input, signature) {
+			return kid, nil
+		}
+	}
+	if header.Kid != "" {
+		return "", fmt.Errorf("%w: signed with key %s, which could not be verified with any trusted key", ErrInvalidSignature, header.Kid)
+	}
+	return "", fmt.Errorf("%w: could not be verified with any trusted key", ErrInvalidSignature)
+}
+
+func signingInput(header string, payload []byte) []byte {
+	return []byte(header + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
+
+// KeyID returns the id of a public key: the first 16 hex digits of the SHA-256 hash of the key
+func KeyID(key ed25519.PublicKey) string {
+	h := sha256.Sum256(key)
+	return hex.EncodeToString(h[:8])
+}
+
+// LoadPrivateKey reads an Ed25519 private key in PKCS #8, PEM-encoded form, as generated by
+// 'openssl genpkey -algorithm ed25519'. keyOrFile is either the PEM-encoded key itself or the name of a file containing it
+func LoadPrivateKey(keyOrFile string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(keyOrFile)
+	if err != nil {
+		return nil, err
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	key, ok := k.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an Ed25519 private key", ErrInvalidKey)
+	}
+	return key, nil
+}
+
+// LoadPublicKey reads an Ed25519 public key in PKIX, PEM-encoded form, as generated by 'openssl pkey -pubout'.
+// keyOrFile is either the PEM-encoded key itself or the name of a file containing it
+func LoadPublicKey(keyOrFile string) (ed25519.PublicKey, error) {
+	block, err := readPEM(keyOrFile)
+	if err != nil {
+		return nil, err
+	}
+	k, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	key, ok := k.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an Ed25519 public key", ErrInvalidKey)
+	}
+	return key, nil
+}
+
+// LoadPublicKeys reads all public keys with LoadPublicKey
+func LoadPublicKeys(keysOrFiles []string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, kf := range keysOrFiles {
+		k, err := LoadPublicKey(kf)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func readPEM(keyOrFile string) (*pem.Block, error) {
+	content := []byte(keyOrFile)
+	if !strings.HasPrefix(strings.TrimSpace(keyOrFile), "-----BEGIN") {
+		name, err := utils.ExpandHome(keyOrFile)
+		if err != nil {
+			return nil, err
+		}
+		content, err = os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read key file: %w", err)
+		}
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", ErrInvalidKey)
+	}
+	return block, nil
+}
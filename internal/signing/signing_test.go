@@ -0,0 +1,112 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tm = `{
+  "id": "omnicorp/omnicorp/lamp/v1.0.0-20240101120000-a1b2c3d4e5f6.tm.json",
+  "title": "Lamp",
+  "version": {"model": "1.0.0"}
+}`
+
+func TestSignAndVerify(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, otherPriv, _ := ed25519.GenerateKey(nil)
+
+	sig, err := Sign([]byte(tm), priv)
+	assert.NoError(t, err)
+	parts := strings.Split(string(sig), ".")
+	if assert.Len(t, parts, 3) {
+		assert.Empty(t, parts[1])
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		kid, err := Verify([]byte(tm), sig, []ed25519.PublicKey{otherPub, pub})
+		assert.NoError(t, err)
+		assert.Equal(t, KeyID(pub), kid)
+	})
+	t.Run("different id and line endings", func(t *testing.T) {
+		content := strings.ReplaceAll(strings.Replace(tm, "a1b2c3d4e5f6", "000000000000", 1), "\n", "\r\n")
+		_, err := Verify([]byte(content), sig, []ed25519.PublicKey{pub})
+		assert.NoError(t, err)
+	})
+	t.Run("modified content", func(t *testing.T) {
+		content := strings.Replace(tm, "Lamp", "Lamp 2", 1)
+		_, err := Verify([]byte(content), sig, []ed25519.PublicKey{pub})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+	t.Run("untrusted key", func(t *testing.T) {
+		_, err := Verify([]byte(tm), sig, []ed25519.PublicKey{otherPub})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		assert.ErrorContains(t, err, KeyID(pub))
+	})
+	t.Run("forged key id", func(t *testing.T) {
+		forged, _ := Sign([]byte(tm), otherPriv)
+		header := strings.Split(string(sig), ".")[0]
+		forged = []byte(header + ".." + strings.Split(string(forged), ".")[2])
+		_, err := Verify([]byte(tm), forged, []ed25519.PublicKey{pub})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+	t.Run("empty signature", func(t *testing.T) {
+		_, err := Verify([]byte(tm), []byte("\n"), []ed25519.PublicKey{pub})
+		assert.ErrorIs(t, err, ErrUnsigned)
+	})
+	t.Run("malformed signature", func(t *testing.T) {
+		for _, s := range []string{"abc", "a.b.c", "!!..abc", "e30..abc"} {
+			_, err := Verify([]byte(tm), []byte(s), []ed25519.PublicKey{pub})
+			assert.ErrorIs(t, err, ErrInvalidSignature, s)
+		}
+	})
+}
+
+func TestLoadKeys(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	privDer, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubDer, _ := x509.MarshalPKIXPublicKey(pub)
+	privPem := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer}))
+	pubPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}))
+
+	temp, _ := os.MkdirTemp("", "signing")
+	defer os.RemoveAll(temp)
+	privFile := filepath.Join(temp, "private.pem")
+	pubFile := filepath.Join(temp, "public.pem")
+	_ = os.WriteFile(privFile, []byte(privPem), 0600)
+	_ = os.WriteFile(pubFile, []byte(pubPem), 0600)
+
+	t.Run("private key from file", func(t *testing.T) {
+		k, err := LoadPrivateKey(privFile)
+		assert.NoError(t, err)
+		assert.Equal(t, priv, k)
+	})
+	t.Run("private key inline", func(t *testing.T) {
+		k, err := LoadPrivateKey(privPem)
+		assert.NoError(t, err)
+		assert.Equal(t, priv, k)
+	})
+	t.Run("public keys", func(t *testing.T) {
+		ks, err := LoadPublicKeys([]string{pubFile, pubPem})
+		assert.NoError(t, err)
+		assert.Equal(t, []ed25519.PublicKey{pub, pub}, ks)
+	})
+	t.Run("public key as private key", func(t *testing.T) {
+		_, err := LoadPrivateKey(pubPem)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+	t.Run("no PEM", func(t *testing.T) {
+		_, err := LoadPublicKey("-----BEGIN nothing")
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadPublicKey(filepath.Join(temp, "missing.pem"))
+		assert.ErrorContains(t, err, "could not read key file")
+	})
+}
@@ -75,3 +75,14 @@ func MockReposGetDescriptions(t interface {
 	}
 	t.Cleanup(func() { repos.GetDescriptions = org })
 }
+
+// MockReposGetDigestMode temporarily replaces the GetDigestMode() function with one returning the given mode
+func MockReposGetDigestMode(t interface {
+	Cleanup(func())
+}, mode string) {
+	org := repos.GetDigestMode
+	repos.GetDigestMode = func(spec model.RepoSpec) (string, error) {
+		return mode, nil
+	}
+	t.Cleanup(func() { repos.GetDigestMode = org })
+}
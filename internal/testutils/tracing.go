@@ -0,0 +1,85 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wot-oss/tmc/internal/tracing"
+)
+
+// Span is the part of a span written by the file tracing exporter which is of interest for tests
+type Span struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value any
+		}
+	}
+}
+
+// Attr returns the value of the attribute with given key, or nil if the span does not have it
+func (s Span) Attr(key string) any {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value.Value
+		}
+	}
+	return nil
+}
+
+// SetupTracing sets up tracing with the file exporter for the duration of the test. The returned function flushes the
+// spans recorded so far, stops tracing and returns the spans
+func SetupTracing(t *testing.T) func() []Span {
+	file := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := tracing.Setup(context.Background(), tracing.Options{Exporter: tracing.ExporterFile, File: file})
+	if err != nil {
+		t.Fatalf("could not set up tracing: %v", err)
+	}
+	stopped := false
+	stop := func() []Span {
+		if !stopped {
+			stopped = true
+			if err := shutdown(context.Background()); err != nil {
+				t.Fatalf("could not shut down tracing: %v", err)
+			}
+		}
+		return readSpans(t, file)
+	}
+	t.Cleanup(func() { stop() })
+	return stop
+}
+
+func readSpans(t *testing.T, file string) []Span {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("could not read spans: %v", err)
+	}
+	defer f.Close()
+	var spans []Span
+	dec := json.NewDecoder(f)
+	for {
+		var s Span
+		err := dec.Decode(&s)
+		if errors.Is(err, io.EOF) {
+			return spans
+		}
+		if err != nil {
+			t.Fatalf("could not read spans: %v", err)
+		}
+		spans = append(spans, s)
+	}
+}
@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wot-oss/tmc/internal/utils"
+)
+
+const (
+	ExporterNone   = ""
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	tracerName  = "github.com/wot-oss/tmc"
+	serviceName = "tmc"
+
+	// AttrRepo is the span attribute holding the name of the repository a span refers to
+	AttrRepo = attribute.Key("tmc.repo")
+)
+
+var SupportedExporters = []string{ExporterOTLP, ExporterStdout, ExporterFile}
+
+var ErrInvalidExporter = errors.New("invalid tracing exporter")
+
+var enabled atomic.Bool
+
+type Options struct {
+	// Exporter is the exporter spans are sent with, one of SupportedExporters. Tracing is disabled if empty.
+	// The OTLP exporter is configured by the standard OTEL_EXPORTER_OTLP_* environment variables
+	Exporter string
+	// File is the file spans are written to as JSON by ExporterFile
+	File string
+}
+
+// Setup configures the global tracer provider with the exporter given by opts and installs the W3C trace context
+// propagator. Without Setup, all spans are no-ops. The returned function flushes pending spans and must be called
+// before the program exits
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Exporter == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+	var exp sdktrace.SpanExporter
+	var closer io.Closer
+	var err error
+	switch opts.Exporter {
+	case ExporterOTLP:
+		exp, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		if opts.File == "" {
+			return nil, fmt.Errorf("%w: %s requires a file name", ErrInvalidExporter, ExporterFile)
+		}
+		var f *os.File
+		f, err = os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		closer = f
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("%w: %s. Supported exporters are %v", ErrInvalidExporter, opts.Exporter, SupportedExporters)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", utils.GetTmcVersion()),
+	))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled.Store(true)
+
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// Start starts a span with the given name as child of the span in ctx, if any
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End records err on span, if not nil, and ends span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Enabled returns whether spans are recorded, i.e. whether Setup has been called with an exporter
+func Enabled() bool {
+	return enabled.Load()
+}
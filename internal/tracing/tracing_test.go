@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), Options{})
+		assert.NoError(t, err)
+		assert.False(t, Enabled())
+		assert.NoError(t, shutdown(context.Background()))
+	})
+	t.Run("invalid exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), Options{Exporter: "jaeger"})
+		assert.ErrorIs(t, err, ErrInvalidExporter)
+		assert.False(t, Enabled())
+	})
+	t.Run("file exporter without file", func(t *testing.T) {
+		_, err := Setup(context.Background(), Options{Exporter: ExporterFile})
+		assert.ErrorIs(t, err, ErrInvalidExporter)
+	})
+	t.Run("file exporter", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "spans.json")
+		shutdown, err := Setup(context.Background(), Options{Exporter: ExporterFile, File: file})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, Enabled())
+
+		ctx, parent := Start(context.Background(), "parent")
+		_, child := Start(ctx, "child")
+		End(child, errors.New("failed"))
+		End(parent, nil)
+		assert.Equal(t, parent.SpanContext().TraceID(), child.SpanContext().TraceID())
+
+		assert.NoError(t, shutdown(context.Background()))
+		assert.False(t, Enabled())
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"Name":"child"`)
+		assert.Contains(t, string(content), `"Name":"parent"`)
+		assert.Contains(t, string(content), `"Description":"failed"`)
+	})
+}
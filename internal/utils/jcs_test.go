@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		exp     string
+		wantErr bool
+	}{
+		{in: `{ "b": 1, "a": [true, false, null] }`, exp: `{"a":[true,false,null],"b":1}`},
+		{in: "{\r\n  \"title\": \"Lamp\",\n  \"id\": \"\"\n}\n", exp: `{"id":"","title":"Lamp"}`},
+		{in: `{"nested": {"z": {}, "y": []}}`, exp: `{"nested":{"y":[],"z":{}}}`},
+		// example from RFC 8785, section 3.2.3
+		{in: `{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh", "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+			exp: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"},
+		// example from RFC 8785, section 3.2.2
+		{in: `{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "literals": [null, true, false]}`,
+			exp: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`},
+		{in: `"<&>"`, exp: `"<&>"`},
+		{in: `{"a": 1} {"b": 2}`, wantErr: true},
+		{in: `{"a": }`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			res, err := CanonicalizeJSON([]byte(test.in))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.exp, string(res))
+		})
+	}
+}
+
+func TestFormatES6Number(t *testing.T) {
+	tests := []struct {
+		in  float64
+		exp string
+	}{
+		{0, "0"},
+		{-0.0, "0"},
+		{1, "1"},
+		{-1.5, "-1.5"},
+		{100, "100"},
+		{123456789012345680000, "123456789012345680000"},
+		{1e21, "1e+21"},
+		{1.5e22, "1.5e+22"},
+		{0.000001, "0.000001"},
+		{0.0000001, "1e-7"},
+		{-1.25e-10, "-1.25e-10"},
+		{9007199254740992, "9007199254740992"},
+		{4.35, "4.35"},
+		{5e-324, "5e-324"},
+		{1.7976931348623157e308, "1.7976931348623157e+308"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.exp, formatES6Number(test.in), test.exp)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/buger/jsonparser"
 )
 
 var TmcVersion = "dev"
@@ -82,6 +83,13 @@ func ToTrimmedLower(s string) string {
 	return s
 }
 
+// PrepareForHashing returns the contents of a TM file, which its digest and signature are calculated over: the contents
+// with normalized line endings and 'id' set to empty string
+func PrepareForHashing(raw []byte) ([]byte, error) {
+	raw = NormalizeLineEndings(raw)
+	return jsonparser.Set(raw, []byte("\"\""), "id")
+}
+
 func NormalizeLineEndings(bytes []byte) []byte {
 	res := make([]byte, 0, len(bytes))
 	var prevB byte
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/wot-oss/tmc/cmd"
 	_ "github.com/wot-oss/tmc/cmd/attachment"
+	_ "github.com/wot-oss/tmc/cmd/label"
 	_ "github.com/wot-oss/tmc/cmd/repo"
 )
 